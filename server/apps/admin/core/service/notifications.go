@@ -62,9 +62,19 @@ func (s *notificationsService) OnEvent(e event.Event) error {
 	case ev.ServerSetupCompleted:
 		return s.StartWebhook()
 	case containerstypes.EventContainerStatusChange:
-		if e.Status == containerstypes.ContainerStatusOff || e.Status == containerstypes.ContainerStatusError || e.Status == containerstypes.ContainerStatusRunning {
+		switch e.Status {
+		case containerstypes.ContainerStatusOff,
+			containerstypes.ContainerStatusError,
+			containerstypes.ContainerStatusRunning,
+			containerstypes.ContainerStatusUnhealthy:
 			s.sendStatus(e.Name, e.Status)
 		}
+	case containerstypes.EventContainerUpdateAvailable:
+		s.send(e.Name, "An update is available.", 3447003)
+	case containerstypes.EventContainerUpdated:
+		s.send(e.Name, "Updated to the latest image of "+e.Image+".", 5763719)
+	case containerstypes.EventContainerUpdateRolledBack:
+		s.send(e.Name, "Rolled back to image "+e.Image+": "+e.Reason+".", 10038562)
 	}
 	return nil
 }
@@ -79,11 +89,17 @@ func (s *notificationsService) sendStatus(name string, status string) {
 		color = 15548997
 	case containerstypes.ContainerStatusError:
 		color = 10038562
+	case containerstypes.ContainerStatusUnhealthy:
+		color = 15105570
 	}
 
+	s.send(name, "Status: "+status, color)
+}
+
+func (s *notificationsService) send(title string, description string, color int) {
 	embed := discord.NewEmbedBuilder().
-		SetTitle(name).
-		SetDescriptionf("Status: %s", status).
+		SetTitle(title).
+		SetDescription(description).
 		SetColor(color).
 		Build()
 
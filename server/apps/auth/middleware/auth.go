@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
 	"github.com/juju/errors"
 	"github.com/vertex-center/vertex/server/apps/auth/api"
 	"github.com/vertex-center/vertex/server/common/log"
@@ -14,6 +15,10 @@ func ReadAuth(ctx *gin.Context) {
 	tokenStr := ctx.Request.Header.Get("Authorization")
 	tokenStr = strings.TrimPrefix(tokenStr, "Bearer")
 	tokenStr = strings.TrimSpace(tokenStr)
+	// Browsers can't set headers on websockets, so the token is passed in the query.
+	if tokenStr == "" && websocket.IsWebSocketUpgrade(ctx.Request) {
+		tokenStr = ctx.Query("token")
+	}
 	ctx.Set("token", tokenStr)
 	log.Debug("reading auth", vlog.String("token", tokenStr))
 	ctx.Next()
@@ -0,0 +1,175 @@
+package adapter
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/juju/errors"
+	"github.com/vertex-center/uuid"
+	"github.com/vertex-center/vertex/server/apps/containers/core/port"
+	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+	"github.com/vertex-center/vertex/server/common/storage"
+)
+
+type backupDBAdapter struct {
+	db storage.DB
+}
+
+func NewBackupDBAdapter(db storage.DB) port.BackupAdapter {
+	return &backupDBAdapter{db}
+}
+
+func (a *backupDBAdapter) GetBackups(ctx context.Context, containerID uuid.UUID) (types.Backups, error) {
+	var backups types.Backups
+	err := a.db.Select(&backups, `
+		SELECT * FROM backups
+		WHERE container_id = $1
+		ORDER BY version DESC
+	`, containerID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range backups {
+		err = a.db.Select(&backups[i].Volumes, `
+			SELECT * FROM backup_volumes
+			WHERE backup_id = $1
+			ORDER BY path
+		`, backups[i].ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return backups, nil
+}
+
+func (a *backupDBAdapter) GetBackup(ctx context.Context, id uuid.UUID) (*types.Backup, error) {
+	var backup types.Backup
+	err := a.db.Get(&backup, `
+		SELECT * FROM backups
+		WHERE id = $1
+	`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, types.ErrBackupNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	err = a.db.Select(&backup.Volumes, `
+		SELECT * FROM backup_volumes
+		WHERE backup_id = $1
+		ORDER BY path
+	`, id)
+	return &backup, err
+}
+
+func (a *backupDBAdapter) GetLatestBackupVersion(ctx context.Context, containerID uuid.UUID) (int, error) {
+	var version sql.NullInt64
+	err := a.db.Get(&version, `
+		SELECT MAX(version) FROM backups
+		WHERE container_id = $1
+	`, containerID)
+	return int(version.Int64), err
+}
+
+func (a *backupDBAdapter) CreateBackup(ctx context.Context, backup types.Backup) error {
+	tx, err := a.db.Beginx()
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.NamedExec(`
+		INSERT INTO backups (id, container_id, version, created_at, size)
+		VALUES (:id, :container_id, :version, :created_at, :size)
+	`, backup)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	for _, v := range backup.Volumes {
+		_, err = tx.NamedExec(`
+			INSERT INTO backup_volumes (id, backup_id, volume_id, type, name, path, archive, size, checksum)
+			VALUES (:id, :backup_id, :volume_id, :type, :name, :path, :archive, :size, :checksum)
+		`, v)
+		if err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (a *backupDBAdapter) DeleteBackup(ctx context.Context, id uuid.UUID) error {
+	tx, err := a.db.Beginx()
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`DELETE FROM backup_volumes WHERE backup_id = $1`, id)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	_, err = tx.Exec(`DELETE FROM backups WHERE id = $1`, id)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (a *backupDBAdapter) GetBackupSchedules(ctx context.Context) ([]types.BackupSchedule, error) {
+	var schedules []types.BackupSchedule
+	err := a.db.Select(&schedules, `
+		SELECT * FROM backup_schedules
+	`)
+	return schedules, err
+}
+
+func (a *backupDBAdapter) GetBackupSchedule(ctx context.Context, containerID uuid.UUID) (*types.BackupSchedule, error) {
+	var schedule types.BackupSchedule
+	err := a.db.Get(&schedule, `
+		SELECT * FROM backup_schedules
+		WHERE container_id = $1
+	`, containerID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, types.ErrBackupScheduleNotFound
+	}
+	return &schedule, err
+}
+
+func (a *backupDBAdapter) SetBackupSchedule(ctx context.Context, schedule types.BackupSchedule) error {
+	tx, err := a.db.Beginx()
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`DELETE FROM backup_schedules WHERE container_id = $1`, schedule.ContainerID)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	_, err = tx.NamedExec(`
+		INSERT INTO backup_schedules (container_id, schedule, retention, stop_container)
+		VALUES (:container_id, :schedule, :retention, :stop_container)
+	`, schedule)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (a *backupDBAdapter) DeleteBackupSchedule(ctx context.Context, containerID uuid.UUID) error {
+	_, err := a.db.Exec(`
+		DELETE FROM backup_schedules
+		WHERE container_id = $1
+	`, containerID)
+	return err
+}
@@ -0,0 +1,94 @@
+package adapter
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/juju/errors"
+	"github.com/vertex-center/uuid"
+	"github.com/vertex-center/vertex/server/apps/containers/core/port"
+	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+	"github.com/vertex-center/vertex/server/common/storage"
+)
+
+type buildDBAdapter struct {
+	db storage.DB
+}
+
+func NewBuildDBAdapter(db storage.DB) port.BuildAdapter {
+	return &buildDBAdapter{db}
+}
+
+func (a *buildDBAdapter) GetContainerBuild(ctx context.Context, id uuid.UUID) (*types.ContainerBuild, error) {
+	var build types.ContainerBuild
+	err := a.db.Get(&build, `
+		SELECT * FROM container_builds
+		WHERE container_id = $1
+	`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, types.ErrContainerBuildNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	err = a.db.Select(&build.Args, `
+		SELECT * FROM build_args
+		WHERE container_id = $1
+		ORDER BY name
+	`, id)
+	return &build, err
+}
+
+func (a *buildDBAdapter) SetContainerBuild(ctx context.Context, build types.ContainerBuild) error {
+	tx, err := a.db.Beginx()
+	if err != nil {
+		return err
+	}
+
+	for _, table := range []string{"container_builds", "build_args"} {
+		_, err = tx.Exec(`DELETE FROM `+table+` WHERE container_id = $1`, build.ContainerID)
+		if err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+
+	_, err = tx.NamedExec(`
+		INSERT INTO container_builds (id, container_id, repository, dockerfile)
+		VALUES (:id, :container_id, :repository, :dockerfile)
+	`, build)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	for _, arg := range build.Args {
+		_, err = tx.NamedExec(`
+			INSERT INTO build_args (id, container_id, name, value)
+			VALUES (:id, :container_id, :name, :value)
+		`, arg)
+		if err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (a *buildDBAdapter) DeleteContainerBuild(ctx context.Context, id uuid.UUID) error {
+	tx, err := a.db.Beginx()
+	if err != nil {
+		return err
+	}
+
+	for _, table := range []string{"build_args", "container_builds"} {
+		_, err = tx.Exec(`DELETE FROM `+table+` WHERE container_id = $1`, id)
+		if err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
@@ -39,6 +39,14 @@ func (a *capDBAdapter) CreateCap(ctx context.Context, c types.Capability) error
 	return err
 }
 
+func (a *capDBAdapter) DeleteCap(ctx context.Context, id uuid.UUID) error {
+	_, err := a.db.Exec(`
+		DELETE FROM capabilities
+		WHERE id = $1
+	`, id)
+	return err
+}
+
 func (a *capDBAdapter) DeleteContainerCaps(ctx context.Context, id uuid.UUID) error {
 	_, err := a.db.Exec(`
 		DELETE FROM capabilities
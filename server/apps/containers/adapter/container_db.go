@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/vertex-center/uuid"
@@ -39,13 +40,23 @@ func (a *containerDBAdapter) GetContainers(ctx context.Context) (types.Container
 func (a *containerDBAdapter) GetContainersWithFilters(ctx context.Context, filters types.ContainerFilters) (types.Containers, error) {
 	var containers types.Containers
 	query := `SELECT containers.* FROM containers`
-	var args []interface{}
+	var (
+		args  []interface{}
+		where []string
+	)
 	if filters.Tags != nil {
 		query += ` INNER JOIN container_tags ct on containers.id = ct.container_id`
 		query += ` INNER JOIN tags t on ct.tag_id = t.id`
-		query += ` WHERE t.name IN ($1)`
 		tags := strings.Join(*filters.Tags, ", ")
 		args = append(args, tags)
+		where = append(where, fmt.Sprintf(`t.name IN ($%d)`, len(args)))
+	}
+	if filters.StackID != nil {
+		args = append(args, *filters.StackID)
+		where = append(where, fmt.Sprintf(`containers.stack_id = $%d`, len(args)))
+	}
+	if len(where) > 0 {
+		query += ` WHERE ` + strings.Join(where, ` AND `)
 	}
 	query += ` ORDER BY containers.name`
 	err := a.db.Select(&containers, query, args...)
@@ -57,8 +68,8 @@ func (a *containerDBAdapter) GetContainersWithFilters(ctx context.Context, filte
 
 func (a *containerDBAdapter) CreateContainer(ctx context.Context, c types.Container) error {
 	_, err := a.db.NamedExec(`
-		INSERT INTO containers (id, template_id, user_id, image, image_tag, status, launch_on_startup, name, description, color, icon, command)
-		VALUES (:id, :template_id, :user_id, :image, :image_tag, :status, :launch_on_startup, :name, :description, :color, :icon, :command)
+		INSERT INTO containers (id, template_id, user_id, image, image_tag, status, launch_on_startup, name, description, color, icon, command, restart_policy, restart_max_retries, stopped_by_user, stack_id, stack_member, update_policy, update_window, pinned_image, previous_image, template_revision)
+		VALUES (:id, :template_id, :user_id, :image, :image_tag, :status, :launch_on_startup, :name, :description, :color, :icon, :command, :restart_policy, :restart_max_retries, :stopped_by_user, :stack_id, :stack_member, :update_policy, :update_window, :pinned_image, :previous_image, :template_revision)
 	`, c)
 	return err
 }
@@ -76,7 +87,17 @@ func (a *containerDBAdapter) UpdateContainer(ctx context.Context, c types.Contai
 			description = :description,
 			color = :color,
 			icon = :icon,
-			command = :command
+			command = :command,
+			restart_policy = :restart_policy,
+			restart_max_retries = :restart_max_retries,
+			stopped_by_user = :stopped_by_user,
+			stack_id = :stack_id,
+			stack_member = :stack_member,
+			update_policy = :update_policy,
+			update_window = :update_window,
+			pinned_image = :pinned_image,
+			previous_image = :previous_image,
+			template_revision = :template_revision
 		WHERE id = :id
 	`, c)
 	return err
@@ -127,3 +148,12 @@ func (a *containerDBAdapter) SetStatus(ctx context.Context, id uuid.UUID, status
 	`, status, id)
 	return err
 }
+
+func (a *containerDBAdapter) SetStoppedByUser(ctx context.Context, id uuid.UUID, stopped bool) error {
+	_, err := a.db.Exec(`
+		UPDATE containers
+		SET stopped_by_user = $1
+		WHERE id = $2
+	`, stopped, id)
+	return err
+}
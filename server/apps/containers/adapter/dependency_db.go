@@ -0,0 +1,80 @@
+package adapter
+
+import (
+	"context"
+
+	"github.com/vertex-center/uuid"
+	"github.com/vertex-center/vertex/server/apps/containers/core/port"
+	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+	"github.com/vertex-center/vertex/server/common/storage"
+)
+
+type dependencyDBAdapter struct {
+	db storage.DB
+}
+
+func NewDependencyDBAdapter(db storage.DB) port.DependencyAdapter {
+	return &dependencyDBAdapter{db}
+}
+
+func (a *dependencyDBAdapter) GetDependencies(ctx context.Context) (types.Dependencies, error) {
+	var deps types.Dependencies
+	err := a.db.Select(&deps, `
+		SELECT * FROM container_dependencies
+	`)
+	return deps, err
+}
+
+func (a *dependencyDBAdapter) GetContainerDependencies(ctx context.Context, id uuid.UUID) (types.Dependencies, error) {
+	var deps types.Dependencies
+	err := a.db.Select(&deps, `
+		SELECT * FROM container_dependencies
+		WHERE container_id = $1
+	`, id)
+	return deps, err
+}
+
+func (a *dependencyDBAdapter) SetDependency(ctx context.Context, dep types.Dependency) error {
+	tx, err := a.db.Beginx()
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		DELETE FROM container_dependencies
+		WHERE container_id = $1 AND depends_on_id = $2
+	`, dep.ContainerID, dep.DependsOnID)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	_, err = tx.NamedExec(`
+		INSERT INTO container_dependencies (container_id, depends_on_id, wait_for)
+		VALUES (:container_id, :depends_on_id, :wait_for)
+	`, dep)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (a *dependencyDBAdapter) DeleteDependency(ctx context.Context, id uuid.UUID, dependsOnID uuid.UUID) error {
+	_, err := a.db.Exec(`
+		DELETE FROM container_dependencies
+		WHERE container_id = $1 AND depends_on_id = $2
+	`, id, dependsOnID)
+	return err
+}
+
+// DeleteContainerDependencies deletes the dependencies of a container,
+// and the dependencies of other containers on it.
+func (a *dependencyDBAdapter) DeleteContainerDependencies(ctx context.Context, id uuid.UUID) error {
+	_, err := a.db.Exec(`
+		DELETE FROM container_dependencies
+		WHERE container_id = $1 OR depends_on_id = $1
+	`, id)
+	return err
+}
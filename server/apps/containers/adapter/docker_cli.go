@@ -2,11 +2,16 @@ package adapter
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
+	"sort"
+	"strings"
 
 	dockertypes "github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/registry"
 	"github.com/docker/docker/api/types/volume"
 	"github.com/docker/docker/client"
 	"github.com/docker/docker/pkg/archive"
@@ -61,6 +66,7 @@ func (a dockerCliAdapter) CreateContainer(options types.CreateDockerContainerOpt
 		AttachStdout: true,
 		AttachStderr: true,
 		Cmd:          options.Cmd,
+		Healthcheck:  options.Healthcheck,
 	}
 
 	hostConfig := container.HostConfig{
@@ -70,12 +76,40 @@ func (a dockerCliAdapter) CreateContainer(options types.CreateDockerContainerOpt
 		CapAdd:       options.CapAdd,
 		Sysctls:      options.Sysctls,
 	}
+	if options.Resources != nil {
+		hostConfig.Resources = *options.Resources
+	}
+
+	// Docker only accepts one network at creation, the others are
+	// connected once the container exists.
+	var networkConfig *network.NetworkingConfig
+	if len(options.Networks) > 0 {
+		first := options.Networks[0]
+		hostConfig.NetworkMode = container.NetworkMode(first.Name)
+		networkConfig = &network.NetworkingConfig{
+			EndpointsConfig: map[string]*network.EndpointSettings{
+				first.Name: {Aliases: first.Aliases},
+			},
+		}
+	}
 
-	res, err := a.cli.ContainerCreate(context.Background(), &config, &hostConfig, nil, nil, options.ContainerName)
+	res, err := a.cli.ContainerCreate(context.Background(), &config, &hostConfig, networkConfig, nil, options.ContainerName)
 	if err != nil {
 		return types.CreateContainerResponse{}, err
 	}
 
+	for i := 1; i < len(options.Networks); i++ {
+		err = a.ConnectNetwork(types.ConnectNetworkOptions{
+			Network:   options.Networks[i].Name,
+			Container: res.ID,
+			Aliases:   options.Networks[i].Aliases,
+		})
+		if err != nil {
+			_ = a.cli.ContainerRemove(context.Background(), res.ID, container.RemoveOptions{})
+			return types.CreateContainerResponse{}, err
+		}
+	}
+
 	return types.CreateContainerResponse{
 		ID:       res.ID,
 		Warnings: res.Warnings,
@@ -101,19 +135,101 @@ func (a dockerCliAdapter) InfoContainer(id string) (types.InfoContainerResponse,
 		p := fmt.Sprintf("%s:%s", out, in[0].HostPort)
 		ports = append(ports, p)
 	}
+	var (
+		health   string
+		exitCode int
+	)
+	if info.State != nil {
+		exitCode = info.State.ExitCode
+		if info.State.Health != nil {
+			health = info.State.Health.Status
+		}
+	}
+
 	return types.InfoContainerResponse{
 		ID:           info.ID,
 		Name:         info.Name,
 		Platform:     info.Platform,
 		Image:        info.Image,
 		PortBindings: ports,
+		Health:       health,
+		ExitCode:     exitCode,
 	}, nil
 }
 
+// InspectContainer returns the configuration of a container, and the
+// configuration it inherits from its image.
+func (a dockerCliAdapter) InspectContainer(id string) (types.InspectContainerResponse, error) {
+	info, err := a.cli.ContainerInspect(context.Background(), id)
+	if err != nil {
+		return types.InspectContainerResponse{}, err
+	}
+
+	res := types.InspectContainerResponse{
+		ID:     info.ID,
+		Name:   strings.TrimPrefix(info.Name, "/"),
+		Mounts: types.NewMounts(info.Mounts),
+	}
+	if info.Config != nil {
+		res.Image = info.Config.Image
+		res.Env = info.Config.Env
+		res.Cmd = info.Config.Cmd
+	}
+	if info.HostConfig != nil {
+		res.CapAdd = info.HostConfig.CapAdd
+		res.Sysctls = info.HostConfig.Sysctls
+		res.RestartPolicy = string(info.HostConfig.RestartPolicy.Name)
+		res.RestartMaxRetries = info.HostConfig.RestartPolicy.MaximumRetryCount
+		for p, bindings := range info.HostConfig.PortBindings {
+			for _, b := range bindings {
+				res.PortBindings = append(res.PortBindings, types.DockerPortBinding{
+					Container: string(p),
+					Host:      b.HostPort,
+					HostIP:    b.HostIP,
+				})
+			}
+		}
+		sort.Slice(res.PortBindings, func(i, j int) bool {
+			return res.PortBindings[i].Container < res.PortBindings[j].Container
+		})
+	}
+
+	img, _, err := a.cli.ImageInspectWithRaw(context.Background(), info.Image)
+	if err != nil && !client.IsErrNotFound(err) {
+		return types.InspectContainerResponse{}, err
+	}
+	if err == nil && img.Config != nil {
+		res.ImageEnv = img.Config.Env
+		res.ImageCmd = img.Config.Cmd
+	}
+	return res, nil
+}
+
+func (a dockerCliAdapter) RenameContainer(id string, name string) error {
+	return a.cli.ContainerRename(context.Background(), id, name)
+}
+
+// StatsContainer returns a single sample of the container stats. Docker
+// waits for a second sample to compute the CPU usage.
+func (a dockerCliAdapter) StatsContainer(id string) (types.ContainerStats, error) {
+	res, err := a.cli.ContainerStats(context.Background(), id, false)
+	if err != nil {
+		return types.ContainerStats{}, err
+	}
+	defer res.Body.Close()
+
+	var stats dockertypes.StatsJSON
+	err = json.NewDecoder(res.Body).Decode(&stats)
+	if err != nil {
+		return types.ContainerStats{}, err
+	}
+	return types.NewContainerStats(stats), nil
+}
+
 func (a dockerCliAdapter) LogsStdoutContainer(id string) (io.ReadCloser, error) {
 	return a.cli.ContainerLogs(context.Background(), id, container.LogsOptions{
 		ShowStdout: true,
-		Timestamps: false,
+		Timestamps: true,
 		Follow:     true,
 		Tail:       "0",
 	})
@@ -122,7 +238,7 @@ func (a dockerCliAdapter) LogsStdoutContainer(id string) (io.ReadCloser, error)
 func (a dockerCliAdapter) LogsStderrContainer(id string) (io.ReadCloser, error) {
 	return a.cli.ContainerLogs(context.Background(), id, container.LogsOptions{
 		ShowStderr: true,
-		Timestamps: false,
+		Timestamps: true,
 		Follow:     true,
 		Tail:       "0",
 	})
@@ -147,23 +263,42 @@ func (a dockerCliAdapter) InfoImage(id string) (types.InfoImageResponse, error)
 	if err != nil {
 		return types.InfoImageResponse{}, err
 	}
+	var labels map[string]string
+	if info.Config != nil {
+		labels = info.Config.Labels
+	}
 	return types.InfoImageResponse{
 		ID:           info.ID,
 		Architecture: info.Architecture,
 		OS:           info.Os,
 		Size:         info.Size,
 		Tags:         info.RepoTags,
+		Digests:      info.RepoDigests,
+		Labels:       labels,
 	}, nil
 }
 
 func (a dockerCliAdapter) PullImage(options types.PullImageOptions) (io.ReadCloser, error) {
-	return a.cli.ImagePull(context.Background(), options.Image, dockertypes.ImagePullOptions{})
+	var pullOptions dockertypes.ImagePullOptions
+	if options.Auth != nil {
+		auth, err := registry.EncodeAuthConfig(registry.AuthConfig{
+			Username: options.Auth.Username,
+			Password: options.Auth.Password,
+		})
+		if err != nil {
+			return nil, err
+		}
+		pullOptions.RegistryAuth = auth
+	}
+	return a.cli.ImagePull(context.Background(), options.Image, pullOptions)
 }
 
 func (a dockerCliAdapter) BuildImage(options types.BuildImageOptions) (dockertypes.ImageBuildResponse, error) {
 	buildOptions := dockertypes.ImageBuildOptions{
 		Dockerfile: options.Dockerfile,
 		Tags:       []string{options.Name},
+		BuildArgs:  options.BuildArgs,
+		Labels:     options.Labels,
 		Remove:     true,
 	}
 
@@ -186,3 +321,101 @@ func (a dockerCliAdapter) CreateVolume(options types.CreateVolumeOptions) (volum
 func (a dockerCliAdapter) DeleteVolume(name string) error {
 	return a.cli.VolumeRemove(context.Background(), name, true)
 }
+
+// GetVolumeMountpoint returns the path on the host where the data of a volume is stored.
+func (a dockerCliAdapter) GetVolumeMountpoint(name string) (string, error) {
+	vol, err := a.cli.VolumeInspect(context.Background(), name)
+	if err != nil {
+		return "", err
+	}
+	return vol.Mountpoint, nil
+}
+
+// ExecContainer runs an interactive command with a TTY in a running container.
+func (a dockerCliAdapter) ExecContainer(id string, options types.ExecOptions) (port.ExecSession, error) {
+	config := dockertypes.ExecConfig{
+		Tty:          true,
+		AttachStdin:  true,
+		AttachStdout: true,
+		AttachStderr: true,
+		Cmd:          options.Cmd,
+	}
+	if options.Cols > 0 && options.Rows > 0 {
+		config.ConsoleSize = &[2]uint{options.Rows, options.Cols}
+	}
+
+	exec, err := a.cli.ContainerExecCreate(context.Background(), id, config)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := a.cli.ContainerExecAttach(context.Background(), exec.ID, dockertypes.ExecStartCheck{
+		Tty:         true,
+		ConsoleSize: config.ConsoleSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &dockerExecSession{
+		cli:  a.cli,
+		id:   exec.ID,
+		conn: res,
+	}, nil
+}
+
+// CreateNetwork creates a bridge network. It does nothing if the network
+// already exists.
+func (a dockerCliAdapter) CreateNetwork(options types.CreateNetworkOptions) error {
+	_, err := a.cli.NetworkInspect(context.Background(), options.Name, dockertypes.NetworkInspectOptions{})
+	if err == nil {
+		return nil
+	} else if !client.IsErrNotFound(err) {
+		return err
+	}
+
+	_, err = a.cli.NetworkCreate(context.Background(), options.Name, dockertypes.NetworkCreate{
+		Driver: "bridge",
+	})
+	return err
+}
+
+func (a dockerCliAdapter) DeleteNetwork(name string) error {
+	return a.cli.NetworkRemove(context.Background(), name)
+}
+
+func (a dockerCliAdapter) ConnectNetwork(options types.ConnectNetworkOptions) error {
+	return a.cli.NetworkConnect(context.Background(), options.Network, options.Container, &network.EndpointSettings{
+		Aliases: options.Aliases,
+	})
+}
+
+func (a dockerCliAdapter) DisconnectNetwork(options types.DisconnectNetworkOptions) error {
+	return a.cli.NetworkDisconnect(context.Background(), options.Network, options.Container, false)
+}
+
+type dockerExecSession struct {
+	cli  *client.Client
+	id   string
+	conn dockertypes.HijackedResponse
+}
+
+func (s *dockerExecSession) Read(p []byte) (int, error) {
+	return s.conn.Reader.Read(p)
+}
+
+func (s *dockerExecSession) Write(p []byte) (int, error) {
+	return s.conn.Conn.Write(p)
+}
+
+func (s *dockerExecSession) Close() error {
+	s.conn.Close()
+	return nil
+}
+
+func (s *dockerExecSession) Resize(cols, rows uint) error {
+	return s.cli.ContainerExecResize(context.Background(), s.id, container.ResizeOptions{
+		Height: rows,
+		Width:  cols,
+	})
+}
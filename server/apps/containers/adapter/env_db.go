@@ -9,14 +9,30 @@ import (
 	"github.com/vertex-center/vertex/server/apps/containers/core/port"
 	"github.com/vertex-center/vertex/server/apps/containers/core/types"
 	"github.com/vertex-center/vertex/server/common/storage"
+	"github.com/vertex-center/vertex/server/pkg/vcrypto"
 )
 
+// envDBAdapter stores the environment variables. The values of the secret
+// variables are encrypted with the keyring, and are returned encrypted.
 type envDBAdapter struct {
-	db storage.DB
+	db      storage.DB
+	keyring *vcrypto.Keyring
 }
 
-func NewEnvDBAdapter(db storage.DB) port.EnvAdapter {
-	return &envDBAdapter{db}
+func NewEnvDBAdapter(db storage.DB, keyring *vcrypto.Keyring) port.EnvAdapter {
+	return &envDBAdapter{db, keyring}
+}
+
+func (a *envDBAdapter) GetEnv(ctx context.Context, id uuid.UUID) (*types.EnvVariable, error) {
+	var env types.EnvVariable
+	err := a.db.Get(&env, `
+		SELECT * FROM env_variables
+		WHERE id = $1
+	`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, types.ErrEnvVariableNotFound
+	}
+	return &env, err
 }
 
 func (a *envDBAdapter) GetEnvs(ctx context.Context, filters types.EnvVariableFilters) ([]types.EnvVariable, error) {
@@ -36,7 +52,12 @@ func (a *envDBAdapter) GetEnvs(ctx context.Context, filters types.EnvVariableFil
 }
 
 func (a *envDBAdapter) CreateEnv(ctx context.Context, v types.EnvVariable) error {
-	_, err := a.db.NamedExec(`
+	var err error
+	v.Value, err = a.seal(v.Secret, v.Value)
+	if err != nil {
+		return err
+	}
+	_, err = a.db.NamedExec(`
 		INSERT INTO env_variables (id, container_id, type, name, display_name, value, default_value, description, secret)
 		VALUES (:id, :container_id, :type, :name, :display_name, :value, :default_value, :description, :secret)
 	`, v)
@@ -60,7 +81,18 @@ func (a *envDBAdapter) DeleteEnvs(ctx context.Context, id uuid.UUID) error {
 }
 
 func (a *envDBAdapter) UpdateEnvByID(ctx context.Context, v types.EnvVariable) error {
-	_, err := a.db.Exec(`
+	var secret bool
+	err := a.db.Get(&secret, `SELECT secret FROM env_variables WHERE id = $1`, v.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return types.ErrEnvVariableNotFound
+	} else if err != nil {
+		return err
+	}
+	v.Value, err = a.seal(secret, v.Value)
+	if err != nil {
+		return err
+	}
+	_, err = a.db.Exec(`
 		UPDATE env_variables
 		SET name = $1, value = $2
 		WHERE id = $3
@@ -69,10 +101,35 @@ func (a *envDBAdapter) UpdateEnvByID(ctx context.Context, v types.EnvVariable) e
 }
 
 func (a *envDBAdapter) UpdateEnvByName(ctx context.Context, v types.EnvVariable) error {
-	_, err := a.db.Exec(`
+	var secret bool
+	err := a.db.Get(&secret, `SELECT secret FROM env_variables WHERE container_id = $1 AND name = $2`, v.ContainerID, v.Name)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	v.Value, err = a.seal(secret, v.Value)
+	if err != nil {
+		return err
+	}
+	_, err = a.db.Exec(`
 		UPDATE env_variables
 		SET value = $1
 		WHERE container_id = $2 AND name = $3
 	`, v.Value, v.ContainerID, v.Name)
 	return err
 }
+
+// seal encrypts the value of a secret variable with the current key. Values
+// encrypted with a previous key are encrypted again.
+func (a *envDBAdapter) seal(secret bool, value string) (string, error) {
+	if !secret || a.keyring.IsCurrent(value) {
+		return value, nil
+	}
+	if vcrypto.IsEncrypted(value) {
+		var err error
+		value, err = a.keyring.Decrypt(value)
+		if err != nil {
+			return "", err
+		}
+	}
+	return a.keyring.Encrypt(value)
+}
@@ -0,0 +1,60 @@
+package adapter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/vertex-center/vertex/server/pkg/vcrypto"
+)
+
+type EnvDBAdapterTestSuite struct {
+	suite.Suite
+
+	adapter *envDBAdapter
+	keyring *vcrypto.Keyring
+}
+
+func TestEnvDBAdapterTestSuite(t *testing.T) {
+	suite.Run(t, new(EnvDBAdapterTestSuite))
+}
+
+func (suite *EnvDBAdapterTestSuite) SetupTest() {
+	var err error
+	suite.keyring, err = vcrypto.LoadOrCreateKeyring(suite.T().TempDir())
+	suite.Require().NoError(err)
+	suite.adapter = &envDBAdapter{keyring: suite.keyring}
+}
+
+func (suite *EnvDBAdapterTestSuite) TestSeal() {
+	value, err := suite.adapter.seal(false, "value")
+	suite.Require().NoError(err)
+	suite.Equal("value", value)
+
+	sealed, err := suite.adapter.seal(true, "secret")
+	suite.Require().NoError(err)
+	suite.True(suite.keyring.IsCurrent(sealed))
+	plaintext, err := suite.keyring.Decrypt(sealed)
+	suite.Require().NoError(err)
+	suite.Equal("secret", plaintext)
+
+	// A value encrypted with the current key is kept as is.
+	value, err = suite.adapter.seal(true, sealed)
+	suite.Require().NoError(err)
+	suite.Equal(sealed, value)
+}
+
+func (suite *EnvDBAdapterTestSuite) TestSealPreviousKey() {
+	previous, err := suite.keyring.Encrypt("secret")
+	suite.Require().NoError(err)
+	suite.Require().NoError(suite.keyring.Rotate())
+
+	sealed, err := suite.adapter.seal(true, previous)
+	suite.Require().NoError(err)
+	suite.NotEqual(previous, sealed)
+	suite.True(suite.keyring.IsCurrent(sealed))
+
+	suite.Require().NoError(suite.keyring.Prune())
+	plaintext, err := suite.keyring.Decrypt(sealed)
+	suite.Require().NoError(err)
+	suite.Equal("secret", plaintext)
+}
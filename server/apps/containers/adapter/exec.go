@@ -0,0 +1,67 @@
+package adapter
+
+import (
+	"io"
+	"sync"
+
+	"github.com/gorilla/websocket"
+	"github.com/vertex-center/vertex/server/apps/containers/core/port"
+	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+)
+
+// execSession is an exec session running in the kernel, reached through a websocket.
+type execSession struct {
+	conn *websocket.Conn
+
+	// mu guards writes, as the input and the resizes are sent from different goroutines.
+	mu  sync.Mutex
+	buf []byte
+}
+
+func newExecSession(conn *websocket.Conn) port.ExecSession {
+	return &execSession{conn: conn}
+}
+
+func (s *execSession) Read(p []byte) (int, error) {
+	for len(s.buf) == 0 {
+		_, msg, err := s.conn.ReadMessage()
+		if websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+			return 0, io.EOF
+		} else if err != nil {
+			return 0, err
+		}
+		s.buf = msg
+	}
+	n := copy(p, s.buf)
+	s.buf = s.buf[n:]
+	return n, nil
+}
+
+func (s *execSession) Write(p []byte) (int, error) {
+	err := s.send(types.ExecMessage{
+		Type: types.ExecMessageInput,
+		Data: string(p),
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (s *execSession) Resize(cols, rows uint) error {
+	return s.send(types.ExecMessage{
+		Type: types.ExecMessageResize,
+		Cols: cols,
+		Rows: rows,
+	})
+}
+
+func (s *execSession) Close() error {
+	return s.conn.Close()
+}
+
+func (s *execSession) send(msg types.ExecMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.conn.WriteJSON(msg)
+}
@@ -0,0 +1,67 @@
+package adapter
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/juju/errors"
+	"github.com/vertex-center/uuid"
+	"github.com/vertex-center/vertex/server/apps/containers/core/port"
+	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+	"github.com/vertex-center/vertex/server/common/storage"
+)
+
+type healthCheckDBAdapter struct {
+	db storage.DB
+}
+
+func NewHealthCheckDBAdapter(db storage.DB) port.HealthCheckAdapter {
+	return &healthCheckDBAdapter{db}
+}
+
+func (a *healthCheckDBAdapter) GetContainerHealthCheck(ctx context.Context, id uuid.UUID) (*types.HealthCheck, error) {
+	var hc types.HealthCheck
+	err := a.db.Get(&hc, `
+		SELECT * FROM health_checks
+		WHERE container_id = $1
+	`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, types.ErrHealthCheckNotFound
+	}
+	return &hc, err
+}
+
+func (a *healthCheckDBAdapter) SetContainerHealthCheck(ctx context.Context, hc types.HealthCheck) error {
+	tx, err := a.db.Beginx()
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		DELETE FROM health_checks
+		WHERE container_id = $1
+	`, hc.ContainerID)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	_, err = tx.NamedExec(`
+		INSERT INTO health_checks (id, container_id, type, port, path, command, check_interval, check_timeout, start_period, retries)
+		VALUES (:id, :container_id, :type, :port, :path, :command, :check_interval, :check_timeout, :start_period, :retries)
+	`, hc)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (a *healthCheckDBAdapter) DeleteContainerHealthCheck(ctx context.Context, id uuid.UUID) error {
+	_, err := a.db.Exec(`
+		DELETE FROM health_checks
+		WHERE container_id = $1
+	`, id)
+	return err
+}
@@ -0,0 +1,41 @@
+package adapter
+
+import (
+	"errors"
+	"net"
+	"strconv"
+	"syscall"
+
+	"github.com/vertex-center/vertex/server/apps/containers/core/port"
+	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+)
+
+type hostPortsAdapter struct{}
+
+// NewHostPortsAdapter returns an adapter to check the ports listened on the
+// host, including those not opened by Vertex containers.
+func NewHostPortsAdapter() port.HostPortsAdapter {
+	return &hostPortsAdapter{}
+}
+
+// IsPortFree tries to listen on the port. Only a port already in use is
+// reported as not free, so that ports reserved to root are still accepted.
+func (a *hostPortsAdapter) IsPortFree(protocol types.PortProtocol, p int) bool {
+	addr := ":" + strconv.Itoa(p)
+
+	var err error
+	if protocol == types.PortProtocolUDP {
+		var conn net.PacketConn
+		conn, err = net.ListenPacket("udp", addr)
+		if err == nil {
+			_ = conn.Close()
+		}
+	} else {
+		var l net.Listener
+		l, err = net.Listen("tcp", addr)
+		if err == nil {
+			_ = l.Close()
+		}
+	}
+	return !errors.Is(err, syscall.EADDRINUSE)
+}
@@ -0,0 +1,29 @@
+package adapter
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+)
+
+type HostPortsTestSuite struct {
+	suite.Suite
+}
+
+func TestHostPortsTestSuite(t *testing.T) {
+	suite.Run(t, new(HostPortsTestSuite))
+}
+
+func (suite *HostPortsTestSuite) TestIsPortFree() {
+	l, err := net.Listen("tcp", ":0")
+	suite.Require().NoError(err)
+	p := l.Addr().(*net.TCPAddr).Port
+
+	a := NewHostPortsAdapter()
+	suite.False(a.IsPortFree(types.PortProtocolTCP, p))
+
+	suite.Require().NoError(l.Close())
+	suite.True(a.IsPortFree(types.PortProtocolTCP, p))
+}
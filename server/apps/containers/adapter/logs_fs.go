@@ -1,9 +1,14 @@
 package adapter
 
 import (
-	"fmt"
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"io"
 	"os"
 	"path"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -14,22 +19,54 @@ import (
 	"github.com/vertex-center/vertex/server/apps/containers/core/types"
 	"github.com/vertex-center/vertex/server/common/log"
 	"github.com/vertex-center/vertex/server/common/storage"
+	"github.com/vertex-center/vertex/server/pkg/varchiver"
 	"github.com/vertex-center/vlog"
 )
 
-const bufferSize = 50
+const (
+	bufferSize = 50
+
+	defaultMaxLogFileSize = 10 * 1024 * 1024
+	defaultLogsRetention  = 30 * 24 * time.Hour
+
+	logFilePrefix     = "logs_"
+	logFileExt        = ".jsonl"
+	logFileTimeFormat = "2006-01-02_15-04-05.000000"
+)
 
 var (
 	ErrLoggerNotFound = errors.NotFoundf("logger")
+	ErrLogsNotFound   = errors.NotFoundf("logs")
 )
 
 type ContainerLogger struct {
 	uuid        uuid.UUID
-	file        *os.File
 	buffer      []types.LogLine
 	currentLine int
 	scheduler   *gocron.Scheduler
 	dir         string
+
+	// maxSize is the size after which the log file is rotated. 0 means no limit.
+	maxSize int64
+	// retention is the duration after which rotated files are deleted. 0 means forever.
+	retention time.Duration
+
+	// mu guards the file, which is replaced on rotation.
+	mu   sync.Mutex
+	file *os.File
+	size int64
+
+	// cleanupMu makes sure a file is not compressed twice at the same time.
+	cleanupMu sync.Mutex
+}
+
+// logRecord is a line of a log file.
+type logRecord struct {
+	Time    time.Time      `json:"time"`
+	Kind    string         `json:"kind"`
+	Level   string         `json:"level,omitempty"`
+	Fields  map[string]any `json:"fields,omitempty"`
+	Message string         `json:"message"`
 }
 
 type logsFSAdapter struct {
@@ -37,10 +74,14 @@ type logsFSAdapter struct {
 	loggersMu sync.RWMutex
 
 	containersPath string
+	maxFileSize    int64
+	retention      time.Duration
 }
 
 type LogsFSAdapterParams struct {
 	ContainersPath string
+	MaxFileSize    int64
+	Retention      time.Duration
 }
 
 func NewLogsFSAdapter(params *LogsFSAdapterParams) port.LogsAdapter {
@@ -52,11 +93,21 @@ func NewLogsFSAdapter(params *LogsFSAdapterParams) port.LogsAdapter {
 		params.ContainersPath = path.Join(storage.FSPath, "apps", "containers", "containers")
 	}
 
+	if params.MaxFileSize == 0 {
+		params.MaxFileSize = defaultMaxLogFileSize
+	}
+
+	if params.Retention == 0 {
+		params.Retention = defaultLogsRetention
+	}
+
 	return &logsFSAdapter{
 		loggers:   map[uuid.UUID]*ContainerLogger{},
 		loggersMu: sync.RWMutex{},
 
 		containersPath: params.ContainersPath,
+		maxFileSize:    params.MaxFileSize,
+		retention:      params.Retention,
 	}
 }
 
@@ -69,9 +120,11 @@ func (a *logsFSAdapter) Register(uuid uuid.UUID) error {
 	}
 
 	l := ContainerLogger{
-		uuid:   uuid,
-		buffer: []types.LogLine{},
-		dir:    dir,
+		uuid:      uuid,
+		buffer:    []types.LogLine{},
+		dir:       dir,
+		maxSize:   a.maxFileSize,
+		retention: a.retention,
 	}
 
 	a.loggersMu.Lock()
@@ -114,13 +167,16 @@ func (a *logsFSAdapter) Push(uuid uuid.UUID, line types.LogLine) {
 		log.Error(err)
 		return
 	}
+	if line.Time.IsZero() {
+		line.Time = time.Now()
+	}
 	l.currentLine += 1
 	l.buffer = append(l.buffer, line)
 	if len(l.buffer) > bufferSize {
 		l.buffer = l.buffer[1:]
 	}
 
-	_, err = fmt.Fprintf(l.file, "%s\n", line.Message.String())
+	err = l.Write(line)
 	if err != nil {
 		log.Error(err)
 	}
@@ -147,6 +203,63 @@ func (a *logsFSAdapter) LoadBuffer(uuid uuid.UUID) ([]types.LogLine, error) {
 	return l.buffer, nil
 }
 
+// Search reads the log files of a container, including the rotated ones. It
+// works for containers that are not running.
+func (a *logsFSAdapter) Search(uuid uuid.UUID, filters types.LogFilters) ([]types.LogLine, error) {
+	files, err := logFiles(a.dir(uuid))
+	if err != nil {
+		return nil, err
+	}
+
+	limit := filters.Limit
+	if limit <= 0 {
+		limit = types.DefaultLogsLimit
+	}
+
+	lines := []types.LogLine{}
+	for i, f := range files {
+		// The lines of a file are older than the start of the next one.
+		if !filters.Until.IsZero() && !f.start.Before(filters.Until) {
+			break
+		}
+		if !filters.Since.IsZero() && i+1 < len(files) && !files[i+1].start.After(filters.Since) {
+			continue
+		}
+
+		err := readLogFile(f.path, func(r logRecord) {
+			line := types.LogLine{
+				Time:    r.Time,
+				Kind:    r.Kind,
+				Level:   r.Level,
+				Fields:  r.Fields,
+				Message: types.NewLogLineMessageString(r.Message),
+			}
+			if !filters.Match(line) {
+				return
+			}
+			lines = append(lines, line)
+			if len(lines) > limit {
+				lines = lines[1:]
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return lines, nil
+}
+
+// Archive writes all the log files of a container, as they are on disk, in a tar.gz archive.
+func (a *logsFSAdapter) Archive(uuid uuid.UUID, w io.Writer) error {
+	dir := a.dir(uuid)
+	if _, err := os.Stat(dir); errors.Is(err, os.ErrNotExist) {
+		return ErrLogsNotFound
+	} else if err != nil {
+		return err
+	}
+	return varchiver.Tar(dir, w)
+}
+
 func (a *logsFSAdapter) UnregisterAll() error {
 	var ids []uuid.UUID
 
@@ -187,20 +300,22 @@ func (a *logsFSAdapter) dir(uuid uuid.UUID) string {
 	return path.Join(a.containersPath, uuid.String(), ".vertex", "logs")
 }
 
+// Open creates a new log file. The files left by previous runs are compressed.
 func (l *ContainerLogger) Open() error {
-	filename := fmt.Sprintf("logs_%s.txt", time.Now().Format(time.DateOnly))
-	filepath := path.Join(l.dir, filename)
-
-	file, err := os.OpenFile(filepath, os.O_RDWR|os.O_CREATE|os.O_APPEND, os.ModePerm)
+	l.mu.Lock()
+	err := l.open()
+	l.mu.Unlock()
 	if err != nil {
 		return err
 	}
-	l.file = file
 	log.Info("opened container logger", vlog.String("uuid", l.uuid.String()))
-	return nil
+	return l.cleanup()
 }
 
 func (l *ContainerLogger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
 	err := l.file.Close()
 	if err != nil {
 		return err
@@ -210,15 +325,114 @@ func (l *ContainerLogger) Close() error {
 	return nil
 }
 
+// Write appends a line to the log file, and rotates the file if it is too large.
+func (l *ContainerLogger) Write(line types.LogLine) error {
+	b, err := json.Marshal(logRecord{
+		Time:    line.Time,
+		Kind:    line.Kind,
+		Level:   line.Level,
+		Fields:  line.Fields,
+		Message: line.Message.String(),
+	})
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	if l.file == nil {
+		l.mu.Unlock()
+		return nil
+	}
+	n, err := l.file.Write(append(b, '\n'))
+	l.size += int64(n)
+	rotate := err == nil && l.maxSize > 0 && l.size >= l.maxSize
+	l.mu.Unlock()
+
+	if err != nil || !rotate {
+		return err
+	}
+	return l.Rotate()
+}
+
+// Rotate replaces the log file with a new one, compresses the previous file
+// and deletes the files older than the retention.
+func (l *ContainerLogger) Rotate() error {
+	l.mu.Lock()
+	if l.file == nil {
+		l.mu.Unlock()
+		return nil
+	}
+	err := l.file.Close()
+	if err == nil {
+		err = l.open()
+	}
+	l.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return l.cleanup()
+}
+
+func (l *ContainerLogger) open() error {
+	filename := logFilePrefix + time.Now().Format(logFileTimeFormat) + logFileExt
+	filepath := path.Join(l.dir, filename)
+
+	file, err := os.OpenFile(filepath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, os.ModePerm)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	l.file = file
+	l.size = info.Size()
+	return nil
+}
+
+func (l *ContainerLogger) cleanup() error {
+	l.cleanupMu.Lock()
+	defer l.cleanupMu.Unlock()
+
+	var current string
+	l.mu.Lock()
+	if l.file != nil {
+		current = l.file.Name()
+	}
+	l.mu.Unlock()
+
+	entries, err := os.ReadDir(l.dir)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		p := path.Join(l.dir, e.Name())
+		if e.IsDir() || p == current {
+			continue
+		}
+
+		info, err := e.Info()
+		if err != nil {
+			return err
+		}
+		if l.retention > 0 && time.Since(info.ModTime()) > l.retention {
+			err = os.Remove(p)
+		} else if strings.HasSuffix(p, logFileExt) {
+			err = compressLogFile(p)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (l *ContainerLogger) startCron() error {
 	l.scheduler = gocron.NewScheduler(time.Local)
 	_, err := l.scheduler.Every(1).Day().At("00:00").Do(func() {
-		err := l.Close()
-		if err != nil {
-			log.Error(err)
-			return
-		}
-		err = l.Open()
+		err := l.Rotate()
 		if err != nil {
 			log.Error(err)
 		}
@@ -235,3 +449,123 @@ func (l *ContainerLogger) stopCron() error {
 	l.scheduler.Stop()
 	return nil
 }
+
+// compressLogFile replaces a log file with its gzipped version.
+func compressLogFile(p string) error {
+	src, err := os.Open(p)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	tmp := p + ".gz.tmp"
+	dest, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, os.ModePerm)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp)
+	defer dest.Close()
+
+	w := gzip.NewWriter(dest)
+	_, err = io.Copy(w, src)
+	if err != nil {
+		return err
+	}
+	err = w.Close()
+	if err != nil {
+		return err
+	}
+	err = dest.Close()
+	if err != nil {
+		return err
+	}
+
+	// The rename is atomic, so readers never see a partial archive.
+	err = os.Rename(tmp, p+".gz")
+	if err != nil {
+		return err
+	}
+	return os.Remove(p)
+}
+
+type logFile struct {
+	path  string
+	start time.Time
+}
+
+// logFiles returns the log files of a directory, from the oldest. The files
+// written before the history was kept as JSON are ignored.
+func logFiles(dir string) ([]logFile, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	files := map[string]logFile{}
+	for _, e := range entries {
+		name := e.Name()
+		compressed := strings.HasSuffix(name, logFileExt+".gz")
+		if e.IsDir() || !strings.HasPrefix(name, logFilePrefix) || !(compressed || strings.HasSuffix(name, logFileExt)) {
+			continue
+		}
+
+		base := strings.TrimSuffix(strings.TrimSuffix(name, ".gz"), logFileExt)
+		start, err := time.ParseInLocation(logFileTimeFormat, strings.TrimPrefix(base, logFilePrefix), time.Local)
+		if err != nil {
+			continue
+		}
+
+		// While a file is being compressed, both versions exist.
+		if _, ok := files[base]; ok && !compressed {
+			continue
+		}
+		files[base] = logFile{
+			path:  path.Join(dir, name),
+			start: start,
+		}
+	}
+
+	sorted := make([]logFile, 0, len(files))
+	for _, f := range files {
+		sorted = append(sorted, f)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].start.Before(sorted[j].start)
+	})
+	return sorted, nil
+}
+
+// readLogFile calls fn for each line of a log file, compressed or not.
+func readLogFile(p string, fn func(r logRecord)) error {
+	file, err := os.Open(p)
+	if errors.Is(err, os.ErrNotExist) {
+		// The file was compressed since it was listed.
+		return nil
+	} else if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var r io.Reader = file
+	if strings.HasSuffix(p, ".gz") {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var record logRecord
+		if json.Unmarshal(scanner.Bytes(), &record) != nil {
+			continue
+		}
+		fn(record)
+	}
+	return scanner.Err()
+}
@@ -1,8 +1,14 @@
 package adapter
 
 import (
+	"bytes"
+	"fmt"
+	"io"
 	"os"
+	"path"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/vertex-center/uuid"
 	containerstypes "github.com/vertex-center/vertex/server/apps/containers/core/types"
@@ -204,3 +210,119 @@ func (suite *ContainerLogsFSAdapterTestSuite) TestPop() {
 	suite.Require().NoError(err)
 	suite.Empty(l.buffer)
 }
+
+func (suite *ContainerLogsFSAdapterTestSuite) TestSearch() {
+	instID := uuid.New()
+
+	err := suite.adapter.Register(instID)
+	suite.Require().NoError(err)
+
+	start := time.Now()
+	for i, kind := range []string{containerstypes.LogKindOut, containerstypes.LogKindErr, containerstypes.LogKindOut} {
+		suite.adapter.Push(instID, containerstypes.LogLine{
+			Time:    start.Add(time.Duration(i) * time.Second),
+			Kind:    kind,
+			Message: containerstypes.NewLogLineMessageString(fmt.Sprintf("Line %d", i)),
+		})
+	}
+
+	// Stopped containers can still be searched.
+	err = suite.adapter.Unregister(instID)
+	suite.Require().NoError(err)
+
+	lines, err := suite.adapter.Search(instID, containerstypes.LogFilters{Kinds: []string{containerstypes.LogKindOut}})
+	suite.Require().NoError(err)
+	suite.Require().Len(lines, 2)
+	suite.Equal("Line 0", lines[0].Message.String())
+	suite.Equal("Line 2", lines[1].Message.String())
+
+	lines, err = suite.adapter.Search(instID, containerstypes.LogFilters{Query: "line 1"})
+	suite.Require().NoError(err)
+	suite.Require().Len(lines, 1)
+	suite.Equal(containerstypes.LogKindErr, lines[0].Kind)
+
+	// The most recent lines are kept, and the previous page ends before the oldest line.
+	lines, err = suite.adapter.Search(instID, containerstypes.LogFilters{Limit: 2})
+	suite.Require().NoError(err)
+	suite.Require().Len(lines, 2)
+	suite.Equal("Line 1", lines[0].Message.String())
+
+	lines, err = suite.adapter.Search(instID, containerstypes.LogFilters{Limit: 2, Until: lines[0].Time})
+	suite.Require().NoError(err)
+	suite.Require().Len(lines, 1)
+	suite.Equal("Line 0", lines[0].Message.String())
+
+	lines, err = suite.adapter.Search(uuid.New(), containerstypes.LogFilters{Limit: 2})
+	suite.Require().NoError(err)
+	suite.Empty(lines)
+}
+
+func (suite *ContainerLogsFSAdapterTestSuite) TestRotate() {
+	suite.adapter.maxFileSize = 1
+	instID := uuid.New()
+
+	err := suite.adapter.Register(instID)
+	suite.Require().NoError(err)
+	defer func() {
+		err := suite.adapter.Unregister(instID)
+		suite.Require().NoError(err)
+	}()
+
+	for i := 0; i < 3; i++ {
+		suite.adapter.Push(instID, containerstypes.LogLine{
+			Kind:    containerstypes.LogKindOut,
+			Message: containerstypes.NewLogLineMessageString(fmt.Sprintf("Line %d", i)),
+		})
+	}
+
+	// Each line is in its own compressed file, and a new file is open.
+	compressed, err := filepath.Glob(path.Join(suite.adapter.dir(instID), "*.jsonl.gz"))
+	suite.Require().NoError(err)
+	suite.Len(compressed, 3)
+
+	lines, err := suite.adapter.Search(instID, containerstypes.LogFilters{Query: "line"})
+	suite.Require().NoError(err)
+	suite.Require().Len(lines, 3)
+	suite.Equal("Line 2", lines[2].Message.String())
+}
+
+func (suite *ContainerLogsFSAdapterTestSuite) TestRetention() {
+	instID := uuid.New()
+	dir := suite.adapter.dir(instID)
+	err := os.MkdirAll(dir, os.ModePerm)
+	suite.Require().NoError(err)
+
+	old := path.Join(dir, "logs_2020-01-01.txt")
+	err = os.WriteFile(old, []byte("old\n"), os.ModePerm)
+	suite.Require().NoError(err)
+	err = os.Chtimes(old, time.Now(), time.Now().Add(-2*defaultLogsRetention))
+	suite.Require().NoError(err)
+
+	err = suite.adapter.Register(instID)
+	suite.Require().NoError(err)
+	defer func() {
+		err := suite.adapter.Unregister(instID)
+		suite.Require().NoError(err)
+	}()
+
+	suite.NoFileExists(old)
+}
+
+func (suite *ContainerLogsFSAdapterTestSuite) TestArchive() {
+	instID := uuid.New()
+
+	err := suite.adapter.Archive(instID, io.Discard)
+	suite.ErrorIs(err, ErrLogsNotFound)
+
+	err = suite.adapter.Register(instID)
+	suite.Require().NoError(err)
+	defer func() {
+		err := suite.adapter.Unregister(instID)
+		suite.Require().NoError(err)
+	}()
+
+	var buf bytes.Buffer
+	err = suite.adapter.Archive(instID, &buf)
+	suite.Require().NoError(err)
+	suite.NotZero(buf.Len())
+}
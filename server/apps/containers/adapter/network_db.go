@@ -0,0 +1,190 @@
+package adapter
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/juju/errors"
+	"github.com/vertex-center/uuid"
+	"github.com/vertex-center/vertex/server/apps/containers/core/port"
+	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+	"github.com/vertex-center/vertex/server/common/storage"
+)
+
+type networkDBAdapter struct {
+	db storage.DB
+}
+
+func NewNetworkDBAdapter(db storage.DB) port.NetworkAdapter {
+	return &networkDBAdapter{db}
+}
+
+type containerNetworkAlias struct {
+	ContainerID uuid.UUID `db:"container_id"`
+	NetworkID   uuid.UUID `db:"network_id"`
+	Alias       string    `db:"alias"`
+}
+
+func (a *networkDBAdapter) GetNetworks(ctx context.Context) (types.Networks, error) {
+	networks := types.Networks{}
+	err := a.db.Select(&networks, `
+		SELECT * FROM networks
+		ORDER BY name
+	`)
+	return networks, err
+}
+
+func (a *networkDBAdapter) GetNetwork(ctx context.Context, id uuid.UUID) (*types.Network, error) {
+	var n types.Network
+	err := a.db.Get(&n, `
+		SELECT * FROM networks
+		WHERE id = $1
+	`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, types.ErrNetworkNotFound
+	}
+	return &n, err
+}
+
+func (a *networkDBAdapter) GetNetworkByName(ctx context.Context, name string) (*types.Network, error) {
+	var n types.Network
+	err := a.db.Get(&n, `
+		SELECT * FROM networks
+		WHERE name = $1
+	`, name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, types.ErrNetworkNotFound
+	}
+	return &n, err
+}
+
+func (a *networkDBAdapter) CreateNetwork(ctx context.Context, n types.Network) error {
+	_, err := a.db.NamedExec(`
+		INSERT INTO networks (id, name, created_at)
+		VALUES (:id, :name, :created_at)
+	`, n)
+	return err
+}
+
+func (a *networkDBAdapter) DeleteNetwork(ctx context.Context, id uuid.UUID) error {
+	_, err := a.db.Exec(`
+		DELETE FROM networks
+		WHERE id = $1
+	`, id)
+	return err
+}
+
+func (a *networkDBAdapter) GetContainerNetworks(ctx context.Context, containerID uuid.UUID) (types.ContainerNetworks, error) {
+	return a.getContainerNetworks("container_id", containerID)
+}
+
+func (a *networkDBAdapter) GetNetworkContainers(ctx context.Context, networkID uuid.UUID) (types.ContainerNetworks, error) {
+	return a.getContainerNetworks("network_id", networkID)
+}
+
+// getContainerNetworks returns the attachments where the given column
+// matches the id, with their aliases.
+func (a *networkDBAdapter) getContainerNetworks(column string, id uuid.UUID) (types.ContainerNetworks, error) {
+	networks := types.ContainerNetworks{}
+	err := a.db.Select(&networks, `
+		SELECT * FROM container_networks
+		WHERE `+column+` = $1
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var aliases []containerNetworkAlias
+	err = a.db.Select(&aliases, `
+		SELECT * FROM container_network_aliases
+		WHERE `+column+` = $1
+		ORDER BY alias
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, alias := range aliases {
+		for i := range networks {
+			if networks[i].ContainerID == alias.ContainerID && networks[i].NetworkID == alias.NetworkID {
+				networks[i].Aliases = append(networks[i].Aliases, alias.Alias)
+			}
+		}
+	}
+	return networks, nil
+}
+
+func (a *networkDBAdapter) SetContainerNetwork(ctx context.Context, n types.ContainerNetwork) error {
+	tx, err := a.db.Beginx()
+	if err != nil {
+		return err
+	}
+
+	for _, table := range []string{"container_network_aliases", "container_networks"} {
+		_, err = tx.Exec(`DELETE FROM `+table+` WHERE container_id = $1 AND network_id = $2`, n.ContainerID, n.NetworkID)
+		if err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+
+	_, err = tx.NamedExec(`
+		INSERT INTO container_networks (container_id, network_id)
+		VALUES (:container_id, :network_id)
+	`, n)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	for _, alias := range n.Aliases {
+		_, err = tx.NamedExec(`
+			INSERT INTO container_network_aliases (container_id, network_id, alias)
+			VALUES (:container_id, :network_id, :alias)
+		`, containerNetworkAlias{
+			ContainerID: n.ContainerID,
+			NetworkID:   n.NetworkID,
+			Alias:       alias,
+		})
+		if err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (a *networkDBAdapter) DeleteContainerNetwork(ctx context.Context, containerID uuid.UUID, networkID uuid.UUID) error {
+	tx, err := a.db.Beginx()
+	if err != nil {
+		return err
+	}
+
+	for _, table := range []string{"container_network_aliases", "container_networks"} {
+		_, err = tx.Exec(`DELETE FROM `+table+` WHERE container_id = $1 AND network_id = $2`, containerID, networkID)
+		if err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (a *networkDBAdapter) DeleteContainerNetworks(ctx context.Context, containerID uuid.UUID) error {
+	tx, err := a.db.Beginx()
+	if err != nil {
+		return err
+	}
+
+	for _, table := range []string{"container_network_aliases", "container_networks"} {
+		_, err = tx.Exec(`DELETE FROM `+table+` WHERE container_id = $1`, containerID)
+		if err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
@@ -36,8 +36,8 @@ func (a *portDBAdapter) GetPorts(ctx context.Context, filters types.PortFilters)
 
 func (a *portDBAdapter) CreatePort(ctx context.Context, port types.Port) error {
 	_, err := a.db.NamedExec(`
-		INSERT INTO ports (id, container_id, internal_port, external_port)
-		VALUES (:id, :container_id, :internal_port, :external_port)
+		INSERT INTO ports (id, container_id, internal_port, external_port, protocol, template_out)
+		VALUES (:id, :container_id, :internal_port, :external_port, :protocol, :template_out)
 	`, port)
 	return err
 }
@@ -61,7 +61,7 @@ func (a *portDBAdapter) DeletePorts(ctx context.Context, id uuid.UUID) error {
 func (a *portDBAdapter) UpdatePortByID(ctx context.Context, port types.Port) error {
 	_, err := a.db.NamedExec(`
         UPDATE ports
-        SET internal_port = :internal_port, external_port = :external_port
+        SET internal_port = :internal_port, external_port = :external_port, protocol = :protocol
         WHERE id = :id
     `, port)
 	return err
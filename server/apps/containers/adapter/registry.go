@@ -0,0 +1,143 @@
+package adapter
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/juju/errors"
+	"github.com/vertex-center/vertex/server/apps/containers/core/port"
+	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+)
+
+const (
+	// registryCacheTTL is how long the digest of an image tag is kept.
+	registryCacheTTL = 10 * time.Minute
+
+	// registryInterval is the minimum time between two requests to the same registry.
+	registryInterval = 100 * time.Millisecond
+)
+
+type (
+	// registryClient reads the manifest digests of images from their
+	// registry, without downloading any layer. Digests are cached, and the
+	// requests are rate limited per registry.
+	registryClient struct {
+		ttl      time.Duration
+		interval time.Duration
+		digest   func(ref string) (string, error)
+		now      func() time.Time
+		sleep    func(d time.Duration)
+
+		mu         sync.Mutex
+		cache      map[string]cachedDigest
+		registries map[string]*registryLimiter
+	}
+
+	cachedDigest struct {
+		digest  string
+		expires time.Time
+	}
+
+	registryLimiter struct {
+		mu   sync.Mutex
+		last time.Time
+	}
+
+	// registryKeychain picks the stored credential of a registry by its host.
+	// Registries without credential are accessed anonymously.
+	registryKeychain struct {
+		credentials port.RegistryCredentialAdapter
+	}
+)
+
+func newRegistryClient(keychain authn.Keychain) *registryClient {
+	return &registryClient{
+		ttl:      registryCacheTTL,
+		interval: registryInterval,
+		digest: func(ref string) (string, error) {
+			return crane.Digest(ref, crane.WithAuthFromKeychain(keychain))
+		},
+		now:        time.Now,
+		sleep:      time.Sleep,
+		cache:      map[string]cachedDigest{},
+		registries: map[string]*registryLimiter{},
+	}
+}
+
+// Digest returns the digest of the manifest an image reference points to.
+func (r *registryClient) Digest(ref string) (string, error) {
+	parsed, err := name.ParseReference(ref)
+	if err != nil {
+		return "", err
+	}
+	key := parsed.Name()
+
+	r.mu.Lock()
+	if c, ok := r.cache[key]; ok && r.now().Before(c.expires) {
+		r.mu.Unlock()
+		return c.digest, nil
+	}
+	limiter, ok := r.registries[parsed.Context().RegistryStr()]
+	if !ok {
+		limiter = &registryLimiter{}
+		r.registries[parsed.Context().RegistryStr()] = limiter
+	}
+	r.mu.Unlock()
+
+	limiter.wait(r)
+
+	digest, err := r.digest(key)
+	if err != nil {
+		return "", err
+	}
+
+	r.mu.Lock()
+	r.cache[key] = cachedDigest{
+		digest:  digest,
+		expires: r.now().Add(r.ttl),
+	}
+	r.mu.Unlock()
+	return digest, nil
+}
+
+// wait blocks until the registry can receive a new request from the client.
+func (l *registryLimiter) wait(r *registryClient) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if d := l.last.Add(r.interval).Sub(r.now()); d > 0 {
+		r.sleep(d)
+	}
+	l.last = r.now()
+}
+
+func (k registryKeychain) Resolve(res authn.Resource) (authn.Authenticator, error) {
+	auth, err := k.auth(res.RegistryStr())
+	if err != nil || auth == nil {
+		return authn.Anonymous, err
+	}
+	return &authn.Basic{
+		Username: auth.Username,
+		Password: auth.Password,
+	}, nil
+}
+
+// auth returns the credential of a registry, or nil if there is none.
+func (k registryKeychain) auth(registry string) (*types.RegistryAuth, error) {
+	if k.credentials == nil {
+		return nil, nil
+	}
+	cred, err := k.credentials.GetRegistryCredentialByRegistry(context.Background(), registry)
+	if errors.Is(err, types.ErrRegistryCredentialNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return &types.RegistryAuth{
+		Username: cred.Username,
+		Password: cred.Password,
+	}, nil
+}
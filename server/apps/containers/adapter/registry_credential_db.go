@@ -0,0 +1,155 @@
+package adapter
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/juju/errors"
+	"github.com/vertex-center/uuid"
+	"github.com/vertex-center/vertex/server/apps/containers/core/port"
+	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+	"github.com/vertex-center/vertex/server/common/storage"
+	"github.com/vertex-center/vertex/server/pkg/vcrypto"
+)
+
+// registryCredentialDBAdapter stores the registry credentials with their
+// password encrypted by the keyring. Passwords stored before the keyring are
+// still decrypted with legacyKey.
+type registryCredentialDBAdapter struct {
+	db        storage.DB
+	keyring   *vcrypto.Keyring
+	legacyKey []byte
+}
+
+func NewRegistryCredentialDBAdapter(db storage.DB, keyring *vcrypto.Keyring, legacyKey []byte) port.RegistryCredentialAdapter {
+	return &registryCredentialDBAdapter{db, keyring, legacyKey}
+}
+
+func (a *registryCredentialDBAdapter) GetRegistryCredentials(ctx context.Context) (types.RegistryCredentials, error) {
+	var creds types.RegistryCredentials
+	err := a.db.Select(&creds, `
+		SELECT * FROM registry_credentials
+		ORDER BY registry
+	`)
+	if err != nil {
+		return nil, err
+	}
+	for i := range creds {
+		err = a.decrypt(&creds[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return creds, nil
+}
+
+func (a *registryCredentialDBAdapter) GetRegistryCredential(ctx context.Context, id uuid.UUID) (*types.RegistryCredential, error) {
+	var cred types.RegistryCredential
+	err := a.db.Get(&cred, `
+		SELECT * FROM registry_credentials
+		WHERE id = $1
+	`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, types.ErrRegistryCredentialNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return &cred, a.decrypt(&cred)
+}
+
+func (a *registryCredentialDBAdapter) GetRegistryCredentialByRegistry(ctx context.Context, registry string) (*types.RegistryCredential, error) {
+	var cred types.RegistryCredential
+	err := a.db.Get(&cred, `
+		SELECT * FROM registry_credentials
+		WHERE registry = $1
+	`, registry)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, types.ErrRegistryCredentialNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return &cred, a.decrypt(&cred)
+}
+
+func (a *registryCredentialDBAdapter) CreateRegistryCredential(ctx context.Context, cred types.RegistryCredential) error {
+	err := a.encrypt(&cred)
+	if err != nil {
+		return err
+	}
+	_, err = a.db.NamedExec(`
+		INSERT INTO registry_credentials (id, registry, username, password)
+		VALUES (:id, :registry, :username, :password)
+	`, cred)
+	return err
+}
+
+func (a *registryCredentialDBAdapter) UpdateRegistryCredential(ctx context.Context, cred types.RegistryCredential) error {
+	err := a.encrypt(&cred)
+	if err != nil {
+		return err
+	}
+	_, err = a.db.NamedExec(`
+		UPDATE registry_credentials
+		SET registry = :registry, username = :username, password = :password
+		WHERE id = :id
+	`, cred)
+	return err
+}
+
+func (a *registryCredentialDBAdapter) DeleteRegistryCredential(ctx context.Context, id uuid.UUID) error {
+	_, err := a.db.Exec(`
+		DELETE FROM registry_credentials
+		WHERE id = $1
+	`, id)
+	return err
+}
+
+// EncryptRegistryCredentials encrypts the passwords that are not encrypted
+// with the current key of the keyring.
+func (a *registryCredentialDBAdapter) EncryptRegistryCredentials(ctx context.Context) error {
+	var creds types.RegistryCredentials
+	err := a.db.Select(&creds, `SELECT * FROM registry_credentials`)
+	if err != nil {
+		return err
+	}
+	for _, cred := range creds {
+		if a.keyring.IsCurrent(cred.Password) {
+			continue
+		}
+		err = a.decrypt(&cred)
+		if err != nil {
+			return err
+		}
+		err = a.UpdateRegistryCredential(ctx, cred)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *registryCredentialDBAdapter) encrypt(cred *types.RegistryCredential) error {
+	password, err := a.keyring.Encrypt(cred.Password)
+	if err != nil {
+		return err
+	}
+	cred.Password = password
+	return nil
+}
+
+func (a *registryCredentialDBAdapter) decrypt(cred *types.RegistryCredential) error {
+	var (
+		password string
+		err      error
+	)
+	if vcrypto.IsEncrypted(cred.Password) {
+		password, err = a.keyring.Decrypt(cred.Password)
+	} else {
+		password, err = vcrypto.Decrypt(a.legacyKey, cred.Password)
+	}
+	if err != nil {
+		return errors.Annotatef(err, "decrypt credential of %s", cred.Registry)
+	}
+	cred.Password = password
+	return nil
+}
@@ -0,0 +1,95 @@
+package adapter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/suite"
+	"github.com/vertex-center/uuid"
+	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+	"github.com/vertex-center/vertex/server/apps/containers/database"
+	"github.com/vertex-center/vertex/server/common/storage"
+	"github.com/vertex-center/vertex/server/pkg/vcrypto"
+	"github.com/vertex-center/vertex/server/pkg/vsql"
+)
+
+type RegistryCredentialDBAdapterTestSuite struct {
+	suite.Suite
+
+	adapter   *registryCredentialDBAdapter
+	db        storage.DB
+	keyring   *vcrypto.Keyring
+	legacyKey []byte
+}
+
+func TestRegistryCredentialDBAdapterTestSuite(t *testing.T) {
+	suite.Run(t, new(RegistryCredentialDBAdapterTestSuite))
+}
+
+func (suite *RegistryCredentialDBAdapterTestSuite) SetupTest() {
+	conn, err := sqlx.Connect("sqlite", ":memory:")
+	suite.Require().NoError(err)
+	// Each connection opens its own in-memory database.
+	conn.SetMaxOpenConns(1)
+	_, err = conn.Exec(database.GetSchema(vsql.DriverFromName("sqlite")))
+	suite.Require().NoError(err)
+	suite.db = storage.DB{DB: conn}
+
+	suite.keyring, err = vcrypto.LoadOrCreateKeyring(suite.T().TempDir())
+	suite.Require().NoError(err)
+	suite.legacyKey, err = vcrypto.NewKey()
+	suite.Require().NoError(err)
+	suite.adapter = NewRegistryCredentialDBAdapter(suite.db, suite.keyring, suite.legacyKey).(*registryCredentialDBAdapter)
+}
+
+func (suite *RegistryCredentialDBAdapterTestSuite) TearDownTest() {
+	suite.db.Close()
+}
+
+// storedPassword returns the password as stored in the database.
+func (suite *RegistryCredentialDBAdapterTestSuite) storedPassword(id uuid.UUID) string {
+	var password string
+	err := suite.db.Get(&password, `SELECT password FROM registry_credentials WHERE id = $1`, id)
+	suite.Require().NoError(err)
+	return password
+}
+
+func (suite *RegistryCredentialDBAdapterTestSuite) TestCreateRegistryCredential() {
+	cred := types.RegistryCredential{ID: uuid.New(), Registry: "ghcr.io", Username: "user", Password: "hunter2"}
+	err := suite.adapter.CreateRegistryCredential(context.Background(), cred)
+	suite.Require().NoError(err)
+	suite.True(suite.keyring.IsCurrent(suite.storedPassword(cred.ID)))
+
+	stored, err := suite.adapter.GetRegistryCredentialByRegistry(context.Background(), "ghcr.io")
+	suite.Require().NoError(err)
+	suite.Equal("hunter2", stored.Password)
+}
+
+func (suite *RegistryCredentialDBAdapterTestSuite) TestEncryptRegistryCredentials() {
+	legacy, err := vcrypto.Encrypt(suite.legacyKey, "legacy")
+	suite.Require().NoError(err)
+	legacyID := uuid.New()
+	_, err = suite.db.Exec(`
+		INSERT INTO registry_credentials (id, registry, username, password)
+		VALUES ($1, 'docker.io', 'user', $2)
+	`, legacyID, legacy)
+	suite.Require().NoError(err)
+
+	cred := types.RegistryCredential{ID: uuid.New(), Registry: "ghcr.io", Username: "user", Password: "hunter2"}
+	err = suite.adapter.CreateRegistryCredential(context.Background(), cred)
+	suite.Require().NoError(err)
+	suite.Require().NoError(suite.keyring.Rotate())
+
+	err = suite.adapter.EncryptRegistryCredentials(context.Background())
+	suite.Require().NoError(err)
+	suite.Require().NoError(suite.keyring.Prune())
+
+	suite.True(suite.keyring.IsCurrent(suite.storedPassword(legacyID)))
+	suite.True(suite.keyring.IsCurrent(suite.storedPassword(cred.ID)))
+	creds, err := suite.adapter.GetRegistryCredentials(context.Background())
+	suite.Require().NoError(err)
+	suite.Require().Len(creds, 2)
+	suite.Equal("legacy", creds[0].Password)
+	suite.Equal("hunter2", creds[1].Password)
+}
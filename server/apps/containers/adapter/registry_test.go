@@ -0,0 +1,82 @@
+package adapter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/stretchr/testify/suite"
+)
+
+type RegistryTestSuite struct {
+	suite.Suite
+
+	client   *registryClient
+	requests []string
+	now      time.Time
+	sleeps   []time.Duration
+}
+
+func TestRegistryTestSuite(t *testing.T) {
+	suite.Run(t, new(RegistryTestSuite))
+}
+
+func (suite *RegistryTestSuite) SetupTest() {
+	suite.requests = nil
+	suite.now = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	suite.sleeps = nil
+	suite.client = newRegistryClient(authn.NewMultiKeychain())
+	suite.client.interval = 20 * time.Millisecond
+	suite.client.now = func() time.Time { return suite.now }
+	suite.client.sleep = func(d time.Duration) {
+		suite.sleeps = append(suite.sleeps, d)
+		suite.now = suite.now.Add(d)
+	}
+	suite.client.digest = func(ref string) (string, error) {
+		suite.requests = append(suite.requests, ref)
+		return "sha256:" + ref, nil
+	}
+}
+
+func (suite *RegistryTestSuite) TestDigestCached() {
+	digest, err := suite.client.Digest("postgres:16")
+	suite.Require().NoError(err)
+	suite.Equal("sha256:index.docker.io/library/postgres:16", digest)
+
+	_, err = suite.client.Digest("docker.io/library/postgres:16")
+	suite.Require().NoError(err)
+	suite.Len(suite.requests, 1)
+
+	suite.client.ttl = 0
+	suite.client.cache = map[string]cachedDigest{}
+	_, err = suite.client.Digest("postgres:16")
+	suite.Require().NoError(err)
+	_, err = suite.client.Digest("postgres:16")
+	suite.Require().NoError(err)
+	suite.Len(suite.requests, 3)
+}
+
+func (suite *RegistryTestSuite) TestDigestRateLimited() {
+	for _, ref := range []string{"redis:7", "redis:6", "redis:5"} {
+		_, err := suite.client.Digest(ref)
+		suite.Require().NoError(err)
+	}
+	suite.Equal([]time.Duration{20 * time.Millisecond, 20 * time.Millisecond}, suite.sleeps)
+
+	// Another registry is not slowed down by the first one.
+	_, err := suite.client.Digest("ghcr.io/vertex-center/vertex:latest")
+	suite.Require().NoError(err)
+	suite.Len(suite.sleeps, 2)
+
+	// Only the rest of the interval is waited.
+	suite.now = suite.now.Add(5 * time.Millisecond)
+	_, err = suite.client.Digest("redis:4")
+	suite.Require().NoError(err)
+	suite.Equal([]time.Duration{20 * time.Millisecond, 20 * time.Millisecond, 15 * time.Millisecond}, suite.sleeps)
+}
+
+func (suite *RegistryTestSuite) TestDigestInvalidReference() {
+	_, err := suite.client.Digest("INVALID::ref")
+	suite.Error(err)
+	suite.Empty(suite.requests)
+}
@@ -0,0 +1,94 @@
+package adapter
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/juju/errors"
+	"github.com/vertex-center/uuid"
+	"github.com/vertex-center/vertex/server/apps/containers/core/port"
+	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+	"github.com/vertex-center/vertex/server/common/storage"
+)
+
+type resourceLimitsDBAdapter struct {
+	db storage.DB
+}
+
+func NewResourceLimitsDBAdapter(db storage.DB) port.ResourceLimitsAdapter {
+	return &resourceLimitsDBAdapter{db}
+}
+
+func (a *resourceLimitsDBAdapter) GetContainerResourceLimits(ctx context.Context, id uuid.UUID) (*types.ResourceLimits, error) {
+	var limits types.ResourceLimits
+	err := a.db.Get(&limits, `
+		SELECT * FROM resource_limits
+		WHERE container_id = $1
+	`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, types.ErrResourceLimitsNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	err = a.db.Select(&limits.Ulimits, `
+		SELECT * FROM ulimits
+		WHERE container_id = $1
+		ORDER BY name
+	`, id)
+	return &limits, err
+}
+
+func (a *resourceLimitsDBAdapter) SetContainerResourceLimits(ctx context.Context, limits types.ResourceLimits) error {
+	tx, err := a.db.Beginx()
+	if err != nil {
+		return err
+	}
+
+	for _, table := range []string{"resource_limits", "ulimits"} {
+		_, err = tx.Exec(`DELETE FROM `+table+` WHERE container_id = $1`, limits.ContainerID)
+		if err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+
+	_, err = tx.NamedExec(`
+		INSERT INTO resource_limits (id, container_id, cpu_shares, cpu_period, cpu_quota, memory, memory_swap, pids_limit)
+		VALUES (:id, :container_id, :cpu_shares, :cpu_period, :cpu_quota, :memory, :memory_swap, :pids_limit)
+	`, limits)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	for _, u := range limits.Ulimits {
+		_, err = tx.NamedExec(`
+			INSERT INTO ulimits (id, container_id, name, soft, hard)
+			VALUES (:id, :container_id, :name, :soft, :hard)
+		`, u)
+		if err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (a *resourceLimitsDBAdapter) DeleteContainerResourceLimits(ctx context.Context, id uuid.UUID) error {
+	tx, err := a.db.Beginx()
+	if err != nil {
+		return err
+	}
+
+	for _, table := range []string{"ulimits", "resource_limits"} {
+		_, err = tx.Exec(`DELETE FROM `+table+` WHERE container_id = $1`, id)
+		if err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
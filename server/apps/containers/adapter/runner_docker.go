@@ -7,7 +7,10 @@ import (
 	goerrors "errors"
 	"fmt"
 	"io"
+	"os"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/pkg/jsonmessage"
@@ -18,13 +21,21 @@ import (
 	"github.com/vertex-center/vertex/server/apps/containers/core/types"
 	"github.com/vertex-center/vertex/server/apps/containers/core/types/builder"
 	"github.com/vertex-center/vertex/server/common/log"
+	vstorage "github.com/vertex-center/vertex/server/pkg/storage"
 	"github.com/vertex-center/vlog"
 )
 
-type runnerDockerAdapter struct{}
+type runnerDockerAdapter struct {
+	keychain registryKeychain
+	registry *registryClient
+}
 
-func NewRunnerDockerAdapter() port.RunnerAdapter {
-	return runnerDockerAdapter{}
+func NewRunnerDockerAdapter(credentials port.RegistryCredentialAdapter) port.RunnerAdapter {
+	keychain := registryKeychain{credentials}
+	return runnerDockerAdapter{
+		keychain: keychain,
+		registry: newRegistryClient(keychain),
+	}
 }
 
 func (a runnerDockerAdapter) GetDockerContainers(ctx context.Context) ([]types.DockerContainer, error) {
@@ -32,6 +43,18 @@ func (a runnerDockerAdapter) GetDockerContainers(ctx context.Context) ([]types.D
 	return cli.GetContainers(context.Background())
 }
 
+func (a runnerDockerAdapter) InspectDockerContainer(ctx context.Context, id string) (types.InspectContainerResponse, error) {
+	cli := containersapi.NewContainersKernelClient(ctx)
+	return cli.InspectContainer(context.Background(), id)
+}
+
+// AdoptDockerContainer renames a Docker container with the name Vertex
+// gives to the container c, so that it is managed by Vertex.
+func (a runnerDockerAdapter) AdoptDockerContainer(ctx context.Context, id string, c types.Container) error {
+	cli := containersapi.NewContainersKernelClient(ctx)
+	return cli.RenameContainer(context.Background(), id, c.DockerContainerName())
+}
+
 func (a runnerDockerAdapter) DeleteContainer(ctx context.Context, c *types.Container, volumes []string) error {
 	id, err := a.getContainerID(ctx, *c)
 	if err != nil {
@@ -72,6 +95,10 @@ func (a runnerDockerAdapter) Start(
 	env []types.EnvVariable,
 	caps types.Capabilities,
 	sysctls types.Sysctls,
+	healthCheck *types.HealthCheck,
+	limits *types.ResourceLimits,
+	build *types.ContainerBuild,
+	networks []types.NetworkAttachment,
 	setStatus func(status string),
 ) (io.ReadCloser, io.ReadCloser, error) {
 	rErr, wErr := io.Pipe()
@@ -84,8 +111,20 @@ func (a runnerDockerAdapter) Start(
 
 		log.Debug("building image", vlog.String("image", imageName))
 
-		// Build
-		stdout, err := a.buildImageFromName(ctx, c.GetImageNameWithTag())
+		// Build. A pinned image is already present locally.
+		var (
+			stdout   io.ReadCloser
+			err      error
+			buildErr error
+		)
+		switch {
+		case c.PinnedImage != nil:
+			stdout = io.NopCloser(strings.NewReader(""))
+		case build != nil:
+			stdout, err = a.buildImageFromRepository(ctx, c, *build)
+		default:
+			stdout, err = a.buildImageFromName(ctx, c.GetImageNameWithTag())
+		}
 		if err != nil {
 			log.Error(err, vlog.String("step", "build"))
 			setStatus(types.ContainerStatusError)
@@ -113,6 +152,32 @@ func (a runnerDockerAdapter) Start(
 					continue
 				}
 
+				if msg.Error != nil {
+					buildErr = msg.Error
+					log.Error(buildErr,
+						vlog.String("step", "build"),
+						vlog.String("id", c.ID.String()))
+					setStatus(types.ContainerStatusError)
+					_, _ = wErr.Write([]byte(buildErr.Error() + "\n"))
+					return
+				}
+
+				// Lines printed by the steps of a Dockerfile.
+				if msg.Stream != "" {
+					for _, line := range strings.Split(strings.TrimRight(msg.Stream, "\n"), "\n") {
+						_, err = fmt.Fprintln(wOut, line)
+						if err != nil {
+							log.Error(err,
+								vlog.String("step", "build"),
+								vlog.String("id", c.ID.String()))
+						}
+					}
+					continue
+				}
+				if msg.Status == "" {
+					continue
+				}
+
 				progress := types.DownloadProgress{
 					ID:     msg.ID,
 					Status: msg.Status,
@@ -169,6 +234,10 @@ func (a runnerDockerAdapter) Start(
 
 		wg.Wait()
 
+		if buildErr != nil {
+			return
+		}
+
 		log.Info("image built", vlog.String("uuid", c.ID.String()))
 
 		// Create
@@ -178,16 +247,42 @@ func (a runnerDockerAdapter) Start(
 
 			log.Info("container doesn't exists, create it.", vlog.String("name", containerName))
 
-			opts := builder.NewContainerOpts().
+			b := builder.NewContainerOpts().
 				WithName(containerName).
-				WithImage(c.GetImageNameWithTag()).
+				WithImage(c.ImageToRun()).
 				WithEnv(env).
 				WithCaps(caps).
 				WithSysctls(sysctls).
 				WithPorts(ports).
 				WithVolumes(volumes).
 				WithCommand(c.Command).
-				Build()
+				WithHealthCheck(healthCheck).
+				WithResourceLimits(limits)
+
+			// Containers of a stack share a network, where they are reachable by their member name.
+			if c.StackID != nil {
+				var aliases []string
+				if c.StackMember != nil {
+					aliases = append(aliases, *c.StackMember)
+				}
+				b.WithNetwork(types.StackNetworkName(*c.StackID), aliases...)
+			}
+			b.WithNetworks(networks)
+
+			opts := b.Build()
+
+			for _, network := range opts.Networks {
+				err = a.CreateNetwork(ctx, network.Name)
+				if err != nil {
+					break
+				}
+			}
+			if err != nil {
+				log.Error(err, vlog.String("step", "create network"))
+				setStatus(types.ContainerStatusError)
+				_, _ = wErr.Write([]byte(err.Error() + "\n"))
+				return
+			}
 
 			id, err = a.createContainer(ctx, opts)
 			if err != nil {
@@ -234,18 +329,26 @@ func (a runnerDockerAdapter) Start(
 			}
 		}()
 
+		// stderr has its own pipe, so that its lines are not mixed with
+		// stdout and keep their error kind.
 		go func() {
 			defer stderr.Close()
 			defer wErr.Close()
 
-			_, err := io.Copy(wOut, stdout)
+			_, err := io.Copy(wErr, stderr)
 			if err != nil {
 				log.Error(err)
 				return
 			}
 		}()
 
+		stopWatching := make(chan struct{})
+		if healthCheck != nil {
+			go a.watchHealth(ctx, id, healthCheck.PollInterval(), setStatus, stopWatching)
+		}
+
 		err = a.WaitCondition(ctx, c, types.WaitContainerCondition(container.WaitConditionNotRunning))
+		close(stopWatching)
 		if err != nil {
 			log.Error(err, vlog.String("step", "wait condition"))
 			setStatus(types.ContainerStatusError)
@@ -258,6 +361,34 @@ func (a runnerDockerAdapter) Start(
 	return rOut, rErr, nil
 }
 
+// watchHealth reads the Docker health status of the container every interval
+// and reports it as the container status, until stop is closed.
+func (a runnerDockerAdapter) watchHealth(ctx context.Context, id string, interval time.Duration, setStatus func(status string), stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	cli := containersapi.NewContainersKernelClient(ctx)
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			info, err := cli.GetContainerInfo(context.Background(), id)
+			if err != nil {
+				log.Error(err, vlog.String("step", "health"))
+				continue
+			}
+			select {
+			case <-stop:
+				// The container stopped while reading its health.
+				return
+			default:
+				setStatus(types.StatusFromDockerHealth(info.Health))
+			}
+		}
+	}
+}
+
 func (a runnerDockerAdapter) Stop(ctx context.Context, c *types.Container) error {
 	id, err := a.getContainerID(ctx, *c)
 	if err != nil {
@@ -291,29 +422,95 @@ func (a runnerDockerAdapter) Info(ctx context.Context, c types.Container) (map[s
 	}, nil
 }
 
-func (a runnerDockerAdapter) CheckForUpdates(ctx context.Context, c *types.Container) error {
-	imageName := c.GetImageNameWithTag()
+func (a runnerDockerAdapter) Stats(ctx context.Context, c types.Container) (types.ContainerStats, error) {
+	id, err := a.getContainerID(ctx, c)
+	if err != nil {
+		return types.ContainerStats{}, err
+	}
 
-	res, err := a.pullImage(ctx, imageName)
+	cli := containersapi.NewContainersKernelClient(ctx)
+	return cli.GetContainerStats(context.Background(), id)
+}
+
+func (a runnerDockerAdapter) ExitCode(ctx context.Context, c types.Container) (int, error) {
+	id, err := a.getContainerID(ctx, c)
 	if err != nil {
+		return 0, err
+	}
+
+	cli := containersapi.NewContainersKernelClient(ctx)
+	info, err := cli.GetContainerInfo(context.Background(), id)
+	if err != nil {
+		return 0, err
+	}
+	return info.ExitCode, nil
+}
+
+// CreateNetwork creates a bridge network. It does nothing if the network
+// already exists.
+func (a runnerDockerAdapter) CreateNetwork(ctx context.Context, name string) error {
+	cli := containersapi.NewContainersKernelClient(ctx)
+	return cli.CreateNetwork(context.Background(), name)
+}
+
+func (a runnerDockerAdapter) DeleteNetwork(ctx context.Context, name string) error {
+	cli := containersapi.NewContainersKernelClient(ctx)
+	return cli.DeleteNetwork(context.Background(), name)
+}
+
+// ConnectNetwork connects the container to a network. It does nothing if the
+// Docker container doesn't exist yet, as the networks are then connected when
+// it is created.
+func (a runnerDockerAdapter) ConnectNetwork(ctx context.Context, c types.Container, network types.NetworkAttachment) error {
+	id, err := a.getContainerID(ctx, c)
+	if errors.Is(err, errors.NotFound) {
+		return nil
+	} else if err != nil {
 		return err
 	}
-	defer res.Close()
 
-	client := containersapi.NewContainersKernelClient(ctx)
-	imageInfo, err := client.GetImageInfo(context.Background(), imageName)
+	cli := containersapi.NewContainersKernelClient(ctx)
+	err = cli.CreateNetwork(context.Background(), network.Name)
 	if err != nil {
 		return err
 	}
+	return cli.ConnectNetwork(context.Background(), types.ConnectNetworkOptions{
+		Network:   network.Name,
+		Container: id,
+		Aliases:   network.Aliases,
+	})
+}
+
+// DisconnectNetwork disconnects the container from a network. It does nothing
+// if the Docker container doesn't exist.
+func (a runnerDockerAdapter) DisconnectNetwork(ctx context.Context, c types.Container, name string) error {
+	id, err := a.getContainerID(ctx, c)
+	if errors.Is(err, errors.NotFound) {
+		return nil
+	} else if err != nil {
+		return err
+	}
 
-	latestImageID := imageInfo.ID
+	cli := containersapi.NewContainersKernelClient(ctx)
+	return cli.DisconnectNetwork(context.Background(), types.DisconnectNetworkOptions{
+		Network:   name,
+		Container: id,
+	})
+}
 
-	currentImageID, err := a.getImageID(ctx, *c)
+func (a runnerDockerAdapter) CheckForUpdates(ctx context.Context, c *types.Container) error {
+	// Images built from a repository are updated by rebuilding them.
+	if c.IsBuilt() {
+		c.Update = nil
+		return nil
+	}
+
+	current, latest, err := a.getDigests(ctx, *c)
 	if err != nil {
 		return err
 	}
 
-	if latestImageID == currentImageID {
+	if current == latest {
 		log.Info("already up-to-date",
 			vlog.String("uuid", c.ID.String()),
 		)
@@ -323,22 +520,72 @@ func (a runnerDockerAdapter) CheckForUpdates(ctx context.Context, c *types.Conta
 			vlog.String("uuid", c.ID.String()),
 		)
 		c.Update = &types.ContainerUpdate{
-			CurrentVersion: currentImageID,
-			LatestVersion:  latestImageID,
+			CurrentVersion: current,
+			LatestVersion:  latest,
 		}
 	}
 
 	return nil
 }
 
+// GetImageID returns the ID of the image the Docker container was created from.
+func (a runnerDockerAdapter) GetImageID(ctx context.Context, c types.Container) (string, error) {
+	return a.getImageID(ctx, c)
+}
+
 func (a runnerDockerAdapter) GetAllVersions(ctx context.Context, c types.Container) ([]string, error) {
 	log.Info("querying all versions of image", vlog.String("image", c.Image))
-	return crane.ListTags(c.Image)
+	return crane.ListTags(c.Image, crane.WithAuthFromKeychain(a.keychain))
 }
 
 func (a runnerDockerAdapter) HasUpdateAvailable(ctx context.Context, c types.Container) (bool, error) {
-	//TODO implement me
-	return false, nil
+	if c.IsBuilt() {
+		return false, nil
+	}
+	current, latest, err := a.getDigests(ctx, c)
+	if err != nil {
+		return false, err
+	}
+	return current != latest, nil
+}
+
+func (a runnerDockerAdapter) CreateVolume(ctx context.Context, name string) error {
+	cli := containersapi.NewContainersKernelClient(ctx)
+	_, err := cli.CreateVolume(context.Background(), name)
+	return err
+}
+
+func (a runnerDockerAdapter) ArchiveVolume(ctx context.Context, v types.Volume, dest string) (types.ArchiveVolumeResponse, error) {
+	cli := containersapi.NewContainersKernelClient(ctx)
+	return cli.ArchiveVolume(context.Background(), types.ArchiveVolumeOptions{
+		Type: v.Type,
+		Name: v.Out,
+		Dest: dest,
+	})
+}
+
+func (a runnerDockerAdapter) RestoreVolume(ctx context.Context, v types.BackupVolume) error {
+	cli := containersapi.NewContainersKernelClient(ctx)
+	return cli.RestoreVolume(context.Background(), types.RestoreVolumeOptions{
+		Type:     v.Type,
+		Name:     v.Name,
+		Src:      v.Archive,
+		Checksum: v.Checksum,
+	})
+}
+
+func (a runnerDockerAdapter) Exec(ctx context.Context, c types.Container, options types.ExecOptions) (port.ExecSession, error) {
+	id, err := a.getContainerID(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	cli := containersapi.NewContainersKernelClient(ctx)
+	conn, err := cli.ExecContainer(ctx, id, options)
+	if err != nil {
+		return nil, err
+	}
+	return newExecSession(conn), nil
 }
 
 func (a runnerDockerAdapter) WaitCondition(ctx context.Context, c *types.Container, cond types.WaitContainerCondition) error {
@@ -384,13 +631,95 @@ func (a runnerDockerAdapter) getImageID(ctx context.Context, c types.Container)
 	return dc.ImageID, nil
 }
 
+// getDigests returns the digest of the image the container runs, and the
+// digest its tag points to in the registry. Only the manifest is fetched.
+func (a runnerDockerAdapter) getDigests(ctx context.Context, c types.Container) (string, string, error) {
+	imageID, err := a.getImageID(ctx, c)
+	if err != nil {
+		return "", "", err
+	}
+
+	client := containersapi.NewContainersKernelClient(ctx)
+	info, err := client.GetImageInfo(context.Background(), imageID)
+	if err != nil {
+		return "", "", err
+	}
+
+	latest, err := a.registry.Digest(c.GetImageNameWithTag())
+	if err != nil {
+		return "", "", err
+	}
+
+	// Images built locally have no repo digest, so they are always
+	// considered outdated.
+	current := ""
+	for _, d := range info.Digests {
+		_, digest, ok := strings.Cut(d, "@")
+		if !ok {
+			continue
+		}
+		current = digest
+		if digest == latest {
+			break
+		}
+	}
+	return current, latest, nil
+}
+
 func (a runnerDockerAdapter) pullImage(ctx context.Context, imageName string) (io.ReadCloser, error) {
 	options := types.PullImageOptions{Image: imageName}
 
+	registry, err := types.ImageRegistry(imageName)
+	if err != nil {
+		return nil, err
+	}
+	options.Auth, err = a.keychain.auth(registry)
+	if err != nil {
+		return nil, err
+	}
+
 	cli := containersapi.NewContainersKernelClient(ctx)
 	return cli.PullImage(context.Background(), options)
 }
 
+// buildImageFromRepository builds the image of a container from its git
+// repository. The repository is cloned if needed, but never pulled. The
+// image is only rebuilt if the checked out commit changed.
+func (a runnerDockerAdapter) buildImageFromRepository(ctx context.Context, c *types.Container, build types.ContainerBuild) (io.ReadCloser, error) {
+	if _, err := os.Stat(build.Dir); os.IsNotExist(err) {
+		err = vstorage.CloneRepository(build.Repository, build.Dir)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	commit, err := vstorage.RepositoryHead(build.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	cli := containersapi.NewContainersKernelClient(ctx)
+	info, err := cli.GetImageInfo(context.Background(), c.DockerImageVertexName())
+	if err == nil && info.Labels[types.BuildCommitLabel] == commit {
+		log.Info("image already built", vlog.String("uuid", c.ID.String()), vlog.String("commit", commit))
+		return io.NopCloser(strings.NewReader("")), nil
+	}
+
+	log.Info("building image from repository",
+		vlog.String("uuid", c.ID.String()),
+		vlog.String("repository", build.Repository),
+		vlog.String("commit", commit),
+	)
+
+	return cli.BuildImage(context.Background(), types.BuildImageOptions{
+		Dir:        build.Dir,
+		Name:       c.DockerImageVertexName(),
+		Dockerfile: build.Dockerfile,
+		BuildArgs:  build.Args.DockerBuildArgs(),
+		Labels:     map[string]string{types.BuildCommitLabel: commit},
+	})
+}
+
 func (a runnerDockerAdapter) buildImageFromName(ctx context.Context, imageName string) (io.ReadCloser, error) {
 	res, err := a.pullImage(ctx, imageName)
 	if err != nil {
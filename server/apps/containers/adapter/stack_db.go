@@ -0,0 +1,57 @@
+package adapter
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/juju/errors"
+	"github.com/vertex-center/uuid"
+	"github.com/vertex-center/vertex/server/apps/containers/core/port"
+	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+	"github.com/vertex-center/vertex/server/common/storage"
+)
+
+type stackDBAdapter struct {
+	db storage.DB
+}
+
+func NewStackDBAdapter(db storage.DB) port.StackAdapter {
+	return &stackDBAdapter{db}
+}
+
+func (a *stackDBAdapter) GetStack(ctx context.Context, id uuid.UUID) (*types.Stack, error) {
+	var stack types.Stack
+	err := a.db.Get(&stack, `
+		SELECT * FROM stacks
+		WHERE id = $1
+	`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, types.ErrStackNotFound
+	}
+	return &stack, err
+}
+
+func (a *stackDBAdapter) GetStacks(ctx context.Context) (types.Stacks, error) {
+	var stacks types.Stacks
+	err := a.db.Select(&stacks, `
+		SELECT * FROM stacks
+		ORDER BY name
+	`)
+	return stacks, err
+}
+
+func (a *stackDBAdapter) CreateStack(ctx context.Context, stack types.Stack) error {
+	_, err := a.db.NamedExec(`
+		INSERT INTO stacks (id, template_id, user_id, name, description, color, icon)
+		VALUES (:id, :template_id, :user_id, :name, :description, :color, :icon)
+	`, stack)
+	return err
+}
+
+func (a *stackDBAdapter) DeleteStack(ctx context.Context, id uuid.UUID) error {
+	_, err := a.db.Exec(`
+		DELETE FROM stacks
+		WHERE id = $1
+	`, id)
+	return err
+}
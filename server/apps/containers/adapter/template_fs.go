@@ -1,24 +1,55 @@
 package adapter
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
 	"path"
+	"slices"
+	"sync"
+	"time"
 
+	"github.com/vertex-center/uuid"
 	"github.com/vertex-center/vertex/server/apps/containers/core/port"
 	"github.com/vertex-center/vertex/server/apps/containers/core/types"
 	"github.com/vertex-center/vertex/server/common/log"
 	"github.com/vertex-center/vertex/server/common/storage"
+	vstorage "github.com/vertex-center/vertex/server/pkg/storage"
+	"github.com/vertex-center/vlog"
 	"gopkg.in/yaml.v3"
 )
 
+// catalogSignatureFile is the signature of a catalog directory. Index
+// files are signed by the same URL, followed by .sig.
+const catalogSignatureFile = "catalog.sig"
+
+// templateFSAdapter reads the templates of all the template sources. The
+// built-in source is the services directory, kept up to date by the updater.
 type templateFSAdapter struct {
 	servicesPath string
-	templates    []types.Template
+	sourcesPath  string // Where the git sources are cloned.
+	client       *http.Client
+
+	// reloadMu serializes the reloads, as they share the checkouts of the
+	// git sources.
+	reloadMu sync.Mutex
+
+	mu        sync.RWMutex
+	sources   types.TemplateSources
+	templates []types.Template
+	raw       map[string][]byte
+	status    map[uuid.UUID]types.TemplateSourceStatus
+
+	// user are the templates created by users. They take precedence over
+	// the templates of the sources.
+	user []types.Template
 }
 
 type TemplateFSAdapterParams struct {
 	templatesPath string
+	sourcesPath   string
 }
 
 func NewTemplateFSAdapter(params *TemplateFSAdapterParams) port.TemplateAdapter {
@@ -28,10 +59,17 @@ func NewTemplateFSAdapter(params *TemplateFSAdapterParams) port.TemplateAdapter
 	if params.templatesPath == "" {
 		params.templatesPath = path.Join(storage.FSPath, "services")
 	}
+	if params.sourcesPath == "" {
+		params.sourcesPath = path.Join(storage.FSPath, "apps", "containers", "sources")
+	}
 
 	adapter := &templateFSAdapter{
 		servicesPath: params.templatesPath,
+		sourcesPath:  params.sourcesPath,
+		client:       &http.Client{Timeout: 30 * time.Second},
+		status:       map[uuid.UUID]types.TemplateSourceStatus{},
 	}
+	adapter.SetSources(nil)
 	err := adapter.Reload()
 	if err != nil {
 		log.Error(fmt.Errorf("failed to reload templates: %w", err))
@@ -40,6 +78,14 @@ func NewTemplateFSAdapter(params *TemplateFSAdapterParams) port.TemplateAdapter
 }
 
 func (a *templateFSAdapter) Get(id string) (types.Template, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	for _, template := range a.user {
+		if template.ID == id {
+			return template, nil
+		}
+	}
 	for _, service := range a.templates {
 		if service.ID == id {
 			return service, nil
@@ -50,54 +96,293 @@ func (a *templateFSAdapter) Get(id string) (types.Template, error) {
 }
 
 func (a *templateFSAdapter) GetRaw(id string) (interface{}, error) {
-	servicePath := path.Join(a.servicesPath, "services", id, "service.yml")
-
-	data, err := os.ReadFile(servicePath)
-	if err != nil && os.IsNotExist(err) {
+	a.mu.RLock()
+	data, ok := a.raw[id]
+	for _, template := range a.user {
+		if template.ID == id {
+			var err error
+			data, err = yaml.Marshal(template)
+			if err != nil {
+				a.mu.RUnlock()
+				return nil, err
+			}
+			ok = true
+			break
+		}
+	}
+	a.mu.RUnlock()
+	if !ok {
 		return nil, types.ErrTemplateNotFound
-	} else if err != nil {
-		return nil, err
 	}
 
 	var template interface{}
-	err = yaml.Unmarshal(data, &template)
+	err := yaml.Unmarshal(data, &template)
 	return template, err
 }
 
+// GetAll returns the templates created by users, followed by the templates
+// of the sources they don't replace.
 func (a *templateFSAdapter) GetAll() []types.Template {
-	return a.templates
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if len(a.user) == 0 {
+		return a.templates
+	}
+
+	all := make([]types.Template, 0, len(a.user)+len(a.templates))
+	all = append(all, a.user...)
+	for _, template := range a.templates {
+		if !slices.ContainsFunc(a.user, func(t types.Template) bool { return t.ID == template.ID }) {
+			all = append(all, template)
+		}
+	}
+	return all
+}
+
+// SetUserTemplates replaces the templates created by users.
+func (a *templateFSAdapter) SetUserTemplates(templates []types.Template) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.user = templates
+}
+
+// GetSources returns the template sources, with the status of their latest
+// reload.
+func (a *templateFSAdapter) GetSources() types.TemplateSources {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	sources := make(types.TemplateSources, len(a.sources))
+	for i, s := range a.sources {
+		s.Status = a.status[s.ID]
+		sources[i] = s
+	}
+	return sources
+}
+
+// SetSources replaces the sources read by the next reload. The built-in
+// source is always kept.
+func (a *templateFSAdapter) SetSources(sources types.TemplateSources) {
+	builtIn := types.TemplateSource{
+		Name:    "vertex",
+		Type:    types.TemplateSourceTypeDir,
+		URL:     a.servicesPath,
+		BuiltIn: true,
+	}
+
+	all := append(types.TemplateSources{builtIn}, sources...)
+	all.Sort()
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.sources = all
 }
 
+// Reload reads the templates of all the sources. A source that fails doesn't
+// prevent the others from being read: its errors are kept in its status,
+// and returned together.
 func (a *templateFSAdapter) Reload() error {
-	servicesPath := path.Join(a.servicesPath, "services")
+	a.reloadMu.Lock()
+	defer a.reloadMu.Unlock()
+
+	a.mu.RLock()
+	sources := a.sources
+	a.mu.RUnlock()
+
+	var (
+		templates []types.Template
+		raw       = map[string][]byte{}
+		status    = map[uuid.UUID]types.TemplateSourceStatus{}
+		errs      []error
+	)
+
+	for _, source := range sources {
+		files, err := a.read(source)
+
+		now := time.Now()
+		st := types.TemplateSourceStatus{RefreshedAt: &now}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", source.Name, err))
+			st.Errors = append(st.Errors, err.Error())
+		}
+
+		for _, name := range sortedFileNames(files) {
+			var template types.Template
+			err := yaml.Unmarshal(files[name], &template)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %s: %w", source.Name, name, err))
+				st.Errors = append(st.Errors, fmt.Sprintf("%s: %s", name, err))
+				continue
+			}
+			st.Templates++
+
+			// Sources are sorted by priority, so the first template read wins.
+			if _, ok := raw[template.ID]; ok {
+				continue
+			}
+			template.Source = source.Name
+			templates = append(templates, template)
+			raw[template.ID] = files[name]
+		}
+		status[source.ID] = st
+	}
 
-	a.templates = []types.Template{}
+	a.mu.Lock()
+	a.templates = templates
+	if a.templates == nil {
+		a.templates = []types.Template{}
+	}
+	a.raw = raw
+	a.status = status
+	a.mu.Unlock()
 
-	entries, err := os.ReadDir(servicesPath)
+	return errors.Join(errs...)
+}
+
+// read returns the template files of a source, by name. On error, the files
+// that could be read are still returned.
+func (a *templateFSAdapter) read(source types.TemplateSource) (map[string][]byte, error) {
+	switch source.Type {
+	case types.TemplateSourceTypeDir:
+		return readCatalogDir(source, source.URL)
+	case types.TemplateSourceTypeGit:
+		dir := path.Join(a.sourcesPath, source.ID.String())
+		err := vstorage.CloneOrPullRepository(source.URL, dir)
+		if err != nil {
+			// The previous checkout, if any, is still used.
+			log.Error(err, vlog.String("source", source.Name))
+			files, _ := readCatalogDir(source, dir)
+			return files, err
+		}
+		return readCatalogDir(source, dir)
+	case types.TemplateSourceTypeHTTP:
+		return a.readCatalogIndex(source)
+	}
+	return nil, types.ErrInvalidTemplateSource
+}
+
+// DeleteSourceFiles deletes the files kept for a source, like its clone.
+func (a *templateFSAdapter) DeleteSourceFiles(id uuid.UUID) error {
+	a.reloadMu.Lock()
+	defer a.reloadMu.Unlock()
+	return os.RemoveAll(path.Join(a.sourcesPath, id.String()))
+}
+
+// ReadTemplateDir reads the template files of a directory, laid out either
+// as services/<id>/service.yml, like the sources, or as <id>/service.yml.
+func ReadTemplateDir(dir string) (map[string][]byte, error) {
+	_, err := os.Stat(path.Join(dir, "services"))
+	if err == nil {
+		return readTemplateFiles(dir, "services")
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+	return readTemplateFiles(dir, "")
+}
+
+// readCatalogDir reads the services/<id>/service.yml files of a directory,
+// and checks their signature if the source has a public key.
+func readCatalogDir(source types.TemplateSource, dir string) (map[string][]byte, error) {
+	files, err := readTemplateFiles(dir, "services")
+	if files == nil {
+		return nil, err
+	}
+
+	if source.PublicKey != nil {
+		signature, err := os.ReadFile(path.Join(dir, catalogSignatureFile))
+		if err != nil {
+			return nil, types.ErrInvalidCatalogSignature
+		}
+		err = source.Verify(types.CatalogManifest(files), signature)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, err
+}
+
+// readTemplateFiles reads the <sub>/<id>/service.yml files of a directory.
+// Unreadable templates are reported, and skipped.
+func readTemplateFiles(dir string, sub string) (map[string][]byte, error) {
+	entries, err := os.ReadDir(path.Join(dir, sub))
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	for _, dir := range entries {
-		if !dir.IsDir() {
+	var errs []error
+	files := map[string][]byte{}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		name := path.Join(sub, entry.Name(), "service.yml")
+		data, err := os.ReadFile(path.Join(dir, name))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", entry.Name(), err))
 			continue
 		}
+		files[name] = data
+	}
+	return files, errors.Join(errs...)
+}
 
-		servicePath := path.Join(servicesPath, dir.Name(), "service.yml")
+// readCatalogIndex reads an index file, which lists the templates under
+// its templates key.
+func (a *templateFSAdapter) readCatalogIndex(source types.TemplateSource) (map[string][]byte, error) {
+	data, err := a.download(source.URL)
+	if err != nil {
+		return nil, err
+	}
 
-		file, err := os.ReadFile(servicePath)
+	if source.PublicKey != nil {
+		signature, err := a.download(source.URL + ".sig")
 		if err != nil {
-			return err
+			return nil, types.ErrInvalidCatalogSignature
 		}
+		err = source.Verify(data, signature)
+		if err != nil {
+			return nil, err
+		}
+	}
 
-		var template types.Template
-		err = yaml.Unmarshal(file, &template)
+	var index struct {
+		Templates []yaml.Node `yaml:"templates"`
+	}
+	err = yaml.Unmarshal(data, &index)
+	if err != nil {
+		return nil, err
+	}
+
+	files := map[string][]byte{}
+	for i, node := range index.Templates {
+		data, err := yaml.Marshal(&node)
 		if err != nil {
-			return err
+			return nil, err
 		}
+		files[fmt.Sprintf("templates[%d]", i)] = data
+	}
+	return files, nil
+}
+
+func (a *templateFSAdapter) download(url string) ([]byte, error) {
+	res, err := a.client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
 
-		a.templates = append(a.templates, template)
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download %s: %s", url, res.Status)
 	}
+	return io.ReadAll(res.Body)
+}
 
-	return nil
+func sortedFileNames(files map[string][]byte) []string {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
 }
@@ -1,9 +1,14 @@
 package adapter
 
 import (
+	"os"
+	"path"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/suite"
+	"github.com/vertex-center/uuid"
+	"github.com/vertex-center/vertex/server/apps/containers/core/types"
 )
 
 const (
@@ -13,7 +18,7 @@ const (
 type AvailableTestSuite struct {
 	suite.Suite
 
-	adapter templateFSAdapter
+	adapter *templateFSAdapter
 }
 
 func TestAvailableTestSuite(t *testing.T) {
@@ -21,7 +26,7 @@ func TestAvailableTestSuite(t *testing.T) {
 }
 
 func (suite *AvailableTestSuite) SetupSuite() {
-	suite.adapter = *NewTemplateFSAdapter(&TemplateFSAdapterParams{
+	suite.adapter = NewTemplateFSAdapter(&TemplateFSAdapterParams{
 		templatesPath: PathServices,
 	}).(*templateFSAdapter)
 
@@ -33,3 +38,85 @@ func (suite *AvailableTestSuite) SetupSuite() {
 func (suite *AvailableTestSuite) TestGetAvailable() {
 	suite.Len(suite.adapter.GetAll(), 1)
 }
+
+func (suite *AvailableTestSuite) TestSourcesPriority() {
+	dir := suite.T().TempDir()
+	for _, id := range []string{"redis", "postgres"} {
+		suite.Require().NoError(os.MkdirAll(path.Join(dir, "services", id), 0755))
+		content := "id: " + id + "\nname: Custom " + id + "\n"
+		suite.Require().NoError(os.WriteFile(path.Join(dir, "services", id, "service.yml"), []byte(content), 0644))
+	}
+
+	a := NewTemplateFSAdapter(&TemplateFSAdapterParams{
+		templatesPath: PathServices,
+	}).(*templateFSAdapter)
+	a.SetSources(types.TemplateSources{
+		{ID: uuid.New(), Name: "custom", Type: types.TemplateSourceTypeDir, URL: dir, Priority: 10},
+		{ID: uuid.New(), Name: "missing", Type: types.TemplateSourceTypeDir, URL: path.Join(dir, "missing")},
+	})
+
+	err := a.Reload()
+	suite.Error(err)
+
+	redis, err := a.Get("redis")
+	suite.Require().NoError(err)
+	suite.Equal("Custom redis", redis.Name)
+	suite.Equal("custom", redis.Source)
+
+	for _, s := range a.GetSources() {
+		switch s.Name {
+		case "custom":
+			suite.Equal(2, s.Status.Templates)
+			suite.Empty(s.Status.Errors)
+		case "missing":
+			suite.Len(s.Status.Errors, 1)
+		case "vertex":
+			suite.True(s.BuiltIn)
+			suite.Empty(s.Status.Errors)
+		}
+	}
+}
+
+func (suite *AvailableTestSuite) TestConcurrentReloads() {
+	a := NewTemplateFSAdapter(&TemplateFSAdapterParams{
+		templatesPath: PathServices,
+		sourcesPath:   suite.T().TempDir(),
+	}).(*templateFSAdapter)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			suite.NoError(a.Reload())
+		}()
+		go func() {
+			defer wg.Done()
+			suite.NoError(a.DeleteSourceFiles(uuid.New()))
+		}()
+	}
+	wg.Wait()
+	suite.Len(a.GetAll(), 1)
+}
+
+func (suite *AvailableTestSuite) TestUserTemplates() {
+	a := NewTemplateFSAdapter(&TemplateFSAdapterParams{
+		templatesPath: PathServices,
+	}).(*templateFSAdapter)
+	a.templates = []types.Template{{ID: "redis", Name: "Redis"}, {ID: "postgres", Name: "Postgres"}}
+
+	a.SetUserTemplates([]types.Template{{ID: "redis", Name: "My Redis", Source: types.TemplateSourceUser}})
+
+	all := a.GetAll()
+	suite.Len(all, 2)
+	suite.Equal("My Redis", all[0].Name)
+	suite.Equal("Postgres", all[1].Name)
+
+	redis, err := a.Get("redis")
+	suite.Require().NoError(err)
+	suite.Equal(types.TemplateSourceUser, redis.Source)
+
+	raw, err := a.GetRaw("redis")
+	suite.Require().NoError(err)
+	suite.Equal("My Redis", raw.(map[string]interface{})["name"])
+}
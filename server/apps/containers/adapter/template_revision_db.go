@@ -0,0 +1,75 @@
+package adapter
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/vertex-center/vertex/server/apps/containers/core/port"
+	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+	"github.com/vertex-center/vertex/server/common/storage"
+	"gopkg.in/yaml.v3"
+)
+
+// templateRevisionDBAdapter keeps the revisions of the templates that
+// containers were created from, to compare them with the latest templates.
+type templateRevisionDBAdapter struct {
+	db storage.DB
+}
+
+type templateRevisionRow struct {
+	TemplateID string `db:"template_id"`
+	Revision   string `db:"revision"`
+	Content    string `db:"content"`
+	CreatedAt  int64  `db:"created_at"`
+}
+
+func NewTemplateRevisionDBAdapter(db storage.DB) port.TemplateRevisionAdapter {
+	return &templateRevisionDBAdapter{db}
+}
+
+func (a *templateRevisionDBAdapter) GetRevision(ctx context.Context, templateID string, revision string) (*types.Template, error) {
+	var row templateRevisionRow
+	err := a.db.Get(&row, `
+		SELECT * FROM template_revisions
+		WHERE template_id = $1 AND revision = $2
+	`, templateID, revision)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, types.ErrTemplateRevisionNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	t, err := types.ParseTemplate([]byte(row.Content))
+	if err != nil {
+		return nil, errors.Annotatef(err, "read revision %s of template %s", revision, templateID)
+	}
+	return t, nil
+}
+
+// CreateRevision saves a revision of a template. Revisions already saved
+// are left unchanged.
+func (a *templateRevisionDBAdapter) CreateRevision(ctx context.Context, revision string, template types.Template) error {
+	_, err := a.GetRevision(ctx, template.ID, revision)
+	if err == nil {
+		return nil
+	} else if !errors.Is(err, types.ErrTemplateRevisionNotFound) {
+		return err
+	}
+
+	content, err := yaml.Marshal(template)
+	if err != nil {
+		return err
+	}
+	_, err = a.db.NamedExec(`
+		INSERT INTO template_revisions (template_id, revision, content, created_at)
+		VALUES (:template_id, :revision, :content, :created_at)
+	`, templateRevisionRow{
+		TemplateID: template.ID,
+		Revision:   revision,
+		Content:    string(content),
+		CreatedAt:  time.Now().Unix(),
+	})
+	return err
+}
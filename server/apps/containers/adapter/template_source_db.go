@@ -0,0 +1,57 @@
+package adapter
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/juju/errors"
+	"github.com/vertex-center/uuid"
+	"github.com/vertex-center/vertex/server/apps/containers/core/port"
+	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+	"github.com/vertex-center/vertex/server/common/storage"
+)
+
+type templateSourceDBAdapter struct {
+	db storage.DB
+}
+
+func NewTemplateSourceDBAdapter(db storage.DB) port.TemplateSourceAdapter {
+	return &templateSourceDBAdapter{db}
+}
+
+func (a *templateSourceDBAdapter) GetSources(ctx context.Context) (types.TemplateSources, error) {
+	var sources types.TemplateSources
+	err := a.db.Select(&sources, `
+		SELECT * FROM template_sources
+		ORDER BY priority DESC, name
+	`)
+	return sources, err
+}
+
+func (a *templateSourceDBAdapter) GetSource(ctx context.Context, id uuid.UUID) (*types.TemplateSource, error) {
+	var source types.TemplateSource
+	err := a.db.Get(&source, `
+		SELECT * FROM template_sources
+		WHERE id = $1
+	`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, types.ErrTemplateSourceNotFound
+	}
+	return &source, err
+}
+
+func (a *templateSourceDBAdapter) CreateSource(ctx context.Context, source types.TemplateSource) error {
+	_, err := a.db.NamedExec(`
+		INSERT INTO template_sources (id, name, type, url, priority, public_key)
+		VALUES (:id, :name, :type, :url, :priority, :public_key)
+	`, source)
+	return err
+}
+
+func (a *templateSourceDBAdapter) DeleteSource(ctx context.Context, id uuid.UUID) error {
+	_, err := a.db.Exec(`
+		DELETE FROM template_sources
+		WHERE id = $1
+	`, id)
+	return err
+}
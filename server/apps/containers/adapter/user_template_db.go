@@ -0,0 +1,104 @@
+package adapter
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/vertex-center/uuid"
+	"github.com/vertex-center/vertex/server/apps/containers/core/port"
+	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+	"github.com/vertex-center/vertex/server/common/storage"
+	"gopkg.in/yaml.v3"
+)
+
+// userTemplateDBAdapter stores the templates created by users as YAML, so
+// that they are upgraded like the other templates when they are read.
+type userTemplateDBAdapter struct {
+	db storage.DB
+}
+
+type userTemplateRow struct {
+	ID        string    `db:"id"`
+	UserID    uuid.UUID `db:"user_id"`
+	Content   string    `db:"content"`
+	CreatedAt int64     `db:"created_at"`
+}
+
+func NewUserTemplateDBAdapter(db storage.DB) port.UserTemplateAdapter {
+	return &userTemplateDBAdapter{db}
+}
+
+func (a *userTemplateDBAdapter) GetTemplates(ctx context.Context) ([]types.Template, error) {
+	var rows []userTemplateRow
+	err := a.db.Select(&rows, `
+		SELECT * FROM user_templates
+		ORDER BY created_at
+	`)
+	if err != nil {
+		return nil, err
+	}
+
+	templates := make([]types.Template, 0, len(rows))
+	for _, row := range rows {
+		t, err := row.template()
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, *t)
+	}
+	return templates, nil
+}
+
+func (a *userTemplateDBAdapter) GetTemplate(ctx context.Context, id string) (*types.Template, error) {
+	var row userTemplateRow
+	err := a.db.Get(&row, `
+		SELECT * FROM user_templates
+		WHERE id = $1
+	`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, types.ErrTemplateNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return row.template()
+}
+
+func (a *userTemplateDBAdapter) CreateTemplate(ctx context.Context, template types.Template) error {
+	if template.UserID == nil {
+		return errors.NewNotValid(nil, "user templates must have an owner")
+	}
+	content, err := yaml.Marshal(template)
+	if err != nil {
+		return err
+	}
+	_, err = a.db.NamedExec(`
+		INSERT INTO user_templates (id, user_id, content, created_at)
+		VALUES (:id, :user_id, :content, :created_at)
+	`, userTemplateRow{
+		ID:        template.ID,
+		UserID:    *template.UserID,
+		Content:   string(content),
+		CreatedAt: time.Now().Unix(),
+	})
+	return err
+}
+
+func (a *userTemplateDBAdapter) DeleteTemplate(ctx context.Context, id string) error {
+	_, err := a.db.Exec(`
+		DELETE FROM user_templates
+		WHERE id = $1
+	`, id)
+	return err
+}
+
+func (r userTemplateRow) template() (*types.Template, error) {
+	t, err := types.ParseTemplate([]byte(r.Content))
+	if err != nil {
+		return nil, errors.Annotatef(err, "read user template %s", r.ID)
+	}
+	t.Source = types.TemplateSourceUser
+	t.UserID = &r.UserID
+	return t, nil
+}
@@ -39,6 +39,14 @@ func (a *volumeDBAdapter) CreateVolume(ctx context.Context, vol types.Volume) er
 	return err
 }
 
+func (a *volumeDBAdapter) DeleteVolume(ctx context.Context, id uuid.UUID) error {
+	_, err := a.db.Exec(`
+		DELETE FROM volumes
+		WHERE id = $1
+	`, id)
+	return err
+}
+
 func (a *volumeDBAdapter) DeleteContainerVolumes(ctx context.Context, id uuid.UUID) error {
 	_, err := a.db.Exec(`
 		DELETE FROM volumes
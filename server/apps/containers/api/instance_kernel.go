@@ -2,11 +2,19 @@ package containersapi
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
 
 	"github.com/docker/docker/api/types/volume"
+	"github.com/gorilla/websocket"
+	"github.com/juju/errors"
 	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+	"github.com/vertex-center/vertex/server/apps/containers/meta"
+	"github.com/vertex-center/vertex/server/common/server"
+	"github.com/vertex-center/vertex/server/config"
 )
 
 func (c *KernelClient) CreateContainer(ctx context.Context, options types.CreateDockerContainerOptions) (types.CreateContainerResponse, error) {
@@ -49,6 +57,34 @@ func (c *KernelClient) GetContainerInfo(ctx context.Context, id string) (types.I
 	return info, err
 }
 
+func (c *KernelClient) InspectContainer(ctx context.Context, id string) (types.InspectContainerResponse, error) {
+	var info types.InspectContainerResponse
+	err := c.Request().
+		Pathf("./docker/containers/%s/inspect", id).
+		ToJSON(&info).
+		Fetch(ctx)
+	return info, err
+}
+
+func (c *KernelClient) RenameContainer(ctx context.Context, id string, name string) error {
+	return c.Request().
+		Pathf("./docker/containers/%s/rename", id).
+		BodyJSON(types.RenameContainerOptions{
+			Name: name,
+		}).
+		Post().
+		Fetch(ctx)
+}
+
+func (c *KernelClient) GetContainerStats(ctx context.Context, id string) (types.ContainerStats, error) {
+	var stats types.ContainerStats
+	err := c.Request().
+		Pathf("./docker/containers/%s/stats", id).
+		ToJSON(&stats).
+		Fetch(ctx)
+	return stats, err
+}
+
 func (c *KernelClient) GetImageInfo(ctx context.Context, id string) (types.InfoImageResponse, error) {
 	var info types.InfoImageResponse
 	err := c.Request().
@@ -134,6 +170,42 @@ func (c *KernelClient) GetContainerStderr(ctx context.Context, id string) (io.Re
 	return stderr.Body, err
 }
 
+// ExecContainer opens a websocket to a terminal running in the container.
+func (c *KernelClient) ExecContainer(ctx context.Context, id string, options types.ExecOptions) (*websocket.Conn, error) {
+	u := *config.Current.KernelAddr(meta.Meta.ID)
+	switch u.Scheme {
+	case "https":
+		u.Scheme = "wss"
+	default:
+		u.Scheme = "ws"
+	}
+	u.Path = fmt.Sprintf("%s/docker/containers/%s/exec", u.Path, url.PathEscape(id))
+
+	q := url.Values{}
+	for _, arg := range options.Cmd {
+		q.Add("cmd", arg)
+	}
+	if options.Cols > 0 && options.Rows > 0 {
+		q.Set("cols", strconv.FormatUint(uint64(options.Cols), 10))
+		q.Set("rows", strconv.FormatUint(uint64(options.Rows), 10))
+	}
+	u.RawQuery = q.Encode()
+
+	header := http.Header{}
+	if correlationID, ok := ctx.Value(server.KeyCorrelationID).(string); ok {
+		header.Set("X-Correlation-ID", correlationID)
+	}
+
+	conn, res, err := websocket.DefaultDialer.DialContext(ctx, u.String(), header)
+	if res != nil && res.Body != nil {
+		_ = res.Body.Close()
+	}
+	if err != nil && res != nil && res.StatusCode == http.StatusNotFound {
+		return nil, errors.NewNotFound(err, "container not found")
+	}
+	return conn, err
+}
+
 func (c *KernelClient) DeleteMounts(ctx context.Context, id string) error {
 	return c.Request().
 		Pathf("./docker/containers/%s/mounts", id).
@@ -161,6 +233,25 @@ func (c *KernelClient) CreateVolume(ctx context.Context, name string) (volume.Vo
 	return res, err
 }
 
+func (c *KernelClient) ArchiveVolume(ctx context.Context, options types.ArchiveVolumeOptions) (types.ArchiveVolumeResponse, error) {
+	var res types.ArchiveVolumeResponse
+	err := c.Request().
+		Path("./docker/volumes/archive").
+		BodyJSON(options).
+		ToJSON(&res).
+		Post().
+		Fetch(ctx)
+	return res, err
+}
+
+func (c *KernelClient) RestoreVolume(ctx context.Context, options types.RestoreVolumeOptions) error {
+	return c.Request().
+		Path("./docker/volumes/restore").
+		BodyJSON(options).
+		Post().
+		Fetch(ctx)
+}
+
 func (c *KernelClient) DeleteVolume(ctx context.Context, name string) error {
 	return c.Request().
 		Path("./docker/volumes").
@@ -170,3 +261,39 @@ func (c *KernelClient) DeleteVolume(ctx context.Context, name string) error {
 		Delete().
 		Fetch(ctx)
 }
+
+func (c *KernelClient) CreateNetwork(ctx context.Context, name string) error {
+	return c.Request().
+		Path("./docker/networks").
+		BodyJSON(map[string]string{
+			"name": name,
+		}).
+		Post().
+		Fetch(ctx)
+}
+
+func (c *KernelClient) DeleteNetwork(ctx context.Context, name string) error {
+	return c.Request().
+		Path("./docker/networks").
+		BodyJSON(map[string]string{
+			"name": name,
+		}).
+		Delete().
+		Fetch(ctx)
+}
+
+func (c *KernelClient) ConnectNetwork(ctx context.Context, options types.ConnectNetworkOptions) error {
+	return c.Request().
+		Path("./docker/networks/connect").
+		BodyJSON(options).
+		Post().
+		Fetch(ctx)
+}
+
+func (c *KernelClient) DisconnectNetwork(ctx context.Context, options types.DisconnectNetworkOptions) error {
+	return c.Request().
+		Path("./docker/networks/disconnect").
+		BodyJSON(options).
+		Post().
+		Fetch(ctx)
+}
@@ -1,6 +1,7 @@
 package containers
 
 import (
+	"context"
 	"os"
 	"path"
 
@@ -18,6 +19,7 @@ import (
 	"github.com/vertex-center/vertex/server/common/middleware"
 	"github.com/vertex-center/vertex/server/common/storage"
 	"github.com/vertex-center/vertex/server/common/updater"
+	"github.com/vertex-center/vertex/server/pkg/vcrypto"
 	"github.com/wI2L/fizz"
 )
 
@@ -27,6 +29,20 @@ var (
 	tagsService      port.TagsService
 	metricsService   port.MetricsService
 	portsService     port.PortsService
+	healthService    port.HealthCheckService
+	resourcesService port.ResourceLimitsService
+	updateService    port.UpdateService
+	depsService      port.DependencyService
+	stackService     port.StackService
+	composeService   port.ComposeService
+	adoptService     port.AdoptService
+	registryService  port.RegistryService
+	backupService    port.BackupService
+	execService      port.ExecService
+	networkService   port.NetworkService
+	templateService  port.TemplateService
+	sourceService    port.TemplateSourceService
+	upgradeService   port.TemplateUpdateService
 
 	dockerKernelService port.DockerService
 )
@@ -71,6 +87,17 @@ func (a *App) Initialize() error {
 		return err
 	}
 
+	// Registry credentials stored before the keyring were encrypted with this key.
+	legacyKey, err := vcrypto.LoadOrCreateKey(path.Join(storage.FSPath, "apps", "containers", "secret.key"))
+	if err != nil {
+		return err
+	}
+
+	keyring, err := vcrypto.LoadOrCreateKeyring(path.Join(storage.FSPath, "apps", "containers", "keys"))
+	if err != nil {
+		return err
+	}
+
 	var (
 		caps       = adapter.NewCapDBAdapter(db)
 		ports      = adapter.NewPortDBAdapter(db)
@@ -78,19 +105,59 @@ func (a *App) Initialize() error {
 		tags       = adapter.NewTagDBAdapter(db)
 		volumes    = adapter.NewVolumeDBAdapter(db)
 		containers = adapter.NewContainerDBAdapter(db)
-		env        = adapter.NewEnvDBAdapter(db)
+		env        = adapter.NewEnvDBAdapter(db, keyring)
+		health     = adapter.NewHealthCheckDBAdapter(db)
+		resources  = adapter.NewResourceLimitsDBAdapter(db)
+		deps       = adapter.NewDependencyDBAdapter(db)
+		stacks     = adapter.NewStackDBAdapter(db)
+		registries = adapter.NewRegistryCredentialDBAdapter(db, keyring, legacyKey)
+		builds     = adapter.NewBuildDBAdapter(db)
+		backups    = adapter.NewBackupDBAdapter(db)
+		networks   = adapter.NewNetworkDBAdapter(db)
+		hostPorts  = adapter.NewHostPortsAdapter()
 		logs       = adapter.NewLogsFSAdapter(nil)
-		runner     = adapter.NewRunnerDockerAdapter()
+		runner     = adapter.NewRunnerDockerAdapter(registries)
+		sources    = adapter.NewTemplateSourceDBAdapter(db)
+		templates  = adapter.NewUserTemplateDBAdapter(db)
 		services   = adapter.NewTemplateFSAdapter(nil)
+		revisions  = adapter.NewTemplateRevisionDBAdapter(db)
 	)
 
-	containerService = service.NewContainerService(a.ctx, caps, containers, env, ports, volumes, tags, sysctls, runner, services, logs)
-	envService = service.NewEnvService(env)
+	containerService = service.NewContainerService(a.ctx, caps, containers, env, ports, volumes, tags, sysctls, health, resources, builds, deps, runner, services, revisions, logs, networks, hostPorts, keyring)
+	envService = service.NewEnvService(env, containers, services, registries, keyring)
 	tagsService = service.NewTagsService(tags)
 	metricsService = service.NewMetricsService(a.ctx)
-	portsService = service.NewPortsService(ports)
+	portsService = service.NewPortsService(ports, hostPorts)
+	healthService = service.NewHealthCheckService(containers, health)
+	resourcesService = service.NewResourceLimitsService(containers, resources)
+	updateService = service.NewUpdateService(a.ctx, containerService, containers, runner)
+	depsService = service.NewDependencyService(containers, deps)
+	stackService = service.NewStackService(containerService, stacks, containers, env, deps, runner, services)
+	registryService = service.NewRegistryService(registries)
+	backupService = service.NewBackupService(a.ctx, containerService, containers, volumes, backups, runner)
+	execService = service.NewExecService(a.ctx, containers, runner)
+	networkService = service.NewNetworkService(containers, networks, runner)
+	composeService = service.NewComposeService(containerService, containers, env, ports, hostPorts, volumes, caps, sysctls, health, deps)
+	adoptService = service.NewAdoptService(containerService, containers, env, ports, volumes, caps, sysctls, runner)
+	templateService = service.NewTemplateService(services, templates, containers, env, ports, volumes, caps, sysctls, health, builds)
+	sourceService = service.NewTemplateSourceService(a.ctx, sources, services)
+	upgradeService = service.NewTemplateUpdateService(a.ctx, containerService, containers, services, revisions, env, ports, volumes, caps, hostPorts, keyring)
+
+	err = templateService.LoadUserTemplates(context.Background())
+	if err != nil {
+		return err
+	}
 
-	return nil
+	// The sources are read in the background. A source that can't be read is
+	// reported in its status. The revisions of the templates are tracked once
+	// the sources are read.
+	err = sourceService.LoadSources(context.Background())
+	if err != nil {
+		return err
+	}
+
+	// Secrets stored before they were encrypted with the keyring are encrypted now.
+	return envService.EncryptSecrets(context.Background())
 }
 
 func (a *App) InitializeRouter(r *fizz.RouterGroup) error {
@@ -99,13 +166,28 @@ func (a *App) InitializeRouter(r *fizz.RouterGroup) error {
 	metric.Serve(r, metricsService)
 
 	var (
-		templatesHandler  = handler.NewTemplateHandler(containerService)
+		templatesHandler  = handler.NewTemplateHandler(containerService, templateService, sourceService)
 		envHandler        = handler.NewEnvHandler(envService)
 		tagsHandler       = handler.NewTagsHandler(tagsService)
 		containersHandler = handler.NewContainerHandler(a.ctx, containerService)
 		portsHandler      = handler.NewPortsHandler(portsService)
+		healthHandler     = handler.NewHealthCheckHandler(healthService)
+		resourcesHandler  = handler.NewResourceLimitsHandler(resourcesService)
+		updateHandler     = handler.NewUpdateHandler(updateService)
+		upgradeHandler    = handler.NewTemplateUpdateHandler(upgradeService)
+		depsHandler       = handler.NewDependencyHandler(depsService)
+		stacksHandler     = handler.NewStackHandler(stackService)
+		composeHandler    = handler.NewComposeHandler(composeService)
+		adoptHandler      = handler.NewAdoptHandler(adoptService)
+		registryHandler   = handler.NewRegistryHandler(registryService)
+		backupHandler     = handler.NewBackupHandler(backupService)
+		execHandler       = handler.NewExecHandler(execService)
+		networkHandler    = handler.NewNetworkHandler(networkService)
 
 		containers   = r.Group("/containers", "Containers", "", authmiddleware.Authenticated)
+		stacks       = r.Group("/stacks", "Stacks", "", authmiddleware.Authenticated)
+		registries   = r.Group("/registries", "Registries", "", authmiddleware.Authenticated)
+		networks     = r.Group("/networks", "Networks", "", authmiddleware.Authenticated)
 		environments = r.Group("/environments", "Environment variables", "", authmiddleware.Authenticated)
 		ports        = r.Group("/ports", "Ports", "", authmiddleware.Authenticated)
 		tags         = r.Group("/tags", "Tags", "", authmiddleware.Authenticated)
@@ -130,9 +212,26 @@ func (a *App) InitializeRouter(r *fizz.RouterGroup) error {
 		fizz.Summary("Reload a container"),
 	}, containersHandler.ReloadContainer())
 
+	containers.POST("/:container_id/duplicate", []fizz.OperationOption{
+		fizz.ID("duplicateContainer"),
+		fizz.Summary("Duplicate a container"),
+		fizz.Description("Create a copy of a container with its configuration. Ports already in use are replaced by free ones, and the volumes of the copy are new volumes, optionally filled with the data of the original."),
+		fizz.Response("404", "Container not found", nil, nil, map[string]interface{}{"error": "container not found"}),
+	}, containersHandler.DuplicateContainer())
+
+	containers.POST("/:container_id/rebuild", []fizz.OperationOption{
+		fizz.ID("rebuildContainer"),
+		fizz.Summary("Rebuild a container"),
+		fizz.Description("Pull the repository of a container built from sources. If it has new commits, the image is rebuilt and the container recreated."),
+		fizz.Response("404", "Container build not found", nil, nil, map[string]interface{}{"error": "container build not found"}),
+	}, containersHandler.RebuildContainer())
+
 	containers.POST("", []fizz.OperationOption{
 		fizz.ID("createContainer"),
 		fizz.Summary("Create a container"),
+		fizz.Description("Ports of the template already used are rejected, unless allocate_ports is set, in which case the next free host ports are used. The values given in env replace the defaults of the template, and are validated against the type and constraints of the variables."),
+		fizz.Response("400", "Invalid environment variable value", nil, nil, map[string]interface{}{"error": "PORT: must be at most 65535: environment variable value not valid"}),
+		fizz.Response("409", "Port already used", nil, nil, map[string]interface{}{"error": "port 8080/tcp already used on the host"}),
 		fizz.Response("500", "", nil, nil, map[string]interface{}{"error": "failed to create container"}),
 	}, containersHandler.CreateContainer())
 
@@ -178,6 +277,14 @@ func (a *App) InitializeRouter(r *fizz.RouterGroup) error {
 		fizz.Response("500", "", nil, nil, map[string]interface{}{"error": "failed to get container events"}),
 	}, middleware.SSE, containersHandler.ContainerEvents())
 
+	containers.GET("/:container_id/stats", []fizz.OperationOption{
+		fizz.ID("statsContainer"),
+		fizz.Summary("Get container stats"),
+		fizz.Description("Get the CPU, memory, network and block I/O usage of a running container, sent as Server-Sent Events (SSE)."),
+		fizz.Response("404", "Container not found", nil, nil, map[string]interface{}{"error": "container not found"}),
+		fizz.Response("500", "", nil, nil, map[string]interface{}{"error": "failed to get container stats"}),
+	}, middleware.SSE, containersHandler.ContainerStats())
+
 	containers.GET("/:container_id/docker", []fizz.OperationOption{
 		fizz.ID("getDockerContainer"),
 		fizz.Summary("Get Docker container info"),
@@ -195,11 +302,18 @@ func (a *App) InitializeRouter(r *fizz.RouterGroup) error {
 	containers.GET("/:container_id/logs", []fizz.OperationOption{
 		fizz.ID("getContainerLogs"),
 		fizz.Summary("Get container logs"),
-		fizz.Description("Get latest container logs."),
+		fizz.Description("Get latest container logs. With filters, search the log history instead, by time, text, kind or level: the most recent matching lines are returned, and the previous page is requested with the time of the oldest line as until."),
 		fizz.Response("404", "Container not found", nil, nil, map[string]interface{}{"error": "container not found"}),
 		fizz.Response("500", "", nil, nil, map[string]interface{}{"error": "failed to get container logs"}),
 	}, containersHandler.GetLogs())
 
+	containers.GET("/:container_id/logs/download", []fizz.OperationOption{
+		fizz.ID("downloadContainerLogs"),
+		fizz.Summary("Download container logs"),
+		fizz.Description("Download all the log files of a container in a tar.gz archive."),
+		fizz.Response("404", "Container not found", nil, nil, map[string]interface{}{"error": "container not found"}),
+	}, containersHandler.DownloadLogs())
+
 	containers.GET("/:container_id/versions", []fizz.OperationOption{
 		fizz.ID("getContainerVersions"),
 		fizz.Summary("Get container image versions"),
@@ -217,16 +331,314 @@ func (a *App) InitializeRouter(r *fizz.RouterGroup) error {
 		fizz.Response("500", "", nil, nil, map[string]interface{}{"error": "failed to wait for status change"}),
 	}, containersHandler.WaitStatus())
 
+	containers.GET("/:container_id/healthcheck", []fizz.OperationOption{
+		fizz.ID("getContainerHealthCheck"),
+		fizz.Summary("Get container health check"),
+		fizz.Response("404", "Health check not found", nil, nil, map[string]interface{}{"error": "health check not found"}),
+	}, healthHandler.GetHealthCheck())
+
+	containers.PUT("/:container_id/healthcheck", []fizz.OperationOption{
+		fizz.ID("setContainerHealthCheck"),
+		fizz.Summary("Set container health check"),
+		fizz.Description("Set the health check of a container. It is applied when the Docker container is recreated."),
+		fizz.Response("400", "Invalid health check", nil, nil, map[string]interface{}{"error": "health check not valid"}),
+		fizz.Response("500", "", nil, nil, map[string]interface{}{"error": "failed to set health check"}),
+	}, healthHandler.SetHealthCheck())
+
+	containers.DELETE("/:container_id/healthcheck", []fizz.OperationOption{
+		fizz.ID("deleteContainerHealthCheck"),
+		fizz.Summary("Delete container health check"),
+		fizz.Response("500", "", nil, nil, map[string]interface{}{"error": "failed to delete health check"}),
+	}, healthHandler.DeleteHealthCheck())
+
+	containers.GET("/:container_id/resources", []fizz.OperationOption{
+		fizz.ID("getContainerResourceLimits"),
+		fizz.Summary("Get container resource limits"),
+		fizz.Response("404", "Resource limits not found", nil, nil, map[string]interface{}{"error": "resource limits not found"}),
+	}, resourcesHandler.GetResourceLimits())
+
+	containers.PUT("/:container_id/resources", []fizz.OperationOption{
+		fizz.ID("setContainerResourceLimits"),
+		fizz.Summary("Set container resource limits"),
+		fizz.Description("Set the CPU, memory, PID and ulimit limits of a container. They are applied when the Docker container is recreated."),
+		fizz.Response("400", "Invalid resource limits", nil, nil, map[string]interface{}{"error": "resource limits not valid"}),
+		fizz.Response("404", "Container not found", nil, nil, map[string]interface{}{"error": "container not found"}),
+		fizz.Response("500", "", nil, nil, map[string]interface{}{"error": "failed to set resource limits"}),
+	}, resourcesHandler.SetResourceLimits())
+
+	containers.DELETE("/:container_id/resources", []fizz.OperationOption{
+		fizz.ID("deleteContainerResourceLimits"),
+		fizz.Summary("Delete container resource limits"),
+		fizz.Response("500", "", nil, nil, map[string]interface{}{"error": "failed to delete resource limits"}),
+	}, resourcesHandler.DeleteResourceLimits())
+
+	containers.GET("/:container_id/exec", []fizz.OperationOption{
+		fizz.ID("execContainer"),
+		fizz.Summary("Open a terminal in a container"),
+		fizz.Description("Open a websocket to an interactive terminal in a running container. Send input and resize messages as JSON; the output is sent back as binary messages. Browsers can pass the token in the query."),
+		fizz.Response("404", "Container not found", nil, nil, map[string]interface{}{"error": "container not found"}),
+	}, execHandler.Exec())
+
+	containers.GET("/:container_id/backups", []fizz.OperationOption{
+		fizz.ID("getContainerBackups"),
+		fizz.Summary("Get container backups"),
+		fizz.Description("Get the backups of the volumes of a container, from the most recent."),
+	}, backupHandler.GetBackups())
+
+	containers.POST("/:container_id/backups", []fizz.OperationOption{
+		fizz.ID("createContainerBackup"),
+		fizz.Summary("Create container backup"),
+		fizz.Description("Archive all the volumes of a container. With stop, the container is stopped during the backup."),
+		fizz.Response("404", "Container not found", nil, nil, map[string]interface{}{"error": "container not found"}),
+		fizz.Response("500", "", nil, nil, map[string]interface{}{"error": "failed to create backup"}),
+	}, backupHandler.CreateBackup())
+
+	containers.GET("/:container_id/backups/schedule", []fizz.OperationOption{
+		fizz.ID("getContainerBackupSchedule"),
+		fizz.Summary("Get container backup schedule"),
+		fizz.Response("404", "Backup schedule not found", nil, nil, map[string]interface{}{"error": "backup schedule not found"}),
+	}, backupHandler.GetBackupSchedule())
+
+	containers.PUT("/:container_id/backups/schedule", []fizz.OperationOption{
+		fizz.ID("setContainerBackupSchedule"),
+		fizz.Summary("Set container backup schedule"),
+		fizz.Description("Back up the container periodically with a cron expression, and only keep the most recent backups."),
+		fizz.Response("400", "Invalid backup schedule", nil, nil, map[string]interface{}{"error": "backup schedule not valid"}),
+		fizz.Response("404", "Container not found", nil, nil, map[string]interface{}{"error": "container not found"}),
+	}, backupHandler.SetBackupSchedule())
+
+	containers.DELETE("/:container_id/backups/schedule", []fizz.OperationOption{
+		fizz.ID("deleteContainerBackupSchedule"),
+		fizz.Summary("Delete container backup schedule"),
+	}, backupHandler.DeleteBackupSchedule())
+
+	containers.DELETE("/:container_id/backups/:backup_id", []fizz.OperationOption{
+		fizz.ID("deleteContainerBackup"),
+		fizz.Summary("Delete container backup"),
+		fizz.Response("404", "Backup not found", nil, nil, map[string]interface{}{"error": "backup not found"}),
+	}, backupHandler.DeleteBackup())
+
+	containers.POST("/:container_id/backups/:backup_id/restore", []fizz.OperationOption{
+		fizz.ID("restoreContainerBackup"),
+		fizz.Summary("Restore container backup"),
+		fizz.Description("Replace the content of the volumes of a container with a backup. The container is stopped during the restore."),
+		fizz.Response("400", "Backup corrupted", nil, nil, map[string]interface{}{"error": "backup checksum not valid"}),
+		fizz.Response("404", "Backup not found", nil, nil, map[string]interface{}{"error": "backup not found"}),
+	}, backupHandler.RestoreBackup())
+
+	containers.GET("/:container_id/dependencies", []fizz.OperationOption{
+		fizz.ID("getContainerDependencies"),
+		fizz.Summary("Get container dependencies"),
+		fizz.Description("Get the containers that must be started before this container."),
+	}, depsHandler.GetDependencies())
+
+	containers.PUT("/:container_id/dependencies/:dependency_id", []fizz.OperationOption{
+		fizz.ID("setContainerDependency"),
+		fizz.Summary("Set container dependency"),
+		fizz.Description("Make a container depend on another container. When all containers are started, the container waits for its dependency to be running or healthy."),
+		fizz.Response("400", "Invalid dependency", nil, nil, map[string]interface{}{"error": "dependency cycle not valid"}),
+		fizz.Response("404", "Container not found", nil, nil, map[string]interface{}{"error": "container not found"}),
+	}, depsHandler.SetDependency())
+
+	containers.DELETE("/:container_id/dependencies/:dependency_id", []fizz.OperationOption{
+		fizz.ID("deleteContainerDependency"),
+		fizz.Summary("Delete container dependency"),
+		fizz.Response("500", "", nil, nil, map[string]interface{}{"error": "failed to delete dependency"}),
+	}, depsHandler.DeleteDependency())
+
+	containers.GET("/:container_id/networks", []fizz.OperationOption{
+		fizz.ID("getContainerNetworks"),
+		fizz.Summary("Get container networks"),
+		fizz.Description("Get the networks the container is attached to, with its aliases."),
+	}, networkHandler.GetContainerNetworks())
+
+	containers.PUT("/:container_id/networks/:network_id", []fizz.OperationOption{
+		fizz.ID("attachContainerNetwork"),
+		fizz.Summary("Attach container to network"),
+		fizz.Description("Attach a container to a network, or replace its aliases. A created container is reconnected immediately."),
+		fizz.Response("400", "Invalid alias", nil, nil, map[string]interface{}{"error": "invalid network alias: my alias: network not valid"}),
+		fizz.Response("404", "Network not found", nil, nil, map[string]interface{}{"error": "network not found"}),
+	}, networkHandler.AttachContainer())
+
+	containers.DELETE("/:container_id/networks/:network_id", []fizz.OperationOption{
+		fizz.ID("detachContainerNetwork"),
+		fizz.Summary("Detach container from network"),
+		fizz.Response("404", "Network not found", nil, nil, map[string]interface{}{"error": "network not found"}),
+	}, networkHandler.DetachContainer())
+
+	containers.GET("/unmanaged", []fizz.OperationOption{
+		fizz.ID("getUnmanagedContainers"),
+		fizz.Summary("Get unmanaged containers"),
+		fizz.Description("Get the Docker containers that were not created by Vertex."),
+	}, adoptHandler.GetUnmanagedContainers())
+
+	containers.POST("/unmanaged/:docker_id/adopt", []fizz.OperationOption{
+		fizz.ID("adoptContainer"),
+		fizz.Summary("Adopt an unmanaged container"),
+		fizz.Description("Import the image, environment, ports, volumes, capabilities and command of a Docker container not created by Vertex, and rename the Docker container so that Vertex manages it. The settings that could not be imported are listed in the response."),
+		fizz.Response("400", "Container already managed", nil, nil, map[string]interface{}{"error": "docker container already managed by vertex not valid"}),
+		fizz.Response("404", "Docker container not found", nil, nil, map[string]interface{}{"error": "docker container not found"}),
+	}, adoptHandler.Adopt())
+
+	containers.POST("/compose/import", []fizz.OperationOption{
+		fizz.ID("importCompose"),
+		fizz.Summary("Import a compose file"),
+		fizz.Description("Create a container for each service of a docker-compose file. The parts of the file that could not be imported are listed in the response."),
+		fizz.Response("400", "Invalid compose file", nil, nil, map[string]interface{}{"error": "compose file not valid"}),
+		fizz.Response("500", "", nil, nil, map[string]interface{}{"error": "failed to import compose file"}),
+	}, composeHandler.Import())
+
+	containers.POST("/compose/export", []fizz.OperationOption{
+		fizz.ID("exportCompose"),
+		fizz.Summary("Export containers to a compose file"),
+		fizz.Description("Convert containers into a docker-compose file. The parts of the containers that could not be exported are listed in the response."),
+		fizz.Response("404", "Container not found", nil, nil, map[string]interface{}{"error": "container not found"}),
+	}, composeHandler.Export())
+
 	containers.GET("/checkupdates", []fizz.OperationOption{
 		fizz.ID("checkForUpdates"),
 		fizz.Summary("Check for updates"),
 	}, containersHandler.CheckForUpdates())
 
+	containers.PUT("/:container_id/update/policy", []fizz.OperationOption{
+		fizz.ID("setContainerUpdatePolicy"),
+		fizz.Summary("Set container update policy"),
+		fizz.Description("Choose to be notified of updates, to update automatically in an optional daily maintenance window like 02:00-05:00, or to pin the current image."),
+		fizz.Response("400", "Invalid update policy", nil, nil, map[string]interface{}{"error": "update policy not valid"}),
+		fizz.Response("500", "", nil, nil, map[string]interface{}{"error": "failed to set update policy"}),
+	}, updateHandler.SetUpdatePolicy())
+
+	containers.POST("/:container_id/update/apply", []fizz.OperationOption{
+		fizz.ID("applyContainerUpdate"),
+		fizz.Summary("Update container"),
+		fizz.Description("Recreate the container on the latest image of its tag. It is rolled back automatically if it fails in the next minutes."),
+		fizz.Response("500", "", nil, nil, map[string]interface{}{"error": "failed to update container"}),
+	}, updateHandler.ApplyUpdate())
+
+	containers.POST("/:container_id/update/rollback", []fizz.OperationOption{
+		fizz.ID("rollbackContainerUpdate"),
+		fizz.Summary("Roll back container update"),
+		fizz.Description("Recreate the container on the image it used before its last update."),
+		fizz.Response("404", "No previous image", nil, nil, map[string]interface{}{"error": "previous image not found"}),
+		fizz.Response("500", "", nil, nil, map[string]interface{}{"error": "failed to roll back container"}),
+	}, updateHandler.Rollback())
+
+	containers.GET("/:container_id/template-update", []fizz.OperationOption{
+		fizz.ID("getContainerTemplateUpdate"),
+		fizz.Summary("Get container template update"),
+		fizz.Description("Get the changes of the template of the container since it was created or last updated: added, removed and updated environment variables and ports, added and removed volumes and capabilities, and image changes. Changes to values edited in the container are marked as edited."),
+		fizz.Response("400", "Not created from a template", nil, nil, map[string]interface{}{"error": "the container was not created from a template"}),
+		fizz.Response("404", "Template not found", nil, nil, map[string]interface{}{"error": "template not found"}),
+	}, upgradeHandler.GetTemplateUpdate())
+
+	containers.POST("/:container_id/template-update", []fizz.OperationOption{
+		fizz.ID("applyContainerTemplateUpdate"),
+		fizz.Summary("Apply container template update"),
+		fizz.Description("Apply the selected changes of the template to the container, or all of them if none is selected, and recreate it if it is running. The changes that are not selected are dismissed. Values edited in the container are kept."),
+		fizz.Response("400", "Unknown change", nil, nil, map[string]interface{}{"error": "unknown change env.LOG_LEVEL"}),
+		fizz.Response("404", "Template not found", nil, nil, map[string]interface{}{"error": "template not found"}),
+	}, upgradeHandler.ApplyTemplateUpdate())
+
 	containers.GET("/events", []fizz.OperationOption{
 		fizz.ID("events"),
 		fizz.Summary("Get events"),
 	}, middleware.SSE, containersHandler.ContainersEvents())
 
+	// Stacks
+
+	stacks.GET("", []fizz.OperationOption{
+		fizz.ID("getStacks"),
+		fizz.Summary("Get stacks"),
+	}, stacksHandler.GetStacks())
+
+	stacks.GET("/:stack_id", []fizz.OperationOption{
+		fizz.ID("getStack"),
+		fizz.Summary("Get a stack"),
+		fizz.Description("Get a stack with all its containers."),
+		fizz.Response("404", "Stack not found", nil, nil, map[string]interface{}{"error": "stack not found"}),
+	}, stacksHandler.GetStack())
+
+	stacks.POST("", []fizz.OperationOption{
+		fizz.ID("createStack"),
+		fizz.Summary("Create a stack"),
+		fizz.Description("Create a stack and all its containers from a stack template."),
+		fizz.Response("400", "Not a stack template", nil, nil, map[string]interface{}{"error": "stack template not valid"}),
+		fizz.Response("404", "Template not found", nil, nil, map[string]interface{}{"error": "template not found"}),
+		fizz.Response("500", "", nil, nil, map[string]interface{}{"error": "failed to create stack"}),
+	}, stacksHandler.CreateStack())
+
+	stacks.DELETE("/:stack_id", []fizz.OperationOption{
+		fizz.ID("deleteStack"),
+		fizz.Summary("Delete a stack"),
+		fizz.Description("Delete a stack and all its containers."),
+		fizz.Response("404", "Stack not found", nil, nil, map[string]interface{}{"error": "stack not found"}),
+		fizz.Response("500", "", nil, nil, map[string]interface{}{"error": "container still running"}),
+	}, stacksHandler.DeleteStack())
+
+	stacks.POST("/:stack_id/start", []fizz.OperationOption{
+		fizz.ID("startStack"),
+		fizz.Summary("Start a stack"),
+		fizz.Description("Start all the containers of a stack, in the order of their dependencies."),
+		fizz.Response("404", "Stack not found", nil, nil, map[string]interface{}{"error": "stack not found"}),
+		fizz.Response("500", "", nil, nil, map[string]interface{}{"error": "failed to start stack"}),
+	}, stacksHandler.StartStack())
+
+	stacks.POST("/:stack_id/stop", []fizz.OperationOption{
+		fizz.ID("stopStack"),
+		fizz.Summary("Stop a stack"),
+		fizz.Response("404", "Stack not found", nil, nil, map[string]interface{}{"error": "stack not found"}),
+		fizz.Response("500", "", nil, nil, map[string]interface{}{"error": "failed to stop stack"}),
+	}, stacksHandler.StopStack())
+
+	// Registries
+
+	registries.GET("/credentials", []fizz.OperationOption{
+		fizz.ID("getRegistryCredentials"),
+		fizz.Summary("Get registry credentials"),
+		fizz.Description("Get the credentials used to pull images from private registries. Passwords are never returned."),
+	}, registryHandler.GetRegistryCredentials())
+
+	registries.POST("/credentials", []fizz.OperationOption{
+		fizz.ID("createRegistryCredential"),
+		fizz.Summary("Create registry credential"),
+		fizz.Description("Store the credential of a registry, like ghcr.io or docker.io. The password is encrypted at rest."),
+		fizz.Response("400", "Invalid registry credential", nil, nil, map[string]interface{}{"error": "registry not valid"}),
+		fizz.Response("409", "Registry credential already exists", nil, nil, map[string]interface{}{"error": "registry credential already exists"}),
+	}, registryHandler.CreateRegistryCredential())
+
+	registries.PATCH("/credentials/:credential_id", []fizz.OperationOption{
+		fizz.ID("patchRegistryCredential"),
+		fizz.Summary("Patch registry credential"),
+		fizz.Response("404", "Registry credential not found", nil, nil, map[string]interface{}{"error": "registry credential not found"}),
+	}, registryHandler.PatchRegistryCredential())
+
+	registries.DELETE("/credentials/:credential_id", []fizz.OperationOption{
+		fizz.ID("deleteRegistryCredential"),
+		fizz.Summary("Delete registry credential"),
+		fizz.Response("404", "Registry credential not found", nil, nil, map[string]interface{}{"error": "registry credential not found"}),
+	}, registryHandler.DeleteRegistryCredential())
+
+	// Networks
+
+	networks.GET("", []fizz.OperationOption{
+		fizz.ID("getNetworks"),
+		fizz.Summary("Get networks"),
+	}, networkHandler.GetNetworks())
+
+	networks.POST("", []fizz.OperationOption{
+		fizz.ID("createNetwork"),
+		fizz.Summary("Create network"),
+		fizz.Description("Create a bridge network. The containers attached to the same network can reach each other by their aliases."),
+		fizz.Response("409", "Network already exists", nil, nil, map[string]interface{}{"error": "network already exists"}),
+	}, networkHandler.CreateNetwork())
+
+	networks.DELETE("/:network_id", []fizz.OperationOption{
+		fizz.ID("deleteNetwork"),
+		fizz.Summary("Delete network"),
+		fizz.Response("400", "Network in use", nil, nil, map[string]interface{}{"error": "detach the containers of the network before deleting it: network in use not valid"}),
+		fizz.Response("404", "Network not found", nil, nil, map[string]interface{}{"error": "network not found"}),
+	}, networkHandler.DeleteNetwork())
+
 	// Environment
 
 	environments.GET("", []fizz.OperationOption{
@@ -236,9 +648,24 @@ func (a *App) InitializeRouter(r *fizz.RouterGroup) error {
 		fizz.Response("500", "", nil, nil, map[string]interface{}{"error": "failed to get container environment"}),
 	}, envHandler.GetEnv())
 
+	environments.GET("/:env_id/reveal", []fizz.OperationOption{
+		fizz.ID("revealEnvironment"),
+		fizz.Summary("Reveal an environment variable"),
+		fizz.Description("Get an environment variable with its value, even if it is a secret. Secret values are redacted everywhere else."),
+		fizz.Response("404", "Environment variable not found", nil, nil, map[string]interface{}{"error": "environment variable not found"}),
+	}, envHandler.RevealEnv())
+
+	environments.POST("/rotate-key", []fizz.OperationOption{
+		fizz.ID("rotateEnvironmentKey"),
+		fizz.Summary("Rotate the key of the secrets"),
+		fizz.Description("Encrypt all the secret environment variables with a new key, and delete the previous one."),
+	}, envHandler.RotateKey())
+
 	environments.PATCH("/:env_id", []fizz.OperationOption{
 		fizz.ID("patchEnvironment"),
 		fizz.Summary("Patch an environment variable"),
+		fizz.Description("Values of variables of containers created from a template are validated against the type and constraints of the variable in the template."),
+		fizz.Response("400", "Invalid value", nil, nil, map[string]interface{}{"error": "LOG_LEVEL: 'trace' is not one of [debug info]: environment variable value not valid"}),
 		fizz.Response("404", "Environment variable not found", nil, nil, map[string]interface{}{"error": "environment variable not found"}),
 		fizz.Response("500", "", nil, nil, map[string]interface{}{"error": "failed to patch container environment"}),
 	}, envHandler.PatchEnv())
@@ -268,6 +695,7 @@ func (a *App) InitializeRouter(r *fizz.RouterGroup) error {
 		fizz.ID("patchPort"),
 		fizz.Summary("Patch ports"),
 		fizz.Response("404", "Port not found", nil, nil, map[string]interface{}{"error": "port not found"}),
+		fizz.Response("409", "Port already used", nil, nil, map[string]interface{}{"error": "port 8080/tcp already used on the host"}),
 		fizz.Response("500", "", nil, nil, map[string]interface{}{"error": "failed to patch port"}),
 	}, portsHandler.PatchPort())
 
@@ -281,6 +709,8 @@ func (a *App) InitializeRouter(r *fizz.RouterGroup) error {
 	ports.POST("", []fizz.OperationOption{
 		fizz.ID("createPort"),
 		fizz.Summary("Create port"),
+		fizz.Description("Expose a port, or a range of ports like 8000-8010. The host ports must not be used by another container or by the host."),
+		fizz.Response("409", "Port already used", nil, nil, map[string]interface{}{"error": "port 8080/tcp already used on the host"}),
 		fizz.Response("500", "", nil, nil, map[string]interface{}{"error": "failed to create port"}),
 	}, portsHandler.CreatePort())
 
@@ -318,6 +748,55 @@ func (a *App) InitializeRouter(r *fizz.RouterGroup) error {
 		fizz.Summary("Get templates"),
 	}, authmiddleware.Authenticated, templatesHandler.GetTemplates())
 
+	templates.POST("", []fizz.OperationOption{
+		fizz.ID("createTemplate"),
+		fizz.Summary("Create template"),
+		fizz.Description("Save a template, either from a template definition or from the configuration of a container. Templates created by users replace the templates of the sources with the same ID, and their ID can't be one already used."),
+		fizz.Response("400", "Invalid template", nil, nil, map[string]interface{}{"error": "version 4 is not supported, the latest version is 3: template not valid"}),
+		fizz.Response("404", "Container not found", nil, nil, map[string]interface{}{"error": "container not found"}),
+	}, authmiddleware.Authenticated, templatesHandler.CreateTemplate())
+
+	templates.POST("/validate", []fizz.OperationOption{
+		fizz.ID("validateTemplate"),
+		fizz.Summary("Validate template"),
+		fizz.Description("Check a template file, given as YAML in content, without saving it. All the problems found are returned, with the path of the faulty field."),
+	}, authmiddleware.Authenticated, templatesHandler.ValidateTemplate())
+
+	templates.DELETE("/:template_id", []fizz.OperationOption{
+		fizz.ID("deleteTemplate"),
+		fizz.Summary("Delete template"),
+		fizz.Description("Delete a template created by the current user. Templates of the sources can't be deleted."),
+		fizz.Response("403", "Template of another user", nil, nil, map[string]interface{}{"error": "the template belongs to another user"}),
+		fizz.Response("404", "Template not found", nil, nil, map[string]interface{}{"error": "template not found"}),
+	}, authmiddleware.Authenticated, templatesHandler.DeleteTemplate())
+
+	templates.GET("/sources", []fizz.OperationOption{
+		fizz.ID("getTemplateSources"),
+		fizz.Summary("Get template sources"),
+		fizz.Description("Get the sources the templates are read from, with the status of their latest refresh. Templates of sources with a higher priority replace those with the same ID."),
+	}, authmiddleware.Authenticated, templatesHandler.GetSources())
+
+	templates.POST("/sources", []fizz.OperationOption{
+		fizz.ID("createTemplateSource"),
+		fizz.Summary("Create template source"),
+		fizz.Description("Add a local directory, a git repository or an HTTP index file as a template source. If a public key is set, the catalog must be signed with it. The source is read in the background."),
+		fizz.Response("400", "Invalid template source", nil, nil, map[string]interface{}{"error": "template source not valid"}),
+		fizz.Response("409", "Template source already exists", nil, nil, map[string]interface{}{"error": "template source already exists"}),
+	}, authmiddleware.Authenticated, templatesHandler.CreateSource())
+
+	templates.DELETE("/sources/:source_id", []fizz.OperationOption{
+		fizz.ID("deleteTemplateSource"),
+		fizz.Summary("Delete template source"),
+		fizz.Response("400", "Built-in template source", nil, nil, map[string]interface{}{"error": "built-in template source not valid"}),
+		fizz.Response("404", "Template source not found", nil, nil, map[string]interface{}{"error": "template source not found"}),
+	}, authmiddleware.Authenticated, templatesHandler.DeleteSource())
+
+	templates.POST("/sources/refresh", []fizz.OperationOption{
+		fizz.ID("refreshTemplateSources"),
+		fizz.Summary("Refresh template sources"),
+		fizz.Description("Start a reload of the templates of all the sources, in the background. Sources are also refreshed every hour."),
+	}, authmiddleware.Authenticated, templatesHandler.RefreshSources())
+
 	templates.GinRouterGroup().Static("/icons", "./live/services/icons")
 
 	return nil
@@ -365,6 +844,23 @@ func (a *App) InitializeKernelRouter(r *fizz.RouterGroup) error {
 		fizz.Summary("Get container info"),
 	}, dockerHandler.InfoContainer())
 
+	docker.GET("/containers/:id/inspect", []fizz.OperationOption{
+		fizz.ID("inspectContainer"),
+		fizz.Summary("Inspect container"),
+		fizz.Description("Get the configuration of a container, and the configuration it inherits from its image."),
+	}, dockerHandler.InspectContainer())
+
+	docker.POST("/containers/:id/rename", []fizz.OperationOption{
+		fizz.ID("renameContainer"),
+		fizz.Summary("Rename container"),
+	}, dockerHandler.RenameContainer())
+
+	docker.GET("/containers/:id/stats", []fizz.OperationOption{
+		fizz.ID("statsContainer"),
+		fizz.Summary("Get container stats"),
+		fizz.Description("Get the CPU, memory, network and block I/O usage of a container."),
+	}, dockerHandler.StatsContainer())
+
 	docker.GET("/containers/:id/logs/stdout", []fizz.OperationOption{
 		fizz.ID("logsStdoutContainer"),
 		fizz.Summary("Get container stdout logs"),
@@ -382,6 +878,12 @@ func (a *App) InitializeKernelRouter(r *fizz.RouterGroup) error {
 		fizz.Summary("Wait container"),
 	}, dockerHandler.WaitContainer())
 
+	docker.GET("/containers/:id/exec", []fizz.OperationOption{
+		fizz.ID("execContainer"),
+		fizz.Summary("Exec in container"),
+		fizz.Description("Open a websocket to an interactive command running with a TTY in a container."),
+	}, dockerHandler.ExecContainer())
+
 	docker.DELETE("/containers/:id/mounts", []fizz.OperationOption{
 		fizz.ID("deleteMounts"),
 		fizz.Summary("Delete mounts"),
@@ -412,5 +914,37 @@ func (a *App) InitializeKernelRouter(r *fizz.RouterGroup) error {
 		fizz.Summary("Delete volume"),
 	}, dockerHandler.DeleteVolume())
 
+	docker.POST("/volumes/archive", []fizz.OperationOption{
+		fizz.ID("archiveVolume"),
+		fizz.Summary("Archive volume"),
+		fizz.Description("Write the content of a volume or a bind mount into a tar.gz archive."),
+	}, dockerHandler.ArchiveVolume())
+
+	docker.POST("/volumes/restore", []fizz.OperationOption{
+		fizz.ID("restoreVolume"),
+		fizz.Summary("Restore volume"),
+		fizz.Description("Replace the content of a volume or a bind mount with a tar.gz archive."),
+	}, dockerHandler.RestoreVolume())
+
+	docker.POST("/networks", []fizz.OperationOption{
+		fizz.ID("createNetwork"),
+		fizz.Summary("Create network"),
+	}, dockerHandler.CreateNetwork())
+
+	docker.DELETE("/networks", []fizz.OperationOption{
+		fizz.ID("deleteNetwork"),
+		fizz.Summary("Delete network"),
+	}, dockerHandler.DeleteNetwork())
+
+	docker.POST("/networks/connect", []fizz.OperationOption{
+		fizz.ID("connectNetwork"),
+		fizz.Summary("Connect container to network"),
+	}, dockerHandler.ConnectNetwork())
+
+	docker.POST("/networks/disconnect", []fizz.OperationOption{
+		fizz.ID("disconnectNetwork"),
+		fizz.Summary("Disconnect container from network"),
+	}, dockerHandler.DisconnectNetwork())
+
 	return nil
 }
@@ -22,6 +22,14 @@ type (
 		AddTag(ctx context.Context, id uuid.UUID, tagID uuid.UUID) error
 		DeleteTags(ctx context.Context, id uuid.UUID) error
 		SetStatus(ctx context.Context, id uuid.UUID, status string) error
+		SetStoppedByUser(ctx context.Context, id uuid.UUID, stopped bool) error
+	}
+
+	StackAdapter interface {
+		GetStack(ctx context.Context, id uuid.UUID) (*types.Stack, error)
+		GetStacks(ctx context.Context) (types.Stacks, error)
+		CreateStack(ctx context.Context, stack types.Stack) error
+		DeleteStack(ctx context.Context, id uuid.UUID) error
 	}
 
 	PortAdapter interface {
@@ -32,9 +40,14 @@ type (
 		UpdatePortByID(ctx context.Context, port types.Port) error
 	}
 
+	HostPortsAdapter interface {
+		IsPortFree(protocol types.PortProtocol, port int) bool
+	}
+
 	VolumeAdapter interface {
 		GetContainerVolumes(ctx context.Context, id uuid.UUID) (types.Volumes, error)
 		CreateVolume(ctx context.Context, vol types.Volume) error
+		DeleteVolume(ctx context.Context, id uuid.UUID) error
 		DeleteContainerVolumes(ctx context.Context, id uuid.UUID) error
 	}
 
@@ -45,6 +58,7 @@ type (
 	}
 
 	EnvAdapter interface {
+		GetEnv(ctx context.Context, id uuid.UUID) (*types.EnvVariable, error)
 		GetEnvs(ctx context.Context, filters types.EnvVariableFilters) ([]types.EnvVariable, error)
 		CreateEnv(ctx context.Context, variable types.EnvVariable) error
 		DeleteEnv(ctx context.Context, id uuid.UUID) error
@@ -56,9 +70,71 @@ type (
 	CapAdapter interface {
 		GetContainerCaps(ctx context.Context, id uuid.UUID) (types.Capabilities, error)
 		CreateCap(ctx context.Context, c types.Capability) error
+		DeleteCap(ctx context.Context, id uuid.UUID) error
 		DeleteContainerCaps(ctx context.Context, id uuid.UUID) error
 	}
 
+	HealthCheckAdapter interface {
+		GetContainerHealthCheck(ctx context.Context, id uuid.UUID) (*types.HealthCheck, error)
+		SetContainerHealthCheck(ctx context.Context, hc types.HealthCheck) error
+		DeleteContainerHealthCheck(ctx context.Context, id uuid.UUID) error
+	}
+
+	ResourceLimitsAdapter interface {
+		GetContainerResourceLimits(ctx context.Context, id uuid.UUID) (*types.ResourceLimits, error)
+		SetContainerResourceLimits(ctx context.Context, limits types.ResourceLimits) error
+		DeleteContainerResourceLimits(ctx context.Context, id uuid.UUID) error
+	}
+
+	BuildAdapter interface {
+		GetContainerBuild(ctx context.Context, id uuid.UUID) (*types.ContainerBuild, error)
+		SetContainerBuild(ctx context.Context, build types.ContainerBuild) error
+		DeleteContainerBuild(ctx context.Context, id uuid.UUID) error
+	}
+
+	BackupAdapter interface {
+		GetBackups(ctx context.Context, containerID uuid.UUID) (types.Backups, error)
+		GetBackup(ctx context.Context, id uuid.UUID) (*types.Backup, error)
+		GetLatestBackupVersion(ctx context.Context, containerID uuid.UUID) (int, error)
+		CreateBackup(ctx context.Context, backup types.Backup) error
+		DeleteBackup(ctx context.Context, id uuid.UUID) error
+		GetBackupSchedules(ctx context.Context) ([]types.BackupSchedule, error)
+		GetBackupSchedule(ctx context.Context, containerID uuid.UUID) (*types.BackupSchedule, error)
+		SetBackupSchedule(ctx context.Context, schedule types.BackupSchedule) error
+		DeleteBackupSchedule(ctx context.Context, containerID uuid.UUID) error
+	}
+
+	RegistryCredentialAdapter interface {
+		GetRegistryCredentials(ctx context.Context) (types.RegistryCredentials, error)
+		GetRegistryCredential(ctx context.Context, id uuid.UUID) (*types.RegistryCredential, error)
+		GetRegistryCredentialByRegistry(ctx context.Context, registry string) (*types.RegistryCredential, error)
+		CreateRegistryCredential(ctx context.Context, cred types.RegistryCredential) error
+		UpdateRegistryCredential(ctx context.Context, cred types.RegistryCredential) error
+		DeleteRegistryCredential(ctx context.Context, id uuid.UUID) error
+		EncryptRegistryCredentials(ctx context.Context) error
+	}
+
+	DependencyAdapter interface {
+		GetDependencies(ctx context.Context) (types.Dependencies, error)
+		GetContainerDependencies(ctx context.Context, id uuid.UUID) (types.Dependencies, error)
+		SetDependency(ctx context.Context, dep types.Dependency) error
+		DeleteDependency(ctx context.Context, id uuid.UUID, dependsOnID uuid.UUID) error
+		DeleteContainerDependencies(ctx context.Context, id uuid.UUID) error
+	}
+
+	NetworkAdapter interface {
+		GetNetworks(ctx context.Context) (types.Networks, error)
+		GetNetwork(ctx context.Context, id uuid.UUID) (*types.Network, error)
+		GetNetworkByName(ctx context.Context, name string) (*types.Network, error)
+		CreateNetwork(ctx context.Context, n types.Network) error
+		DeleteNetwork(ctx context.Context, id uuid.UUID) error
+		GetContainerNetworks(ctx context.Context, containerID uuid.UUID) (types.ContainerNetworks, error)
+		GetNetworkContainers(ctx context.Context, networkID uuid.UUID) (types.ContainerNetworks, error)
+		SetContainerNetwork(ctx context.Context, n types.ContainerNetwork) error
+		DeleteContainerNetwork(ctx context.Context, containerID uuid.UUID, networkID uuid.UUID) error
+		DeleteContainerNetworks(ctx context.Context, containerID uuid.UUID) error
+	}
+
 	TagAdapter interface {
 		GetTag(ctx context.Context, userID uuid.UUID, name string) (types.Tag, error)
 		GetTags(ctx context.Context, userID uuid.UUID) (types.Tags, error)
@@ -73,20 +149,41 @@ type (
 		Push(id uuid.UUID, line types.LogLine)
 		Pop(id uuid.UUID) (types.LogLine, error)
 		LoadBuffer(id uuid.UUID) ([]types.LogLine, error) // LoadBuffer loads the latest logs kept in memory.
+		Search(id uuid.UUID, filters types.LogFilters) ([]types.LogLine, error)
+		Archive(id uuid.UUID, w io.Writer) error
 		Exists(id uuid.UUID) bool
 	}
 
 	RunnerAdapter interface {
 		GetDockerContainers(ctx context.Context) ([]types.DockerContainer, error)
+		InspectDockerContainer(ctx context.Context, id string) (types.InspectContainerResponse, error)
+		AdoptDockerContainer(ctx context.Context, id string, c types.Container) error
 		DeleteContainer(ctx context.Context, c *types.Container, volumes []string) error
 		DeleteMounts(ctx context.Context, c *types.Container) error
-		Start(ctx context.Context, c *types.Container, ports types.Ports, volumes types.Volumes, env []types.EnvVariable, caps types.Capabilities, sysctls types.Sysctls, setStatus func(status string)) (stdout io.ReadCloser, stderr io.ReadCloser, err error)
+		Start(ctx context.Context, c *types.Container, ports types.Ports, volumes types.Volumes, env []types.EnvVariable, caps types.Capabilities, sysctls types.Sysctls, healthCheck *types.HealthCheck, limits *types.ResourceLimits, build *types.ContainerBuild, networks []types.NetworkAttachment, setStatus func(status string)) (stdout io.ReadCloser, stderr io.ReadCloser, err error)
 		Stop(ctx context.Context, c *types.Container) error
 		Info(ctx context.Context, c types.Container) (map[string]any, error)
+		Stats(ctx context.Context, c types.Container) (types.ContainerStats, error)
+		ExitCode(ctx context.Context, c types.Container) (int, error)
+		CreateNetwork(ctx context.Context, name string) error
+		DeleteNetwork(ctx context.Context, name string) error
+		ConnectNetwork(ctx context.Context, c types.Container, network types.NetworkAttachment) error
+		DisconnectNetwork(ctx context.Context, c types.Container, name string) error
 		WaitCondition(ctx context.Context, c *types.Container, cond types.WaitContainerCondition) error
 		CheckForUpdates(ctx context.Context, c *types.Container) error
+		GetImageID(ctx context.Context, c types.Container) (string, error)
 		HasUpdateAvailable(ctx context.Context, c types.Container) (bool, error)
 		GetAllVersions(ctx context.Context, c types.Container) ([]string, error)
+		CreateVolume(ctx context.Context, name string) error
+		ArchiveVolume(ctx context.Context, v types.Volume, dest string) (types.ArchiveVolumeResponse, error)
+		RestoreVolume(ctx context.Context, v types.BackupVolume) error
+		Exec(ctx context.Context, c types.Container, options types.ExecOptions) (ExecSession, error)
+	}
+
+	// ExecSession is an interactive command running in a container with a TTY.
+	ExecSession interface {
+		io.ReadWriteCloser
+		Resize(cols, rows uint) error
 	}
 
 	TemplateAdapter interface {
@@ -94,6 +191,31 @@ type (
 		GetRaw(id string) (interface{}, error)
 		GetAll() []types.Template
 		Reload() error
+
+		GetSources() types.TemplateSources
+		SetSources(sources types.TemplateSources)
+		DeleteSourceFiles(id uuid.UUID) error
+
+		SetUserTemplates(templates []types.Template)
+	}
+
+	UserTemplateAdapter interface {
+		GetTemplates(ctx context.Context) ([]types.Template, error)
+		GetTemplate(ctx context.Context, id string) (*types.Template, error)
+		CreateTemplate(ctx context.Context, template types.Template) error
+		DeleteTemplate(ctx context.Context, id string) error
+	}
+
+	TemplateRevisionAdapter interface {
+		GetRevision(ctx context.Context, templateID string, revision string) (*types.Template, error)
+		CreateRevision(ctx context.Context, revision string, template types.Template) error
+	}
+
+	TemplateSourceAdapter interface {
+		GetSources(ctx context.Context) (types.TemplateSources, error)
+		GetSource(ctx context.Context, id uuid.UUID) (*types.TemplateSource, error)
+		CreateSource(ctx context.Context, source types.TemplateSource) error
+		DeleteSource(ctx context.Context, id uuid.UUID) error
 	}
 
 	DockerAdapter interface {
@@ -103,6 +225,9 @@ type (
 		StartContainer(id string) error
 		StopContainer(id string) error
 		InfoContainer(id string) (types.InfoContainerResponse, error)
+		InspectContainer(id string) (types.InspectContainerResponse, error)
+		RenameContainer(id string, name string) error
+		StatsContainer(id string) (types.ContainerStats, error)
 		LogsStdoutContainer(id string) (io.ReadCloser, error)
 		LogsStderrContainer(id string) (io.ReadCloser, error)
 		WaitContainer(id string, cond types.WaitContainerCondition) error
@@ -111,5 +236,11 @@ type (
 		BuildImage(options types.BuildImageOptions) (dockertypes.ImageBuildResponse, error)
 		CreateVolume(options types.CreateVolumeOptions) (volume.Volume, error)
 		DeleteVolume(name string) error
+		GetVolumeMountpoint(name string) (string, error)
+		ExecContainer(id string, options types.ExecOptions) (ExecSession, error)
+		CreateNetwork(options types.CreateNetworkOptions) error
+		DeleteNetwork(name string) error
+		ConnectNetwork(options types.ConnectNetworkOptions) error
+		DisconnectNetwork(options types.DisconnectNetworkOptions) error
 	}
 )
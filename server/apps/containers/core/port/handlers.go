@@ -16,17 +16,42 @@ type (
 		AddContainerTag() gin.HandlerFunc
 		GetDocker() gin.HandlerFunc
 		RecreateDocker() gin.HandlerFunc
+		DuplicateContainer() gin.HandlerFunc
 		ReloadContainer() gin.HandlerFunc
+		RebuildContainer() gin.HandlerFunc
 		GetLogs() gin.HandlerFunc
+		DownloadLogs() gin.HandlerFunc
 		GetVersions() gin.HandlerFunc
 		WaitStatus() gin.HandlerFunc
 		CheckForUpdates() gin.HandlerFunc
 		ContainerEvents() gin.HandlerFunc
+		ContainerStats() gin.HandlerFunc
 		ContainersEvents() gin.HandlerFunc
 	}
 
+	StackHandler interface {
+		GetStack() gin.HandlerFunc
+		GetStacks() gin.HandlerFunc
+		CreateStack() gin.HandlerFunc
+		DeleteStack() gin.HandlerFunc
+		StartStack() gin.HandlerFunc
+		StopStack() gin.HandlerFunc
+	}
+
+	AdoptHandler interface {
+		GetUnmanagedContainers() gin.HandlerFunc
+		Adopt() gin.HandlerFunc
+	}
+
+	ComposeHandler interface {
+		Import() gin.HandlerFunc
+		Export() gin.HandlerFunc
+	}
+
 	EnvHandler interface {
 		GetEnv() gin.HandlerFunc
+		RevealEnv() gin.HandlerFunc
+		RotateKey() gin.HandlerFunc
 		PatchEnv() gin.HandlerFunc
 		DeleteEnv() gin.HandlerFunc
 		CreateEnv() gin.HandlerFunc
@@ -39,9 +64,75 @@ type (
 		CreatePort() gin.HandlerFunc
 	}
 
+	HealthCheckHandler interface {
+		GetHealthCheck() gin.HandlerFunc
+		SetHealthCheck() gin.HandlerFunc
+		DeleteHealthCheck() gin.HandlerFunc
+	}
+
+	UpdateHandler interface {
+		SetUpdatePolicy() gin.HandlerFunc
+		ApplyUpdate() gin.HandlerFunc
+		Rollback() gin.HandlerFunc
+	}
+
+	ResourceLimitsHandler interface {
+		GetResourceLimits() gin.HandlerFunc
+		SetResourceLimits() gin.HandlerFunc
+		DeleteResourceLimits() gin.HandlerFunc
+	}
+
+	BackupHandler interface {
+		GetBackups() gin.HandlerFunc
+		CreateBackup() gin.HandlerFunc
+		DeleteBackup() gin.HandlerFunc
+		RestoreBackup() gin.HandlerFunc
+		GetBackupSchedule() gin.HandlerFunc
+		SetBackupSchedule() gin.HandlerFunc
+		DeleteBackupSchedule() gin.HandlerFunc
+	}
+
+	ExecHandler interface {
+		Exec() gin.HandlerFunc
+	}
+
+	RegistryHandler interface {
+		GetRegistryCredentials() gin.HandlerFunc
+		CreateRegistryCredential() gin.HandlerFunc
+		PatchRegistryCredential() gin.HandlerFunc
+		DeleteRegistryCredential() gin.HandlerFunc
+	}
+
+	DependencyHandler interface {
+		GetDependencies() gin.HandlerFunc
+		SetDependency() gin.HandlerFunc
+		DeleteDependency() gin.HandlerFunc
+	}
+
+	NetworkHandler interface {
+		GetNetworks() gin.HandlerFunc
+		CreateNetwork() gin.HandlerFunc
+		DeleteNetwork() gin.HandlerFunc
+		GetContainerNetworks() gin.HandlerFunc
+		AttachContainer() gin.HandlerFunc
+		DetachContainer() gin.HandlerFunc
+	}
+
 	TemplateHandler interface {
 		GetTemplate() gin.HandlerFunc
 		GetTemplates() gin.HandlerFunc
+		CreateTemplate() gin.HandlerFunc
+		ValidateTemplate() gin.HandlerFunc
+		DeleteTemplate() gin.HandlerFunc
+		GetSources() gin.HandlerFunc
+		CreateSource() gin.HandlerFunc
+		DeleteSource() gin.HandlerFunc
+		RefreshSources() gin.HandlerFunc
+	}
+
+	TemplateUpdateHandler interface {
+		GetTemplateUpdate() gin.HandlerFunc
+		ApplyTemplateUpdate() gin.HandlerFunc
 	}
 
 	TagsHandler interface {
@@ -58,14 +149,24 @@ type (
 		StartContainer() gin.HandlerFunc
 		StopContainer() gin.HandlerFunc
 		InfoContainer() gin.HandlerFunc
+		InspectContainer() gin.HandlerFunc
+		RenameContainer() gin.HandlerFunc
+		StatsContainer() gin.HandlerFunc
 		LogsStdoutContainer() gin.HandlerFunc
 		LogsStderrContainer() gin.HandlerFunc
 		WaitContainer() gin.HandlerFunc
+		ExecContainer() gin.HandlerFunc
 		DeleteMounts() gin.HandlerFunc
 		InfoImage() gin.HandlerFunc
 		PullImage() gin.HandlerFunc
 		BuildImage() gin.HandlerFunc
 		CreateVolume() gin.HandlerFunc
 		DeleteVolume() gin.HandlerFunc
+		ArchiveVolume() gin.HandlerFunc
+		RestoreVolume() gin.HandlerFunc
+		CreateNetwork() gin.HandlerFunc
+		DeleteNetwork() gin.HandlerFunc
+		ConnectNetwork() gin.HandlerFunc
+		DisconnectNetwork() gin.HandlerFunc
 	}
 )
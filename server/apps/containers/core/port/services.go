@@ -17,31 +17,60 @@ type (
 		GetContainers(ctx context.Context) (types.Containers, error)
 		GetContainersWithFilters(ctx context.Context, filters types.ContainerFilters) (types.Containers, error)
 		CreateContainer(ctx context.Context, opts types.CreateContainerOptions) (*types.Container, error)
+		DuplicateContainer(ctx context.Context, id uuid.UUID, opts types.DuplicateContainerOptions) (*types.Container, error)
 		Delete(ctx context.Context, id uuid.UUID) error
 		UpdateContainer(ctx context.Context, id uuid.UUID, c types.Container) error
 		Start(ctx context.Context, id uuid.UUID) error
 		StartAll(ctx context.Context) error
+		StartContainers(ctx context.Context, ids []uuid.UUID) error
 		Stop(ctx context.Context, id uuid.UUID) error
 		StopAll(ctx context.Context) error
+		StopContainers(ctx context.Context, ids []uuid.UUID) error
 		AddContainerTag(ctx context.Context, id uuid.UUID, tagID uuid.UUID) error
 		RecreateContainer(ctx context.Context, id uuid.UUID) error
+		RebuildContainer(ctx context.Context, id uuid.UUID) error
 		DeleteAll(ctx context.Context) error
 		CheckForUpdates(ctx context.Context) (types.Containers, error)
 		SetDatabases(ctx context.Context, c *types.Container, databases map[string]uuid.UUID, options map[string]*types.SetDatabasesOptions) error
 		GetAllVersions(ctx context.Context, id uuid.UUID, useCache bool) ([]string, error)
 		GetContainerInfo(ctx context.Context, id uuid.UUID) (map[string]any, error)
+		GetContainerStats(ctx context.Context, id uuid.UUID) (types.ContainerStats, error)
 		WaitStatus(ctx context.Context, id uuid.UUID, status string) error
 		GetLatestLogs(id uuid.UUID) ([]types.LogLine, error)
+		SearchLogs(ctx context.Context, id uuid.UUID, filters types.LogFilters) ([]types.LogLine, error)
+		DownloadLogs(ctx context.Context, id uuid.UUID, w io.Writer) error
 		GetTemplateByID(ctx context.Context, id string) (*types.Template, error)
 		GetTemplates(ctx context.Context) []types.Template
 		ReloadContainer(ctx context.Context, id uuid.UUID) error
 	}
 
+	StackService interface {
+		GetStack(ctx context.Context, id uuid.UUID) (*types.Stack, error)
+		GetStacks(ctx context.Context) (types.Stacks, error)
+		CreateStack(ctx context.Context, opts types.CreateStackOptions) (*types.Stack, error)
+		DeleteStack(ctx context.Context, id uuid.UUID) error
+		StartStack(ctx context.Context, id uuid.UUID) error
+		StopStack(ctx context.Context, id uuid.UUID) error
+	}
+
+	AdoptService interface {
+		GetUnmanagedContainers(ctx context.Context) ([]types.DockerContainer, error)
+		Adopt(ctx context.Context, dockerID string, opts types.AdoptContainerOptions) (*types.ContainerAdoption, error)
+	}
+
+	ComposeService interface {
+		Import(ctx context.Context, data []byte) (*types.ComposeImport, error)
+		Export(ctx context.Context, ids []uuid.UUID) (*types.ComposeExport, error)
+	}
+
 	EnvService interface {
 		GetEnvs(ctx context.Context, filters types.EnvVariableFilters) ([]types.EnvVariable, error)
+		RevealEnv(ctx context.Context, id uuid.UUID) (*types.EnvVariable, error)
 		PatchEnv(ctx context.Context, env types.EnvVariable) error
 		DeleteEnv(ctx context.Context, id uuid.UUID) error
 		CreateEnv(ctx context.Context, env types.EnvVariable) error
+		EncryptSecrets(ctx context.Context) error
+		RotateKey(ctx context.Context) error
 	}
 
 	MetricsService interface {
@@ -55,6 +84,82 @@ type (
 		CreatePort(ctx context.Context, p types.Port) error
 	}
 
+	HealthCheckService interface {
+		GetHealthCheck(ctx context.Context, containerID uuid.UUID) (*types.HealthCheck, error)
+		SetHealthCheck(ctx context.Context, hc types.HealthCheck) error
+		DeleteHealthCheck(ctx context.Context, containerID uuid.UUID) error
+	}
+
+	UpdateService interface {
+		SetUpdatePolicy(ctx context.Context, id uuid.UUID, policy types.UpdatePolicy, window *string) error
+		ApplyUpdate(ctx context.Context, id uuid.UUID) error
+		Rollback(ctx context.Context, id uuid.UUID) error
+	}
+
+	ResourceLimitsService interface {
+		GetResourceLimits(ctx context.Context, containerID uuid.UUID) (*types.ResourceLimits, error)
+		SetResourceLimits(ctx context.Context, limits types.ResourceLimits) error
+		DeleteResourceLimits(ctx context.Context, containerID uuid.UUID) error
+	}
+
+	BackupService interface {
+		GetBackups(ctx context.Context, containerID uuid.UUID) (types.Backups, error)
+		CreateBackup(ctx context.Context, containerID uuid.UUID, stop bool) (*types.Backup, error)
+		DeleteBackup(ctx context.Context, containerID uuid.UUID, id uuid.UUID) error
+		RestoreBackup(ctx context.Context, containerID uuid.UUID, id uuid.UUID) error
+		GetBackupSchedule(ctx context.Context, containerID uuid.UUID) (*types.BackupSchedule, error)
+		SetBackupSchedule(ctx context.Context, schedule types.BackupSchedule) error
+		DeleteBackupSchedule(ctx context.Context, containerID uuid.UUID) error
+	}
+
+	ExecService interface {
+		Exec(ctx context.Context, containerID uuid.UUID, userID uuid.UUID, options types.ExecOptions) (ExecSession, error)
+	}
+
+	RegistryService interface {
+		GetRegistryCredentials(ctx context.Context) (types.RegistryCredentials, error)
+		CreateRegistryCredential(ctx context.Context, cred types.RegistryCredential) (*types.RegistryCredential, error)
+		PatchRegistryCredential(ctx context.Context, id uuid.UUID, patch types.RegistryCredentialPatch) (*types.RegistryCredential, error)
+		DeleteRegistryCredential(ctx context.Context, id uuid.UUID) error
+	}
+
+	DependencyService interface {
+		GetDependencies(ctx context.Context, containerID uuid.UUID) (types.Dependencies, error)
+		SetDependency(ctx context.Context, dep types.Dependency) error
+		DeleteDependency(ctx context.Context, containerID uuid.UUID, dependsOnID uuid.UUID) error
+	}
+
+	NetworkService interface {
+		GetNetworks(ctx context.Context) (types.Networks, error)
+		CreateNetwork(ctx context.Context, name string) (*types.Network, error)
+		DeleteNetwork(ctx context.Context, id uuid.UUID) error
+		GetContainerNetworks(ctx context.Context, containerID uuid.UUID) (types.ContainerNetworks, error)
+		AttachContainer(ctx context.Context, n types.ContainerNetwork) error
+		DetachContainer(ctx context.Context, containerID uuid.UUID, networkID uuid.UUID) error
+	}
+
+	TemplateService interface {
+		CreateTemplate(ctx context.Context, userID uuid.UUID, template types.Template) (*types.Template, error)
+		ValidateTemplate(ctx context.Context, data []byte) types.TemplateLint
+		CreateTemplateFromContainer(ctx context.Context, userID uuid.UUID, containerID uuid.UUID, opts types.ContainerTemplateOptions) (*types.Template, error)
+		DeleteTemplate(ctx context.Context, userID uuid.UUID, id string) error
+		LoadUserTemplates(ctx context.Context) error
+	}
+
+	TemplateUpdateService interface {
+		GetTemplateUpdate(ctx context.Context, containerID uuid.UUID) (*types.TemplateUpdate, error)
+		ApplyTemplateUpdate(ctx context.Context, containerID uuid.UUID, opts types.ApplyTemplateUpdateOptions) error
+		TrackRevisions(ctx context.Context) error
+	}
+
+	TemplateSourceService interface {
+		GetSources(ctx context.Context) types.TemplateSources
+		CreateSource(ctx context.Context, source types.TemplateSource) (*types.TemplateSource, error)
+		DeleteSource(ctx context.Context, id uuid.UUID) error
+		RefreshSources(ctx context.Context) types.TemplateSources
+		LoadSources(ctx context.Context) error
+	}
+
 	TagsService interface {
 		GetTag(ctx context.Context, userID uuid.UUID, name string) (types.Tag, error)
 		GetTags(ctx context.Context, userID uuid.UUID) (types.Tags, error)
@@ -69,6 +174,9 @@ type (
 		StartContainer(id string) error
 		StopContainer(id string) error
 		InfoContainer(id string) (types.InfoContainerResponse, error)
+		InspectContainer(id string) (types.InspectContainerResponse, error)
+		RenameContainer(id string, name string) error
+		StatsContainer(id string) (types.ContainerStats, error)
 		LogsStdoutContainer(id string) (io.ReadCloser, error)
 		LogsStderrContainer(id string) (io.ReadCloser, error)
 		WaitContainer(id string, cond types.WaitContainerCondition) error
@@ -78,5 +186,12 @@ type (
 		BuildImage(options types.BuildImageOptions) (vtypes.ImageBuildResponse, error)
 		CreateVolume(name string) (volume.Volume, error)
 		DeleteVolume(name string) error
+		ArchiveVolume(options types.ArchiveVolumeOptions) (types.ArchiveVolumeResponse, error)
+		RestoreVolume(options types.RestoreVolumeOptions) error
+		ExecContainer(id string, options types.ExecOptions) (ExecSession, error)
+		CreateNetwork(name string) error
+		DeleteNetwork(name string) error
+		ConnectNetwork(options types.ConnectNetworkOptions) error
+		DisconnectNetwork(options types.DisconnectNetworkOptions) error
 	}
 )
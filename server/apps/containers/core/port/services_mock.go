@@ -2,6 +2,7 @@ package port
 
 import (
 	"context"
+	"io"
 
 	"github.com/stretchr/testify/mock"
 	"github.com/vertex-center/uuid"
@@ -44,6 +45,14 @@ func (m *MockContainerService) CreateContainer(ctx context.Context, opts types.C
 	return args.Get(0).(*types.Container), args.Error(1)
 }
 
+func (m *MockContainerService) DuplicateContainer(ctx context.Context, id uuid.UUID, opts types.DuplicateContainerOptions) (*types.Container, error) {
+	args := m.Called(ctx, id, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*types.Container), args.Error(1)
+}
+
 func (m *MockContainerService) Delete(ctx context.Context, uuid uuid.UUID) error {
 	args := m.Called(ctx, uuid)
 	return args.Error(0)
@@ -69,11 +78,21 @@ func (m *MockContainerService) StartAll(ctx context.Context) error {
 	return args.Error(0)
 }
 
+func (m *MockContainerService) StartContainers(ctx context.Context, ids []uuid.UUID) error {
+	args := m.Called(ctx, ids)
+	return args.Error(0)
+}
+
 func (m *MockContainerService) StopAll(ctx context.Context) error {
 	args := m.Called(ctx)
 	return args.Error(0)
 }
 
+func (m *MockContainerService) StopContainers(ctx context.Context, ids []uuid.UUID) error {
+	args := m.Called(ctx, ids)
+	return args.Error(0)
+}
+
 func (m *MockContainerService) LoadAll(ctx context.Context) error {
 	args := m.Called(ctx)
 	return args.Error(0)
@@ -119,6 +138,11 @@ func (m *MockContainerService) RecreateContainer(ctx context.Context, uuid uuid.
 	return args.Error(0)
 }
 
+func (m *MockContainerService) RebuildContainer(ctx context.Context, uuid uuid.UUID) error {
+	args := m.Called(ctx, uuid)
+	return args.Error(0)
+}
+
 func (m *MockContainerService) CheckForUpdates(ctx context.Context) (types.Containers, error) {
 	args := m.Called(ctx)
 	return args.Get(0).(types.Containers), args.Error(1)
@@ -134,6 +158,11 @@ func (m *MockContainerService) GetAllVersions(ctx context.Context, id uuid.UUID,
 	return args.Get(0).([]string), args.Error(1)
 }
 
+func (m *MockContainerService) GetContainerStats(ctx context.Context, id uuid.UUID) (types.ContainerStats, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(types.ContainerStats), args.Error(1)
+}
+
 func (m *MockContainerService) GetContainerInfo(ctx context.Context, id uuid.UUID) (map[string]any, error) {
 	args := m.Called(ctx, id)
 	return args.Get(0).(map[string]any), args.Error(1)
@@ -149,6 +178,16 @@ func (m *MockContainerService) GetLatestLogs(id uuid.UUID) ([]types.LogLine, err
 	return args.Get(0).([]types.LogLine), args.Error(1)
 }
 
+func (m *MockContainerService) SearchLogs(ctx context.Context, id uuid.UUID, filters types.LogFilters) ([]types.LogLine, error) {
+	args := m.Called(ctx, id, filters)
+	return args.Get(0).([]types.LogLine), args.Error(1)
+}
+
+func (m *MockContainerService) DownloadLogs(ctx context.Context, id uuid.UUID, w io.Writer) error {
+	args := m.Called(ctx, id, w)
+	return args.Error(0)
+}
+
 func (m *MockContainerService) GetTemplateByID(ctx context.Context, id string) (*types.Template, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
@@ -0,0 +1,258 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/vertex-center/uuid"
+	"github.com/vertex-center/vertex/server/apps/containers/core/port"
+	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+	"github.com/vertex-center/vertex/server/common/log"
+	"github.com/vertex-center/vlog"
+)
+
+type adoptService struct {
+	containerService port.ContainerService
+	containers       port.ContainerAdapter
+	vars             port.EnvAdapter
+	ports            port.PortAdapter
+	volumes          port.VolumeAdapter
+	caps             port.CapAdapter
+	sysctls          port.SysctlAdapter
+	runner           port.RunnerAdapter
+}
+
+func NewAdoptService(
+	containerService port.ContainerService,
+	containers port.ContainerAdapter,
+	vars port.EnvAdapter,
+	ports port.PortAdapter,
+	volumes port.VolumeAdapter,
+	caps port.CapAdapter,
+	sysctls port.SysctlAdapter,
+	runner port.RunnerAdapter,
+) port.AdoptService {
+	return &adoptService{
+		containerService: containerService,
+		containers:       containers,
+		vars:             vars,
+		ports:            ports,
+		volumes:          volumes,
+		caps:             caps,
+		sysctls:          sysctls,
+		runner:           runner,
+	}
+}
+
+// GetUnmanagedContainers returns the Docker containers not created by Vertex.
+func (s *adoptService) GetUnmanagedContainers(ctx context.Context) ([]types.DockerContainer, error) {
+	all, err := s.runner.GetDockerContainers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	containers := []types.DockerContainer{}
+	for _, dc := range all {
+		if !dc.IsManaged() {
+			containers = append(containers, dc)
+		}
+	}
+	return containers, nil
+}
+
+// Adopt imports the configuration of a Docker container not created by
+// Vertex, and renames the Docker container so that Vertex manages it. The
+// Docker container keeps running, and is recreated from the imported
+// configuration like any other container.
+func (s *adoptService) Adopt(ctx context.Context, dockerID string, opts types.AdoptContainerOptions) (*types.ContainerAdoption, error) {
+	dc, err := s.getDockerContainer(ctx, dockerID)
+	if err != nil {
+		return nil, err
+	}
+	if dc.IsManaged() {
+		return nil, types.ErrDockerContainerManaged
+	}
+
+	info, err := s.runner.InspectDockerContainer(ctx, dc.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &types.ContainerAdoption{
+		Unmapped: []string{},
+	}
+
+	c, err := s.importContainer(ctx, info, opts, res)
+	if err != nil {
+		if c != nil {
+			s.rollback(ctx, c.ID)
+		}
+		return nil, err
+	}
+
+	err = s.runner.AdoptDockerContainer(ctx, dc.ID, *c)
+	if err != nil {
+		s.rollback(ctx, c.ID)
+		return nil, err
+	}
+
+	// The logs of a running container are only followed once started by Vertex.
+	if isUp(dc.State) {
+		err = s.containerService.Start(ctx, c.ID)
+		if err != nil {
+			log.Error(err, vlog.String("id", c.ID.String()))
+		}
+	}
+
+	c, err = s.containers.GetContainer(ctx, c.ID)
+	if err != nil {
+		return nil, err
+	}
+	res.Container = *c
+	return res, nil
+}
+
+func (s *adoptService) getDockerContainer(ctx context.Context, id string) (types.DockerContainer, error) {
+	containers, err := s.runner.GetDockerContainers(ctx)
+	if err != nil {
+		return types.DockerContainer{}, err
+	}
+
+	for _, dc := range containers {
+		if dc.ID == id || dc.Name() == id {
+			return dc, nil
+		}
+	}
+	return types.DockerContainer{}, types.ErrDockerContainerNotFound
+}
+
+func (s *adoptService) importContainer(ctx context.Context, info types.InspectContainerResponse, opts types.AdoptContainerOptions, res *types.ContainerAdoption) (*types.Container, error) {
+	image, tag := types.SplitImageRef(info.Image)
+	c, err := s.containerService.CreateContainer(ctx, types.CreateContainerOptions{
+		Image:    &image,
+		ImageTag: &tag,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	c.Name = info.Name
+	if opts.Name != nil {
+		c.Name = *opts.Name
+	}
+	c.Command = info.Command()
+	c.RestartPolicy = types.RestartPolicy(info.RestartPolicy)
+	c.RestartMaxRetries = info.RestartMaxRetries
+	if c.RestartPolicy == "" {
+		// Docker doesn't restart containers without restart policy.
+		c.RestartPolicy = types.RestartPolicyNo
+	}
+	if c.RestartPolicy.Validate() != nil {
+		res.Unmapped = append(res.Unmapped, "restart_policy")
+		c.RestartPolicy, c.RestartMaxRetries = types.RestartPolicyNo, 0
+	}
+
+	err = s.containerService.UpdateContainer(ctx, c.ID, *c)
+	if err != nil {
+		return c, err
+	}
+
+	env := info.OwnEnv()
+	for _, name := range sortedKeys(env) {
+		err = s.vars.CreateEnv(ctx, types.EnvVariable{
+			ID:          uuid.New(),
+			ContainerID: c.ID,
+			Type:        types.EnvVariableTypeString,
+			Name:        name,
+			DisplayName: name,
+			Value:       env[name],
+		})
+		if err != nil {
+			return c, err
+		}
+	}
+
+	for i, p := range info.PortBindings {
+		// Vertex publishes the ports on all interfaces, so ports published
+		// on a specific address are not adopted.
+		if p.Host == "" || !allInterfaces(p.HostIP) {
+			res.Unmapped = append(res.Unmapped, fmt.Sprintf("port_bindings[%d]", i))
+			continue
+		}
+		in, protocol, _ := strings.Cut(p.Container, "/")
+		if protocol == "" {
+			protocol = string(types.PortProtocolTCP)
+		}
+		err = s.ports.CreatePort(ctx, types.Port{
+			ID:          uuid.New(),
+			ContainerID: c.ID,
+			In:          in,
+			Out:         p.Host,
+			Protocol:    types.PortProtocol(protocol),
+		})
+		if err != nil {
+			return c, err
+		}
+	}
+
+	// The volumes keep their names, so that the data is not lost.
+	for i, m := range info.Mounts {
+		vol := types.Volume{
+			ID:          uuid.New(),
+			ContainerID: c.ID,
+			In:          m.Destination,
+		}
+		switch m.Type {
+		case string(types.VolumeTypeBind):
+			vol.Type, vol.Out = types.VolumeTypeBind, m.Source
+		case string(types.VolumeTypeVolume):
+			vol.Type, vol.Out = types.VolumeTypeVolume, m.Name
+		default:
+			res.Unmapped = append(res.Unmapped, fmt.Sprintf("mounts[%d]", i))
+			continue
+		}
+		err = s.volumes.CreateVolume(ctx, vol)
+		if err != nil {
+			return c, err
+		}
+	}
+
+	for _, cp := range info.CapAdd {
+		err = s.caps.CreateCap(ctx, types.Capability{
+			ID:          uuid.New(),
+			ContainerID: c.ID,
+			Name:        cp,
+		})
+		if err != nil {
+			return c, err
+		}
+	}
+
+	for _, name := range sortedKeys(info.Sysctls) {
+		err = s.sysctls.CreateSysctl(ctx, types.Sysctl{
+			ID:          uuid.New(),
+			ContainerID: c.ID,
+			Name:        name,
+			Value:       info.Sysctls[name],
+		})
+		if err != nil {
+			return c, err
+		}
+	}
+
+	return c, nil
+}
+
+// rollback deletes a container that could not be adopted. Its volumes are
+// forgotten first, so that the volumes of the Docker container are kept.
+func (s *adoptService) rollback(ctx context.Context, id uuid.UUID) {
+	err := s.volumes.DeleteContainerVolumes(ctx, id)
+	if err != nil {
+		log.Error(err, vlog.String("id", id.String()))
+	}
+	err = s.containerService.Delete(ctx, id)
+	if err != nil {
+		log.Error(err, vlog.String("id", id.String()))
+	}
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/vertex-center/uuid"
+	"github.com/vertex-center/vertex/server/apps/containers/core/port"
+	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+)
+
+type AdoptServiceTestSuite struct {
+	suite.Suite
+
+	service          *adoptService
+	containerService port.MockContainerService
+	containers       *fakeContainerAdapter
+	ports            *fakePortAdapter
+	runner           *fakeRunnerAdapter
+}
+
+func TestAdoptServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(AdoptServiceTestSuite))
+}
+
+func (suite *AdoptServiceTestSuite) SetupTest() {
+	suite.containers = &fakeContainerAdapter{containers: map[uuid.UUID]types.Container{}}
+	suite.ports = &fakePortAdapter{}
+	suite.runner = &fakeRunnerAdapter{
+		dockerContainers: []types.DockerContainer{{ID: "abc", Names: []string{"/postgres"}, State: "exited"}},
+	}
+	suite.containerService = port.MockContainerService{}
+	c := types.Container{ID: uuid.New()}
+	suite.containers.containers[c.ID] = c
+	suite.containerService.On("CreateContainer", mock.Anything, mock.Anything).Return(&c, nil)
+	suite.containerService.On("UpdateContainer", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	suite.service = NewAdoptService(&suite.containerService, suite.containers, &fakeEnvAdapter{}, suite.ports,
+		&fakeVolumeAdapter{}, &fakeCapAdapter{}, nil, suite.runner).(*adoptService)
+}
+
+func (suite *AdoptServiceTestSuite) TestAdoptPortBindings() {
+	suite.runner.inspect = types.InspectContainerResponse{
+		ID:    "abc",
+		Name:  "postgres",
+		Image: "postgres:16",
+		PortBindings: []types.DockerPortBinding{
+			{Container: "5432/tcp", Host: "5432"},
+			{Container: "53/udp", Host: "53", HostIP: "0.0.0.0"},
+			{Container: "8080/tcp", Host: "8080", HostIP: "127.0.0.1"},
+			{Container: "9000/tcp"},
+		},
+	}
+
+	res, err := suite.service.Adopt(context.Background(), "postgres", types.AdoptContainerOptions{})
+	suite.Require().NoError(err)
+
+	suite.Equal([]string{"port_bindings[2]", "port_bindings[3]"}, res.Unmapped)
+	suite.Len(suite.ports.ports, 2)
+	suite.NotNil(suite.ports.find("5432", types.PortProtocolTCP))
+	suite.NotNil(suite.ports.find("53", types.PortProtocolUDP))
+}
@@ -0,0 +1,366 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/go-co-op/gocron"
+	"github.com/juju/errors"
+	"github.com/vertex-center/uuid"
+	"github.com/vertex-center/vertex/server/apps/containers/core/port"
+	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+	"github.com/vertex-center/vertex/server/common/app"
+	ev "github.com/vertex-center/vertex/server/common/event"
+	"github.com/vertex-center/vertex/server/common/log"
+	"github.com/vertex-center/vertex/server/common/server"
+	"github.com/vertex-center/vertex/server/common/storage"
+	"github.com/vertex-center/vertex/server/pkg/event"
+	"github.com/vertex-center/vlog"
+)
+
+type backupService struct {
+	uuid             uuid.UUID
+	ctx              *app.Context
+	containerService port.ContainerService
+	containers       port.ContainerAdapter
+	volumes          port.VolumeAdapter
+	backups          port.BackupAdapter
+	runner           port.RunnerAdapter
+
+	scheduler *gocron.Scheduler
+
+	// mu makes sure a container is not backed up and restored at the same time.
+	mu sync.Mutex
+}
+
+func NewBackupService(ctx *app.Context, containerService port.ContainerService, containers port.ContainerAdapter, volumes port.VolumeAdapter, backups port.BackupAdapter, runner port.RunnerAdapter) port.BackupService {
+	s := &backupService{
+		uuid:             uuid.New(),
+		ctx:              ctx,
+		containerService: containerService,
+		containers:       containers,
+		volumes:          volumes,
+		backups:          backups,
+		runner:           runner,
+	}
+	s.ctx.AddListener(s)
+	return s
+}
+
+func (s *backupService) GetUUID() uuid.UUID {
+	return s.uuid
+}
+
+func (s *backupService) OnEvent(e event.Event) error {
+	switch e := e.(type) {
+	case ev.ServerSetupCompleted:
+		return s.startScheduler()
+	case ev.ServerStop:
+		s.stopScheduler()
+	case types.EventContainerDeleted:
+		go s.deleteContainerBackups(e.ContainerID)
+	}
+	return nil
+}
+
+func (s *backupService) GetBackups(ctx context.Context, containerID uuid.UUID) (types.Backups, error) {
+	return s.backups.GetBackups(ctx, containerID)
+}
+
+// CreateBackup archives all the volumes of a container. If stop is true and
+// the container is running, it is stopped during the backup.
+func (s *backupService) CreateBackup(ctx context.Context, containerID uuid.UUID, stop bool) (*types.Backup, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, err := s.containers.GetContainer(ctx, containerID)
+	if err != nil {
+		return nil, err
+	}
+
+	volumes, err := s.volumes.GetContainerVolumes(ctx, containerID)
+	if err != nil {
+		return nil, err
+	}
+	if len(volumes) == 0 {
+		return nil, types.ErrNoVolumesToBackup
+	}
+
+	version, err := s.backups.GetLatestBackupVersion(ctx, containerID)
+	if err != nil {
+		return nil, err
+	}
+
+	backup := types.Backup{
+		ID:          uuid.New(),
+		ContainerID: containerID,
+		Version:     version + 1,
+		CreatedAt:   time.Now().Unix(),
+	}
+
+	dir := backupDir(containerID, backup.Version)
+	err = os.MkdirAll(dir, 0700)
+	if err != nil {
+		return nil, err
+	}
+
+	restart := stop && c.IsRunning()
+	if restart {
+		err = s.containerService.Stop(ctx, containerID)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			err := s.containerService.Start(ctx, containerID)
+			if err != nil {
+				log.Error(err, vlog.String("container_id", containerID.String()))
+			}
+		}()
+	}
+
+	s.log(containerID, fmt.Sprintf("Backing up %d volume(s)...", len(volumes)))
+
+	for _, v := range volumes {
+		archive := path.Join(dir, v.ID.String()+".tar.gz")
+		res, err := s.runner.ArchiveVolume(ctx, v, archive)
+		if err != nil {
+			_ = os.RemoveAll(dir)
+			return nil, err
+		}
+
+		backup.Size += res.Size
+		backup.Volumes = append(backup.Volumes, types.BackupVolume{
+			ID:       uuid.New(),
+			BackupID: backup.ID,
+			VolumeID: v.ID,
+			Type:     v.Type,
+			Name:     v.Out,
+			Path:     v.In,
+			Archive:  archive,
+			Size:     res.Size,
+			Checksum: res.Checksum,
+		})
+	}
+
+	err = s.backups.CreateBackup(ctx, backup)
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		return nil, err
+	}
+
+	s.log(containerID, fmt.Sprintf("Backup v%d created.", backup.Version))
+	return &backup, nil
+}
+
+func (s *backupService) DeleteBackup(ctx context.Context, containerID uuid.UUID, id uuid.UUID) error {
+	backup, err := s.getBackup(ctx, containerID, id)
+	if err != nil {
+		return err
+	}
+	return s.deleteBackup(ctx, *backup)
+}
+
+// RestoreBackup replaces the content of the volumes of a container with a
+// backup. The container is stopped during the restore.
+func (s *backupService) RestoreBackup(ctx context.Context, containerID uuid.UUID, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, err := s.containers.GetContainer(ctx, containerID)
+	if err != nil {
+		return err
+	}
+
+	backup, err := s.getBackup(ctx, containerID, id)
+	if err != nil {
+		return err
+	}
+
+	if c.IsRunning() {
+		err = s.containerService.Stop(ctx, containerID)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			err := s.containerService.Start(ctx, containerID)
+			if err != nil {
+				log.Error(err, vlog.String("container_id", containerID.String()))
+			}
+		}()
+	}
+
+	s.log(containerID, fmt.Sprintf("Restoring backup v%d...", backup.Version))
+
+	for _, v := range backup.Volumes {
+		err = s.runner.RestoreVolume(ctx, v)
+		if err != nil {
+			return errors.Annotatef(err, "restore %s", v.Path)
+		}
+	}
+
+	s.log(containerID, fmt.Sprintf("Backup v%d restored.", backup.Version))
+	return nil
+}
+
+func (s *backupService) GetBackupSchedule(ctx context.Context, containerID uuid.UUID) (*types.BackupSchedule, error) {
+	return s.backups.GetBackupSchedule(ctx, containerID)
+}
+
+func (s *backupService) SetBackupSchedule(ctx context.Context, schedule types.BackupSchedule) error {
+	_, err := s.containers.GetContainer(ctx, schedule.ContainerID)
+	if err != nil {
+		return err
+	}
+
+	err = schedule.Validate()
+	if err != nil {
+		return err
+	}
+
+	err = s.backups.SetBackupSchedule(ctx, schedule)
+	if err != nil {
+		return err
+	}
+	return s.schedule(schedule)
+}
+
+func (s *backupService) DeleteBackupSchedule(ctx context.Context, containerID uuid.UUID) error {
+	err := s.backups.DeleteBackupSchedule(ctx, containerID)
+	if err != nil {
+		return err
+	}
+	if s.scheduler != nil {
+		_ = s.scheduler.RemoveByTag(containerID.String())
+	}
+	return nil
+}
+
+func (s *backupService) getBackup(ctx context.Context, containerID uuid.UUID, id uuid.UUID) (*types.Backup, error) {
+	backup, err := s.backups.GetBackup(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if backup.ContainerID != containerID {
+		return nil, types.ErrBackupNotFound
+	}
+	return backup, nil
+}
+
+func (s *backupService) deleteBackup(ctx context.Context, backup types.Backup) error {
+	err := s.backups.DeleteBackup(ctx, backup.ID)
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(backupDir(backup.ContainerID, backup.Version))
+}
+
+// applyRetention deletes the oldest backups of a container, to only keep
+// the given number of backups.
+func (s *backupService) applyRetention(ctx context.Context, containerID uuid.UUID, retention int) error {
+	backups, err := s.backups.GetBackups(ctx, containerID)
+	if err != nil {
+		return err
+	}
+	for i := retention; i < len(backups); i++ {
+		err = s.deleteBackup(ctx, backups[i])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *backupService) deleteContainerBackups(containerID uuid.UUID) {
+	ctx := server.NewBackgroundContext()
+
+	err := s.DeleteBackupSchedule(ctx, containerID)
+	if err != nil {
+		log.Error(err, vlog.String("container_id", containerID.String()))
+	}
+
+	err = s.applyRetention(ctx, containerID, 0)
+	if err != nil {
+		log.Error(err, vlog.String("container_id", containerID.String()))
+	}
+
+	err = os.RemoveAll(path.Join(backupsPath, containerID.String()))
+	if err != nil {
+		log.Error(err, vlog.String("container_id", containerID.String()))
+	}
+}
+
+func (s *backupService) startScheduler() error {
+	s.scheduler = gocron.NewScheduler(time.Local)
+	s.scheduler.StartAsync()
+
+	schedules, err := s.backups.GetBackupSchedules(context.Background())
+	if err != nil {
+		return err
+	}
+	for _, schedule := range schedules {
+		err = s.schedule(schedule)
+		if err != nil {
+			log.Error(err, vlog.String("container_id", schedule.ContainerID.String()))
+		}
+	}
+	return nil
+}
+
+func (s *backupService) stopScheduler() {
+	if s.scheduler != nil {
+		s.scheduler.Clear()
+		s.scheduler.Stop()
+	}
+}
+
+// schedule replaces the scheduled backups of a container.
+func (s *backupService) schedule(schedule types.BackupSchedule) error {
+	if s.scheduler == nil {
+		return nil
+	}
+
+	tag := schedule.ContainerID.String()
+	_ = s.scheduler.RemoveByTag(tag)
+	_, err := s.scheduler.Cron(schedule.Schedule).Tag(tag).Do(s.scheduledBackup, schedule)
+	return err
+}
+
+func (s *backupService) scheduledBackup(schedule types.BackupSchedule) {
+	ctx := server.NewBackgroundContext()
+
+	_, err := s.CreateBackup(ctx, schedule.ContainerID, schedule.StopContainer)
+	if err != nil {
+		log.Error(err, vlog.String("container_id", schedule.ContainerID.String()))
+		s.ctx.DispatchEvent(types.EventContainerLog{
+			ContainerID: schedule.ContainerID,
+			Kind:        types.LogKindVertexErr,
+			Message:     types.NewLogLineMessageString("Scheduled backup failed: " + err.Error()),
+		})
+		return
+	}
+
+	err = s.applyRetention(ctx, schedule.ContainerID, schedule.Retention)
+	if err != nil {
+		log.Error(err, vlog.String("container_id", schedule.ContainerID.String()))
+	}
+}
+
+func (s *backupService) log(containerID uuid.UUID, msg string) {
+	s.ctx.DispatchEvent(types.EventContainerLog{
+		ContainerID: containerID,
+		Kind:        types.LogKindVertexOut,
+		Message:     types.NewLogLineMessageString(msg),
+	})
+}
+
+// backupsPath is the directory where backups are stored.
+var backupsPath = path.Join(storage.FSPath, "apps", "containers", "backups")
+
+// backupDir is the directory of the archives of a backup.
+func backupDir(containerID uuid.UUID, version int) string {
+	return path.Join(backupsPath, containerID.String(), fmt.Sprintf("v%d", version))
+}
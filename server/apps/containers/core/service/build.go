@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"path"
+
+	"github.com/vertex-center/uuid"
+	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+	"github.com/vertex-center/vertex/server/common/storage"
+	vstorage "github.com/vertex-center/vertex/server/pkg/storage"
+)
+
+// buildDir is the directory where the repository of a container is cloned.
+func buildDir(id uuid.UUID) string {
+	return path.Join(storage.FSPath, id.String())
+}
+
+// RebuildContainer pulls the repository of a container built from sources.
+// If it has new commits, the image is rebuilt, right away if the container
+// is running, or the next time it starts.
+func (s *containerService) RebuildContainer(ctx context.Context, id uuid.UUID) error {
+	c, err := s.containers.GetContainer(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	build, err := s.builds.GetContainerBuild(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	dir := buildDir(id)
+	previous, _ := vstorage.RepositoryHead(dir)
+
+	s.ctx.DispatchEvent(types.EventContainerLog{
+		ContainerID: id,
+		Kind:        types.LogKindVertexOut,
+		Message:     types.NewLogLineMessageString("Pulling " + build.Repository + "..."),
+	})
+
+	err = vstorage.CloneOrPullRepository(build.Repository, dir)
+	if err != nil {
+		return err
+	}
+
+	commit, err := vstorage.RepositoryHead(dir)
+	if err != nil {
+		return err
+	}
+	if commit == previous {
+		return types.ErrNoUpdateAvailable
+	}
+
+	s.ctx.DispatchEvent(types.EventContainerLog{
+		ContainerID: id,
+		Kind:        types.LogKindVertexOut,
+		Message:     types.NewLogLineMessageString("Rebuilding from commit " + commit + "..."),
+	})
+
+	if !c.IsRunning() {
+		return nil
+	}
+	return s.RecreateContainer(ctx, id)
+}
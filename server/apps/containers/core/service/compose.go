@@ -0,0 +1,506 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/vertex-center/uuid"
+	"github.com/vertex-center/vertex/server/apps/containers/core/port"
+	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+	"github.com/vertex-center/vertex/server/common/log"
+	"github.com/vertex-center/vlog"
+	"gopkg.in/yaml.v3"
+)
+
+type composeService struct {
+	containerService port.ContainerService
+	containers       port.ContainerAdapter
+	vars             port.EnvAdapter
+	ports            port.PortAdapter
+	hostPorts        port.HostPortsAdapter
+	volumes          port.VolumeAdapter
+	caps             port.CapAdapter
+	sysctls          port.SysctlAdapter
+	health           port.HealthCheckAdapter
+	deps             port.DependencyAdapter
+}
+
+func NewComposeService(
+	containerService port.ContainerService,
+	containers port.ContainerAdapter,
+	vars port.EnvAdapter,
+	ports port.PortAdapter,
+	hostPorts port.HostPortsAdapter,
+	volumes port.VolumeAdapter,
+	caps port.CapAdapter,
+	sysctls port.SysctlAdapter,
+	health port.HealthCheckAdapter,
+	deps port.DependencyAdapter,
+) port.ComposeService {
+	return &composeService{
+		containerService: containerService,
+		containers:       containers,
+		vars:             vars,
+		ports:            ports,
+		hostPorts:        hostPorts,
+		volumes:          volumes,
+		caps:             caps,
+		sysctls:          sysctls,
+		health:           health,
+		deps:             deps,
+	}
+}
+
+// Import creates one container per service of the compose file. If one of
+// them cannot be created, the containers already created are deleted.
+func (s *composeService) Import(ctx context.Context, data []byte) (*types.ComposeImport, error) {
+	compose, err := types.ParseCompose(data)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &types.ComposeImport{
+		Containers: types.Containers{},
+		Unmapped:   []string{},
+	}
+
+	for _, key := range sortedKeys(compose.Extra) {
+		res.Unmapped = append(res.Unmapped, key)
+	}
+	for _, name := range sortedKeys(compose.Volumes) {
+		if compose.Volumes[name] != nil {
+			res.Unmapped = append(res.Unmapped, "volumes."+name)
+		}
+	}
+
+	ids := map[string]uuid.UUID{}
+	for _, name := range compose.ServiceNames() {
+		c, err := s.importService(ctx, name, compose.Services[name], res)
+		// The container is rolled back even if its configuration failed.
+		if c != nil {
+			ids[name] = c.ID
+		}
+		if err != nil {
+			s.rollback(ctx, ids)
+			return nil, err
+		}
+		if c != nil {
+			res.Containers = append(res.Containers, *c)
+		}
+	}
+
+	for _, name := range compose.ServiceNames() {
+		id, ok := ids[name]
+		if !ok {
+			continue
+		}
+		for _, dep := range sortedKeys(compose.Services[name].DependsOn) {
+			field := fmt.Sprintf("services.%s.depends_on.%s", name, dep)
+
+			dependsOnID, ok := ids[dep]
+			if !ok {
+				res.Unmapped = append(res.Unmapped, field)
+				continue
+			}
+
+			waitFor := types.DependencyConditionRunning
+			switch compose.Services[name].DependsOn[dep].Condition {
+			case types.ComposeConditionStarted:
+			case types.ComposeConditionHealthy:
+				waitFor = types.DependencyConditionHealthy
+			default:
+				res.Unmapped = append(res.Unmapped, field+".condition")
+			}
+
+			err = setDependency(ctx, s.deps, types.Dependency{
+				ContainerID: id,
+				DependsOnID: dependsOnID,
+				WaitFor:     waitFor,
+			})
+			if err != nil {
+				s.rollback(ctx, ids)
+				return nil, err
+			}
+		}
+	}
+
+	return res, nil
+}
+
+// importService creates the container of a service. Once created, the
+// container is returned even on error, so that it can be rolled back.
+func (s *composeService) importService(ctx context.Context, name string, service types.ComposeService, res *types.ComposeImport) (*types.Container, error) {
+	field := "services." + name
+
+	for _, key := range sortedKeys(service.Extra) {
+		res.Unmapped = append(res.Unmapped, field+"."+key)
+	}
+
+	if service.Image == "" {
+		res.Unmapped = append(res.Unmapped, field)
+		return nil, nil
+	}
+
+	image, tag := service.ImageAndTag()
+	c, err := s.containerService.CreateContainer(ctx, types.CreateContainerOptions{
+		Image:    &image,
+		ImageTag: &tag,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	c.Name = name
+	if service.ContainerName != "" {
+		c.Name = service.ContainerName
+	}
+	if len(service.Command) > 0 {
+		cmd := service.Command.String()
+		c.Command = &cmd
+	}
+	c.RestartPolicy, c.RestartMaxRetries, err = service.RestartPolicy()
+	if err != nil {
+		res.Unmapped = append(res.Unmapped, field+".restart")
+		c.RestartPolicy, c.RestartMaxRetries = types.RestartPolicyNo, 0
+	}
+
+	err = s.containerService.UpdateContainer(ctx, c.ID, *c)
+	if err != nil {
+		return c, err
+	}
+
+	for _, key := range sortedKeys(service.Environment) {
+		err = s.vars.CreateEnv(ctx, types.EnvVariable{
+			ID:          uuid.New(),
+			ContainerID: c.ID,
+			Type:        types.EnvVariableTypeString,
+			Name:        key,
+			DisplayName: key,
+			Value:       service.Environment[key],
+		})
+		if err != nil {
+			return c, err
+		}
+	}
+
+	used, err := s.ports.GetPorts(ctx, types.PortFilters{})
+	if err != nil {
+		return c, err
+	}
+	for i, cp := range service.Ports {
+		// Vertex publishes the ports on all interfaces, so ports published
+		// on a specific address are not imported.
+		if !allInterfaces(cp.HostIP) {
+			res.Unmapped = append(res.Unmapped, fmt.Sprintf("%s.ports[%d]", field, i))
+			continue
+		}
+		in, protocol, _ := strings.Cut(cp.Target, "/")
+		if protocol == "" {
+			protocol = string(types.PortProtocolTCP)
+		}
+		p := types.Port{
+			ID:          uuid.New(),
+			ContainerID: c.ID,
+			In:          in,
+			Out:         cp.Published,
+			Protocol:    types.PortProtocol(protocol),
+		}
+		err = p.Validate()
+		if err != nil {
+			return c, err
+		}
+		err = checkPort(s.hostPorts, used, p)
+		if err != nil {
+			return c, err
+		}
+		err = s.ports.CreatePort(ctx, p)
+		if err != nil {
+			return c, err
+		}
+		used = append(used, p)
+	}
+
+	for i, v := range service.Volumes {
+		// Volumes are mounted read-write, so volumes with another mode are
+		// not imported.
+		if v.Source == "" || (v.Mode != "" && v.Mode != "rw") {
+			res.Unmapped = append(res.Unmapped, fmt.Sprintf("%s.volumes[%d]", field, i))
+			continue
+		}
+
+		vol := types.Volume{
+			ID:          uuid.New(),
+			ContainerID: c.ID,
+			Type:        types.VolumeTypeBind,
+			In:          v.Target,
+			Out:         v.Source,
+		}
+		if !v.IsBind() {
+			vol.Type = types.VolumeTypeVolume
+			vol.Out = "VERTEX_VOLUME_" + c.ID.String() + "_" + v.Source
+		}
+		err = s.volumes.CreateVolume(ctx, vol)
+		if err != nil {
+			return c, err
+		}
+	}
+
+	for _, cp := range service.CapAdd {
+		err = s.caps.CreateCap(ctx, types.Capability{
+			ID:          uuid.New(),
+			ContainerID: c.ID,
+			Name:        cp,
+		})
+		if err != nil {
+			return c, err
+		}
+	}
+
+	for _, key := range sortedKeys(service.Sysctls) {
+		err = s.sysctls.CreateSysctl(ctx, types.Sysctl{
+			ID:          uuid.New(),
+			ContainerID: c.ID,
+			Name:        key,
+			Value:       service.Sysctls[key],
+		})
+		if err != nil {
+			return c, err
+		}
+	}
+
+	if service.Healthcheck != nil {
+		if cmd, ok := service.Healthcheck.Shell(); ok {
+			hc := types.HealthCheck{
+				ID:          uuid.New(),
+				ContainerID: c.ID,
+				Type:        types.HealthCheckTypeExec,
+				Command:     &cmd,
+				Interval:    service.Healthcheck.Interval,
+				Timeout:     service.Healthcheck.Timeout,
+				StartPeriod: service.Healthcheck.StartPeriod,
+				Retries:     service.Healthcheck.Retries,
+			}
+			if hc.Validate() != nil {
+				res.Unmapped = append(res.Unmapped, field+".healthcheck")
+			} else {
+				err = s.health.SetContainerHealthCheck(ctx, hc)
+				if err != nil {
+					return c, err
+				}
+			}
+		}
+	}
+
+	return s.containers.GetContainer(ctx, c.ID)
+}
+
+func (s *composeService) rollback(ctx context.Context, ids map[string]uuid.UUID) {
+	for _, id := range ids {
+		err := s.containerService.Delete(ctx, id)
+		if err != nil {
+			log.Error(err, vlog.String("id", id.String()))
+		}
+	}
+}
+
+// Export converts containers into a compose file. Each container becomes a
+// service named after its name.
+func (s *composeService) Export(ctx context.Context, ids []uuid.UUID) (*types.ComposeExport, error) {
+	res := &types.ComposeExport{
+		Unmapped: []string{},
+	}
+
+	compose := types.ComposeFile{
+		Services: map[string]types.ComposeService{},
+		Volumes:  map[string]any{},
+	}
+
+	// Service names must be known first to write dependencies.
+	names := map[uuid.UUID]string{}
+	containers := types.Containers{}
+	for _, id := range ids {
+		c, err := s.containers.GetContainer(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if c.ID != id {
+			return nil, types.ErrContainerNotFound
+		}
+
+		name := c.Name
+		if c.StackMember != nil {
+			name = *c.StackMember
+		}
+		name = composeServiceName(name)
+		for i := 2; compose.Services[name].Image != ""; i++ {
+			name = composeServiceName(c.Name) + "-" + strconv.Itoa(i)
+		}
+		names[id] = name
+		compose.Services[name] = types.ComposeService{Image: c.GetImageNameWithTag()}
+		containers = append(containers, *c)
+	}
+
+	for _, c := range containers {
+		name := names[c.ID]
+		service, err := s.exportContainer(ctx, c, name, names, &compose, res)
+		if err != nil {
+			return nil, err
+		}
+		compose.Services[name] = service
+	}
+
+	if len(compose.Volumes) == 0 {
+		compose.Volumes = nil
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	err := enc.Encode(compose)
+	if err != nil {
+		return nil, err
+	}
+	res.Compose = buf.String()
+	return res, nil
+}
+
+func (s *composeService) exportContainer(ctx context.Context, c types.Container, name string, names map[uuid.UUID]string, compose *types.ComposeFile, res *types.ComposeExport) (types.ComposeService, error) {
+	field := "services." + name
+	service := types.ComposeService{
+		Image:   c.GetImageNameWithTag(),
+		Restart: string(c.RestartPolicy),
+	}
+	if c.Command != nil {
+		service.Command = types.SplitCommand(*c.Command)
+	}
+	switch c.RestartPolicy {
+	case types.RestartPolicyNo, "":
+		service.Restart = ""
+	case types.RestartPolicyOnFailure:
+		if c.RestartMaxRetries > 0 {
+			service.Restart += ":" + strconv.Itoa(c.RestartMaxRetries)
+		}
+	}
+
+	env, err := s.vars.GetEnvs(ctx, types.EnvVariableFilters{ContainerID: &c.ID})
+	if err != nil {
+		return service, err
+	}
+	if len(env) > 0 {
+		service.Environment = types.ComposeMapOrList{}
+		for _, e := range env {
+			// Secrets are never exported.
+			if e.Secret {
+				res.Unmapped = append(res.Unmapped, field+".environment."+e.Name)
+				continue
+			}
+			service.Environment[e.Name] = e.Value
+		}
+	}
+
+	ports, err := s.ports.GetPorts(ctx, types.PortFilters{ContainerID: &c.ID})
+	if err != nil {
+		return service, err
+	}
+	for _, p := range ports {
+		target := p.In
+		if p.Protocol == types.PortProtocolUDP {
+			target += "/" + string(p.Protocol)
+		}
+		service.Ports = append(service.Ports, types.ComposePort{Published: p.Out, Target: target})
+	}
+
+	volumes, err := s.volumes.GetContainerVolumes(ctx, c.ID)
+	if err != nil {
+		return service, err
+	}
+	for _, v := range volumes {
+		source := v.Out
+		if v.Type == types.VolumeTypeVolume {
+			source = strings.TrimPrefix(source, "VERTEX_VOLUME_"+c.ID.String()+"_")
+			compose.Volumes[source] = nil
+		}
+		service.Volumes = append(service.Volumes, types.ComposeVolume{Source: source, Target: v.In})
+	}
+
+	caps, err := s.caps.GetContainerCaps(ctx, c.ID)
+	if err != nil {
+		return service, err
+	}
+	for _, cp := range caps {
+		service.CapAdd = append(service.CapAdd, cp.Name)
+	}
+
+	sysctls, err := s.sysctls.GetContainerSysctls(ctx, c.ID)
+	if err != nil {
+		return service, err
+	}
+	if len(sysctls) > 0 {
+		service.Sysctls = types.ComposeMapOrList{}
+		for _, sc := range sysctls {
+			service.Sysctls[sc.Name] = sc.Value
+		}
+	}
+
+	hc, err := s.health.GetContainerHealthCheck(ctx, c.ID)
+	if err != nil && !errors.Is(err, errors.NotFound) {
+		return service, err
+	} else if err == nil {
+		service.Healthcheck = &types.ComposeHealthcheck{
+			Test:        hc.Test(),
+			Interval:    hc.Interval,
+			Timeout:     hc.Timeout,
+			StartPeriod: hc.StartPeriod,
+			Retries:     hc.Retries,
+		}
+	}
+
+	deps, err := s.deps.GetContainerDependencies(ctx, c.ID)
+	if err != nil {
+		return service, err
+	}
+	for _, dep := range deps {
+		depName, ok := names[dep.DependsOnID]
+		if !ok {
+			res.Unmapped = append(res.Unmapped, fmt.Sprintf("%s.depends_on.%s", field, dep.DependsOnID))
+			continue
+		}
+		if service.DependsOn == nil {
+			service.DependsOn = types.ComposeDependsOn{}
+		}
+		condition := types.ComposeConditionStarted
+		if dep.WaitFor == types.DependencyConditionHealthy {
+			condition = types.ComposeConditionHealthy
+		}
+		service.DependsOn[depName] = types.ComposeDependency{Condition: condition}
+	}
+
+	return service, nil
+}
+
+var composeNameRegexp = regexp.MustCompile(`[^a-z0-9_-]+`)
+
+// composeServiceName converts a container name into a valid service name.
+func composeServiceName(name string) string {
+	name = composeNameRegexp.ReplaceAllString(strings.ToLower(name), "-")
+	name = strings.Trim(name, "-")
+	if name == "" {
+		return "service"
+	}
+	return name
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/juju/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/vertex-center/uuid"
+	"github.com/vertex-center/vertex/server/apps/containers/core/port"
+	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+)
+
+type ComposeServiceTestSuite struct {
+	suite.Suite
+
+	service          *composeService
+	containerService port.MockContainerService
+	ports            *fakePortAdapter
+	volumes          *fakeVolumeAdapter
+	host             MockHostPortsAdapter
+
+	container types.Container
+}
+
+func TestComposeServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(ComposeServiceTestSuite))
+}
+
+func (suite *ComposeServiceTestSuite) SetupTest() {
+	suite.container = types.Container{ID: uuid.New()}
+	containers := &fakeContainerAdapter{containers: map[uuid.UUID]types.Container{
+		suite.container.ID: suite.container,
+	}}
+	suite.ports = &fakePortAdapter{}
+	suite.volumes = &fakeVolumeAdapter{}
+	suite.host = MockHostPortsAdapter{}
+	suite.host.On("IsPortFree", mock.Anything, mock.Anything).Return(true)
+	suite.containerService = port.MockContainerService{}
+	suite.containerService.On("CreateContainer", mock.Anything, mock.Anything).Return(&suite.container, nil)
+	suite.containerService.On("UpdateContainer", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	suite.containerService.On("Delete", mock.Anything, mock.Anything).Return(nil)
+	suite.service = NewComposeService(&suite.containerService, containers, &fakeEnvAdapter{}, suite.ports, &suite.host,
+		suite.volumes, &fakeCapAdapter{}, nil, nil, nil).(*composeService)
+}
+
+func (suite *ComposeServiceTestSuite) TestImportPortsAndVolumes() {
+	data := `
+services:
+  app:
+    image: nginx
+    ports:
+      - "8080:80"
+      - "127.0.0.1:8081:81"
+    volumes:
+      - ./html:/usr/share/nginx/html:rw
+      - ./config:/etc/nginx:ro
+`
+
+	res, err := suite.service.Import(context.Background(), []byte(data))
+	suite.Require().NoError(err)
+
+	suite.Equal([]string{"services.app.ports[1]", "services.app.volumes[1]"}, res.Unmapped)
+	suite.Len(suite.ports.ports, 1)
+	suite.Equal("8080", suite.ports.find("80", types.PortProtocolTCP).Out)
+	suite.Equal([]string{"/usr/share/nginx/html"}, suite.volumes.paths())
+}
+
+func (suite *ComposeServiceTestSuite) TestImportPortConflict() {
+	used := types.Port{ID: uuid.New(), ContainerID: uuid.New(), In: "80", Out: "8080", Protocol: types.PortProtocolTCP}
+	suite.ports.ports = types.Ports{used}
+
+	_, err := suite.service.Import(context.Background(), []byte("services:\n  app:\n    image: nginx\n    ports: [\"8080:80\"]\n"))
+	suite.True(errors.Is(err, errors.AlreadyExists))
+	suite.Equal(types.Ports{used}, suite.ports.ports)
+	suite.containerService.AssertCalled(suite.T(), "Delete", mock.Anything, suite.container.ID)
+}
@@ -5,7 +5,9 @@ import (
 	"context"
 	"encoding/json"
 	goerrors "errors"
-	"path"
+	"io"
+	"os"
+	"slices"
 	"strings"
 	"sync"
 
@@ -15,10 +17,10 @@ import (
 	"github.com/vertex-center/vertex/server/apps/containers/core/types"
 	"github.com/vertex-center/vertex/server/common/app"
 	"github.com/vertex-center/vertex/server/common/log"
-	"github.com/vertex-center/vertex/server/common/storage"
 	"github.com/vertex-center/vertex/server/config"
 	"github.com/vertex-center/vertex/server/pkg/event"
 	vstorage "github.com/vertex-center/vertex/server/pkg/storage"
+	"github.com/vertex-center/vertex/server/pkg/vcrypto"
 	"github.com/vertex-center/vlog"
 )
 
@@ -27,6 +29,9 @@ var (
 	ErrContainerNotRunning     = errors.New("the container is not running")
 )
 
+// checkUpdatesWorkers is the number of containers checked for updates at the same time.
+const checkUpdatesWorkers = 8
+
 type containerService struct {
 	uuid       uuid.UUID
 	ctx        *app.Context
@@ -37,12 +42,25 @@ type containerService struct {
 	volumes    port.VolumeAdapter
 	tags       port.TagAdapter
 	sysctls    port.SysctlAdapter
+	health     port.HealthCheckAdapter
+	limits     port.ResourceLimitsAdapter
+	builds     port.BuildAdapter
+	deps       port.DependencyAdapter
 	runner     port.RunnerAdapter
 	templates  port.TemplateAdapter
+	revisions  port.TemplateRevisionAdapter
 	logs       port.LogsAdapter
+	networks   port.NetworkAdapter
+	hostPorts  port.HostPortsAdapter
+	keyring    *vcrypto.Keyring
 
 	cacheImageTags map[string][]string
 	mu             sync.RWMutex
+
+	restarts   map[uuid.UUID]*restartState
+	restartsMu sync.Mutex
+
+	stopStats context.CancelFunc
 }
 
 func NewContainerService(ctx *app.Context,
@@ -53,9 +71,17 @@ func NewContainerService(ctx *app.Context,
 	volumes port.VolumeAdapter,
 	tags port.TagAdapter,
 	sysctls port.SysctlAdapter,
+	health port.HealthCheckAdapter,
+	limits port.ResourceLimitsAdapter,
+	builds port.BuildAdapter,
+	deps port.DependencyAdapter,
 	runner port.RunnerAdapter,
 	services port.TemplateAdapter,
+	revisions port.TemplateRevisionAdapter,
 	logs port.LogsAdapter,
+	networks port.NetworkAdapter,
+	hostPorts port.HostPortsAdapter,
+	keyring *vcrypto.Keyring,
 ) port.ContainerService {
 	s := &containerService{
 		uuid:           uuid.New(),
@@ -67,10 +93,19 @@ func NewContainerService(ctx *app.Context,
 		volumes:        volumes,
 		tags:           tags,
 		sysctls:        sysctls,
+		health:         health,
+		limits:         limits,
+		builds:         builds,
+		deps:           deps,
 		runner:         runner,
 		templates:      services,
+		revisions:      revisions,
 		logs:           logs,
+		networks:       networks,
+		hostPorts:      hostPorts,
+		keyring:        keyring,
 		cacheImageTags: make(map[string][]string),
+		restarts:       make(map[uuid.UUID]*restartState),
 	}
 	s.ctx.AddListener(s)
 	return s
@@ -100,12 +135,17 @@ func (s *containerService) CreateContainer(ctx context.Context, opts types.Creat
 		color       *string
 		icon        *string
 		cmd         *string
-
-		env     []types.TemplateEnv
-		caps    []string
-		ports   []types.TemplatePort
-		volumes = map[string]string{}
-		sysctls = map[string]string{}
+		revision    *string
+
+		env         []types.TemplateEnv
+		caps        []string
+		ports       []types.TemplatePort
+		volumes     = map[string]string{}
+		sysctls     = map[string]string{}
+		healthCheck *types.TemplateHealthCheck
+		resources   *types.TemplateResources
+		build       *types.ContainerBuild
+		networks    []types.TemplateNetwork
 	)
 
 	if opts.TemplateID != nil {
@@ -113,6 +153,9 @@ func (s *containerService) CreateContainer(ctx context.Context, opts types.Creat
 		if err != nil {
 			return nil, err
 		}
+		if template.IsStack() {
+			return nil, errors.NewNotValid(nil, "stack templates must be created as stacks")
+		}
 
 		image = template.Methods.Docker.Image
 		name = &template.Name
@@ -132,31 +175,81 @@ func (s *containerService) CreateContainer(ctx context.Context, opts types.Creat
 		if template.Methods.Docker.Sysctls != nil {
 			sysctls = *template.Methods.Docker.Sysctls
 		}
+		healthCheck = template.Methods.Docker.Healthcheck
+		resources = template.Methods.Docker.Resources
+		networks = template.Networks
+
+		// The revision is kept to show the changes of the template later.
+		rev, err := template.Revision()
+		if err != nil {
+			return nil, err
+		}
+		err = s.revisions.CreateRevision(ctx, rev, template)
+		if err != nil {
+			return nil, err
+		}
+		revision = &rev
 
-		dir := path.Join(storage.FSPath, id.String())
 		if template.Methods.Docker.Clone != nil {
-			err := vstorage.CloneRepository(dir, template.Methods.Docker.Clone.Repository)
+			build = &types.ContainerBuild{
+				ID:          uuid.New(),
+				ContainerID: id,
+				Repository:  template.Methods.Docker.Clone.Repository,
+				Dockerfile:  "Dockerfile",
+			}
+			if template.Methods.Docker.Dockerfile != nil {
+				build.Dockerfile = *template.Methods.Docker.Dockerfile
+			}
+			for name, value := range template.Methods.Docker.BuildArgs {
+				build.Args = append(build.Args, types.BuildArg{
+					ID:          uuid.New(),
+					ContainerID: id,
+					Name:        name,
+					Value:       value,
+				})
+			}
+
+			err := vstorage.CloneRepository(build.Repository, buildDir(id))
 			if err != nil {
 				return nil, err
 			}
+
+			// The image is built from the repository, under the name of the container image.
+			image = new(string)
+			*image = (&types.Container{ID: id}).DockerImageVertexName()
+			imageTag = new(string)
+			*imageTag = "latest"
 		}
 	}
 
+	values, err := types.TemplateEnvValues(env, opts.Env)
+	if err != nil {
+		return nil, err
+	}
+
+	containerPorts, err := s.templatePorts(ctx, id, ports, opts.AllocatePorts)
+	if err != nil {
+		return nil, err
+	}
+
 	c := types.Container{
-		ID:              id,
-		TemplateID:      opts.TemplateID,
-		Image:           *image,
-		ImageTag:        *imageTag,
-		Status:          types.ContainerStatusOff,
-		LaunchOnStartup: true,
-		Name:            *name,
-		Description:     description,
-		Color:           color,
-		Icon:            icon,
-		Command:         cmd,
+		ID:               id,
+		TemplateID:       opts.TemplateID,
+		TemplateRevision: revision,
+		Image:            *image,
+		ImageTag:         *imageTag,
+		Status:           types.ContainerStatusOff,
+		LaunchOnStartup:  true,
+		RestartPolicy:    types.RestartPolicyNo,
+		UpdatePolicy:     types.UpdatePolicyNotify,
+		Name:             *name,
+		Description:      description,
+		Color:            color,
+		Icon:             icon,
+		Command:          cmd,
 	}
 
-	err := s.containers.CreateContainer(ctx, c)
+	err = s.containers.CreateContainer(ctx, c)
 	if err != nil {
 		return nil, err
 	}
@@ -169,10 +262,10 @@ func (s *containerService) CreateContainer(ctx context.Context, opts types.Creat
 			Type:        types.EnvVariableType(e.Type),
 			Name:        e.Name,
 			DisplayName: e.DisplayName,
-			Value:       e.Default,
+			Value:       values[e.Name],
 			Default:     &e.Default,
 			Description: &e.Description,
-			Secret:      e.Secret != nil && *e.Secret,
+			Secret:      e.IsSecret(),
 		})
 		if err != nil {
 			return nil, err
@@ -192,13 +285,8 @@ func (s *containerService) CreateContainer(ctx context.Context, opts types.Creat
 	}
 
 	// Set default ports
-	for _, p := range ports {
-		err = s.ports.CreatePort(ctx, types.Port{
-			ID:          uuid.New(),
-			ContainerID: id,
-			In:          p.Port,
-			Out:         p.Port,
-		})
+	for _, p := range containerPorts {
+		err = s.ports.CreatePort(ctx, p)
 		if err != nil {
 			return nil, err
 		}
@@ -236,6 +324,59 @@ func (s *containerService) CreateContainer(ctx context.Context, opts types.Creat
 		}
 	}
 
+	// Set default health check
+	if healthCheck != nil {
+		err = s.createHealthCheck(ctx, id, *healthCheck)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Set build
+	if build != nil {
+		err = s.builds.SetContainerBuild(ctx, *build)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Set default resource limits
+	if resources != nil {
+		limits, err := resources.ResourceLimits(id)
+		if err != nil {
+			return nil, err
+		}
+		err = s.limits.SetContainerResourceLimits(ctx, limits)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Attach to networks
+	for _, tn := range networks {
+		n, err := s.networks.GetNetworkByName(ctx, tn.Name)
+		if errors.Is(err, errors.NotFound) {
+			n, err = createNetwork(ctx, s.networks, s.runner, tn.Name)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		cn := types.ContainerNetwork{
+			ContainerID: id,
+			NetworkID:   n.ID,
+			Aliases:     tn.Aliases,
+		}
+		err = cn.Validate()
+		if err != nil {
+			return nil, err
+		}
+		err = s.networks.SetContainerNetwork(ctx, cn)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	err = s.logs.Register(id)
 	if err != nil {
 		return nil, err
@@ -247,6 +388,51 @@ func (s *containerService) CreateContainer(ctx context.Context, opts types.Creat
 	return &c, nil
 }
 
+// templatePorts returns the ports of a new container from the ports of its
+// template. Ports already used are either moved to free ones, or rejected.
+func (s *containerService) templatePorts(ctx context.Context, id uuid.UUID, ports []types.TemplatePort, allocate bool) (types.Ports, error) {
+	if len(ports) == 0 {
+		return nil, nil
+	}
+
+	used, err := s.ports.GetPorts(ctx, types.PortFilters{})
+	if err != nil {
+		return nil, err
+	}
+
+	var res types.Ports
+	for _, tp := range ports {
+		p := types.Port{
+			ID:          uuid.New(),
+			ContainerID: id,
+			In:          tp.Port,
+			Out:         tp.Port,
+			Protocol:    types.PortProtocolTCP,
+		}
+		if tp.Protocol != "" {
+			p.Protocol = types.PortProtocol(tp.Protocol)
+		}
+		err = p.Validate()
+		if err != nil {
+			return nil, err
+		}
+
+		if allocate {
+			p.Out, err = allocatePort(s.hostPorts, used, p)
+		} else {
+			err = checkPort(s.hostPorts, used, p)
+		}
+		if err != nil {
+			return nil, err
+		}
+		out := p.Out
+		p.TemplateOut = &out
+		used = append(used, p)
+		res = append(res, p)
+	}
+	return res, nil
+}
+
 func (s *containerService) Delete(ctx context.Context, id uuid.UUID) error {
 	c, err := s.containers.GetContainer(ctx, id)
 	if err != nil {
@@ -257,6 +443,8 @@ func (s *containerService) Delete(ctx context.Context, id uuid.UUID) error {
 		return types.ErrContainerStillRunning
 	}
 
+	s.cancelRestart(id)
+
 	err = s.runner.DeleteMounts(ctx, c)
 	if err != nil && !errors.Is(err, errors.NotFound) {
 		return err
@@ -281,6 +469,11 @@ func (s *containerService) Delete(ctx context.Context, id uuid.UUID) error {
 		s.ports.DeletePorts,
 		s.volumes.DeleteContainerVolumes,
 		s.sysctls.DeleteContainerSysctls,
+		s.health.DeleteContainerHealthCheck,
+		s.limits.DeleteContainerResourceLimits,
+		s.builds.DeleteContainerBuild,
+		s.deps.DeleteContainerDependencies,
+		s.networks.DeleteContainerNetworks,
 		s.vars.DeleteEnvs,
 		s.containers.DeleteTags,
 		s.containers.DeleteContainer,
@@ -297,6 +490,11 @@ func (s *containerService) Delete(ctx context.Context, id uuid.UUID) error {
 		return err
 	}
 
+	err = os.RemoveAll(buildDir(id))
+	if err != nil {
+		return err
+	}
+
 	s.ctx.DispatchEvent(types.EventContainerDeleted{
 		ContainerID: id,
 	})
@@ -307,6 +505,29 @@ func (s *containerService) Delete(ctx context.Context, id uuid.UUID) error {
 
 func (s *containerService) UpdateContainer(ctx context.Context, id uuid.UUID, c types.Container) error {
 	c.ID = id
+	if c.RestartPolicy == "" {
+		c.RestartPolicy = types.RestartPolicyNo
+	}
+	err := c.RestartPolicy.Validate()
+	if err != nil {
+		return err
+	}
+	if c.RestartMaxRetries < 0 {
+		return errors.NewNotValid(nil, "restart max retries must be positive")
+	}
+	if c.UpdatePolicy == "" {
+		c.UpdatePolicy = types.UpdatePolicyNotify
+	}
+	err = c.UpdatePolicy.Validate()
+	if err != nil {
+		return err
+	}
+	if c.UpdateWindow != nil {
+		_, err = types.ParseMaintenanceWindow(*c.UpdateWindow)
+		if err != nil {
+			return err
+		}
+	}
 	return s.containers.UpdateContainer(ctx, c)
 }
 
@@ -320,6 +541,16 @@ func (s *containerService) Start(ctx context.Context, id uuid.UUID) error {
 		return nil
 	}
 
+	s.stopRestartTimer(id)
+
+	if c.StoppedByUser {
+		err = s.containers.SetStoppedByUser(ctx, id, false)
+		if err != nil {
+			return err
+		}
+		c.StoppedByUser = false
+	}
+
 	// We don't want to cancel a container start operation.
 	ctx = context.WithoutCancel(ctx)
 
@@ -348,7 +579,22 @@ func (s *containerService) Start(ctx context.Context, id uuid.UUID) error {
 	}
 
 	setStatus := func(status string) {
+		// The container may have been modified since it was started,
+		// for example by Stop, so the latest version is read first.
+		latest, err := s.containers.GetContainer(ctx, id)
+		if err != nil {
+			log.Error(err, vlog.String("id", id.String()))
+			latest = c
+		}
+		prev := latest.Status
+		c.Status = latest.Status
+
+		// Health reports must not override the stopping status set by Stop.
+		if prev == types.ContainerStatusStopping && (status == types.ContainerStatusHealthy || status == types.ContainerStatusUnhealthy) {
+			return
+		}
 		s.setStatus(c, status)
+		s.onRunnerStatus(*latest, prev, status)
 	}
 
 	ports, err := s.ports.GetPorts(ctx, types.PortFilters{
@@ -372,6 +618,13 @@ func (s *containerService) Start(ctx context.Context, id uuid.UUID) error {
 		s.setStatus(c, types.ContainerStatusError)
 		return err
 	}
+	for i := range env {
+		err = decryptEnv(s.keyring, &env[i])
+		if err != nil {
+			s.setStatus(c, types.ContainerStatusError)
+			return err
+		}
+	}
 
 	caps, err := s.caps.GetContainerCaps(ctx, id)
 	if err != nil {
@@ -385,7 +638,34 @@ func (s *containerService) Start(ctx context.Context, id uuid.UUID) error {
 		return err
 	}
 
-	stdout, stderr, err := s.runner.Start(ctx, c, ports, volumes, env, caps, sysctls, setStatus)
+	healthCheck, err := s.health.GetContainerHealthCheck(ctx, id)
+	if err != nil && !errors.Is(err, errors.NotFound) {
+		s.setStatus(c, types.ContainerStatusError)
+		return err
+	}
+
+	limits, err := s.limits.GetContainerResourceLimits(ctx, id)
+	if err != nil && !errors.Is(err, errors.NotFound) {
+		s.setStatus(c, types.ContainerStatusError)
+		return err
+	}
+
+	build, err := s.builds.GetContainerBuild(ctx, id)
+	if err != nil && !errors.Is(err, errors.NotFound) {
+		s.setStatus(c, types.ContainerStatusError)
+		return err
+	}
+	if build != nil {
+		build.Dir = buildDir(id)
+	}
+
+	networks, err := networkAttachments(ctx, s.networks, id)
+	if err != nil {
+		s.setStatus(c, types.ContainerStatusError)
+		return err
+	}
+
+	stdout, stderr, err := s.runner.Start(ctx, c, ports, volumes, env, caps, sysctls, healthCheck, limits, build, networks, setStatus)
 	if err != nil {
 		s.setStatus(c, types.ContainerStatusError)
 
@@ -426,11 +706,7 @@ func (s *containerService) Start(ctx context.Context, id uuid.UUID) error {
 				continue
 			}
 
-			s.ctx.DispatchEvent(types.EventContainerLog{
-				ContainerID: id,
-				Kind:        types.LogKindOut,
-				Message:     types.NewLogLineMessageString(scanner.Text()),
-			})
+			s.dispatchLogLine(id, types.ParseLogLine(types.LogKindOut, scanner.Text()))
 		}
 		if scanner.Err() != nil {
 			log.Error(scanner.Err())
@@ -446,11 +722,7 @@ func (s *containerService) Start(ctx context.Context, id uuid.UUID) error {
 	go func() {
 		scanner := bufio.NewScanner(stderr)
 		for scanner.Scan() {
-			s.ctx.DispatchEvent(types.EventContainerLog{
-				ContainerID: id,
-				Kind:        types.LogKindErr,
-				Message:     types.NewLogLineMessageString(scanner.Text()),
-			})
+			s.dispatchLogLine(id, types.ParseLogLine(types.LogKindErr, scanner.Text()))
 		}
 
 		if scanner.Err() != nil {
@@ -486,40 +758,148 @@ func (s *containerService) Start(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
+// StartAll starts the containers launched on startup, and the containers
+// they depend on. Each container is started once its dependencies have
+// reached their expected status.
 func (s *containerService) StartAll(ctx context.Context) error {
-	var ids []uuid.UUID
-
-	// TODO: Retrieve only the containers where LaunchOnStartup is true in the DB.
+	ctx = context.WithoutCancel(ctx)
 
 	all, err := s.containers.GetContainers(ctx)
 	if err != nil {
 		return err
 	}
 
+	deps, err := s.deps.GetDependencies(ctx)
+	if err != nil {
+		return err
+	}
+
+	start := map[uuid.UUID]bool{}
 	for _, inst := range all {
 		// vertex containers autostart are managed by the startup service.
-		if inst.LaunchOnStartup {
+		if inst.LaunchOnStartup || inst.RestartPolicy == types.RestartPolicyAlways ||
+			(inst.RestartPolicy == types.RestartPolicyUnlessStopped && !inst.StoppedByUser) {
+			start[inst.ID] = true
+		}
+	}
+
+	// The dependencies of a started container must be started too.
+	for added := true; added; {
+		added = false
+		for _, dep := range deps {
+			if start[dep.ContainerID] && !start[dep.DependsOnID] {
+				start[dep.DependsOnID] = true
+				added = true
+			}
+		}
+	}
+
+	var ids []uuid.UUID
+	for _, inst := range all {
+		if start[inst.ID] {
 			ids = append(ids, inst.ID)
 		}
 	}
 
-	// Start them
+	ids, err = deps.Sort(ids)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		_ = s.startOrdered(ctx, ids, deps)
+	}()
+
+	return nil
+}
+
+// StartContainers starts the given containers, each one once its
+// dependencies have reached their expected status. It returns when all
+// containers are started.
+func (s *containerService) StartContainers(ctx context.Context, ids []uuid.UUID) error {
+	ctx = context.WithoutCancel(ctx)
+
+	deps, err := s.deps.GetDependencies(ctx)
+	if err != nil {
+		return err
+	}
+
+	ids, err = deps.Sort(ids)
+	if err != nil {
+		return err
+	}
+
+	return s.startOrdered(ctx, ids, deps)
+}
+
+// startOrdered starts the containers in parallel, but a container is started
+// only after its dependencies. ids must be sorted by dependencies.
+func (s *containerService) startOrdered(ctx context.Context, ids []uuid.UUID, deps types.Dependencies) error {
+	type result struct {
+		done chan struct{}
+		err  error
+	}
+
+	results := map[uuid.UUID]*result{}
+	for _, id := range ids {
+		results[id] = &result{done: make(chan struct{})}
+	}
+
 	for _, id := range ids {
 		go func(id uuid.UUID) {
-			err = s.Start(ctx, id)
-			if err != nil {
-				log.Warn("failed to auto-start the container",
+			res := results[id]
+			defer close(res.done)
+
+			for _, dep := range deps.Of(id) {
+				depRes, ok := results[dep.DependsOnID]
+				if !ok {
+					continue
+				}
+				<-depRes.done
+
+				err := depRes.err
+				if err == nil {
+					err = s.waitDependency(ctx, dep)
+				}
+				if err != nil {
+					res.err = errors.Annotatef(err, "dependency %s", dep.DependsOnID)
+					break
+				}
+			}
+
+			if res.err == nil {
+				err := s.Start(ctx, id)
+				if err != nil && !errors.Is(err, ErrContainerAlreadyRunning) {
+					res.err = err
+				}
+			}
+
+			if res.err != nil {
+				log.Warn("failed to start the container",
 					vlog.String("id", id.String()),
-					vlog.String("reason", err.Error()),
+					vlog.String("reason", res.err.Error()),
 				)
 			}
 		}(id)
 	}
 
-	return nil
+	var errs []error
+	for _, id := range ids {
+		<-results[id].done
+		if results[id].err != nil {
+			errs = append(errs, results[id].err)
+		}
+	}
+	return goerrors.Join(errs...)
 }
 
+// Stop stops a container at the request of the user. Containers with the
+// unless-stopped restart policy are then no longer restarted.
 func (s *containerService) Stop(ctx context.Context, id uuid.UUID) error {
+	return s.stop(ctx, id, true)
+}
+
+func (s *containerService) stop(ctx context.Context, id uuid.UUID, byUser bool) error {
 	c, err := s.containers.GetContainer(ctx, id)
 	if err != nil {
 		return err
@@ -529,6 +909,23 @@ func (s *containerService) Stop(ctx context.Context, id uuid.UUID) error {
 		return nil
 	}
 
+	if byUser && !c.StoppedByUser {
+		err = s.containers.SetStoppedByUser(ctx, id, true)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Stopping a container waiting to be restarted only cancels the restart.
+	if s.cancelRestart(id) && !c.IsRunning() {
+		s.ctx.DispatchEvent(types.EventContainerLog{
+			ContainerID: id,
+			Kind:        types.LogKindVertexOut,
+			Message:     types.NewLogLineMessageString("Restart cancelled."),
+		})
+		return nil
+	}
+
 	if !c.IsRunning() {
 		s.ctx.DispatchEvent(types.EventContainerLog{
 			ContainerID: id,
@@ -565,22 +962,65 @@ func (s *containerService) Stop(ctx context.Context, id uuid.UUID) error {
 	return err
 }
 
+// StopAll stops all containers, the dependents before their dependencies.
+// It is not considered as a stop by the user, so that the containers are
+// restarted with Vertex.
 func (s *containerService) StopAll(ctx context.Context) error {
 	all, err := s.containers.GetContainers(ctx)
 	if err != nil {
 		return err
 	}
 
+	var ids []uuid.UUID
 	for _, c := range all {
-		err := s.Stop(ctx, c.ID)
-		if err != nil {
-			log.Error(err)
-		}
+		ids = append(ids, c.ID)
+	}
+
+	err = s.stopContainers(ctx, ids, false)
+	if err != nil {
+		log.Error(err)
 	}
 
 	return nil
 }
 
+// StopContainers stops the given containers that are running, the dependents
+// before their dependencies.
+func (s *containerService) StopContainers(ctx context.Context, ids []uuid.UUID) error {
+	return s.stopContainers(ctx, ids, true)
+}
+
+func (s *containerService) stopContainers(ctx context.Context, ids []uuid.UUID, byUser bool) error {
+	deps, err := s.deps.GetDependencies(ctx)
+	if err != nil {
+		return err
+	}
+
+	ids, err = deps.Sort(ids)
+	if err != nil {
+		return err
+	}
+	slices.Reverse(ids)
+
+	var errs []error
+	for _, id := range ids {
+		c, err := s.containers.GetContainer(ctx, id)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !c.IsRunning() {
+			continue
+		}
+
+		err = s.stop(ctx, id, byUser)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return goerrors.Join(errs...)
+}
+
 func (s *containerService) AddContainerTag(ctx context.Context, id uuid.UUID, tagID uuid.UUID) error {
 	return s.containers.AddTag(ctx, id, tagID)
 }
@@ -630,14 +1070,32 @@ func (s *containerService) CheckForUpdates(ctx context.Context) (types.Container
 		return nil, err
 	}
 
-	for _, c := range all {
-		err := s.runner.CheckForUpdates(ctx, &c)
-		if err != nil {
-			return all, err
-		}
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+		sem  = make(chan struct{}, checkUpdatesWorkers)
+	)
+	for i := range all {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(c *types.Container) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			err := s.runner.CheckForUpdates(ctx, c)
+			// Containers that were never started have no image yet.
+			if err != nil && !errors.Is(err, errors.NotFound) {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+		}(&all[i])
 	}
+	wg.Wait()
 
-	return all, nil
+	return all, goerrors.Join(errs...)
 }
 
 func (s *containerService) SetDatabases(ctx context.Context, c *types.Container, databases map[string]uuid.UUID, options map[string]*types.SetDatabasesOptions) error {
@@ -656,9 +1114,100 @@ func (s *containerService) SetDatabases(ctx context.Context, c *types.Container,
 		}
 	}
 
+	// The container must be started after its databases, and no longer
+	// after the databases it was linked to before.
+	var deps, removed types.Dependencies
+	linked := map[uuid.UUID]bool{}
+	for _, dbID := range databases {
+		db, err := s.containers.GetContainer(ctx, dbID)
+		if err != nil {
+			return err
+		}
+		if db.ID != dbID {
+			return types.ErrContainerNotFound
+		}
+		linked[dbID] = true
+		dep := types.Dependency{
+			ContainerID: c.ID,
+			DependsOnID: dbID,
+			WaitFor:     types.DependencyConditionRunning,
+		}
+		err = dep.Validate()
+		if err != nil {
+			return err
+		}
+		deps = append(deps, dep)
+	}
+
+	current, err := s.deps.GetContainerDependencies(ctx, c.ID)
+	if err != nil {
+		return err
+	}
+	for _, dep := range current {
+		if linked[dep.DependsOnID] {
+			continue
+		}
+		isDB, err := s.isDatabase(ctx, dep.DependsOnID)
+		if err != nil {
+			return err
+		}
+		if isDB {
+			removed = append(removed, dep)
+		}
+	}
+
+	all, err := s.deps.GetDependencies(ctx)
+	if err != nil {
+		return err
+	}
+	all = slices.DeleteFunc(all, func(dep types.Dependency) bool {
+		return dep.ContainerID == c.ID && (linked[dep.DependsOnID] || slices.Contains(removed, dep))
+	})
+	err = append(all, deps...).CheckCycles()
+	if err != nil {
+		return err
+	}
+
+	prev := c.Databases
 	c.Databases = databases
-	// TODO: Save
-	return s.remapDatabaseEnv(ctx, c, options)
+	err = s.remapDatabaseEnv(ctx, c, options)
+	if err != nil {
+		c.Databases = prev
+		return err
+	}
+
+	for _, dep := range removed {
+		err = s.deps.DeleteDependency(ctx, dep.ContainerID, dep.DependsOnID)
+		if err != nil {
+			return err
+		}
+	}
+	for _, dep := range deps {
+		err = s.deps.SetDependency(ctx, dep)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isDatabase returns whether the container is created from a template that
+// provides a database. Missing containers and templates are not databases.
+func (s *containerService) isDatabase(ctx context.Context, id uuid.UUID) (bool, error) {
+	c, err := s.containers.GetContainer(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	if c.ID != id || c.TemplateID == nil {
+		return false, nil
+	}
+	t, err := s.templates.Get(*c.TemplateID)
+	if errors.Is(err, errors.NotFound) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return t.Features != nil && t.Features.Databases != nil && len(*t.Features.Databases) > 0, nil
 }
 
 // remapDatabaseEnv remaps the environment variables of a container.
@@ -679,8 +1228,6 @@ func (s *containerService) remapDatabaseEnv(ctx context.Context, c *types.Contai
 			return err
 		}
 
-		host := config.Current.Addr("vertex").String()
-
 		dbEnvNames := (*dbService.Features.Databases)[0]
 		cEnvNames := cService.Databases[databaseID].Names
 
@@ -693,6 +1240,10 @@ func (s *containerService) remapDatabaseEnv(ctx context.Context, c *types.Contai
 
 		var port, username, password, name string
 		for _, v := range dbVars {
+			err = decryptEnv(s.keyring, &v)
+			if err != nil {
+				return err
+			}
 			if v.Name == dbEnvNames.Port {
 				port = v.Value
 			} else if dbEnvNames.Username != nil && v.Name == *dbEnvNames.Username {
@@ -704,6 +1255,11 @@ func (s *containerService) remapDatabaseEnv(ctx context.Context, c *types.Contai
 			}
 		}
 
+		host, port, err := s.databaseAddress(ctx, c, db, port)
+		if err != nil {
+			return err
+		}
+
 		err = s.vars.UpdateEnvByName(ctx, types.EnvVariable{
 			ContainerID: c.ID,
 			Name:        cEnvNames.Host,
@@ -775,6 +1331,53 @@ func (s *containerService) remapDatabaseEnv(ctx context.Context, c *types.Contai
 	return nil
 }
 
+// databaseAddress returns the host and the port where the container c can
+// reach the database db, listening on the given host port. Containers that
+// share a network reach the database with its alias, and its port in the
+// container. Other containers go through the host.
+func (s *containerService) databaseAddress(ctx context.Context, c *types.Container, db *types.Container, port string) (string, string, error) {
+	cNetworks, err := s.networks.GetContainerNetworks(ctx, c.ID)
+	if err != nil {
+		return "", "", err
+	}
+	dbNetworks, err := s.networks.GetContainerNetworks(ctx, db.ID)
+	if err != nil {
+		return "", "", err
+	}
+
+	var host string
+	for _, n := range dbNetworks {
+		if cNetworks.Find(n.NetworkID) == nil {
+			continue
+		}
+		host = db.DockerContainerName()
+		if len(n.Aliases) > 0 {
+			host = n.Aliases[0]
+		}
+		break
+	}
+	if host == "" && c.StackID != nil && db.StackID != nil && *c.StackID == *db.StackID && db.StackMember != nil {
+		host = *db.StackMember
+	}
+	if host == "" {
+		return config.Current.Addr("vertex").String(), port, nil
+	}
+
+	ports, err := s.ports.GetPorts(ctx, types.PortFilters{
+		ContainerID: &db.ID,
+	})
+	if err != nil {
+		return "", "", err
+	}
+	for _, p := range ports {
+		if p.Out == port {
+			port = p.In
+			break
+		}
+	}
+	return host, port, nil
+}
+
 func (s *containerService) GetAllVersions(ctx context.Context, id uuid.UUID, useCache bool) ([]string, error) {
 	c, err := s.containers.GetContainer(ctx, id)
 	if err != nil {
@@ -841,10 +1444,46 @@ func (s *containerService) WaitStatus(ctx context.Context, id uuid.UUID, status
 	return errors.Timeoutf("wait status")
 }
 
+func (s *containerService) dispatchLogLine(id uuid.UUID, line types.LogLine) {
+	s.ctx.DispatchEvent(types.EventContainerLog{
+		ContainerID: id,
+		Kind:        line.Kind,
+		Message:     line.Message,
+		Time:        line.Time,
+		Level:       line.Level,
+		Fields:      line.Fields,
+	})
+}
+
 func (s *containerService) GetLatestLogs(id uuid.UUID) ([]types.LogLine, error) {
 	return s.logs.LoadBuffer(id)
 }
 
+func (s *containerService) SearchLogs(ctx context.Context, id uuid.UUID, filters types.LogFilters) ([]types.LogLine, error) {
+	c, err := s.containers.GetContainer(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if c.ID != id {
+		return nil, types.ErrContainerNotFound
+	}
+	if filters.Limit > types.MaxLogsLimit {
+		filters.Limit = types.MaxLogsLimit
+	}
+	return s.logs.Search(id, filters)
+}
+
+func (s *containerService) DownloadLogs(ctx context.Context, id uuid.UUID, w io.Writer) error {
+	c, err := s.containers.GetContainer(ctx, id)
+	if err != nil {
+		return err
+	}
+	if c.ID != id {
+		return types.ErrContainerNotFound
+	}
+	return s.logs.Archive(id, w)
+}
+
 func (s *containerService) GetTemplateByID(ctx context.Context, id string) (*types.Template, error) {
 	serv, err := s.templates.Get(id)
 	if err != nil {
@@ -885,6 +1524,15 @@ func (s *containerService) ReloadContainer(ctx context.Context, id uuid.UUID) er
 	return nil
 }
 
+func (s *containerService) createHealthCheck(ctx context.Context, id uuid.UUID, t types.TemplateHealthCheck) error {
+	hc := t.HealthCheck(id)
+	err := hc.Validate()
+	if err != nil {
+		return err
+	}
+	return s.health.SetContainerHealthCheck(ctx, hc)
+}
+
 func (s *containerService) setStatus(c *types.Container, status string) {
 	if c.Status == status {
 		return
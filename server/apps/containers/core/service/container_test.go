@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/vertex-center/uuid"
+	"github.com/vertex-center/vertex/server/apps/containers/core/port"
+	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+	"github.com/vertex-center/vertex/server/common"
+	"github.com/vertex-center/vertex/server/common/app"
+)
+
+type ContainerServiceTestSuite struct {
+	suite.Suite
+
+	service    *containerService
+	containers *fakeContainerAdapter
+	templates  *fakeTemplateAdapter
+	deps       *fakeDependencyAdapter
+	vars       *fakeEnvAdapter
+	networks   *fakeNetworkAdapter
+
+	app      types.Container
+	postgres types.Container
+	mariadb  types.Container
+	redis    types.Container
+}
+
+func TestContainerServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(ContainerServiceTestSuite))
+}
+
+func (suite *ContainerServiceTestSuite) SetupTest() {
+	username, password := "POSTGRES_USER", "POSTGRES_PASSWORD"
+	suite.templates = &fakeTemplateAdapter{templates: map[string]types.Template{
+		"app": {ID: "app", Databases: map[string]types.DatabaseEnvironment{
+			"db": {Names: types.DatabaseEnvironmentNames{Host: "DB_HOST", Port: "DB_PORT", Username: "DB_USER", Password: "DB_PASSWORD", Database: "DB_NAME"}},
+		}},
+		"postgres": {ID: "postgres", Features: &types.Features{Databases: &[]types.DatabaseFeature{
+			{Type: "postgres", Port: "PORT", Username: &username, Password: &password},
+		}}},
+		"redis": {ID: "redis"},
+	}}
+
+	network := uuid.New()
+	suite.networks = &fakeNetworkAdapter{networks: map[uuid.UUID]types.ContainerNetworks{}}
+	suite.containers = &fakeContainerAdapter{containers: map[uuid.UUID]types.Container{}}
+	suite.vars = &fakeEnvAdapter{}
+	for _, c := range []*types.Container{&suite.app, &suite.postgres, &suite.mariadb} {
+		*c = types.Container{ID: uuid.New()}
+		suite.networks.networks[c.ID] = types.ContainerNetworks{{ContainerID: c.ID, NetworkID: network, Aliases: []string{c.ID.String()}}}
+	}
+	appID, postgresID, redisID := "app", "postgres", "redis"
+	suite.app.TemplateID = &appID
+	suite.postgres.TemplateID = &postgresID
+	suite.mariadb.TemplateID = &postgresID
+	for _, c := range []types.Container{suite.app, suite.postgres, suite.mariadb} {
+		suite.containers.containers[c.ID] = c
+	}
+	for _, name := range []string{"DB_HOST", "DB_PORT", "DB_USER", "DB_PASSWORD", "DB_NAME"} {
+		suite.vars.env = append(suite.vars.env, types.EnvVariable{ID: uuid.New(), ContainerID: suite.app.ID, Name: name})
+	}
+	suite.vars.env = append(suite.vars.env,
+		types.EnvVariable{ID: uuid.New(), ContainerID: suite.postgres.ID, Name: "PORT", Value: "5432"},
+		types.EnvVariable{ID: uuid.New(), ContainerID: suite.postgres.ID, Name: "POSTGRES_USER", Value: "vertex"},
+	)
+
+	suite.redis = types.Container{ID: uuid.New(), TemplateID: &redisID}
+	suite.containers.containers[suite.redis.ID] = suite.redis
+	suite.deps = &fakeDependencyAdapter{deps: types.Dependencies{
+		{ContainerID: suite.app.ID, DependsOnID: suite.mariadb.ID, WaitFor: types.DependencyConditionRunning},
+		{ContainerID: suite.app.ID, DependsOnID: suite.redis.ID, WaitFor: types.DependencyConditionHealthy},
+	}}
+
+	suite.service = &containerService{
+		ctx:        app.NewContext(common.NewVertexContext(common.About{}, false)),
+		containers: suite.containers,
+		templates:  suite.templates,
+		deps:       suite.deps,
+		vars:       suite.vars,
+		ports:      &fakePortAdapter{},
+		networks:   suite.networks,
+	}
+}
+
+func (suite *ContainerServiceTestSuite) TestSetDatabases() {
+	c := suite.app
+	err := suite.service.SetDatabases(context.Background(), &c, map[string]uuid.UUID{"db": suite.postgres.ID}, nil)
+	suite.Require().NoError(err)
+
+	// The previous database is replaced, the other dependencies are kept.
+	suite.ElementsMatch(types.Dependencies{
+		{ContainerID: suite.app.ID, DependsOnID: suite.postgres.ID, WaitFor: types.DependencyConditionRunning},
+		{ContainerID: suite.app.ID, DependsOnID: suite.redis.ID, WaitFor: types.DependencyConditionHealthy},
+	}, suite.deps.deps)
+
+	values := map[string]string{}
+	for _, v := range suite.vars.env {
+		if v.ContainerID == suite.app.ID {
+			values[v.Name] = v.Value
+		}
+	}
+	suite.Equal(suite.postgres.ID.String(), values["DB_HOST"])
+	suite.Equal("5432", values["DB_PORT"])
+	suite.Equal("vertex", values["DB_USER"])
+}
+
+func (suite *ContainerServiceTestSuite) TestSetDatabasesMissingContainer() {
+	c := suite.app
+	err := suite.service.SetDatabases(context.Background(), &c, map[string]uuid.UUID{"db": uuid.New()}, nil)
+	suite.ErrorIs(err, types.ErrContainerNotFound)
+	suite.Len(suite.deps.deps, 2)
+}
+
+func (suite *ContainerServiceTestSuite) TestSetDatabasesRemapFailed() {
+	suite.networks.err = errors.New("network error")
+
+	c := suite.app
+	err := suite.service.SetDatabases(context.Background(), &c, map[string]uuid.UUID{"db": suite.postgres.ID}, nil)
+	suite.Error(err)
+	suite.Len(suite.deps.deps, 2)
+	suite.Equal(suite.mariadb.ID, suite.deps.deps[0].DependsOnID)
+}
+
+type fakeNetworkAdapter struct {
+	port.NetworkAdapter
+	networks map[uuid.UUID]types.ContainerNetworks
+	err      error
+}
+
+func (a *fakeNetworkAdapter) GetContainerNetworks(ctx context.Context, id uuid.UUID) (types.ContainerNetworks, error) {
+	return a.networks[id], a.err
+}
+
+func (a *fakeNetworkAdapter) SetContainerNetwork(ctx context.Context, n types.ContainerNetwork) error {
+	a.networks[n.ContainerID] = append(a.networks[n.ContainerID], n)
+	return nil
+}
+
+func (a *fakeNetworkAdapter) DeleteContainerNetworks(ctx context.Context, id uuid.UUID) error {
+	delete(a.networks, id)
+	return nil
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/vertex-center/uuid"
+	"github.com/vertex-center/vertex/server/apps/containers/core/port"
+	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+	"github.com/vertex-center/vertex/server/pkg/event"
+)
+
+// dependencyWaitTimeout is how long a container waits for its dependencies
+// to reach their expected status before being started.
+const dependencyWaitTimeout = 5 * time.Minute
+
+type dependencyService struct {
+	containers   port.ContainerAdapter
+	dependencies port.DependencyAdapter
+}
+
+func NewDependencyService(containers port.ContainerAdapter, dependencies port.DependencyAdapter) port.DependencyService {
+	return &dependencyService{containers, dependencies}
+}
+
+func (s *dependencyService) GetDependencies(ctx context.Context, containerID uuid.UUID) (types.Dependencies, error) {
+	return s.dependencies.GetContainerDependencies(ctx, containerID)
+}
+
+// SetDependency creates or replaces a dependency between two containers.
+func (s *dependencyService) SetDependency(ctx context.Context, dep types.Dependency) error {
+	for _, id := range []uuid.UUID{dep.ContainerID, dep.DependsOnID} {
+		c, err := s.containers.GetContainer(ctx, id)
+		if err != nil {
+			return err
+		}
+		if c.ID != id {
+			return types.ErrContainerNotFound
+		}
+	}
+	return setDependency(ctx, s.dependencies, dep)
+}
+
+func (s *dependencyService) DeleteDependency(ctx context.Context, containerID uuid.UUID, dependsOnID uuid.UUID) error {
+	return s.dependencies.DeleteDependency(ctx, containerID, dependsOnID)
+}
+
+// setDependency saves a dependency if it doesn't create a cycle.
+func setDependency(ctx context.Context, dependencies port.DependencyAdapter, dep types.Dependency) error {
+	err := dep.Validate()
+	if err != nil {
+		return err
+	}
+
+	all, err := dependencies.GetDependencies(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = append(all, dep).CheckCycles()
+	if err != nil {
+		return err
+	}
+
+	return dependencies.SetDependency(ctx, dep)
+}
+
+// waitDependency waits for the container the dependency points to to reach
+// the expected status. A dependency without health check is considered
+// healthy once running.
+func (s *containerService) waitDependency(ctx context.Context, dep types.Dependency) error {
+	cond := dep.WaitFor
+	if cond == types.DependencyConditionHealthy {
+		_, err := s.health.GetContainerHealthCheck(ctx, dep.DependsOnID)
+		if errors.Is(err, errors.NotFound) {
+			cond = types.DependencyConditionRunning
+		} else if err != nil {
+			return err
+		}
+	}
+
+	changed := make(chan struct{}, 1)
+	l := event.NewTempListener(func(e event.Event) error {
+		if e, ok := e.(types.EventContainerStatusChange); ok && e.ContainerID == dep.DependsOnID {
+			select {
+			case changed <- struct{}{}:
+			default:
+			}
+		}
+		return nil
+	})
+
+	s.ctx.AddListener(l)
+	defer s.ctx.RemoveListener(l)
+
+	timeout := time.After(dependencyWaitTimeout)
+	for {
+		c, err := s.containers.GetContainer(ctx, dep.DependsOnID)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case cond == types.DependencyConditionRunning && isUp(c.Status):
+			return nil
+		case cond == types.DependencyConditionHealthy && c.Status == types.ContainerStatusHealthy:
+			return nil
+		case c.Status == types.ContainerStatusOff || c.Status == types.ContainerStatusError:
+			return errors.Errorf("dependency %s is %s", dep.DependsOnID, c.Status)
+		}
+
+		select {
+		case <-changed:
+		case <-timeout:
+			return errors.Timeoutf("dependency %s to be %s", dep.DependsOnID, cond)
+		}
+	}
+}
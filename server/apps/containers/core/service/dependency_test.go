@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/vertex-center/uuid"
+	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+)
+
+type DependencyServiceTestSuite struct {
+	suite.Suite
+
+	service *dependencyService
+	deps    *fakeDependencyAdapter
+
+	app types.Container
+	db  types.Container
+}
+
+func TestDependencyServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(DependencyServiceTestSuite))
+}
+
+func (suite *DependencyServiceTestSuite) SetupTest() {
+	suite.app = types.Container{ID: uuid.New()}
+	suite.db = types.Container{ID: uuid.New()}
+	containers := &fakeContainerAdapter{containers: map[uuid.UUID]types.Container{
+		suite.app.ID: suite.app,
+		suite.db.ID:  suite.db,
+	}}
+	suite.deps = &fakeDependencyAdapter{}
+	suite.service = NewDependencyService(containers, suite.deps).(*dependencyService)
+}
+
+func (suite *DependencyServiceTestSuite) TestSetDependency() {
+	err := suite.service.SetDependency(context.Background(), types.Dependency{ContainerID: suite.app.ID, DependsOnID: suite.db.ID})
+	suite.Require().NoError(err)
+	suite.Equal(types.Dependencies{
+		{ContainerID: suite.app.ID, DependsOnID: suite.db.ID, WaitFor: types.DependencyConditionRunning},
+	}, suite.deps.deps)
+
+	err = suite.service.SetDependency(context.Background(), types.Dependency{ContainerID: suite.db.ID, DependsOnID: suite.app.ID})
+	suite.ErrorIs(err, types.ErrDependencyCycle)
+}
+
+func (suite *DependencyServiceTestSuite) TestSetDependencyMissingContainer() {
+	err := suite.service.SetDependency(context.Background(), types.Dependency{ContainerID: suite.app.ID, DependsOnID: uuid.New()})
+	suite.ErrorIs(err, types.ErrContainerNotFound)
+
+	err = suite.service.SetDependency(context.Background(), types.Dependency{ContainerID: uuid.New(), DependsOnID: suite.db.ID})
+	suite.ErrorIs(err, types.ErrContainerNotFound)
+	suite.Empty(suite.deps.deps)
+}
@@ -1,6 +1,8 @@
 package service
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"io"
 	"os"
@@ -11,6 +13,7 @@ import (
 	"github.com/vertex-center/vertex/server/apps/containers/core/port"
 	"github.com/vertex-center/vertex/server/apps/containers/core/types"
 	"github.com/vertex-center/vertex/server/common/log"
+	"github.com/vertex-center/vertex/server/pkg/varchiver"
 	"github.com/vertex-center/vlog"
 )
 
@@ -46,6 +49,18 @@ func (s dockerKernelService) InfoContainer(id string) (types.InfoContainerRespon
 	return s.adapter.InfoContainer(id)
 }
 
+func (s dockerKernelService) InspectContainer(id string) (types.InspectContainerResponse, error) {
+	return s.adapter.InspectContainer(id)
+}
+
+func (s dockerKernelService) RenameContainer(id string, name string) error {
+	return s.adapter.RenameContainer(id, name)
+}
+
+func (s dockerKernelService) StatsContainer(id string) (types.ContainerStats, error) {
+	return s.adapter.StatsContainer(id)
+}
+
 func (s dockerKernelService) LogsStdoutContainer(id string) (io.ReadCloser, error) {
 	return s.adapter.LogsStdoutContainer(id)
 }
@@ -90,3 +105,161 @@ func (s dockerKernelService) CreateVolume(name string) (volume.Volume, error) {
 func (s dockerKernelService) DeleteVolume(name string) error {
 	return s.adapter.DeleteVolume(name)
 }
+
+// ArchiveVolume writes the content of a volume into a tar.gz archive.
+func (s dockerKernelService) ArchiveVolume(options types.ArchiveVolumeOptions) (types.ArchiveVolumeResponse, error) {
+	dir, err := s.volumePath(options.Type, options.Name)
+	if err != nil {
+		return types.ArchiveVolumeResponse{}, err
+	}
+
+	file, err := os.OpenFile(options.Dest, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return types.ArchiveVolumeResponse{}, err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	counter := &countWriter{}
+	err = varchiver.Tar(dir, io.MultiWriter(file, hash, counter))
+	if err != nil {
+		_ = os.Remove(options.Dest)
+		return types.ArchiveVolumeResponse{}, err
+	}
+
+	return types.ArchiveVolumeResponse{
+		Size:     counter.n,
+		Checksum: "sha256:" + hex.EncodeToString(hash.Sum(nil)),
+	}, nil
+}
+
+// RestoreVolume replaces the content of a volume with a tar.gz archive,
+// after checking the archive is intact.
+func (s dockerKernelService) RestoreVolume(options types.RestoreVolumeOptions) error {
+	dir, err := s.volumePath(options.Type, options.Name)
+	if err != nil {
+		return err
+	}
+
+	checksum, err := fileChecksum(options.Src)
+	if err != nil {
+		return err
+	}
+	if checksum != options.Checksum {
+		return types.ErrBackupCorrupted
+	}
+
+	// The archive is extracted next to the volume first, so that the
+	// volume is left untouched if the archive can't be restored.
+	err = os.MkdirAll(path.Dir(dir), os.ModePerm)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.MkdirTemp(path.Dir(dir), ".restore-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	// Keep the mode of the volume if the archive doesn't have it.
+	if info, err := os.Stat(dir); err == nil {
+		err = os.Chmod(tmp, info.Mode().Perm())
+		if err != nil {
+			return err
+		}
+	}
+
+	err = varchiver.UntarWithOptions(options.Src, tmp, varchiver.UntarOptions{Preserve: true})
+	if err != nil {
+		return err
+	}
+
+	info, err := os.Stat(tmp)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(dir, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		err = os.RemoveAll(path.Join(dir, entry.Name()))
+		if err != nil {
+			return err
+		}
+	}
+
+	entries, err = os.ReadDir(tmp)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		err = os.Rename(path.Join(tmp, entry.Name()), path.Join(dir, entry.Name()))
+		if err != nil {
+			return err
+		}
+	}
+	return os.Chmod(dir, info.Mode().Perm())
+}
+
+func (s dockerKernelService) volumePath(t types.VolumeType, name string) (string, error) {
+	if t == types.VolumeTypeVolume {
+		return s.adapter.GetVolumeMountpoint(name)
+	}
+	return name, nil
+}
+
+func (s dockerKernelService) ExecContainer(id string, options types.ExecOptions) (port.ExecSession, error) {
+	if len(options.Cmd) == 0 {
+		options.Cmd = types.DefaultExecCmd
+	}
+	return s.adapter.ExecContainer(id, options)
+}
+
+func (s dockerKernelService) CreateNetwork(name string) error {
+	return s.adapter.CreateNetwork(types.CreateNetworkOptions{
+		Name: name,
+	})
+}
+
+func (s dockerKernelService) DeleteNetwork(name string) error {
+	return s.adapter.DeleteNetwork(name)
+}
+
+func (s dockerKernelService) ConnectNetwork(options types.ConnectNetworkOptions) error {
+	return s.adapter.ConnectNetwork(options)
+}
+
+func (s dockerKernelService) DisconnectNetwork(options types.DisconnectNetworkOptions) error {
+	return s.adapter.DisconnectNetwork(options)
+}
+
+func fileChecksum(p string) (string, error) {
+	file, err := os.Open(p)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	_, err = io.Copy(hash, file)
+	if err != nil {
+		return "", err
+	}
+	return "sha256:" + hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// countWriter counts the bytes written to it.
+type countWriter struct {
+	n int64
+}
+
+func (w *countWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
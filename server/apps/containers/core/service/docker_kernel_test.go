@@ -1,7 +1,11 @@
 package service
 
 import (
+	"archive/tar"
+	"compress/gzip"
 	"io"
+	"os"
+	"path"
 	"testing"
 
 	dockertypes "github.com/docker/docker/api/types"
@@ -11,6 +15,7 @@ import (
 	"github.com/stretchr/testify/suite"
 	"github.com/vertex-center/vertex/server/apps/containers/core/port"
 	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+	"github.com/vertex-center/vertex/server/pkg/varchiver"
 )
 
 type DockerKernelServiceTestSuite struct {
@@ -48,6 +53,60 @@ func (suite *DockerKernelServiceTestSuite) TestDeleteContainer() {
 	suite.adapter.AssertExpectations(suite.T())
 }
 
+func (suite *DockerKernelServiceTestSuite) TestRestoreVolume() {
+	dir := suite.T().TempDir()
+	volume := path.Join(dir, "volume")
+	suite.Require().NoError(os.MkdirAll(volume, 0755))
+	suite.Require().NoError(os.WriteFile(path.Join(volume, "old"), []byte("old"), 0644))
+
+	src := path.Join(dir, "backup")
+	suite.Require().NoError(os.MkdirAll(src, 0755))
+	suite.Require().NoError(os.WriteFile(path.Join(src, "new"), []byte("new"), 0644))
+	archive, err := os.Create(path.Join(dir, "backup.tar.gz"))
+	suite.Require().NoError(err)
+	suite.Require().NoError(varchiver.Tar(src, archive))
+	suite.Require().NoError(archive.Close())
+	checksum, err := fileChecksum(archive.Name())
+	suite.Require().NoError(err)
+
+	err = suite.service.RestoreVolume(types.RestoreVolumeOptions{Type: types.VolumeTypeBind, Name: volume, Src: archive.Name(), Checksum: checksum})
+	suite.Require().NoError(err)
+
+	entries, err := os.ReadDir(volume)
+	suite.Require().NoError(err)
+	suite.Require().Len(entries, 1)
+	suite.Equal("new", entries[0].Name())
+}
+
+func (suite *DockerKernelServiceTestSuite) TestRestoreVolumeInvalidArchive() {
+	dir := suite.T().TempDir()
+	volume := path.Join(dir, "volume")
+	suite.Require().NoError(os.MkdirAll(volume, 0755))
+	suite.Require().NoError(os.WriteFile(path.Join(volume, "data"), []byte("data"), 0644))
+
+	archive, err := os.Create(path.Join(dir, "backup.tar.gz"))
+	suite.Require().NoError(err)
+	stream := gzip.NewWriter(archive)
+	writer := tar.NewWriter(stream)
+	suite.Require().NoError(writer.WriteHeader(&tar.Header{Name: "file", Mode: 0644, Typeflag: tar.TypeReg}))
+	suite.Require().NoError(writer.WriteHeader(&tar.Header{Name: "etc", Typeflag: tar.TypeSymlink, Linkname: "/etc"}))
+	suite.Require().NoError(writer.Close())
+	suite.Require().NoError(stream.Close())
+	suite.Require().NoError(archive.Close())
+	checksum, err := fileChecksum(archive.Name())
+	suite.Require().NoError(err)
+
+	err = suite.service.RestoreVolume(types.RestoreVolumeOptions{Type: types.VolumeTypeBind, Name: volume, Src: archive.Name(), Checksum: checksum})
+	suite.ErrorIs(err, varchiver.ErrZipSlipAttack)
+
+	content, err := os.ReadFile(path.Join(volume, "data"))
+	suite.Require().NoError(err)
+	suite.Equal("data", string(content))
+	entries, err := os.ReadDir(dir)
+	suite.Require().NoError(err)
+	suite.Len(entries, 2, "the extracted files are removed")
+}
+
 func (suite *DockerKernelServiceTestSuite) TestCreateContainer() {
 	suite.adapter.On("CreateContainer", mock.Anything).Return(types.CreateContainerResponse{}, nil)
 
@@ -86,6 +145,16 @@ func (suite *DockerKernelServiceTestSuite) TestInfoContainer() {
 	suite.adapter.AssertExpectations(suite.T())
 }
 
+func (suite *DockerKernelServiceTestSuite) TestStatsContainer() {
+	suite.adapter.On("StatsContainer", mock.Anything).Return(types.ContainerStats{}, nil)
+
+	stats, err := suite.service.StatsContainer("")
+
+	suite.Require().NoError(err)
+	suite.Equal(types.ContainerStats{}, stats)
+	suite.adapter.AssertExpectations(suite.T())
+}
+
 func (suite *DockerKernelServiceTestSuite) TestLogsStdoutContainer() {
 	suite.adapter.On("LogsStdoutContainer", mock.Anything).Return(nil, nil)
 
@@ -179,6 +248,21 @@ func (m *MockDockerAdapter) InfoContainer(id string) (types.InfoContainerRespons
 	return args.Get(0).(types.InfoContainerResponse), args.Error(1)
 }
 
+func (m *MockDockerAdapter) InspectContainer(id string) (types.InspectContainerResponse, error) {
+	args := m.Called(id)
+	return args.Get(0).(types.InspectContainerResponse), args.Error(1)
+}
+
+func (m *MockDockerAdapter) RenameContainer(id string, name string) error {
+	args := m.Called(id, name)
+	return args.Error(0)
+}
+
+func (m *MockDockerAdapter) StatsContainer(id string) (types.ContainerStats, error) {
+	args := m.Called(id)
+	return args.Get(0).(types.ContainerStats), args.Error(1)
+}
+
 func (m *MockDockerAdapter) LogsStdoutContainer(id string) (io.ReadCloser, error) {
 	args := m.Called(id)
 	return nil, args.Error(1)
@@ -218,3 +302,33 @@ func (m *MockDockerAdapter) DeleteVolume(name string) error {
 	args := m.Called(name)
 	return args.Error(0)
 }
+
+func (m *MockDockerAdapter) GetVolumeMountpoint(name string) (string, error) {
+	args := m.Called(name)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockDockerAdapter) ExecContainer(id string, options types.ExecOptions) (port.ExecSession, error) {
+	args := m.Called(id, options)
+	return args.Get(0).(port.ExecSession), args.Error(1)
+}
+
+func (m *MockDockerAdapter) CreateNetwork(options types.CreateNetworkOptions) error {
+	args := m.Called(options)
+	return args.Error(0)
+}
+
+func (m *MockDockerAdapter) DeleteNetwork(name string) error {
+	args := m.Called(name)
+	return args.Error(0)
+}
+
+func (m *MockDockerAdapter) ConnectNetwork(options types.ConnectNetworkOptions) error {
+	args := m.Called(options)
+	return args.Error(0)
+}
+
+func (m *MockDockerAdapter) DisconnectNetwork(options types.DisconnectNetworkOptions) error {
+	args := m.Called(options)
+	return args.Error(0)
+}
@@ -0,0 +1,336 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/vertex-center/uuid"
+	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+	"github.com/vertex-center/vertex/server/common/log"
+	"github.com/vertex-center/vertex/server/common/storage"
+	vstorage "github.com/vertex-center/vertex/server/pkg/storage"
+	"github.com/vertex-center/vlog"
+)
+
+// duplicatesPath is the directory where the volumes are archived while
+// they are copied.
+var duplicatesPath = path.Join(storage.FSPath, "apps", "containers", "duplicates")
+
+// DuplicateContainer creates a copy of a container with its configuration.
+// Host ports already used are replaced by free ones. The volumes of the
+// copy are new volumes, so that the two containers don't share their data.
+func (s *containerService) DuplicateContainer(ctx context.Context, id uuid.UUID, opts types.DuplicateContainerOptions) (*types.Container, error) {
+	src, err := s.containers.GetContainer(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if src.ID != id {
+		return nil, types.ErrContainerNotFound
+	}
+
+	c := *src
+	c.ID = uuid.New()
+	c.Name = src.Name + " (copy)"
+	if opts.Name != nil {
+		c.Name = *opts.Name
+	}
+	c.StackID = nil
+	c.StackMember = nil
+	c.Databases = nil
+
+	// The runtime state of src is not copied: the copy is created stopped.
+	c.Status = types.ContainerStatusOff
+	c.StoppedByUser = false
+
+	// The update state of src is not copied, except the image of a pinned
+	// container. The copy can't be rolled back to the previous image of src.
+	c.Update = nil
+	c.PreviousImage = nil
+	if c.UpdatePolicy != types.UpdatePolicyPin {
+		c.PinnedImage = nil
+	}
+
+	// The configuration is copied from src, so the copy is up to date with
+	// the same revision of its template.
+	c.TemplateRevision = src.TemplateRevision
+	if src.IsBuilt() {
+		c.Image = c.DockerImageVertexName()
+	}
+
+	err = s.containers.CreateContainer(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.duplicateConfig(ctx, *src, c)
+	if err == nil {
+		err = s.duplicateVolumes(ctx, *src, c, opts)
+	}
+	if err == nil {
+		err = s.logs.Register(c.ID)
+	}
+	if err != nil {
+		if err := s.Delete(ctx, c.ID); err != nil {
+			log.Error(err, vlog.String("id", c.ID.String()))
+		}
+		return nil, err
+	}
+
+	s.ctx.DispatchEvent(types.EventContainerCreated{})
+	s.ctx.DispatchEvent(types.EventContainersChange{})
+
+	return &c, nil
+}
+
+// duplicateConfig copies the configuration of src to c, except its volumes.
+func (s *containerService) duplicateConfig(ctx context.Context, src types.Container, c types.Container) error {
+	env, err := s.vars.GetEnvs(ctx, types.EnvVariableFilters{ContainerID: &src.ID})
+	if err != nil {
+		return err
+	}
+	for _, e := range env {
+		e.ID = uuid.New()
+		e.ContainerID = c.ID
+		err = s.vars.CreateEnv(ctx, e)
+		if err != nil {
+			return err
+		}
+	}
+
+	caps, err := s.caps.GetContainerCaps(ctx, src.ID)
+	if err != nil {
+		return err
+	}
+	for _, cp := range caps {
+		cp.ID = uuid.New()
+		cp.ContainerID = c.ID
+		err = s.caps.CreateCap(ctx, cp)
+		if err != nil {
+			return err
+		}
+	}
+
+	sysctls, err := s.sysctls.GetContainerSysctls(ctx, src.ID)
+	if err != nil {
+		return err
+	}
+	for _, sysctl := range sysctls {
+		sysctl.ID = uuid.New()
+		sysctl.ContainerID = c.ID
+		err = s.sysctls.CreateSysctl(ctx, sysctl)
+		if err != nil {
+			return err
+		}
+	}
+
+	tags, err := s.containers.GetContainerTags(ctx, src.ID)
+	if err != nil {
+		return err
+	}
+	for _, tag := range tags {
+		err = s.containers.AddTag(ctx, c.ID, tag.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	used, err := s.ports.GetPorts(ctx, types.PortFilters{})
+	if err != nil {
+		return err
+	}
+	ports, err := s.ports.GetPorts(ctx, types.PortFilters{ContainerID: &src.ID})
+	if err != nil {
+		return err
+	}
+	for _, p := range ports {
+		edited := p.TemplateOut != nil && *p.TemplateOut != p.Out
+		p.ID = uuid.New()
+		p.ContainerID = c.ID
+		p.Out, err = allocatePort(s.hostPorts, used, p)
+		if err != nil {
+			return err
+		}
+		// The reassigned port replaces the port given by the template.
+		if p.TemplateOut != nil && !edited {
+			out := p.Out
+			p.TemplateOut = &out
+		}
+		err = s.ports.CreatePort(ctx, p)
+		if err != nil {
+			return err
+		}
+		used = append(used, p)
+	}
+
+	hc, err := s.health.GetContainerHealthCheck(ctx, src.ID)
+	if err != nil && !errors.Is(err, errors.NotFound) {
+		return err
+	}
+	if hc != nil {
+		hc.ID = uuid.New()
+		hc.ContainerID = c.ID
+		err = s.health.SetContainerHealthCheck(ctx, *hc)
+		if err != nil {
+			return err
+		}
+	}
+
+	limits, err := s.limits.GetContainerResourceLimits(ctx, src.ID)
+	if err != nil && !errors.Is(err, errors.NotFound) {
+		return err
+	}
+	if limits != nil {
+		limits.ID = uuid.New()
+		limits.ContainerID = c.ID
+		for i := range limits.Ulimits {
+			limits.Ulimits[i].ID = uuid.New()
+			limits.Ulimits[i].ContainerID = c.ID
+		}
+		err = s.limits.SetContainerResourceLimits(ctx, *limits)
+		if err != nil {
+			return err
+		}
+	}
+
+	build, err := s.builds.GetContainerBuild(ctx, src.ID)
+	if err != nil && !errors.Is(err, errors.NotFound) {
+		return err
+	}
+	if build != nil {
+		build.ID = uuid.New()
+		build.ContainerID = c.ID
+		for i := range build.Args {
+			build.Args[i].ID = uuid.New()
+			build.Args[i].ContainerID = c.ID
+		}
+		err = vstorage.CloneRepository(build.Repository, buildDir(c.ID))
+		if err != nil {
+			return err
+		}
+		err = s.builds.SetContainerBuild(ctx, *build)
+		if err != nil {
+			return err
+		}
+	}
+
+	deps, err := s.deps.GetContainerDependencies(ctx, src.ID)
+	if err != nil {
+		return err
+	}
+	for _, dep := range deps {
+		dep.ContainerID = c.ID
+		err = s.deps.SetDependency(ctx, dep)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Aliases are not copied, as they would resolve to both containers.
+	networks, err := s.networks.GetContainerNetworks(ctx, src.ID)
+	if err != nil {
+		return err
+	}
+	for _, n := range networks {
+		err = s.networks.SetContainerNetwork(ctx, types.ContainerNetwork{
+			ContainerID: c.ID,
+			NetworkID:   n.NetworkID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// duplicateVolumes creates the volumes of c, in new locations. The data of
+// the volumes of src is copied if requested.
+func (s *containerService) duplicateVolumes(ctx context.Context, src types.Container, c types.Container, opts types.DuplicateContainerOptions) error {
+	volumes, err := s.volumes.GetContainerVolumes(ctx, src.ID)
+	if err != nil {
+		return err
+	}
+	if len(volumes) == 0 {
+		return nil
+	}
+
+	if opts.CopyVolumes && opts.Stop && src.IsRunning() {
+		err = s.Stop(ctx, src.ID)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			err := s.Start(ctx, src.ID)
+			if err != nil {
+				log.Error(err, vlog.String("id", src.ID.String()))
+			}
+		}()
+	}
+
+	dir := path.Join(duplicatesPath, c.ID.String())
+	if opts.CopyVolumes {
+		err = os.MkdirAll(dir, 0700)
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(dir)
+	}
+
+	for _, v := range volumes {
+		dup := v
+		dup.ID = uuid.New()
+		dup.ContainerID = c.ID
+		dup.Out = duplicateVolumeName(v, src.ID, c.ID)
+
+		err = s.volumes.CreateVolume(ctx, dup)
+		if err != nil {
+			return err
+		}
+
+		if opts.CopyVolumes {
+			err = s.copyVolume(ctx, v, dup, path.Join(dir, v.ID.String()+".tar.gz"))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *containerService) copyVolume(ctx context.Context, src types.Volume, dest types.Volume, archive string) error {
+	if dest.Type == types.VolumeTypeVolume {
+		err := s.runner.CreateVolume(ctx, dest.Out)
+		if err != nil {
+			return err
+		}
+	}
+
+	res, err := s.runner.ArchiveVolume(ctx, src, archive)
+	if err != nil {
+		return err
+	}
+
+	return s.runner.RestoreVolume(ctx, types.BackupVolume{
+		Type:     dest.Type,
+		Name:     dest.Out,
+		Archive:  archive,
+		Checksum: res.Checksum,
+	})
+}
+
+// duplicateVolumeName returns the location of the copy of a volume. Volumes
+// named after the container are renamed after the copy, and the others get
+// a suffix.
+func duplicateVolumeName(v types.Volume, srcID uuid.UUID, id uuid.UUID) string {
+	if v.Type == types.VolumeTypeBind {
+		return strings.TrimSuffix(v.Out, "/") + "-" + id.String()[:8]
+	}
+	prefix := "VERTEX_VOLUME_" + srcID.String() + "_"
+	if name, ok := strings.CutPrefix(v.Out, prefix); ok {
+		return "VERTEX_VOLUME_" + id.String() + "_" + name
+	}
+	return v.Out + "_" + id.String()[:8]
+}
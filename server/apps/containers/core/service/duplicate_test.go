@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/juju/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/vertex-center/uuid"
+	"github.com/vertex-center/vertex/server/apps/containers/core/port"
+	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+	"github.com/vertex-center/vertex/server/common"
+	"github.com/vertex-center/vertex/server/common/app"
+)
+
+type DuplicateTestSuite struct {
+	suite.Suite
+
+	service    *containerService
+	containers *fakeContainerAdapter
+	vars       *fakeEnvAdapter
+	ports      *fakePortAdapter
+	logs       *fakeLogsAdapter
+	host       MockHostPortsAdapter
+
+	src   types.Container
+	other types.Container
+}
+
+func TestDuplicateTestSuite(t *testing.T) {
+	suite.Run(t, new(DuplicateTestSuite))
+}
+
+func (suite *DuplicateTestSuite) SetupTest() {
+	pinned, previous, revision := "sha256:pinned", "sha256:previous", "5f2b7c1d9e0a4b83"
+	suite.src = types.Container{
+		ID:               uuid.New(),
+		Name:             "Postgres",
+		Status:           types.ContainerStatusRunning,
+		StoppedByUser:    true,
+		TemplateRevision: &revision,
+		UpdatePolicy:     types.UpdatePolicyAuto,
+		PinnedImage:      &pinned,
+		PreviousImage:    &previous,
+		Update:           &types.ContainerUpdate{CurrentVersion: "15", LatestVersion: "16"},
+	}
+	suite.other = types.Container{ID: uuid.New(), Name: "MariaDB"}
+	suite.containers = &fakeContainerAdapter{containers: map[uuid.UUID]types.Container{
+		suite.src.ID:   suite.src,
+		suite.other.ID: suite.other,
+	}}
+
+	templateOut, editedTemplateOut := "5432", "8080"
+	suite.ports = &fakePortAdapter{ports: types.Ports{
+		{ID: uuid.New(), ContainerID: suite.src.ID, In: "5432", Out: "5432", Protocol: types.PortProtocolTCP, TemplateOut: &templateOut},
+		{ID: uuid.New(), ContainerID: suite.src.ID, In: "80", Out: "8081", Protocol: types.PortProtocolTCP, TemplateOut: &editedTemplateOut},
+		{ID: uuid.New(), ContainerID: suite.other.ID, In: "3306", Out: "5433", Protocol: types.PortProtocolTCP},
+	}}
+	suite.vars = &fakeEnvAdapter{env: []types.EnvVariable{
+		{ID: uuid.New(), ContainerID: suite.src.ID, Name: "POSTGRES_USER", Value: "postgres"},
+	}}
+	suite.logs = &fakeLogsAdapter{}
+
+	// Another process listens on 8082.
+	suite.host = MockHostPortsAdapter{}
+	suite.host.On("IsPortFree", types.PortProtocolTCP, 8082).Return(false)
+	suite.host.On("IsPortFree", mock.Anything, mock.Anything).Return(true)
+
+	suite.service = &containerService{
+		ctx:        app.NewContext(common.NewVertexContext(common.About{}, false)),
+		containers: suite.containers,
+		vars:       suite.vars,
+		ports:      suite.ports,
+		caps:       &fakeCapAdapter{},
+		volumes:    &fakeVolumeAdapter{},
+		sysctls:    &fakeSysctlAdapter{},
+		health:     &fakeHealthCheckAdapter{},
+		limits:     &fakeResourceLimitsAdapter{limits: map[uuid.UUID]types.ResourceLimits{}},
+		builds:     &fakeBuildAdapter{},
+		deps:       &fakeDependencyAdapter{},
+		networks:   &fakeNetworkAdapter{networks: map[uuid.UUID]types.ContainerNetworks{}},
+		runner:     &fakeRunnerAdapter{},
+		logs:       suite.logs,
+		hostPorts:  &suite.host,
+		restarts:   map[uuid.UUID]*restartState{},
+	}
+}
+
+func (suite *DuplicateTestSuite) TestDuplicateContainer() {
+	c, err := suite.service.DuplicateContainer(context.Background(), suite.src.ID, types.DuplicateContainerOptions{})
+	suite.Require().NoError(err)
+
+	suite.Equal("Postgres (copy)", c.Name)
+	suite.Equal(types.ContainerStatusOff, c.Status)
+	suite.False(c.StoppedByUser)
+	suite.Nil(c.Update)
+	suite.Nil(c.PinnedImage)
+	suite.Nil(c.PreviousImage)
+	suite.Equal(*suite.src.TemplateRevision, *c.TemplateRevision)
+	suite.Equal(*c, suite.containers.containers[c.ID])
+
+	env, err := suite.vars.GetEnvs(context.Background(), types.EnvVariableFilters{ContainerID: &c.ID})
+	suite.Require().NoError(err)
+	suite.Require().Len(env, 1)
+	suite.Equal("POSTGRES_USER", env[0].Name)
+}
+
+func (suite *DuplicateTestSuite) TestDuplicateContainerPinned() {
+	suite.src.UpdatePolicy = types.UpdatePolicyPin
+	suite.containers.containers[suite.src.ID] = suite.src
+
+	c, err := suite.service.DuplicateContainer(context.Background(), suite.src.ID, types.DuplicateContainerOptions{})
+	suite.Require().NoError(err)
+	suite.Equal(*suite.src.PinnedImage, *c.PinnedImage)
+	suite.Nil(c.PreviousImage)
+}
+
+func (suite *DuplicateTestSuite) TestDuplicateContainerPorts() {
+	c, err := suite.service.DuplicateContainer(context.Background(), suite.src.ID, types.DuplicateContainerOptions{})
+	suite.Require().NoError(err)
+
+	ports, err := suite.ports.GetPorts(context.Background(), types.PortFilters{ContainerID: &c.ID})
+	suite.Require().NoError(err)
+	suite.Require().Len(ports, 2)
+
+	// 5432 is used by the source, and 5433 by another container.
+	suite.Equal("5434", ports[0].Out)
+	suite.Equal("5434", *ports[0].TemplateOut)
+
+	// 8081 is used by the source, and 8082 by the host. The port was
+	// edited, so the port of the template is kept.
+	suite.Equal("8083", ports[1].Out)
+	suite.Equal("8080", *ports[1].TemplateOut)
+
+	src, err := suite.ports.GetPorts(context.Background(), types.PortFilters{ContainerID: &suite.src.ID})
+	suite.Require().NoError(err)
+	suite.Equal("5432", src[0].Out)
+	suite.Equal("8081", src[1].Out)
+}
+
+func (suite *DuplicateTestSuite) TestDuplicateContainerRollback() {
+	suite.logs.err = errors.New("logs unavailable")
+
+	_, err := suite.service.DuplicateContainer(context.Background(), suite.src.ID, types.DuplicateContainerOptions{})
+	suite.Error(err)
+
+	// Only the source and the other container are left, with their configuration.
+	suite.Len(suite.containers.containers, 2)
+	suite.Contains(suite.containers.containers, suite.src.ID)
+	suite.Len(suite.ports.ports, 3)
+	suite.Len(suite.vars.env, 1)
+}
+
+func (suite *DuplicateTestSuite) TestDuplicateContainerMissing() {
+	_, err := suite.service.DuplicateContainer(context.Background(), uuid.New(), types.DuplicateContainerOptions{})
+	suite.ErrorIs(err, types.ErrContainerNotFound)
+	suite.Len(suite.containers.containers, 2)
+}
+
+func (suite *DuplicateTestSuite) TestDuplicateVolumeName() {
+	src := uuid.MustParse("3b2f8c4e-1d7a-4e6b-9f0c-5a8d2e1b7c9f")
+	id := uuid.MustParse("d1fb743c-f937-4f3d-95b9-1a8475464591")
+
+	v := types.Volume{Type: types.VolumeTypeVolume, Out: "VERTEX_VOLUME_3b2f8c4e-1d7a-4e6b-9f0c-5a8d2e1b7c9f_data"}
+	suite.Equal("VERTEX_VOLUME_d1fb743c-f937-4f3d-95b9-1a8475464591_data", duplicateVolumeName(v, src, id))
+
+	v = types.Volume{Type: types.VolumeTypeVolume, Out: "pgdata"}
+	suite.Equal("pgdata_d1fb743c", duplicateVolumeName(v, src, id))
+
+	v = types.Volume{Type: types.VolumeTypeBind, Out: "/srv/postgres/"}
+	suite.Equal("/srv/postgres-d1fb743c", duplicateVolumeName(v, src, id))
+}
+
+type fakeSysctlAdapter struct {
+	port.SysctlAdapter
+}
+
+func (a *fakeSysctlAdapter) GetContainerSysctls(ctx context.Context, id uuid.UUID) (types.Sysctls, error) {
+	return nil, nil
+}
+
+func (a *fakeSysctlAdapter) DeleteContainerSysctls(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
+
+type fakeHealthCheckAdapter struct {
+	port.HealthCheckAdapter
+}
+
+func (a *fakeHealthCheckAdapter) GetContainerHealthCheck(ctx context.Context, id uuid.UUID) (*types.HealthCheck, error) {
+	return nil, errors.NotFoundf("health check")
+}
+
+func (a *fakeHealthCheckAdapter) DeleteContainerHealthCheck(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
+
+type fakeBuildAdapter struct {
+	port.BuildAdapter
+}
+
+func (a *fakeBuildAdapter) GetContainerBuild(ctx context.Context, id uuid.UUID) (*types.ContainerBuild, error) {
+	return nil, errors.NotFoundf("build")
+}
+
+func (a *fakeBuildAdapter) DeleteContainerBuild(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
+
+type fakeLogsAdapter struct {
+	port.LogsAdapter
+	err error
+}
+
+func (a *fakeLogsAdapter) Register(id uuid.UUID) error {
+	return a.err
+}
+
+func (a *fakeLogsAdapter) Unregister(id uuid.UUID) error {
+	return nil
+}
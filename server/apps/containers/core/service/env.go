@@ -2,32 +2,131 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"slices"
 
+	"github.com/juju/errors"
 	"github.com/vertex-center/uuid"
 	"github.com/vertex-center/vertex/server/apps/containers/core/port"
 	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+	"github.com/vertex-center/vertex/server/common/log"
+	"github.com/vertex-center/vertex/server/pkg/vcrypto"
+	"github.com/vertex-center/vlog"
 )
 
 type envService struct {
-	env port.EnvAdapter
+	env        port.EnvAdapter
+	containers port.ContainerAdapter
+	templates  port.TemplateAdapter
+	registries port.RegistryCredentialAdapter
+	keyring    *vcrypto.Keyring
 }
 
-func NewEnvService(env port.EnvAdapter) port.EnvService {
-	return &envService{env}
+func NewEnvService(env port.EnvAdapter, containers port.ContainerAdapter, templates port.TemplateAdapter, registries port.RegistryCredentialAdapter, keyring *vcrypto.Keyring) port.EnvService {
+	return &envService{env, containers, templates, registries, keyring}
 }
 
+// GetEnvs returns the environment variables, with the values of the secret
+// ones redacted.
 func (s *envService) GetEnvs(ctx context.Context, filters types.EnvVariableFilters) ([]types.EnvVariable, error) {
-	return s.env.GetEnvs(ctx, filters)
+	env, err := s.env.GetEnvs(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+	for i := range env {
+		env[i].Redact()
+	}
+	return env, nil
+}
+
+// RevealEnv returns an environment variable with its value decrypted.
+func (s *envService) RevealEnv(ctx context.Context, id uuid.UUID) (*types.EnvVariable, error) {
+	env, err := s.env.GetEnv(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if env.Secret {
+		log.Info("secret environment variable revealed", vlog.String("id", id.String()), vlog.String("name", env.Name))
+	}
+	err = decryptEnv(s.keyring, env)
+	return env, err
 }
 
 func (s *envService) PatchEnv(ctx context.Context, env types.EnvVariable) error {
-	err := env.Validate()
+	prev, err := s.env.GetEnv(ctx, env.ID)
+	if err != nil {
+		return err
+	}
+
+	// A patch without name keeps the name of the variable.
+	if env.Name == "" {
+		env.Name = prev.Name
+	}
+	err = env.Validate()
+	if err != nil {
+		return err
+	}
+
+	// A redacted value sent back is left unchanged.
+	redacted := env.Value == types.SecretRedacted && prev.Secret
+	if redacted {
+		env.Value = prev.Value
+	}
+
+	err = s.validateTemplateEnv(ctx, prev, env, !redacted)
 	if err != nil {
 		return err
 	}
 	return s.env.UpdateEnvByID(ctx, env)
 }
 
+// validateTemplateEnv checks a patch of a variable declared by the template
+// of its container: the variable can't be renamed, and its value must match
+// the type and constraints of the template.
+func (s *envService) validateTemplateEnv(ctx context.Context, prev *types.EnvVariable, env types.EnvVariable, checkValue bool) error {
+	c, err := s.containers.GetContainer(ctx, prev.ContainerID)
+	if err != nil {
+		return err
+	}
+	if c.ID != prev.ContainerID || c.TemplateID == nil {
+		return nil
+	}
+	template, err := s.templates.Get(*c.TemplateID)
+	if errors.Is(err, types.ErrTemplateNotFound) {
+		// The template was deleted, there is nothing to check against.
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if !slices.ContainsFunc(template.Env, func(e types.TemplateEnv) bool { return e.Name == prev.Name }) {
+		return nil
+	}
+
+	if env.Name != prev.Name {
+		return errors.NewNotValid(nil, fmt.Sprintf("%s is declared by the template, it can't be renamed", prev.Name))
+	}
+	if !checkValue {
+		return nil
+	}
+
+	// The other values decide whether the variable is visible.
+	vars, err := s.env.GetEnvs(ctx, types.EnvVariableFilters{ContainerID: &prev.ContainerID})
+	if err != nil {
+		return err
+	}
+	values := map[string]string{}
+	for _, e := range vars {
+		err = decryptEnv(s.keyring, &e)
+		if err != nil {
+			return err
+		}
+		values[e.Name] = e.Value
+	}
+	values[prev.Name] = env.Value
+
+	return types.ValidateTemplateEnvValue(template.Env, prev.Name, values)
+}
+
 func (s *envService) DeleteEnv(ctx context.Context, id uuid.UUID) error {
 	return s.env.DeleteEnv(ctx, id)
 }
@@ -40,3 +139,51 @@ func (s *envService) CreateEnv(ctx context.Context, env types.EnvVariable) error
 	}
 	return s.env.CreateEnv(ctx, env)
 }
+
+// EncryptSecrets encrypts the secret values and the registry passwords that
+// are not encrypted with the current key, like those stored before they were
+// encrypted.
+func (s *envService) EncryptSecrets(ctx context.Context) error {
+	env, err := s.env.GetEnvs(ctx, types.EnvVariableFilters{})
+	if err != nil {
+		return err
+	}
+	for _, e := range env {
+		if !e.Secret || s.keyring.IsCurrent(e.Value) {
+			continue
+		}
+		// The adapter encrypts the value with the current key.
+		err = s.env.UpdateEnvByID(ctx, e)
+		if err != nil {
+			return err
+		}
+	}
+	return s.registries.EncryptRegistryCredentials(ctx)
+}
+
+// RotateKey encrypts all the secret values and the registry passwords with a
+// new key, and deletes the previous keys.
+func (s *envService) RotateKey(ctx context.Context) error {
+	err := s.keyring.Rotate()
+	if err != nil {
+		return err
+	}
+	err = s.EncryptSecrets(ctx)
+	if err != nil {
+		return err
+	}
+	return s.keyring.Prune()
+}
+
+// decryptEnv decrypts the value of a secret variable.
+func decryptEnv(keyring *vcrypto.Keyring, env *types.EnvVariable) error {
+	if !env.Secret || !vcrypto.IsEncrypted(env.Value) {
+		return nil
+	}
+	value, err := keyring.Decrypt(env.Value)
+	if err != nil {
+		return err
+	}
+	env.Value = value
+	return nil
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/vertex-center/uuid"
+	"github.com/vertex-center/vertex/server/apps/containers/core/port"
+	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+	"github.com/vertex-center/vertex/server/pkg/vcrypto"
+)
+
+type EnvServiceTestSuite struct {
+	suite.Suite
+
+	service    *envService
+	env        *fakeEnvAdapter
+	registries *fakeRegistryCredentialAdapter
+	keyring    *vcrypto.Keyring
+
+	secret types.EnvVariable
+	plain  types.EnvVariable
+}
+
+func TestEnvServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(EnvServiceTestSuite))
+}
+
+func (suite *EnvServiceTestSuite) SetupTest() {
+	var err error
+	suite.keyring, err = vcrypto.LoadOrCreateKeyring(suite.T().TempDir())
+	suite.Require().NoError(err)
+
+	c := types.Container{ID: uuid.New()}
+	password, err := suite.keyring.Encrypt("hunter2")
+	suite.Require().NoError(err)
+	suite.secret = types.EnvVariable{ID: uuid.New(), ContainerID: c.ID, Name: "PASSWORD", Value: password, Secret: true}
+	suite.plain = types.EnvVariable{ID: uuid.New(), ContainerID: c.ID, Name: "USER", Value: "admin"}
+
+	containers := &fakeContainerAdapter{containers: map[uuid.UUID]types.Container{c.ID: c}}
+	suite.env = &fakeEnvAdapter{env: []types.EnvVariable{suite.secret, suite.plain}}
+	suite.registries = &fakeRegistryCredentialAdapter{}
+	suite.service = NewEnvService(suite.env, containers, nil, suite.registries, suite.keyring).(*envService)
+}
+
+func (suite *EnvServiceTestSuite) TestGetEnvsRedacted() {
+	env, err := suite.service.GetEnvs(context.Background(), types.EnvVariableFilters{})
+	suite.Require().NoError(err)
+	suite.Require().Len(env, 2)
+	suite.Equal(types.SecretRedacted, env[0].Value)
+	suite.Equal("admin", env[1].Value)
+}
+
+func (suite *EnvServiceTestSuite) TestPatchEnvSecretRedacted() {
+	// The redacted value sent back by the client keeps the secret.
+	err := suite.service.PatchEnv(context.Background(), types.EnvVariable{ID: suite.secret.ID, Value: types.SecretRedacted})
+	suite.Require().NoError(err)
+	suite.Equal(suite.secret.Value, suite.env.values()["PASSWORD"])
+
+	revealed, err := suite.service.RevealEnv(context.Background(), suite.secret.ID)
+	suite.Require().NoError(err)
+	suite.Equal("hunter2", revealed.Value)
+
+	err = suite.service.PatchEnv(context.Background(), types.EnvVariable{ID: suite.secret.ID, Value: "correct horse"})
+	suite.Require().NoError(err)
+	suite.Equal("correct horse", suite.env.values()["PASSWORD"])
+}
+
+func (suite *EnvServiceTestSuite) TestPatchEnvNotSecretRedacted() {
+	// Variables that are not secret are never redacted, so the value is taken as is.
+	err := suite.service.PatchEnv(context.Background(), types.EnvVariable{ID: suite.plain.ID, Value: types.SecretRedacted})
+	suite.Require().NoError(err)
+	suite.Equal(types.SecretRedacted, suite.env.values()["USER"])
+}
+
+func (suite *EnvServiceTestSuite) TestEncryptSecrets() {
+	err := suite.service.EncryptSecrets(context.Background())
+	suite.Require().NoError(err)
+	suite.Equal(1, suite.registries.encrypted)
+}
+
+type fakeRegistryCredentialAdapter struct {
+	port.RegistryCredentialAdapter
+	encrypted int
+}
+
+func (a *fakeRegistryCredentialAdapter) EncryptRegistryCredentials(ctx context.Context) error {
+	a.encrypted++
+	return nil
+}
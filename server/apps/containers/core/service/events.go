@@ -24,7 +24,14 @@ func (s *containerService) OnEvent(e event.Event) error {
 				log.Error(err)
 			}
 		}()
+
+		var ctx context.Context
+		ctx, s.stopStats = context.WithCancel(context.Background())
+		go s.collectStats(ctx)
 	case ev.ServerStop:
+		if s.stopStats != nil {
+			s.stopStats()
+		}
 		return s.StopAll(context.Background())
 	case types.EventContainerLog:
 		return s.onLogReceived(e)
@@ -54,7 +61,10 @@ func (s *containerService) onLogReceived(e types.EventContainerLog) error {
 		})
 	default:
 		s.logs.Push(e.ContainerID, types.LogLine{
+			Time:    e.Time,
 			Kind:    e.Kind,
+			Level:   e.Level,
+			Fields:  e.Fields,
 			Message: e.Message,
 		})
 	}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/vertex-center/uuid"
+	"github.com/vertex-center/vertex/server/apps/containers/core/port"
+	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+	"github.com/vertex-center/vertex/server/common/app"
+)
+
+type execService struct {
+	ctx        *app.Context
+	containers port.ContainerAdapter
+	runner     port.RunnerAdapter
+}
+
+func NewExecService(ctx *app.Context, containers port.ContainerAdapter, runner port.RunnerAdapter) port.ExecService {
+	return &execService{
+		ctx:        ctx,
+		containers: containers,
+		runner:     runner,
+	}
+}
+
+// Exec opens a terminal in a running container. The opening and the closing
+// of the terminal are written in the container logs for auditing.
+func (s *execService) Exec(ctx context.Context, containerID uuid.UUID, userID uuid.UUID, options types.ExecOptions) (port.ExecSession, error) {
+	c, err := s.containers.GetContainer(ctx, containerID)
+	if err != nil {
+		return nil, err
+	}
+	if !c.IsRunning() {
+		return nil, ErrContainerNotRunning
+	}
+
+	if len(options.Cmd) == 0 {
+		options.Cmd = types.DefaultExecCmd
+	}
+
+	session, err := s.runner.Exec(ctx, *c, options)
+	if err != nil {
+		return nil, err
+	}
+
+	s.log(containerID, fmt.Sprintf("Terminal opened by user %s: %s", userID, strings.Join(options.Cmd, " ")))
+	return &auditedExecSession{
+		ExecSession: session,
+		onClose: func() {
+			s.log(containerID, fmt.Sprintf("Terminal closed by user %s.", userID))
+		},
+	}, nil
+}
+
+func (s *execService) log(containerID uuid.UUID, msg string) {
+	s.ctx.DispatchEvent(types.EventContainerLog{
+		ContainerID: containerID,
+		Kind:        types.LogKindVertexOut,
+		Message:     types.NewLogLineMessageString(msg),
+	})
+}
+
+// auditedExecSession calls onClose once, when the session is closed.
+type auditedExecSession struct {
+	port.ExecSession
+	onClose func()
+	once    sync.Once
+}
+
+func (s *auditedExecSession) Close() error {
+	s.once.Do(s.onClose)
+	return s.ExecSession.Close()
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+
+	"github.com/vertex-center/uuid"
+	"github.com/vertex-center/vertex/server/apps/containers/core/port"
+	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+)
+
+type healthCheckService struct {
+	containers   port.ContainerAdapter
+	healthChecks port.HealthCheckAdapter
+}
+
+func NewHealthCheckService(containers port.ContainerAdapter, healthChecks port.HealthCheckAdapter) port.HealthCheckService {
+	return &healthCheckService{containers, healthChecks}
+}
+
+func (s *healthCheckService) GetHealthCheck(ctx context.Context, containerID uuid.UUID) (*types.HealthCheck, error) {
+	return s.healthChecks.GetContainerHealthCheck(ctx, containerID)
+}
+
+// SetHealthCheck replaces the health check of a container. The new
+// health check is applied the next time the Docker container is created.
+func (s *healthCheckService) SetHealthCheck(ctx context.Context, hc types.HealthCheck) error {
+	c, err := s.containers.GetContainer(ctx, hc.ContainerID)
+	if err != nil {
+		return err
+	}
+	if c.ID != hc.ContainerID {
+		return types.ErrContainerNotFound
+	}
+
+	hc.ID = uuid.New()
+	err = hc.Validate()
+	if err != nil {
+		return err
+	}
+	return s.healthChecks.SetContainerHealthCheck(ctx, hc)
+}
+
+func (s *healthCheckService) DeleteHealthCheck(ctx context.Context, containerID uuid.UUID) error {
+	return s.healthChecks.DeleteContainerHealthCheck(ctx, containerID)
+}
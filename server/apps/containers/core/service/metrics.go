@@ -14,8 +14,29 @@ import (
 const (
 	MetricIDContainerStatus = "vertex_container_status"
 	MetricIDContainersCount = "vertex_containers_count"
+
+	MetricIDContainerCPU         = "vertex_container_cpu_percent"
+	MetricIDContainerMemory      = "vertex_container_memory_bytes"
+	MetricIDContainerMemoryLimit = "vertex_container_memory_limit_bytes"
+	MetricIDContainerNetworkRx   = "vertex_container_network_rx_bytes"
+	MetricIDContainerNetworkTx   = "vertex_container_network_tx_bytes"
+	MetricIDContainerBlockRead   = "vertex_container_block_read_bytes"
+	MetricIDContainerBlockWrite  = "vertex_container_block_write_bytes"
+	MetricIDContainerPIDs        = "vertex_container_pids"
 )
 
+// statsMetrics are the metrics set from the container stats.
+var statsMetrics = []string{
+	MetricIDContainerCPU,
+	MetricIDContainerMemory,
+	MetricIDContainerMemoryLimit,
+	MetricIDContainerNetworkRx,
+	MetricIDContainerNetworkTx,
+	MetricIDContainerBlockRead,
+	MetricIDContainerBlockWrite,
+	MetricIDContainerPIDs,
+}
+
 type metricsService struct {
 	uuid            uuid.UUID
 	ctx             *apptypes.Context
@@ -48,11 +69,16 @@ func (s *metricsService) OnEvent(e event.Event) error {
 	switch e := e.(type) {
 	case types.EventContainerStatusChange:
 		s.updateStatus(e.ContainerID, e.Status)
+	case types.EventContainerStats:
+		s.updateStats(e.ContainerID, e.Stats)
 	case types.EventContainerCreated:
 		s.metricsRegistry.Inc(MetricIDContainersCount)
 	case types.EventContainerDeleted:
 		s.metricsRegistry.Dec(MetricIDContainersCount)
 		s.metricsRegistry.Set(MetricIDContainerStatus, math.NaN(), e.ContainerID.String())
+		for _, id := range statsMetrics {
+			s.metricsRegistry.Set(id, math.NaN(), e.ContainerID.String())
+		}
 	case types.EventContainersLoaded:
 		s.metricsRegistry.Set(MetricIDContainersCount, float64(e.Count))
 	}
@@ -61,13 +87,27 @@ func (s *metricsService) OnEvent(e event.Event) error {
 
 func (s *metricsService) updateStatus(uuid uuid.UUID, status string) {
 	switch status {
-	case types.ContainerStatusRunning:
+	case types.ContainerStatusRunning, types.ContainerStatusHealthy:
 		s.metricsRegistry.Set(MetricIDContainerStatus, metric.StatusOn, uuid.String())
+	case types.ContainerStatusUnhealthy:
+		s.metricsRegistry.Set(MetricIDContainerStatus, metric.StatusUnhealthy, uuid.String())
 	default:
 		s.metricsRegistry.Set(MetricIDContainerStatus, metric.StatusOff, uuid.String())
 	}
 }
 
+func (s *metricsService) updateStats(uuid uuid.UUID, stats types.ContainerStats) {
+	id := uuid.String()
+	s.metricsRegistry.Set(MetricIDContainerCPU, stats.CPUPercent, id)
+	s.metricsRegistry.Set(MetricIDContainerMemory, float64(stats.MemoryUsage), id)
+	s.metricsRegistry.Set(MetricIDContainerMemoryLimit, float64(stats.MemoryLimit), id)
+	s.metricsRegistry.Set(MetricIDContainerNetworkRx, float64(stats.NetworkRx), id)
+	s.metricsRegistry.Set(MetricIDContainerNetworkTx, float64(stats.NetworkTx), id)
+	s.metricsRegistry.Set(MetricIDContainerBlockRead, float64(stats.BlockRead), id)
+	s.metricsRegistry.Set(MetricIDContainerBlockWrite, float64(stats.BlockWrite), id)
+	s.metricsRegistry.Set(MetricIDContainerPIDs, float64(stats.PIDs), id)
+}
+
 func (s *metricsService) Register() error {
 	return s.metricsRegistry.Register([]metric.Metric{
 		{
@@ -83,5 +123,61 @@ func (s *metricsService) Register() error {
 			Description: "The number of containers installed",
 			Type:        metric.TypeGauge,
 		},
+		{
+			ID:          MetricIDContainerCPU,
+			Name:        "Container CPU",
+			Description: "The CPU usage of the container, in percent of one CPU",
+			Type:        metric.TypeGauge,
+			Labels:      []string{"uuid"},
+		},
+		{
+			ID:          MetricIDContainerMemory,
+			Name:        "Container Memory",
+			Description: "The memory used by the container, in bytes",
+			Type:        metric.TypeGauge,
+			Labels:      []string{"uuid"},
+		},
+		{
+			ID:          MetricIDContainerMemoryLimit,
+			Name:        "Container Memory Limit",
+			Description: "The memory the container can use, in bytes",
+			Type:        metric.TypeGauge,
+			Labels:      []string{"uuid"},
+		},
+		{
+			ID:          MetricIDContainerNetworkRx,
+			Name:        "Container Network Received",
+			Description: "The bytes received by the container",
+			Type:        metric.TypeGauge,
+			Labels:      []string{"uuid"},
+		},
+		{
+			ID:          MetricIDContainerNetworkTx,
+			Name:        "Container Network Sent",
+			Description: "The bytes sent by the container",
+			Type:        metric.TypeGauge,
+			Labels:      []string{"uuid"},
+		},
+		{
+			ID:          MetricIDContainerBlockRead,
+			Name:        "Container Block Read",
+			Description: "The bytes read by the container from block devices",
+			Type:        metric.TypeGauge,
+			Labels:      []string{"uuid"},
+		},
+		{
+			ID:          MetricIDContainerBlockWrite,
+			Name:        "Container Block Write",
+			Description: "The bytes written by the container to block devices",
+			Type:        metric.TypeGauge,
+			Labels:      []string{"uuid"},
+		},
+		{
+			ID:          MetricIDContainerPIDs,
+			Name:        "Container PIDs",
+			Description: "The number of processes running in the container",
+			Type:        metric.TypeGauge,
+			Labels:      []string{"uuid"},
+		},
 	})
 }
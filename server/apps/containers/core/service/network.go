@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/vertex-center/uuid"
+	"github.com/vertex-center/vertex/server/apps/containers/core/port"
+	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+)
+
+type networkService struct {
+	containers port.ContainerAdapter
+	networks   port.NetworkAdapter
+	runner     port.RunnerAdapter
+}
+
+func NewNetworkService(containers port.ContainerAdapter, networks port.NetworkAdapter, runner port.RunnerAdapter) port.NetworkService {
+	return &networkService{containers, networks, runner}
+}
+
+func (s *networkService) GetNetworks(ctx context.Context) (types.Networks, error) {
+	return s.networks.GetNetworks(ctx)
+}
+
+func (s *networkService) CreateNetwork(ctx context.Context, name string) (*types.Network, error) {
+	return createNetwork(ctx, s.networks, s.runner, name)
+}
+
+// DeleteNetwork deletes a network. Networks with containers attached
+// cannot be deleted.
+func (s *networkService) DeleteNetwork(ctx context.Context, id uuid.UUID) error {
+	n, err := s.networks.GetNetwork(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	attached, err := s.networks.GetNetworkContainers(ctx, id)
+	if err != nil {
+		return err
+	}
+	if len(attached) > 0 {
+		return errors.NewNotValid(types.ErrNetworkInUse, "detach the containers of the network before deleting it")
+	}
+
+	err = s.runner.DeleteNetwork(ctx, n.DockerName())
+	if err != nil && !errors.Is(err, errors.NotFound) {
+		return err
+	}
+	return s.networks.DeleteNetwork(ctx, id)
+}
+
+func (s *networkService) GetContainerNetworks(ctx context.Context, containerID uuid.UUID) (types.ContainerNetworks, error) {
+	return s.networks.GetContainerNetworks(ctx, containerID)
+}
+
+// AttachContainer attaches a container to a network, or replaces the aliases
+// of a container already attached. A created container is reconnected
+// immediately.
+func (s *networkService) AttachContainer(ctx context.Context, cn types.ContainerNetwork) error {
+	err := cn.Validate()
+	if err != nil {
+		return err
+	}
+
+	c, err := s.containers.GetContainer(ctx, cn.ContainerID)
+	if err != nil {
+		return err
+	}
+	if c.ID != cn.ContainerID {
+		return types.ErrContainerNotFound
+	}
+
+	n, err := s.networks.GetNetwork(ctx, cn.NetworkID)
+	if err != nil {
+		return err
+	}
+
+	current, err := s.networks.GetContainerNetworks(ctx, cn.ContainerID)
+	if err != nil {
+		return err
+	}
+
+	// Docker can't change the aliases of a connected container, so it is
+	// reconnected with the new ones.
+	if current.Find(cn.NetworkID) != nil {
+		err = s.runner.DisconnectNetwork(ctx, *c, n.DockerName())
+		if err != nil && !errors.Is(err, errors.NotFound) {
+			return err
+		}
+	}
+
+	err = s.runner.ConnectNetwork(ctx, *c, types.NetworkAttachment{
+		Name:    n.DockerName(),
+		Aliases: cn.Aliases,
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.networks.SetContainerNetwork(ctx, cn)
+}
+
+func (s *networkService) DetachContainer(ctx context.Context, containerID uuid.UUID, networkID uuid.UUID) error {
+	c, err := s.containers.GetContainer(ctx, containerID)
+	if err != nil {
+		return err
+	}
+	if c.ID != containerID {
+		return types.ErrContainerNotFound
+	}
+
+	n, err := s.networks.GetNetwork(ctx, networkID)
+	if err != nil {
+		return err
+	}
+
+	current, err := s.networks.GetContainerNetworks(ctx, containerID)
+	if err != nil {
+		return err
+	}
+	if current.Find(networkID) == nil {
+		return types.ErrNetworkNotFound
+	}
+
+	err = s.runner.DisconnectNetwork(ctx, *c, n.DockerName())
+	if err != nil && !errors.Is(err, errors.NotFound) {
+		return err
+	}
+
+	return s.networks.DeleteContainerNetwork(ctx, containerID, networkID)
+}
+
+// createNetwork saves a new network and creates its Docker network.
+func createNetwork(ctx context.Context, networks port.NetworkAdapter, runner port.RunnerAdapter, name string) (*types.Network, error) {
+	n := types.Network{
+		ID:        uuid.New(),
+		Name:      name,
+		CreatedAt: time.Now().Unix(),
+	}
+
+	err := n.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = networks.GetNetworkByName(ctx, name)
+	if err == nil {
+		return nil, types.ErrNetworkAlreadyExists
+	} else if !errors.Is(err, errors.NotFound) {
+		return nil, err
+	}
+
+	err = runner.CreateNetwork(ctx, n.DockerName())
+	if err != nil {
+		return nil, err
+	}
+
+	err = networks.CreateNetwork(ctx, n)
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+// networkAttachments returns the Docker networks the container must be
+// connected to.
+func networkAttachments(ctx context.Context, networks port.NetworkAdapter, containerID uuid.UUID) ([]types.NetworkAttachment, error) {
+	attached, err := networks.GetContainerNetworks(ctx, containerID)
+	if err != nil {
+		return nil, err
+	}
+
+	var attachments []types.NetworkAttachment
+	for _, cn := range attached {
+		n, err := networks.GetNetwork(ctx, cn.NetworkID)
+		if err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, types.NetworkAttachment{
+			Name:    n.DockerName(),
+			Aliases: cn.Aliases,
+		})
+	}
+	return attachments, nil
+}
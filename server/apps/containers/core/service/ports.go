@@ -2,7 +2,11 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"strconv"
 
+	"github.com/juju/errors"
 	"github.com/vertex-center/uuid"
 	"github.com/vertex-center/vertex/server/apps/containers/core/port"
 	"github.com/vertex-center/vertex/server/apps/containers/core/types"
@@ -10,18 +14,32 @@ import (
 
 type portsService struct {
 	ports port.PortAdapter
+	host  port.HostPortsAdapter
 }
 
-func NewPortsService(ports port.PortAdapter) port.PortsService {
-	return &portsService{ports}
+func NewPortsService(ports port.PortAdapter, host port.HostPortsAdapter) port.PortsService {
+	return &portsService{ports, host}
 }
 
 func (s *portsService) GetPorts(ctx context.Context, filters types.PortFilters) (types.Ports, error) {
 	return s.ports.GetPorts(ctx, filters)
 }
 
+// PatchPort updates a port. The protocol is kept when it is not given.
 func (s *portsService) PatchPort(ctx context.Context, p types.Port) error {
-	err := p.Validate()
+	all, err := s.ports.GetPorts(ctx, types.PortFilters{})
+	if err != nil {
+		return err
+	}
+	i := slices.IndexFunc(all, func(prev types.Port) bool { return prev.ID == p.ID })
+	if i == -1 {
+		return types.ErrPortNotFound
+	}
+	p.ContainerID = all[i].ContainerID
+	if p.Protocol == "" {
+		p.Protocol = all[i].Protocol
+	}
+	err = s.check(all, p)
 	if err != nil {
 		return err
 	}
@@ -34,9 +52,78 @@ func (s *portsService) DeletePort(ctx context.Context, id uuid.UUID) error {
 
 func (s *portsService) CreatePort(ctx context.Context, p types.Port) error {
 	p.ID = uuid.New()
-	err := p.Validate()
+	if p.Protocol == "" {
+		p.Protocol = types.PortProtocolTCP
+	}
+	all, err := s.ports.GetPorts(ctx, types.PortFilters{})
+	if err != nil {
+		return err
+	}
+	err = s.check(all, p)
 	if err != nil {
 		return err
 	}
 	return s.ports.CreatePort(ctx, p)
 }
+
+func (s *portsService) check(all types.Ports, p types.Port) error {
+	err := p.Validate()
+	if err != nil {
+		return err
+	}
+	return checkPort(s.host, all, p)
+}
+
+// checkPort returns an error if the host ports of p are already used by
+// another container, or by another process on the host.
+func checkPort(host port.HostPortsAdapter, all types.Ports, p types.Port) error {
+	if conflict := all.Conflict(p); conflict != nil {
+		return errors.NewAlreadyExists(nil, fmt.Sprintf("port %s/%s already used by container %s", conflict.Out, p.Protocol, conflict.ContainerID))
+	}
+	if !hostPortsFree(host, all, p) {
+		return errors.NewAlreadyExists(nil, fmt.Sprintf("port %s/%s already used on the host", p.Out, p.Protocol))
+	}
+	return nil
+}
+
+// allocatePort returns the first host ports from the external port of p that
+// are used neither by a container nor by the host.
+func allocatePort(host port.HostPortsAdapter, used types.Ports, p types.Port) (string, error) {
+	for {
+		out, err := used.FreePort(p)
+		if err != nil {
+			return "", err
+		}
+		p.Out = out
+		if hostPortsFree(host, used, p) {
+			return out, nil
+		}
+		// Skip these ports, and look for the next ones.
+		used = append(used, types.Port{ID: uuid.New(), Out: out, Protocol: p.Protocol})
+	}
+}
+
+// hostPortsFree returns whether the host ports of p are free. The ports of
+// the containers are skipped, as their own container may be listening on them.
+func hostPortsFree(host port.HostPortsAdapter, all types.Ports, p types.Port) bool {
+	r, err := types.ParsePortRange(p.Out)
+	if err != nil {
+		return false
+	}
+	for n := r.Start; n <= r.End; n++ {
+		single := types.Port{Out: strconv.Itoa(n), Protocol: p.Protocol}
+		if all.Conflict(single) != nil {
+			continue
+		}
+		if !host.IsPortFree(p.Protocol, n) {
+			return false
+		}
+	}
+	return true
+}
+
+// allInterfaces returns whether a port published on the given host IP is
+// published on all interfaces, like the ports of Vertex.
+func allInterfaces(hostIP string) bool {
+	return hostIP == "" || hostIP == "0.0.0.0" || hostIP == "::"
+}
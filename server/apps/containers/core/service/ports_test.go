@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/juju/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/vertex-center/uuid"
+	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+)
+
+type PortsServiceTestSuite struct {
+	suite.Suite
+
+	host  MockHostPortsAdapter
+	ports types.Ports
+}
+
+func TestPortsServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(PortsServiceTestSuite))
+}
+
+func (suite *PortsServiceTestSuite) SetupTest() {
+	suite.host = MockHostPortsAdapter{}
+	suite.ports = types.Ports{
+		{ID: uuid.New(), ContainerID: uuid.New(), Out: "8080", Protocol: types.PortProtocolTCP},
+	}
+}
+
+func (suite *PortsServiceTestSuite) TestCheckPortContainerConflict() {
+	err := checkPort(&suite.host, suite.ports, types.Port{ID: uuid.New(), Out: "8080", Protocol: types.PortProtocolTCP})
+	suite.True(errors.Is(err, errors.AlreadyExists))
+	suite.host.AssertNotCalled(suite.T(), "IsPortFree", mock.Anything, mock.Anything)
+}
+
+func (suite *PortsServiceTestSuite) TestCheckPortHostConflict() {
+	suite.host.On("IsPortFree", types.PortProtocolTCP, 3000).Return(false)
+
+	err := checkPort(&suite.host, suite.ports, types.Port{ID: uuid.New(), Out: "3000", Protocol: types.PortProtocolTCP})
+	suite.True(errors.Is(err, errors.AlreadyExists))
+}
+
+func (suite *PortsServiceTestSuite) TestCheckPortOwnPort() {
+	// The container may be running and listening on its own port.
+	err := checkPort(&suite.host, suite.ports, suite.ports[0])
+	suite.NoError(err)
+	suite.host.AssertNotCalled(suite.T(), "IsPortFree", mock.Anything, mock.Anything)
+}
+
+func (suite *PortsServiceTestSuite) TestAllocatePort() {
+	suite.host.On("IsPortFree", types.PortProtocolTCP, 8081).Return(false)
+	suite.host.On("IsPortFree", types.PortProtocolTCP, 8082).Return(true)
+
+	out, err := allocatePort(&suite.host, suite.ports, types.Port{ID: uuid.New(), Out: "8080", Protocol: types.PortProtocolTCP})
+	suite.Require().NoError(err)
+	suite.Equal("8082", out)
+}
+
+func (suite *PortsServiceTestSuite) TestPatchPort() {
+	suite.host.On("IsPortFree", mock.Anything, mock.Anything).Return(true)
+	dns := types.Port{ID: uuid.New(), ContainerID: uuid.New(), In: "53", Out: "53", Protocol: types.PortProtocolUDP}
+	ports := &fakePortAdapter{ports: append(suite.ports, dns)}
+	service := NewPortsService(ports, &suite.host)
+
+	err := service.PatchPort(context.Background(), types.Port{ID: dns.ID, In: "53", Out: "5353"})
+	suite.Require().NoError(err)
+	suite.Equal(types.Port{ID: dns.ID, ContainerID: dns.ContainerID, In: "53", Out: "5353", Protocol: types.PortProtocolUDP}, ports.ports[1])
+
+	err = service.PatchPort(context.Background(), types.Port{ID: uuid.New(), In: "53", Out: "5353"})
+	suite.ErrorIs(err, types.ErrPortNotFound)
+}
+
+type MockHostPortsAdapter struct {
+	mock.Mock
+}
+
+func (m *MockHostPortsAdapter) IsPortFree(protocol types.PortProtocol, port int) bool {
+	args := m.Called(protocol, port)
+	return args.Bool(0)
+}
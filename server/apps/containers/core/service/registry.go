@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+
+	"github.com/juju/errors"
+	"github.com/vertex-center/uuid"
+	"github.com/vertex-center/vertex/server/apps/containers/core/port"
+	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+)
+
+type registryService struct {
+	credentials port.RegistryCredentialAdapter
+}
+
+func NewRegistryService(credentials port.RegistryCredentialAdapter) port.RegistryService {
+	return &registryService{credentials}
+}
+
+// GetRegistryCredentials returns the credentials of all registries, without
+// their password.
+func (s *registryService) GetRegistryCredentials(ctx context.Context) (types.RegistryCredentials, error) {
+	creds, err := s.credentials.GetRegistryCredentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range creds {
+		creds[i].Password = ""
+	}
+	return creds, nil
+}
+
+func (s *registryService) CreateRegistryCredential(ctx context.Context, cred types.RegistryCredential) (*types.RegistryCredential, error) {
+	registry, err := types.NormalizeRegistry(cred.Registry)
+	if err != nil {
+		return nil, err
+	}
+	if cred.Username == "" || cred.Password == "" {
+		return nil, errors.NewNotValid(nil, "username and password are required")
+	}
+
+	_, err = s.credentials.GetRegistryCredentialByRegistry(ctx, registry)
+	if err == nil {
+		return nil, types.ErrRegistryCredentialExists
+	} else if !errors.Is(err, types.ErrRegistryCredentialNotFound) {
+		return nil, err
+	}
+
+	cred.ID = uuid.New()
+	cred.Registry = registry
+	err = s.credentials.CreateRegistryCredential(ctx, cred)
+	if err != nil {
+		return nil, err
+	}
+	cred.Password = ""
+	return &cred, nil
+}
+
+func (s *registryService) PatchRegistryCredential(ctx context.Context, id uuid.UUID, patch types.RegistryCredentialPatch) (*types.RegistryCredential, error) {
+	cred, err := s.credentials.GetRegistryCredential(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if patch.Username != nil {
+		cred.Username = *patch.Username
+	}
+	if patch.Password != nil {
+		cred.Password = *patch.Password
+	}
+	if cred.Username == "" || cred.Password == "" {
+		return nil, errors.NewNotValid(nil, "username and password are required")
+	}
+
+	err = s.credentials.UpdateRegistryCredential(ctx, *cred)
+	if err != nil {
+		return nil, err
+	}
+	cred.Password = ""
+	return cred, nil
+}
+
+func (s *registryService) DeleteRegistryCredential(ctx context.Context, id uuid.UUID) error {
+	_, err := s.credentials.GetRegistryCredential(ctx, id)
+	if err != nil {
+		return err
+	}
+	return s.credentials.DeleteRegistryCredential(ctx, id)
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+
+	"github.com/vertex-center/uuid"
+	"github.com/vertex-center/vertex/server/apps/containers/core/port"
+	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+)
+
+type resourceLimitsService struct {
+	containers port.ContainerAdapter
+	limits     port.ResourceLimitsAdapter
+}
+
+func NewResourceLimitsService(containers port.ContainerAdapter, limits port.ResourceLimitsAdapter) port.ResourceLimitsService {
+	return &resourceLimitsService{containers, limits}
+}
+
+func (s *resourceLimitsService) GetResourceLimits(ctx context.Context, containerID uuid.UUID) (*types.ResourceLimits, error) {
+	return s.limits.GetContainerResourceLimits(ctx, containerID)
+}
+
+// SetResourceLimits replaces the resource limits of a container. The new
+// limits are applied the next time the Docker container is created.
+func (s *resourceLimitsService) SetResourceLimits(ctx context.Context, limits types.ResourceLimits) error {
+	c, err := s.containers.GetContainer(ctx, limits.ContainerID)
+	if err != nil {
+		return err
+	}
+	if c.ID != limits.ContainerID {
+		return types.ErrContainerNotFound
+	}
+
+	limits.ID = uuid.New()
+	for i := range limits.Ulimits {
+		limits.Ulimits[i].ID = uuid.New()
+		limits.Ulimits[i].ContainerID = limits.ContainerID
+	}
+
+	err = limits.Validate()
+	if err != nil {
+		return err
+	}
+	return s.limits.SetContainerResourceLimits(ctx, limits)
+}
+
+func (s *resourceLimitsService) DeleteResourceLimits(ctx context.Context, containerID uuid.UUID) error {
+	return s.limits.DeleteContainerResourceLimits(ctx, containerID)
+}
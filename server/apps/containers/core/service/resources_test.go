@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/juju/errors"
+	"github.com/stretchr/testify/suite"
+	"github.com/vertex-center/uuid"
+	"github.com/vertex-center/vertex/server/apps/containers/core/port"
+	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+)
+
+type ResourceLimitsServiceTestSuite struct {
+	suite.Suite
+
+	service *resourceLimitsService
+	limits  *fakeResourceLimitsAdapter
+
+	container types.Container
+}
+
+func TestResourceLimitsServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(ResourceLimitsServiceTestSuite))
+}
+
+func (suite *ResourceLimitsServiceTestSuite) SetupTest() {
+	suite.container = types.Container{ID: uuid.New()}
+	containers := &fakeContainerAdapter{containers: map[uuid.UUID]types.Container{
+		suite.container.ID: suite.container,
+	}}
+	suite.limits = &fakeResourceLimitsAdapter{limits: map[uuid.UUID]types.ResourceLimits{}}
+	suite.service = NewResourceLimitsService(containers, suite.limits).(*resourceLimitsService)
+}
+
+func (suite *ResourceLimitsServiceTestSuite) TestSetResourceLimits() {
+	err := suite.service.SetResourceLimits(context.Background(), types.ResourceLimits{ContainerID: suite.container.ID})
+	suite.Require().NoError(err)
+	suite.Contains(suite.limits.limits, suite.container.ID)
+}
+
+func (suite *ResourceLimitsServiceTestSuite) TestSetResourceLimitsMissingContainer() {
+	err := suite.service.SetResourceLimits(context.Background(), types.ResourceLimits{ContainerID: uuid.New()})
+	suite.ErrorIs(err, types.ErrContainerNotFound)
+	suite.Empty(suite.limits.limits)
+}
+
+type fakeResourceLimitsAdapter struct {
+	port.ResourceLimitsAdapter
+	limits map[uuid.UUID]types.ResourceLimits
+}
+
+func (a *fakeResourceLimitsAdapter) GetContainerResourceLimits(ctx context.Context, id uuid.UUID) (*types.ResourceLimits, error) {
+	limits, ok := a.limits[id]
+	if !ok {
+		return nil, errors.NotFoundf("resource limits")
+	}
+	return &limits, nil
+}
+
+func (a *fakeResourceLimitsAdapter) DeleteContainerResourceLimits(ctx context.Context, id uuid.UUID) error {
+	delete(a.limits, id)
+	return nil
+}
+
+func (a *fakeResourceLimitsAdapter) SetContainerResourceLimits(ctx context.Context, limits types.ResourceLimits) error {
+	a.limits[limits.ContainerID] = limits
+	return nil
+}
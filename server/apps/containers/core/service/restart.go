@@ -0,0 +1,173 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/vertex-center/uuid"
+	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+	"github.com/vertex-center/vertex/server/common/log"
+	"github.com/vertex-center/vertex/server/common/server"
+	"github.com/vertex-center/vlog"
+)
+
+const (
+	restartBackoffMin = time.Second
+	restartBackoffMax = 5 * time.Minute
+
+	// restartResetAfter is how long a container must run to be considered
+	// stable again. The crash-loop backoff is then reset.
+	restartResetAfter = time.Minute
+)
+
+type restartState struct {
+	attempts  int
+	startedAt time.Time
+	timer     *time.Timer
+}
+
+// onRunnerStatus is called when the runner changes the status of a container.
+// prev is the status of the container before this change.
+func (s *containerService) onRunnerStatus(c types.Container, prev string, status string) {
+	switch {
+	case isUp(status) && !isUp(prev):
+		s.restartsMu.Lock()
+		s.restartStateOf(c.ID).startedAt = time.Now()
+		s.restartsMu.Unlock()
+	case (status == types.ContainerStatusOff || status == types.ContainerStatusError) && isUp(prev):
+		// The container exited while nobody asked it to stop.
+		s.onContainerExit(c, status)
+	}
+}
+
+func (s *containerService) onContainerExit(c types.Container, status string) {
+	if c.RestartPolicy == "" || c.RestartPolicy == types.RestartPolicyNo {
+		return
+	}
+	if c.RestartPolicy == types.RestartPolicyUnlessStopped && c.StoppedByUser {
+		return
+	}
+
+	ctx := server.NewBackgroundContext()
+
+	reason := "the container crashed"
+	failed := status == types.ContainerStatusError
+	if !failed {
+		code, err := s.runner.ExitCode(ctx, c)
+		if err != nil {
+			log.Error(err, vlog.String("id", c.ID.String()))
+			failed = true
+		} else {
+			failed = code != 0
+			reason = fmt.Sprintf("exit code %d", code)
+		}
+	}
+
+	if c.RestartPolicy == types.RestartPolicyOnFailure && !failed {
+		return
+	}
+
+	s.restartsMu.Lock()
+	defer s.restartsMu.Unlock()
+
+	state := s.restartStateOf(c.ID)
+	if time.Since(state.startedAt) >= restartResetAfter {
+		state.attempts = 0
+	}
+
+	if c.RestartPolicy == types.RestartPolicyOnFailure && c.RestartMaxRetries > 0 && state.attempts >= c.RestartMaxRetries {
+		s.ctx.DispatchEvent(types.EventContainerLog{
+			ContainerID: c.ID,
+			Kind:        types.LogKindVertexErr,
+			Message:     types.NewLogLineMessageString(fmt.Sprintf("Container exited (%s). Restart limit of %d reached, giving up.", reason, c.RestartMaxRetries)),
+		})
+		delete(s.restarts, c.ID)
+		return
+	}
+
+	state.attempts++
+	delay := restartBackoff(state.attempts)
+
+	log.Info("restarting container",
+		vlog.String("id", c.ID.String()),
+		vlog.String("reason", reason),
+		vlog.Int("attempt", state.attempts),
+		vlog.String("delay", delay.String()),
+	)
+
+	s.ctx.DispatchEvent(types.EventContainerLog{
+		ContainerID: c.ID,
+		Kind:        types.LogKindVertexErr,
+		Message:     types.NewLogLineMessageString(fmt.Sprintf("Container exited (%s). Restarting in %s (attempt %d)...", reason, delay, state.attempts)),
+	})
+
+	id := c.ID
+	state.timer = time.AfterFunc(delay, func() {
+		s.restartsMu.Lock()
+		if st, ok := s.restarts[id]; ok {
+			st.timer = nil
+		}
+		s.restartsMu.Unlock()
+
+		err := s.Start(ctx, id)
+		if err != nil {
+			log.Error(err, vlog.String("id", id.String()))
+			s.ctx.DispatchEvent(types.EventContainerLog{
+				ContainerID: id,
+				Kind:        types.LogKindVertexErr,
+				Message:     types.NewLogLineMessageString("Failed to restart the container: " + err.Error()),
+			})
+		}
+	})
+}
+
+// cancelRestart cancels the pending restart of a container and resets its
+// backoff. It returns true if a restart was pending.
+func (s *containerService) cancelRestart(id uuid.UUID) bool {
+	s.restartsMu.Lock()
+	defer s.restartsMu.Unlock()
+
+	state, ok := s.restarts[id]
+	if !ok {
+		return false
+	}
+	delete(s.restarts, id)
+	return state.timer != nil && state.timer.Stop()
+}
+
+// stopRestartTimer cancels the pending restart of a container, but keeps
+// its backoff, for when the container is started by another way.
+func (s *containerService) stopRestartTimer(id uuid.UUID) {
+	s.restartsMu.Lock()
+	defer s.restartsMu.Unlock()
+
+	if state, ok := s.restarts[id]; ok && state.timer != nil {
+		state.timer.Stop()
+		state.timer = nil
+	}
+}
+
+// restartStateOf must be called with restartsMu locked.
+func (s *containerService) restartStateOf(id uuid.UUID) *restartState {
+	state, ok := s.restarts[id]
+	if !ok {
+		state = &restartState{}
+		s.restarts[id] = state
+	}
+	return state
+}
+
+// restartBackoff returns the delay before the given restart attempt.
+// It doubles at each attempt, from restartBackoffMin to restartBackoffMax.
+func restartBackoff(attempt int) time.Duration {
+	delay := restartBackoffMin
+	for i := 1; i < attempt && delay < restartBackoffMax; i++ {
+		delay *= 2
+	}
+	return min(delay, restartBackoffMax)
+}
+
+// isUp returns true if the status is one of a started container.
+func isUp(status string) bool {
+	return status == types.ContainerStatusRunning || status == types.ContainerStatusHealthy || status == types.ContainerStatusUnhealthy
+}
@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/stretchr/testify/suite"
+	"github.com/vertex-center/uuid"
+	"github.com/vertex-center/vertex/server/apps/containers/core/port"
+	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+	"github.com/vertex-center/vertex/server/common"
+	"github.com/vertex-center/vertex/server/common/app"
+)
+
+type RestartTestSuite struct {
+	suite.Suite
+
+	service    *containerService
+	containers *fakeContainerAdapter
+	runner     *fakeRunnerAdapter
+}
+
+func TestRestartTestSuite(t *testing.T) {
+	suite.Run(t, new(RestartTestSuite))
+}
+
+func (suite *RestartTestSuite) TestRestartBackoff() {
+	suite.Equal(time.Second, restartBackoff(1))
+	suite.Equal(2*time.Second, restartBackoff(2))
+	suite.Equal(8*time.Second, restartBackoff(4))
+	suite.Equal(restartBackoffMax, restartBackoff(10))
+	suite.Equal(restartBackoffMax, restartBackoff(1000))
+}
+
+func (suite *RestartTestSuite) SetupTest() {
+	suite.containers = &fakeContainerAdapter{containers: map[uuid.UUID]types.Container{}}
+	suite.runner = &fakeRunnerAdapter{}
+	suite.service = &containerService{
+		ctx:        app.NewContext(common.NewVertexContext(common.About{}, false)),
+		containers: suite.containers,
+		runner:     suite.runner,
+		restarts:   map[uuid.UUID]*restartState{},
+	}
+}
+
+func (suite *RestartTestSuite) TearDownTest() {
+	for id := range suite.service.restarts {
+		suite.service.cancelRestart(id)
+	}
+}
+
+// restartPending returns whether a restart of the container is scheduled.
+func (suite *RestartTestSuite) restartPending(id uuid.UUID) bool {
+	suite.service.restartsMu.Lock()
+	defer suite.service.restartsMu.Unlock()
+	state, ok := suite.service.restarts[id]
+	return ok && state.timer != nil
+}
+
+func (suite *RestartTestSuite) TestOnContainerExit() {
+	tests := []struct {
+		name          string
+		policy        types.RestartPolicy
+		status        string
+		exitCode      int
+		stoppedByUser bool
+		restart       bool
+	}{
+		{name: "default", policy: "", status: types.ContainerStatusOff, exitCode: 1},
+		{name: "no", policy: types.RestartPolicyNo, status: types.ContainerStatusError},
+		{name: "on-failure success", policy: types.RestartPolicyOnFailure, status: types.ContainerStatusOff},
+		{name: "on-failure exit code", policy: types.RestartPolicyOnFailure, status: types.ContainerStatusOff, exitCode: 1, restart: true},
+		{name: "on-failure error", policy: types.RestartPolicyOnFailure, status: types.ContainerStatusError, restart: true},
+		{name: "always", policy: types.RestartPolicyAlways, status: types.ContainerStatusOff, restart: true},
+		{name: "always stopped by user", policy: types.RestartPolicyAlways, status: types.ContainerStatusOff, stoppedByUser: true, restart: true},
+		{name: "unless-stopped", policy: types.RestartPolicyUnlessStopped, status: types.ContainerStatusOff, restart: true},
+		{name: "unless-stopped stopped by user", policy: types.RestartPolicyUnlessStopped, status: types.ContainerStatusOff, stoppedByUser: true},
+	}
+	for _, tt := range tests {
+		suite.runner.exitCode = tt.exitCode
+		c := types.Container{ID: uuid.New(), RestartPolicy: tt.policy, StoppedByUser: tt.stoppedByUser}
+
+		suite.service.onContainerExit(c, tt.status)
+
+		suite.Equal(tt.restart, suite.restartPending(c.ID), tt.name)
+	}
+}
+
+func (suite *RestartTestSuite) TestOnContainerExitMaxRetries() {
+	suite.runner.exitCode = 1
+	c := types.Container{ID: uuid.New(), RestartPolicy: types.RestartPolicyOnFailure, RestartMaxRetries: 2}
+
+	// The container crashes right after each start.
+	for i := 0; i < 2; i++ {
+		suite.service.onRunnerStatus(c, types.ContainerStatusStarting, types.ContainerStatusRunning)
+		suite.service.onContainerExit(c, types.ContainerStatusOff)
+		suite.True(suite.restartPending(c.ID))
+		suite.service.stopRestartTimer(c.ID)
+	}
+
+	suite.service.onRunnerStatus(c, types.ContainerStatusStarting, types.ContainerStatusRunning)
+	suite.service.onContainerExit(c, types.ContainerStatusOff)
+	suite.False(suite.restartPending(c.ID))
+	suite.NotContains(suite.service.restarts, c.ID)
+}
+
+func (suite *RestartTestSuite) TestOnRunnerStatus() {
+	c := types.Container{ID: uuid.New(), RestartPolicy: types.RestartPolicyAlways}
+
+	suite.service.onRunnerStatus(c, types.ContainerStatusStarting, types.ContainerStatusRunning)
+	suite.Require().Contains(suite.service.restarts, c.ID)
+	suite.WithinDuration(time.Now(), suite.service.restarts[c.ID].startedAt, time.Second)
+	suite.False(suite.restartPending(c.ID))
+
+	// Stopped by Vertex.
+	suite.service.onRunnerStatus(c, types.ContainerStatusStopping, types.ContainerStatusOff)
+	suite.False(suite.restartPending(c.ID))
+
+	// Exited by itself.
+	suite.service.onRunnerStatus(c, types.ContainerStatusHealthy, types.ContainerStatusOff)
+	suite.True(suite.restartPending(c.ID))
+}
+
+func (suite *RestartTestSuite) TestStopByUser() {
+	c := types.Container{ID: uuid.New(), RestartPolicy: types.RestartPolicyUnlessStopped, Status: types.ContainerStatusRunning}
+	suite.containers.containers[c.ID] = c
+
+	err := suite.service.Stop(context.Background(), c.ID)
+	suite.Require().NoError(err)
+	suite.True(suite.containers.containers[c.ID].StoppedByUser)
+	suite.Equal(types.ContainerStatusOff, suite.containers.containers[c.ID].Status)
+
+	// Stopping every container with Vertex is not a stop by the user.
+	c = types.Container{ID: uuid.New(), RestartPolicy: types.RestartPolicyUnlessStopped, Status: types.ContainerStatusRunning}
+	suite.containers.containers = map[uuid.UUID]types.Container{c.ID: c}
+	suite.service.deps = &fakeDependencyAdapter{}
+
+	err = suite.service.StopAll(context.Background())
+	suite.Require().NoError(err)
+	suite.False(suite.containers.containers[c.ID].StoppedByUser)
+	suite.Equal(types.ContainerStatusOff, suite.containers.containers[c.ID].Status)
+}
+
+type fakeRunnerAdapter struct {
+	port.RunnerAdapter
+	exitCode         int
+	dockerContainers []types.DockerContainer
+	inspect          types.InspectContainerResponse
+	images           map[uuid.UUID]string
+	updates          map[uuid.UUID]*types.ContainerUpdate
+	checked          []uuid.UUID
+}
+
+func (a *fakeRunnerAdapter) GetDockerContainers(ctx context.Context) ([]types.DockerContainer, error) {
+	return a.dockerContainers, nil
+}
+
+func (a *fakeRunnerAdapter) InspectDockerContainer(ctx context.Context, id string) (types.InspectContainerResponse, error) {
+	return a.inspect, nil
+}
+
+func (a *fakeRunnerAdapter) AdoptDockerContainer(ctx context.Context, id string, c types.Container) error {
+	return nil
+}
+
+func (a *fakeRunnerAdapter) ExitCode(ctx context.Context, c types.Container) (int, error) {
+	return a.exitCode, nil
+}
+
+func (a *fakeRunnerAdapter) Stop(ctx context.Context, c *types.Container) error {
+	return nil
+}
+
+func (a *fakeRunnerAdapter) DeleteContainer(ctx context.Context, c *types.Container, volumes []string) error {
+	return nil
+}
+
+func (a *fakeRunnerAdapter) DeleteMounts(ctx context.Context, c *types.Container) error {
+	return nil
+}
+
+func (a *fakeRunnerAdapter) GetImageID(ctx context.Context, c types.Container) (string, error) {
+	image, ok := a.images[c.ID]
+	if !ok {
+		return "", errors.NotFoundf("image")
+	}
+	return image, nil
+}
+
+func (a *fakeRunnerAdapter) CheckForUpdates(ctx context.Context, c *types.Container) error {
+	a.checked = append(a.checked, c.ID)
+	c.Update = a.updates[c.ID]
+	return nil
+}
+
+type fakeDependencyAdapter struct {
+	port.DependencyAdapter
+	deps types.Dependencies
+}
+
+func (a *fakeDependencyAdapter) GetDependencies(ctx context.Context) (types.Dependencies, error) {
+	return slices.Clone(a.deps), nil
+}
+
+func (a *fakeDependencyAdapter) GetContainerDependencies(ctx context.Context, id uuid.UUID) (types.Dependencies, error) {
+	return a.deps.Of(id), nil
+}
+
+func (a *fakeDependencyAdapter) SetDependency(ctx context.Context, dep types.Dependency) error {
+	_ = a.DeleteDependency(ctx, dep.ContainerID, dep.DependsOnID)
+	a.deps = append(a.deps, dep)
+	return nil
+}
+
+func (a *fakeDependencyAdapter) DeleteContainerDependencies(ctx context.Context, id uuid.UUID) error {
+	a.deps = slices.DeleteFunc(a.deps, func(dep types.Dependency) bool {
+		return dep.ContainerID == id || dep.DependsOnID == id
+	})
+	return nil
+}
+
+func (a *fakeDependencyAdapter) DeleteDependency(ctx context.Context, id uuid.UUID, dependsOnID uuid.UUID) error {
+	a.deps = slices.DeleteFunc(a.deps, func(dep types.Dependency) bool {
+		return dep.ContainerID == id && dep.DependsOnID == dependsOnID
+	})
+	return nil
+}
@@ -0,0 +1,306 @@
+package service
+
+import (
+	"context"
+	goerrors "errors"
+
+	"github.com/juju/errors"
+	"github.com/vertex-center/uuid"
+	"github.com/vertex-center/vertex/server/apps/containers/core/port"
+	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+	"github.com/vertex-center/vertex/server/common/log"
+	"github.com/vertex-center/vlog"
+)
+
+type stackService struct {
+	containerService port.ContainerService
+	stacks           port.StackAdapter
+	containers       port.ContainerAdapter
+	vars             port.EnvAdapter
+	deps             port.DependencyAdapter
+	runner           port.RunnerAdapter
+	templates        port.TemplateAdapter
+}
+
+func NewStackService(
+	containerService port.ContainerService,
+	stacks port.StackAdapter,
+	containers port.ContainerAdapter,
+	vars port.EnvAdapter,
+	deps port.DependencyAdapter,
+	runner port.RunnerAdapter,
+	templates port.TemplateAdapter,
+) port.StackService {
+	return &stackService{
+		containerService: containerService,
+		stacks:           stacks,
+		containers:       containers,
+		vars:             vars,
+		deps:             deps,
+		runner:           runner,
+		templates:        templates,
+	}
+}
+
+func (s *stackService) GetStack(ctx context.Context, id uuid.UUID) (*types.Stack, error) {
+	stack, err := s.stacks.GetStack(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	stack.Containers, err = s.containers.GetContainersWithFilters(ctx, types.ContainerFilters{
+		StackID: &id,
+	})
+	return stack, err
+}
+
+func (s *stackService) GetStacks(ctx context.Context) (types.Stacks, error) {
+	return s.stacks.GetStacks(ctx)
+}
+
+// CreateStack creates a stack and all its containers from a stack template.
+// If a container cannot be created, everything created before is deleted.
+func (s *stackService) CreateStack(ctx context.Context, opts types.CreateStackOptions) (*types.Stack, error) {
+	template, err := s.templates.Get(opts.TemplateID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !template.IsStack() || template.Stack == nil {
+		return nil, types.ErrTemplateNotStack
+	}
+
+	err = validateStackTemplate(*template.Stack)
+	if err != nil {
+		return nil, err
+	}
+
+	stack := types.Stack{
+		ID:          uuid.New(),
+		TemplateID:  &template.ID,
+		Name:        template.Name,
+		Description: &template.Description,
+		Color:       template.Color,
+		Icon:        template.Icon,
+	}
+	if opts.Name != nil && *opts.Name != "" {
+		stack.Name = *opts.Name
+	}
+
+	err = s.stacks.CreateStack(ctx, stack)
+	if err != nil {
+		return nil, err
+	}
+
+	members := map[string]uuid.UUID{}
+	err = s.createMembers(ctx, stack, template, members)
+	if err != nil {
+		s.rollback(ctx, stack.ID, members)
+		return nil, err
+	}
+
+	return s.GetStack(ctx, stack.ID)
+}
+
+func (s *stackService) createMembers(ctx context.Context, stack types.Stack, template types.Template, members map[string]uuid.UUID) error {
+	for _, m := range template.Stack.Containers {
+		c, err := s.containerService.CreateContainer(ctx, types.CreateContainerOptions{
+			TemplateID: m.Template,
+			Image:      m.Image,
+			ImageTag:   m.ImageTag,
+		})
+		if err != nil {
+			return err
+		}
+		members[m.Name] = c.ID
+
+		c.StackID = &stack.ID
+		c.StackMember = &m.Name
+		if m.Template == nil {
+			c.Name = m.Name
+		}
+		err = s.containerService.UpdateContainer(ctx, c.ID, *c)
+		if err != nil {
+			return err
+		}
+
+		// The environment of the stack is shared by all containers.
+		for _, e := range template.Env {
+			err = s.setEnv(ctx, types.EnvVariable{
+				ContainerID: c.ID,
+				Type:        types.EnvVariableType(e.Type),
+				Name:        e.Name,
+				DisplayName: e.DisplayName,
+				Value:       e.Default,
+				Default:     &e.Default,
+				Description: &e.Description,
+				Secret:      e.Secret != nil && *e.Secret,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		for name, value := range m.Env {
+			err = s.setEnv(ctx, types.EnvVariable{
+				ContainerID: c.ID,
+				Name:        name,
+				DisplayName: name,
+				Value:       value,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, m := range template.Stack.Containers {
+		for _, dep := range m.DependsOn {
+			err := setDependency(ctx, s.deps, types.Dependency{
+				ContainerID: members[m.Name],
+				DependsOnID: members[dep],
+				WaitFor:     types.DependencyConditionRunning,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// setEnv updates the variable if the container already has it, or creates it.
+func (s *stackService) setEnv(ctx context.Context, env types.EnvVariable) error {
+	envs, err := s.vars.GetEnvs(ctx, types.EnvVariableFilters{
+		ContainerID: &env.ContainerID,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, e := range envs {
+		if e.Name == env.Name {
+			e.Value = env.Value
+			return s.vars.UpdateEnvByID(ctx, e)
+		}
+	}
+
+	env.ID = uuid.New()
+	err = env.Validate()
+	if err != nil {
+		return err
+	}
+	return s.vars.CreateEnv(ctx, env)
+}
+
+func (s *stackService) rollback(ctx context.Context, id uuid.UUID, members map[string]uuid.UUID) {
+	for _, containerID := range members {
+		err := s.containerService.Delete(ctx, containerID)
+		if err != nil {
+			log.Error(err, vlog.String("stack_id", id.String()), vlog.String("container_id", containerID.String()))
+		}
+	}
+	err := s.stacks.DeleteStack(ctx, id)
+	if err != nil {
+		log.Error(err, vlog.String("stack_id", id.String()))
+	}
+}
+
+// DeleteStack deletes all the containers of a stack, and the stack itself.
+// All the containers must be stopped first.
+func (s *stackService) DeleteStack(ctx context.Context, id uuid.UUID) error {
+	stack, err := s.GetStack(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range stack.Containers {
+		if c.IsRunning() {
+			return types.ErrContainerStillRunning
+		}
+	}
+
+	var errs []error
+	for _, c := range stack.Containers {
+		err := s.containerService.Delete(ctx, c.ID)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return goerrors.Join(errs...)
+	}
+
+	err = s.runner.DeleteNetwork(ctx, types.StackNetworkName(id))
+	if err != nil && !errors.Is(err, errors.NotFound) {
+		return err
+	}
+
+	return s.stacks.DeleteStack(ctx, id)
+}
+
+// StartStack starts all the containers of a stack in the order of their
+// dependencies. If one of them fails to start, the whole stack is stopped.
+func (s *stackService) StartStack(ctx context.Context, id uuid.UUID) error {
+	ids, err := s.getContainerIDs(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	err = s.containerService.StartContainers(ctx, ids)
+	if err != nil {
+		stopErr := s.containerService.StopContainers(ctx, ids)
+		if stopErr != nil {
+			log.Error(stopErr, vlog.String("stack_id", id.String()))
+		}
+		return err
+	}
+	return nil
+}
+
+// StopStack stops all the containers of a stack, the dependents first.
+func (s *stackService) StopStack(ctx context.Context, id uuid.UUID) error {
+	ids, err := s.getContainerIDs(ctx, id)
+	if err != nil {
+		return err
+	}
+	return s.containerService.StopContainers(ctx, ids)
+}
+
+func (s *stackService) getContainerIDs(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error) {
+	stack, err := s.GetStack(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []uuid.UUID
+	for _, c := range stack.Containers {
+		ids = append(ids, c.ID)
+	}
+	return ids, nil
+}
+
+// validateStackTemplate checks that the containers of a stack have unique
+// names, and only depend on containers of the same stack.
+func validateStackTemplate(stack types.TemplateStack) error {
+	names := map[string]bool{}
+	for _, m := range stack.Containers {
+		if m.Name == "" || names[m.Name] {
+			return errors.NewNotValid(types.ErrStackMemberUnknown, "stack containers must have a unique name")
+		}
+		if m.Template == nil && m.Image == nil {
+			return errors.NewNotValid(types.ErrStackMemberUnknown, "stack container "+m.Name+" needs a template or an image")
+		}
+		names[m.Name] = true
+	}
+
+	for _, m := range stack.Containers {
+		for _, dep := range m.DependsOn {
+			if !names[dep] {
+				return errors.NewNotValid(types.ErrStackMemberUnknown, "stack container "+m.Name+" depends on unknown container "+dep)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/juju/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/vertex-center/uuid"
+	"github.com/vertex-center/vertex/server/apps/containers/core/port"
+	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+)
+
+type StackServiceTestSuite struct {
+	suite.Suite
+
+	service          *stackService
+	containerService port.MockContainerService
+	stacks           *fakeStackAdapter
+	containers       *fakeContainerAdapter
+	vars             *fakeEnvAdapter
+	deps             *fakeDependencyAdapter
+	templates        *fakeTemplateAdapter
+
+	db  types.Container
+	app types.Container
+}
+
+func TestStackServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(StackServiceTestSuite))
+}
+
+func (suite *StackServiceTestSuite) SetupTest() {
+	suite.stacks = &fakeStackAdapter{stacks: map[uuid.UUID]types.Stack{}}
+	suite.containers = &fakeContainerAdapter{containers: map[uuid.UUID]types.Container{}}
+	suite.vars = &fakeEnvAdapter{}
+	suite.deps = &fakeDependencyAdapter{}
+
+	dbImage, appImage := "mariadb", "wordpress"
+	suite.templates = &fakeTemplateAdapter{templates: map[string]types.Template{
+		"wordpress": {
+			ID:   "wordpress",
+			Name: "WordPress",
+			Kind: types.TemplateKindStack,
+			Env: []types.TemplateEnv{
+				{Type: "string", Name: "DB_PASSWORD", DisplayName: "Database password", Default: "wordpress"},
+			},
+			Stack: &types.TemplateStack{
+				Containers: []types.TemplateStackContainer{
+					{Name: "db", Image: &dbImage},
+					{Name: "app", Image: &appImage, DependsOn: []string{"db"}, Env: map[string]string{"DB_HOST": "db"}},
+				},
+			},
+		},
+	}}
+
+	suite.db = types.Container{ID: uuid.New()}
+	suite.app = types.Container{ID: uuid.New()}
+	suite.containerService = port.MockContainerService{}
+	suite.containerService.On("UpdateContainer", mock.Anything, mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		c := args.Get(2).(types.Container)
+		suite.containers.containers[c.ID] = c
+	})
+	suite.containerService.On("Delete", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		delete(suite.containers.containers, args.Get(1).(uuid.UUID))
+	})
+
+	suite.service = NewStackService(&suite.containerService, suite.stacks, suite.containers, suite.vars,
+		suite.deps, nil, suite.templates).(*stackService)
+}
+
+// onCreate makes the next creations of containers return cs in this order.
+func (suite *StackServiceTestSuite) onCreate(cs ...*types.Container) {
+	for _, c := range cs {
+		suite.containers.containers[c.ID] = *c
+		suite.containerService.On("CreateContainer", mock.Anything, mock.Anything).Return(c, nil).Once()
+	}
+}
+
+func (suite *StackServiceTestSuite) TestCreateStack() {
+	suite.onCreate(&suite.db, &suite.app)
+
+	stack, err := suite.service.CreateStack(context.Background(), types.CreateStackOptions{TemplateID: "wordpress"})
+	suite.Require().NoError(err)
+
+	suite.Equal("WordPress", stack.Name)
+	suite.Len(stack.Containers, 2)
+	suite.Equal("app", *suite.containers.containers[suite.app.ID].StackMember)
+	suite.Equal(stack.ID, *suite.containers.containers[suite.app.ID].StackID)
+	suite.Equal(types.Dependencies{
+		{ContainerID: suite.app.ID, DependsOnID: suite.db.ID, WaitFor: types.DependencyConditionRunning},
+	}, suite.deps.deps)
+
+	env := map[uuid.UUID]map[string]string{}
+	for _, e := range suite.vars.env {
+		if env[e.ContainerID] == nil {
+			env[e.ContainerID] = map[string]string{}
+		}
+		env[e.ContainerID][e.Name] = e.Value
+	}
+	suite.Equal(map[string]string{"DB_PASSWORD": "wordpress"}, env[suite.db.ID])
+	suite.Equal(map[string]string{"DB_PASSWORD": "wordpress", "DB_HOST": "db"}, env[suite.app.ID])
+}
+
+func (suite *StackServiceTestSuite) TestCreateStackRollback() {
+	suite.onCreate(&suite.db)
+	suite.containerService.On("CreateContainer", mock.Anything, mock.Anything).Return(nil, errors.New("no space left on device")).Once()
+
+	_, err := suite.service.CreateStack(context.Background(), types.CreateStackOptions{TemplateID: "wordpress"})
+	suite.Error(err)
+
+	suite.containerService.AssertCalled(suite.T(), "Delete", mock.Anything, suite.db.ID)
+	suite.Empty(suite.containers.containers)
+	suite.Empty(suite.stacks.stacks)
+}
+
+func (suite *StackServiceTestSuite) TestCreateStackRollbackDependencyCycle() {
+	template := suite.templates.templates["wordpress"]
+	template.Stack.Containers[0].DependsOn = []string{"app"}
+	suite.onCreate(&suite.db, &suite.app)
+
+	_, err := suite.service.CreateStack(context.Background(), types.CreateStackOptions{TemplateID: "wordpress"})
+	suite.ErrorIs(err, types.ErrDependencyCycle)
+
+	suite.Empty(suite.containers.containers)
+	suite.Empty(suite.stacks.stacks)
+}
+
+func (suite *StackServiceTestSuite) TestCreateStackInvalidTemplate() {
+	_, err := suite.service.CreateStack(context.Background(), types.CreateStackOptions{TemplateID: "missing"})
+	suite.ErrorIs(err, types.ErrTemplateNotFound)
+
+	suite.templates.templates["redis"] = types.Template{ID: "redis"}
+	_, err = suite.service.CreateStack(context.Background(), types.CreateStackOptions{TemplateID: "redis"})
+	suite.ErrorIs(err, types.ErrTemplateNotStack)
+
+	template := suite.templates.templates["wordpress"]
+	template.Stack.Containers[1].DependsOn = []string{"cache"}
+	_, err = suite.service.CreateStack(context.Background(), types.CreateStackOptions{TemplateID: "wordpress"})
+	suite.ErrorIs(err, types.ErrStackMemberUnknown)
+
+	suite.containerService.AssertNotCalled(suite.T(), "CreateContainer", mock.Anything, mock.Anything)
+	suite.Empty(suite.stacks.stacks)
+}
+
+func (suite *StackServiceTestSuite) TestValidateStackTemplate() {
+	image := "redis"
+	tests := []struct {
+		name       string
+		containers []types.TemplateStackContainer
+		valid      bool
+	}{
+		{name: "valid", containers: []types.TemplateStackContainer{{Name: "a", Image: &image}, {Name: "b", Image: &image, DependsOn: []string{"a"}}}, valid: true},
+		{name: "no name", containers: []types.TemplateStackContainer{{Image: &image}}},
+		{name: "duplicate name", containers: []types.TemplateStackContainer{{Name: "a", Image: &image}, {Name: "a", Image: &image}}},
+		{name: "no image", containers: []types.TemplateStackContainer{{Name: "a"}}},
+		{name: "unknown dependency", containers: []types.TemplateStackContainer{{Name: "a", Image: &image, DependsOn: []string{"b"}}}},
+	}
+	for _, tt := range tests {
+		err := validateStackTemplate(types.TemplateStack{Containers: tt.containers})
+		if tt.valid {
+			suite.NoError(err, tt.name)
+		} else {
+			suite.ErrorIs(err, types.ErrStackMemberUnknown, tt.name)
+			suite.True(errors.Is(err, errors.NotValid), tt.name)
+		}
+	}
+}
+
+type fakeStackAdapter struct {
+	port.StackAdapter
+	stacks map[uuid.UUID]types.Stack
+}
+
+func (a *fakeStackAdapter) GetStack(ctx context.Context, id uuid.UUID) (*types.Stack, error) {
+	stack, ok := a.stacks[id]
+	if !ok {
+		return nil, types.ErrStackNotFound
+	}
+	return &stack, nil
+}
+
+func (a *fakeStackAdapter) CreateStack(ctx context.Context, stack types.Stack) error {
+	a.stacks[stack.ID] = stack
+	return nil
+}
+
+func (a *fakeStackAdapter) DeleteStack(ctx context.Context, id uuid.UUID) error {
+	delete(a.stacks, id)
+	return nil
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/vertex-center/uuid"
+	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+	"github.com/vertex-center/vertex/server/common/log"
+	"github.com/vertex-center/vertex/server/common/server"
+	"github.com/vertex-center/vlog"
+)
+
+// statsInterval is the time between two collections of the stats of all
+// the running containers, exported as metrics.
+const statsInterval = 15 * time.Second
+
+// GetContainerStats returns the resources currently used by a container.
+// A container that is not running uses no resources.
+func (s *containerService) GetContainerStats(ctx context.Context, id uuid.UUID) (types.ContainerStats, error) {
+	c, err := s.containers.GetContainer(ctx, id)
+	if err != nil {
+		return types.ContainerStats{}, err
+	}
+	if !isUp(c.Status) {
+		return types.ContainerStats{}, nil
+	}
+
+	stats, err := s.runner.Stats(ctx, *c)
+	if err != nil {
+		return types.ContainerStats{}, err
+	}
+
+	s.ctx.DispatchEvent(types.EventContainerStats{
+		ContainerID: id,
+		Stats:       stats,
+	})
+	return stats, nil
+}
+
+// collectStats periodically reads the stats of the running containers,
+// until the context is cancelled.
+func (s *containerService) collectStats(ctx context.Context) {
+	ticker := time.NewTicker(statsInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		bgCtx := server.NewBackgroundContext()
+		containers, err := s.containers.GetContainers(bgCtx)
+		if err != nil {
+			log.Error(err)
+			continue
+		}
+
+		for _, c := range containers {
+			if !isUp(c.Status) {
+				continue
+			}
+			_, err := s.GetContainerStats(bgCtx, c.ID)
+			if err != nil {
+				log.Error(err, vlog.String("container_id", c.ID.String()))
+			}
+		}
+	}
+}
@@ -0,0 +1,261 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/vertex-center/uuid"
+	"github.com/vertex-center/vertex/server/apps/containers/core/port"
+	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+	"gopkg.in/yaml.v3"
+)
+
+// templateService manages the templates created by users. They are stored in
+// the database, and served with the templates of the sources.
+type templateService struct {
+	templates  port.TemplateAdapter
+	user       port.UserTemplateAdapter
+	containers port.ContainerAdapter
+	vars       port.EnvAdapter
+	ports      port.PortAdapter
+	volumes    port.VolumeAdapter
+	caps       port.CapAdapter
+	sysctls    port.SysctlAdapter
+	health     port.HealthCheckAdapter
+	builds     port.BuildAdapter
+}
+
+func NewTemplateService(
+	templates port.TemplateAdapter,
+	user port.UserTemplateAdapter,
+	containers port.ContainerAdapter,
+	vars port.EnvAdapter,
+	ports port.PortAdapter,
+	volumes port.VolumeAdapter,
+	caps port.CapAdapter,
+	sysctls port.SysctlAdapter,
+	health port.HealthCheckAdapter,
+	builds port.BuildAdapter,
+) port.TemplateService {
+	return &templateService{
+		templates:  templates,
+		user:       user,
+		containers: containers,
+		vars:       vars,
+		ports:      ports,
+		volumes:    volumes,
+		caps:       caps,
+		sysctls:    sysctls,
+		health:     health,
+		builds:     builds,
+	}
+}
+
+// CreateTemplate saves a template for the user. Templates in older versions
+// are upgraded first. The ID must not be used by another template.
+func (s *templateService) CreateTemplate(ctx context.Context, userID uuid.UUID, template types.Template) (*types.Template, error) {
+	// The template goes through the same parsing as the catalog templates.
+	data, err := yaml.Marshal(template)
+	if err != nil {
+		return nil, err
+	}
+	t, err := types.ParseTemplate(data)
+	if err != nil {
+		return nil, err
+	}
+	err = t.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.templates.Get(t.ID)
+	if err == nil {
+		return nil, types.ErrTemplateAlreadyExists
+	} else if !errors.Is(err, types.ErrTemplateNotFound) {
+		return nil, err
+	}
+
+	t.Source = types.TemplateSourceUser
+	t.UserID = &userID
+	err = s.user.CreateTemplate(ctx, *t)
+	if err != nil {
+		return nil, err
+	}
+	return t, s.LoadUserTemplates(ctx)
+}
+
+// ValidateTemplate returns the problems of a template file, without saving it.
+func (s *templateService) ValidateTemplate(ctx context.Context, data []byte) types.TemplateLint {
+	return types.LintTemplate("", data)
+}
+
+// CreateTemplateFromContainer saves the configuration of a container as a
+// template. The values of secret variables are not saved.
+func (s *templateService) CreateTemplateFromContainer(ctx context.Context, userID uuid.UUID, containerID uuid.UUID, opts types.ContainerTemplateOptions) (*types.Template, error) {
+	c, err := s.containers.GetContainer(ctx, containerID)
+	if err != nil {
+		return nil, err
+	}
+	if c.ID != containerID {
+		return nil, types.ErrContainerNotFound
+	}
+
+	t := types.Template{
+		TemplateVersioning: types.TemplateVersioning{Version: types.MaxSupportedVersion},
+		ID:                 opts.ID,
+		Name:               c.Name,
+		Color:              c.Color,
+		Icon:               c.Icon,
+		Methods: types.TemplateMethods{
+			Docker: &types.TemplateMethodDocker{
+				Cmd: c.Command,
+			},
+		},
+	}
+	if opts.Name != nil {
+		t.Name = *opts.Name
+	}
+	if c.Description != nil {
+		t.Description = *c.Description
+	}
+
+	docker := t.Methods.Docker
+	if c.IsBuilt() {
+		build, err := s.builds.GetContainerBuild(ctx, c.ID)
+		if err != nil {
+			return nil, err
+		}
+		docker.Clone = &types.TemplateClone{Repository: build.Repository}
+		docker.Dockerfile = &build.Dockerfile
+		if len(build.Args) > 0 {
+			docker.BuildArgs = map[string]string{}
+			for _, arg := range build.Args {
+				docker.BuildArgs[arg.Name] = arg.Value
+			}
+		}
+	} else {
+		docker.Image = &c.Image
+	}
+
+	env, err := s.vars.GetEnvs(ctx, types.EnvVariableFilters{ContainerID: &c.ID})
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range env {
+		te := types.TemplateEnv{
+			Type:        string(e.Type),
+			Name:        e.Name,
+			DisplayName: e.DisplayName,
+			Default:     e.Value,
+		}
+		if e.Description != nil {
+			te.Description = *e.Description
+		}
+		if e.Secret {
+			secret := true
+			te.Secret = &secret
+			te.Default = ""
+		}
+		t.Env = append(t.Env, te)
+	}
+
+	ports, err := s.ports.GetPorts(ctx, types.PortFilters{ContainerID: &c.ID})
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range ports {
+		t.Ports = append(t.Ports, types.TemplatePort{
+			Name:     p.In,
+			Port:     p.In,
+			Protocol: string(p.Protocol),
+		})
+	}
+
+	volumes, err := s.volumes.GetContainerVolumes(ctx, c.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(volumes) > 0 {
+		m := map[string]string{}
+		for _, v := range volumes {
+			out := v.Out
+			if v.Type == types.VolumeTypeVolume {
+				out = strings.TrimPrefix(out, "VERTEX_VOLUME_"+c.ID.String()+"_")
+			}
+			m[out] = v.In
+		}
+		docker.Volumes = &m
+	}
+
+	caps, err := s.caps.GetContainerCaps(ctx, c.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(caps) > 0 {
+		names := make([]string, 0, len(caps))
+		for _, cp := range caps {
+			names = append(names, cp.Name)
+		}
+		docker.Capabilities = &names
+	}
+
+	sysctls, err := s.sysctls.GetContainerSysctls(ctx, c.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(sysctls) > 0 {
+		m := map[string]string{}
+		for _, sc := range sysctls {
+			m[sc.Name] = sc.Value
+		}
+		docker.Sysctls = &m
+	}
+
+	hc, err := s.health.GetContainerHealthCheck(ctx, c.ID)
+	if err != nil && !errors.Is(err, errors.NotFound) {
+		return nil, err
+	} else if err == nil {
+		docker.Healthcheck = &types.TemplateHealthCheck{
+			Type:        string(hc.Type),
+			Port:        hc.Port,
+			Path:        hc.Path,
+			Command:     hc.Command,
+			Interval:    &hc.Interval,
+			Timeout:     &hc.Timeout,
+			StartPeriod: &hc.StartPeriod,
+			Retries:     &hc.Retries,
+		}
+	}
+
+	return s.CreateTemplate(ctx, userID, t)
+}
+
+// DeleteTemplate deletes a template created by the user. The templates of
+// the sources can't be deleted.
+func (s *templateService) DeleteTemplate(ctx context.Context, userID uuid.UUID, id string) error {
+	t, err := s.user.GetTemplate(ctx, id)
+	if err != nil {
+		return err
+	}
+	if t.UserID == nil || *t.UserID != userID {
+		return errors.NewForbidden(nil, "the template belongs to another user")
+	}
+
+	err = s.user.DeleteTemplate(ctx, id)
+	if err != nil {
+		return err
+	}
+	return s.LoadUserTemplates(ctx)
+}
+
+// LoadUserTemplates reads the templates created by users, and serves them
+// with the templates of the sources.
+func (s *templateService) LoadUserTemplates(ctx context.Context) error {
+	templates, err := s.user.GetTemplates(ctx)
+	if err != nil {
+		return err
+	}
+	s.templates.SetUserTemplates(templates)
+	return nil
+}
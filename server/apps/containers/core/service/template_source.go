@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-co-op/gocron"
+	"github.com/juju/errors"
+	"github.com/vertex-center/uuid"
+	"github.com/vertex-center/vertex/server/apps/containers/core/port"
+	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+	"github.com/vertex-center/vertex/server/common/app"
+	ev "github.com/vertex-center/vertex/server/common/event"
+	"github.com/vertex-center/vertex/server/common/log"
+	"github.com/vertex-center/vertex/server/pkg/event"
+)
+
+// templateSourcesRefreshInterval is the time between two refreshes of the
+// template sources.
+const templateSourcesRefreshInterval = time.Hour
+
+type templateSourceService struct {
+	uuid      uuid.UUID
+	ctx       *app.Context
+	sources   port.TemplateSourceAdapter
+	templates port.TemplateAdapter
+
+	scheduler *gocron.Scheduler
+
+	// refreshes is the number of refreshes started and not finished.
+	refreshes atomic.Int32
+}
+
+func NewTemplateSourceService(ctx *app.Context, sources port.TemplateSourceAdapter, templates port.TemplateAdapter) port.TemplateSourceService {
+	s := &templateSourceService{
+		uuid:      uuid.New(),
+		ctx:       ctx,
+		sources:   sources,
+		templates: templates,
+	}
+	s.ctx.AddListener(s)
+	return s
+}
+
+func (s *templateSourceService) GetUUID() uuid.UUID {
+	return s.uuid
+}
+
+func (s *templateSourceService) OnEvent(e event.Event) error {
+	switch e.(type) {
+	case ev.ServerSetupCompleted:
+		return s.startScheduler()
+	case ev.ServerStop:
+		if s.scheduler != nil {
+			s.scheduler.Clear()
+			s.scheduler.Stop()
+		}
+	}
+	return nil
+}
+
+// GetSources returns the template sources, including the built-in one, with
+// the status of their latest refresh.
+func (s *templateSourceService) GetSources(ctx context.Context) types.TemplateSources {
+	sources := s.templates.GetSources()
+	if s.refreshes.Load() > 0 {
+		for i := range sources {
+			sources[i].Status.Refreshing = true
+		}
+	}
+	return sources
+}
+
+func (s *templateSourceService) CreateSource(ctx context.Context, source types.TemplateSource) (*types.TemplateSource, error) {
+	err := source.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, other := range s.templates.GetSources() {
+		if other.Name == source.Name {
+			return nil, types.ErrTemplateSourceAlreadyExists
+		}
+	}
+
+	source.ID = uuid.New()
+	err = s.sources.CreateSource(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+
+	// The source is read in the background, and its errors are reported in
+	// its status.
+	err = s.LoadSources(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, other := range s.GetSources(ctx) {
+		if other.ID == source.ID {
+			return &other, nil
+		}
+	}
+	return &source, nil
+}
+
+func (s *templateSourceService) DeleteSource(ctx context.Context, id uuid.UUID) error {
+	if id == uuid.Nil {
+		return types.ErrTemplateSourceBuiltIn
+	}
+	_, err := s.sources.GetSource(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	err = s.sources.DeleteSource(ctx, id)
+	if err != nil {
+		return err
+	}
+	err = s.LoadSources(ctx)
+	if err != nil {
+		return err
+	}
+	// The files are deleted once the reloads that still read them are done.
+	return s.templates.DeleteSourceFiles(id)
+}
+
+// RefreshSources starts a reload of the templates of all the sources in
+// the background. The errors of each source are reported in its status.
+func (s *templateSourceService) RefreshSources(ctx context.Context) types.TemplateSources {
+	s.refresh()
+	return s.GetSources(ctx)
+}
+
+// LoadSources reads the sources stored, and starts a reload of their
+// templates in the background.
+func (s *templateSourceService) LoadSources(ctx context.Context) error {
+	sources, err := s.sources.GetSources(ctx)
+	if err != nil {
+		return err
+	}
+	s.templates.SetSources(sources)
+	s.refresh()
+	return nil
+}
+
+func (s *templateSourceService) refresh() {
+	s.refreshes.Add(1)
+	go func() {
+		defer s.refreshes.Add(-1)
+		s.reload()
+	}()
+}
+
+// reload reloads the templates. Reloads are serialized by the adapter. The
+// sources that could be read are loaded even on error.
+func (s *templateSourceService) reload() {
+	err := s.templates.Reload()
+	if err != nil {
+		log.Error(errors.Annotate(err, "refresh template sources"))
+	}
+	s.ctx.DispatchEvent(types.EventTemplatesReloaded{})
+}
+
+func (s *templateSourceService) startScheduler() error {
+	s.scheduler = gocron.NewScheduler(time.Local)
+	_, err := s.scheduler.Every(templateSourcesRefreshInterval).WaitForSchedule().Do(func() {
+		s.refreshes.Add(1)
+		defer s.refreshes.Add(-1)
+		s.reload()
+	})
+	if err != nil {
+		return err
+	}
+	s.scheduler.StartAsync()
+	return nil
+}
@@ -0,0 +1,501 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/vertex-center/uuid"
+	"github.com/vertex-center/vertex/server/apps/containers/core/port"
+	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+	"github.com/vertex-center/vertex/server/common/app"
+	"github.com/vertex-center/vertex/server/common/log"
+	"github.com/vertex-center/vertex/server/common/server"
+	"github.com/vertex-center/vertex/server/pkg/event"
+	"github.com/vertex-center/vertex/server/pkg/vcrypto"
+)
+
+// templateUpdateService compares containers with the latest revision of
+// their template, and applies the changes the user selects.
+type templateUpdateService struct {
+	uuid             uuid.UUID
+	ctx              *app.Context
+	containerService port.ContainerService
+	containers       port.ContainerAdapter
+	templates        port.TemplateAdapter
+	revisions        port.TemplateRevisionAdapter
+	vars             port.EnvAdapter
+	ports            port.PortAdapter
+	volumes          port.VolumeAdapter
+	caps             port.CapAdapter
+	hostPorts        port.HostPortsAdapter
+	keyring          *vcrypto.Keyring
+}
+
+func NewTemplateUpdateService(
+	ctx *app.Context,
+	containerService port.ContainerService,
+	containers port.ContainerAdapter,
+	templates port.TemplateAdapter,
+	revisions port.TemplateRevisionAdapter,
+	vars port.EnvAdapter,
+	ports port.PortAdapter,
+	volumes port.VolumeAdapter,
+	caps port.CapAdapter,
+	hostPorts port.HostPortsAdapter,
+	keyring *vcrypto.Keyring,
+) port.TemplateUpdateService {
+	s := &templateUpdateService{
+		uuid:             uuid.New(),
+		ctx:              ctx,
+		containerService: containerService,
+		containers:       containers,
+		templates:        templates,
+		revisions:        revisions,
+		vars:             vars,
+		ports:            ports,
+		volumes:          volumes,
+		caps:             caps,
+		hostPorts:        hostPorts,
+		keyring:          keyring,
+	}
+	s.ctx.AddListener(s)
+	return s
+}
+
+func (s *templateUpdateService) GetUUID() uuid.UUID {
+	return s.uuid
+}
+
+// OnEvent tracks the revisions once the templates are reloaded, as the
+// templates of the sources are only known from then on.
+func (s *templateUpdateService) OnEvent(e event.Event) error {
+	switch e.(type) {
+	case types.EventTemplatesReloaded:
+		err := s.TrackRevisions(server.NewBackgroundContext())
+		if err != nil {
+			log.Error(errors.Annotate(err, "track template revisions"))
+		}
+	}
+	return nil
+}
+
+// containerState is what a template update is applied to.
+type containerState struct {
+	container *types.Container
+	from      *types.Template
+	to        *types.Template
+	revision  string // Revision of to.
+	env       []types.EnvVariable
+	ports     types.Ports
+	volumes   types.Volumes
+	caps      types.Capabilities
+}
+
+// GetTemplateUpdate returns the changes of the template since the revision
+// the container is up to date with.
+func (s *templateUpdateService) GetTemplateUpdate(ctx context.Context, containerID uuid.UUID) (*types.TemplateUpdate, error) {
+	st, err := s.state(ctx, containerID)
+	if err != nil {
+		return nil, err
+	}
+	changes := st.changes()
+	return &types.TemplateUpdate{
+		Available:       len(changes) > 0,
+		TemplateID:      st.to.ID,
+		CurrentRevision: *st.container.TemplateRevision,
+		LatestRevision:  st.revision,
+		Changes:         changes,
+	}, nil
+}
+
+// ApplyTemplateUpdate applies the selected changes to the container, and
+// marks it up to date with the latest revision of its template. The changes
+// that are not selected are dismissed. Values edited by the user are kept.
+func (s *templateUpdateService) ApplyTemplateUpdate(ctx context.Context, containerID uuid.UUID, opts types.ApplyTemplateUpdateOptions) error {
+	st, err := s.state(ctx, containerID)
+	if err != nil {
+		return err
+	}
+
+	changes := st.changes()
+	if len(opts.Changes) > 0 {
+		for _, id := range opts.Changes {
+			if !slices.ContainsFunc(changes, func(c types.TemplateChange) bool { return c.ID == id }) {
+				return errors.NewNotValid(nil, fmt.Sprintf("unknown change %s", id))
+			}
+		}
+		changes = slices.DeleteFunc(changes, func(c types.TemplateChange) bool {
+			return !slices.Contains(opts.Changes, c.ID)
+		})
+	}
+	for name := range opts.Env {
+		if !slices.ContainsFunc(changes, func(c types.TemplateChange) bool {
+			return c.Type == types.TemplateChangeTypeEnv && c.Action == types.TemplateChangeActionAdd && c.Name == name
+		}) {
+			return errors.NewNotValid(nil, fmt.Sprintf("%s is not an added variable", name))
+		}
+	}
+
+	for _, change := range changes {
+		switch change.Type {
+		case types.TemplateChangeTypeEnv:
+			err = s.applyEnv(ctx, st, change, opts.Env)
+		case types.TemplateChangeTypePort:
+			err = s.applyPort(ctx, st, change)
+		case types.TemplateChangeTypeVolume:
+			err = s.applyVolume(ctx, st, change)
+		case types.TemplateChangeTypeCapability:
+			err = s.applyCap(ctx, st, change)
+		case types.TemplateChangeTypeImage:
+			if !change.Edited {
+				st.container.Image = *change.To
+			}
+		}
+		if err != nil {
+			return errors.Annotatef(err, "apply %s", change.ID)
+		}
+	}
+
+	err = s.revisions.CreateRevision(ctx, st.revision, *st.to)
+	if err != nil {
+		return err
+	}
+	st.container.TemplateRevision = &st.revision
+	err = s.containers.UpdateContainer(ctx, *st.container)
+	if err != nil {
+		return err
+	}
+
+	s.ctx.DispatchEvent(types.EventContainersChange{})
+
+	if len(changes) == 0 || !st.container.IsRunning() {
+		return nil
+	}
+	return s.containerService.RecreateContainer(ctx, containerID)
+}
+
+// TrackRevisions records the current revision of their template for the
+// containers created before revisions were kept. Their changes can only be
+// shown from now on.
+func (s *templateUpdateService) TrackRevisions(ctx context.Context) error {
+	all, err := s.containers.GetContainers(ctx)
+	if err != nil {
+		return err
+	}
+	for _, c := range all {
+		if c.TemplateID == nil || c.TemplateRevision != nil {
+			continue
+		}
+		template, err := s.templates.Get(*c.TemplateID)
+		if errors.Is(err, types.ErrTemplateNotFound) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		revision, err := template.Revision()
+		if err != nil {
+			return err
+		}
+		err = s.revisions.CreateRevision(ctx, revision, template)
+		if err != nil {
+			return err
+		}
+		c.TemplateRevision = &revision
+		err = s.containers.UpdateContainer(ctx, c)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *templateUpdateService) state(ctx context.Context, containerID uuid.UUID) (*containerState, error) {
+	c, err := s.containers.GetContainer(ctx, containerID)
+	if err != nil {
+		return nil, err
+	}
+	if c.ID != containerID {
+		return nil, types.ErrContainerNotFound
+	}
+	if c.TemplateID == nil {
+		return nil, errors.NewNotValid(nil, "the container was not created from a template")
+	}
+	if c.TemplateRevision == nil {
+		return nil, types.ErrTemplateRevisionNotFound
+	}
+
+	from, err := s.revisions.GetRevision(ctx, *c.TemplateID, *c.TemplateRevision)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.templates.Get(*c.TemplateID)
+	if err != nil {
+		return nil, err
+	}
+	revision, err := to.Revision()
+	if err != nil {
+		return nil, err
+	}
+
+	st := &containerState{
+		container: c,
+		from:      from,
+		to:        &to,
+		revision:  revision,
+	}
+	st.env, err = s.vars.GetEnvs(ctx, types.EnvVariableFilters{ContainerID: &c.ID})
+	if err != nil {
+		return nil, err
+	}
+	for i := range st.env {
+		err = decryptEnv(s.keyring, &st.env[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+	st.ports, err = s.ports.GetPorts(ctx, types.PortFilters{ContainerID: &c.ID})
+	if err != nil {
+		return nil, err
+	}
+	st.volumes, err = s.volumes.GetContainerVolumes(ctx, c.ID)
+	if err != nil {
+		return nil, err
+	}
+	st.caps, err = s.caps.GetContainerCaps(ctx, c.ID)
+	if err != nil {
+		return nil, err
+	}
+	return st, nil
+}
+
+// changes returns the changes of the template, marked as edited when the
+// container differs from the revision it was created from.
+func (st *containerState) changes() []types.TemplateChange {
+	changes := types.DiffTemplates(st.from, st.to)
+	for i, change := range changes {
+		switch change.Type {
+		case types.TemplateChangeTypeEnv:
+			if e := st.envVariable(change.Name); e != nil {
+				changes[i].Edited = change.Action == types.TemplateChangeActionAdd || change.From == nil || e.Value != *change.From
+			}
+		case types.TemplateChangeTypePort:
+			if change.Action == types.TemplateChangeActionUpdate {
+				if p := st.port(templatePortByName(st.from.Ports, change.Name)); p != nil {
+					changes[i].Edited = portEdited(*p)
+				}
+			}
+		case types.TemplateChangeTypeImage:
+			changes[i].Edited = st.container.Image != *change.From
+		}
+	}
+	return changes
+}
+
+func (st *containerState) envVariable(name string) *types.EnvVariable {
+	for i := range st.env {
+		if st.env[i].Name == name {
+			return &st.env[i]
+		}
+	}
+	return nil
+}
+
+// port returns the port of the container created from the template port.
+func (st *containerState) port(tp *types.TemplatePort) *types.Port {
+	if tp == nil {
+		return nil
+	}
+	for i, p := range st.ports {
+		if p.In == tp.Port && string(p.Protocol) == templatePortProtocol(*tp) {
+			return &st.ports[i]
+		}
+	}
+	return nil
+}
+
+func (s *templateUpdateService) applyEnv(ctx context.Context, st *containerState, change types.TemplateChange, values map[string]string) error {
+	prev := st.envVariable(change.Name)
+	if change.Action == types.TemplateChangeActionRemove {
+		// Variables edited by the user are kept.
+		if prev == nil || change.Edited {
+			return nil
+		}
+		return s.vars.DeleteEnv(ctx, prev.ID)
+	}
+
+	i := slices.IndexFunc(st.to.Env, func(e types.TemplateEnv) bool { return e.Name == change.Name })
+	e := st.to.Env[i]
+
+	var (
+		value string
+		err   error
+	)
+	switch {
+	case change.Edited:
+		value = prev.Value
+	case values[e.Name] != "":
+		value = values[e.Name]
+		err = e.ValidateValue(value)
+	default:
+		value, err = e.DefaultValue()
+	}
+	if err != nil {
+		return err
+	}
+
+	// The variable is declared again, to update its type and description.
+	if prev != nil {
+		err = s.vars.DeleteEnv(ctx, prev.ID)
+		if err != nil {
+			return err
+		}
+	}
+	return s.vars.CreateEnv(ctx, types.EnvVariable{
+		ID:          uuid.New(),
+		ContainerID: st.container.ID,
+		Type:        types.EnvVariableType(e.Type),
+		Name:        e.Name,
+		DisplayName: e.DisplayName,
+		Value:       value,
+		Default:     &e.Default,
+		Description: &e.Description,
+		Secret:      e.IsSecret(),
+	})
+}
+
+func (s *templateUpdateService) applyPort(ctx context.Context, st *containerState, change types.TemplateChange) error {
+	prev := st.port(templatePortByName(st.from.Ports, change.Name))
+	if change.Action == types.TemplateChangeActionRemove {
+		if prev == nil {
+			return nil
+		}
+		return s.ports.DeletePort(ctx, prev.ID)
+	}
+
+	tp := templatePortByName(st.to.Ports, change.Name)
+	if prev == nil {
+		// The port may have been applied already, by an update that failed
+		// afterward.
+		prev = st.port(tp)
+	}
+
+	p := types.Port{
+		ID:          uuid.New(),
+		ContainerID: st.container.ID,
+		In:          tp.Port,
+		Out:         tp.Port,
+		Protocol:    types.PortProtocol(templatePortProtocol(*tp)),
+	}
+	edited := prev != nil && portEdited(*prev)
+	if prev != nil {
+		p.ID = prev.ID
+	}
+	if edited {
+		// The host port chosen by the user is kept.
+		p.Out = prev.Out
+		p.TemplateOut = prev.TemplateOut
+	}
+	err := p.Validate()
+	if err != nil {
+		return err
+	}
+
+	used, err := s.ports.GetPorts(ctx, types.PortFilters{})
+	if err != nil {
+		return err
+	}
+	if edited {
+		err = checkPort(s.hostPorts, used, p)
+	} else {
+		p.Out, err = allocatePort(s.hostPorts, used, p)
+		out := p.Out
+		p.TemplateOut = &out
+	}
+	if err != nil {
+		return err
+	}
+
+	// The port is created again, to update the port given by the template.
+	if prev != nil {
+		err = s.ports.DeletePort(ctx, prev.ID)
+		if err != nil {
+			return err
+		}
+	}
+	return s.ports.CreatePort(ctx, p)
+}
+
+// applyVolume adds or removes a volume. The data of removed volumes is not
+// deleted.
+func (s *templateUpdateService) applyVolume(ctx context.Context, st *containerState, change types.TemplateChange) error {
+	i := slices.IndexFunc(st.volumes, func(v types.Volume) bool { return v.In == change.Name })
+	if change.Action == types.TemplateChangeActionRemove {
+		if i == -1 {
+			return nil
+		}
+		return s.volumes.DeleteVolume(ctx, st.volumes[i].ID)
+	}
+	if i != -1 {
+		return nil
+	}
+
+	out, tp := *change.To, types.VolumeTypeBind
+	if !strings.Contains(out, "/") {
+		tp = types.VolumeTypeVolume
+		out = "VERTEX_VOLUME_" + st.container.ID.String() + "_" + out
+	}
+	return s.volumes.CreateVolume(ctx, types.Volume{
+		ID:          uuid.New(),
+		ContainerID: st.container.ID,
+		Type:        tp,
+		In:          change.Name,
+		Out:         out,
+	})
+}
+
+func (s *templateUpdateService) applyCap(ctx context.Context, st *containerState, change types.TemplateChange) error {
+	i := slices.IndexFunc(st.caps, func(c types.Capability) bool { return c.Name == change.Name })
+	if change.Action == types.TemplateChangeActionRemove {
+		if i == -1 {
+			return nil
+		}
+		return s.caps.DeleteCap(ctx, st.caps[i].ID)
+	}
+	if i != -1 {
+		return nil
+	}
+	return s.caps.CreateCap(ctx, types.Capability{
+		ID:          uuid.New(),
+		ContainerID: st.container.ID,
+		Name:        change.Name,
+	})
+}
+
+// portEdited returns whether the user changed the host port given when the
+// port was created from the template. The ports created before these were
+// recorded were exposed on the same port.
+func portEdited(p types.Port) bool {
+	if p.TemplateOut == nil {
+		return p.Out != p.In
+	}
+	return p.Out != *p.TemplateOut
+}
+
+func templatePortByName(ports []types.TemplatePort, name string) *types.TemplatePort {
+	for i := range ports {
+		if ports[i].Name == name {
+			return &ports[i]
+		}
+	}
+	return nil
+}
+
+func templatePortProtocol(p types.TemplatePort) string {
+	if p.Protocol == "" {
+		return string(types.PortProtocolTCP)
+	}
+	return p.Protocol
+}
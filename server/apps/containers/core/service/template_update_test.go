@@ -0,0 +1,596 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/juju/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/vertex-center/uuid"
+	"github.com/vertex-center/vertex/server/apps/containers/core/port"
+	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+	"github.com/vertex-center/vertex/server/common"
+	"github.com/vertex-center/vertex/server/common/app"
+)
+
+type TemplateUpdateTestSuite struct {
+	suite.Suite
+}
+
+func TestTemplateUpdateTestSuite(t *testing.T) {
+	suite.Run(t, new(TemplateUpdateTestSuite))
+}
+
+func (suite *TemplateUpdateTestSuite) TestChangesEdited() {
+	image, nextImage := "postgres:15", "postgres:16"
+	st := containerState{
+		container: &types.Container{ID: uuid.New(), Image: "postgres:15"},
+		from: &types.Template{
+			Env: []types.TemplateEnv{
+				{Type: "string", Name: "LOG_LEVEL", Default: "info"},
+				{Type: "string", Name: "TZ", Default: "UTC"},
+			},
+			Ports:   []types.TemplatePort{{Name: "PORT", Port: "5432"}, {Name: "METRICS", Port: "9187"}},
+			Methods: types.TemplateMethods{Docker: &types.TemplateMethodDocker{Image: &image}},
+		},
+		to: &types.Template{
+			Env: []types.TemplateEnv{
+				{Type: "string", Name: "LOG_LEVEL", Default: "warn"},
+				{Type: "string", Name: "TZ", Default: "Europe/Paris"},
+				{Type: "string", Name: "PGDATA"},
+			},
+			Ports:   []types.TemplatePort{{Name: "PORT", Port: "5433"}, {Name: "METRICS", Port: "9188"}},
+			Methods: types.TemplateMethods{Docker: &types.TemplateMethodDocker{Image: &nextImage}},
+		},
+		env: []types.EnvVariable{
+			{Name: "LOG_LEVEL", Value: "debug"},
+			{Name: "TZ", Value: "UTC"},
+			{Name: "PGDATA", Value: "/data"},
+		},
+		ports: types.Ports{
+			{In: "5432", Out: "15432", Protocol: types.PortProtocolTCP},
+			{In: "9187", Out: "9187", Protocol: types.PortProtocolTCP},
+		},
+	}
+
+	edited := map[string]bool{}
+	for _, c := range st.changes() {
+		edited[c.ID] = c.Edited
+	}
+	suite.Equal(map[string]bool{
+		"image.image":   false,
+		"env.LOG_LEVEL": true,
+		"env.TZ":        false,
+		"env.PGDATA":    true, // Already added by the user.
+		"port.PORT":     true,
+		"port.METRICS":  false,
+	}, edited)
+}
+
+type TemplateUpdateServiceTestSuite struct {
+	suite.Suite
+
+	ctx        *app.Context
+	service    *templateUpdateService
+	containers *fakeContainerAdapter
+	templates  *fakeTemplateAdapter
+	revisions  *fakeTemplateRevisionAdapter
+	vars       *fakeEnvAdapter
+	ports      *fakePortAdapter
+	volumes    *fakeVolumeAdapter
+	caps       *fakeCapAdapter
+	host       MockHostPortsAdapter
+
+	container types.Container
+	from      types.Template
+	to        types.Template
+}
+
+func TestTemplateUpdateServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(TemplateUpdateServiceTestSuite))
+}
+
+func (suite *TemplateUpdateServiceTestSuite) SetupTest() {
+	suite.containers = &fakeContainerAdapter{containers: map[uuid.UUID]types.Container{}}
+	suite.templates = &fakeTemplateAdapter{templates: map[string]types.Template{}}
+	suite.revisions = &fakeTemplateRevisionAdapter{revisions: map[string]types.Template{}}
+	suite.vars = &fakeEnvAdapter{}
+	suite.ports = &fakePortAdapter{}
+	suite.volumes = &fakeVolumeAdapter{}
+	suite.caps = &fakeCapAdapter{}
+	suite.host = MockHostPortsAdapter{}
+	suite.host.On("IsPortFree", mock.Anything, mock.Anything).Return(true)
+
+	suite.ctx = app.NewContext(common.NewVertexContext(common.About{}, false))
+	suite.service = NewTemplateUpdateService(suite.ctx, nil, suite.containers, suite.templates, suite.revisions,
+		suite.vars, suite.ports, suite.volumes, suite.caps, &suite.host, nil).(*templateUpdateService)
+
+	image, nextImage := "postgres:15", "postgres:16"
+	caps, nextCaps := []string{"NET_ADMIN"}, []string{"SYS_TIME"}
+	volumes := map[string]string{"data": "/var/lib/postgresql/data", "logs": "/var/log"}
+	nextVolumes := map[string]string{"data": "/var/lib/postgresql/data", "backups": "/backups"}
+	suite.from = types.Template{
+		ID:   "postgres",
+		Name: "Postgres",
+		Env: []types.TemplateEnv{
+			{Type: "string", Name: "LOG_LEVEL", Default: "info"},
+			{Type: "string", Name: "TZ", Default: "UTC"},
+			{Type: "string", Name: "LEGACY"},
+			{Type: "string", Name: "OLD"},
+		},
+		Ports:   []types.TemplatePort{{Name: "PORT", Port: "5432"}, {Name: "METRICS", Port: "9187"}},
+		Methods: types.TemplateMethods{Docker: &types.TemplateMethodDocker{Image: &image, Capabilities: &caps, Volumes: &volumes}},
+	}
+	suite.to = types.Template{
+		ID:   "postgres",
+		Name: "Postgres",
+		Env: []types.TemplateEnv{
+			{Type: "string", Name: "LOG_LEVEL", Default: "warn"},
+			{Type: "string", Name: "TZ", Default: "Europe/Paris"},
+			{Type: "path", Name: "PGDATA", Default: "/data"},
+		},
+		Ports:   []types.TemplatePort{{Name: "PORT", Port: "5433"}, {Name: "DNS", Port: "53", Protocol: "udp"}},
+		Methods: types.TemplateMethods{Docker: &types.TemplateMethodDocker{Image: &nextImage, Capabilities: &nextCaps, Volumes: &nextVolumes}},
+	}
+
+	revision, err := suite.from.Revision()
+	suite.Require().NoError(err)
+	suite.Require().NoError(suite.revisions.CreateRevision(context.Background(), revision, suite.from))
+	suite.templates.templates["postgres"] = suite.to
+
+	id := uuid.New()
+	templateID := "postgres"
+	suite.container = types.Container{ID: id, TemplateID: &templateID, TemplateRevision: &revision, Image: image, Status: types.ContainerStatusOff}
+	suite.containers.containers[id] = suite.container
+
+	suite.vars.env = []types.EnvVariable{
+		{ID: uuid.New(), ContainerID: id, Name: "LOG_LEVEL", Value: "debug"},
+		{ID: uuid.New(), ContainerID: id, Name: "TZ", Value: "UTC"},
+		{ID: uuid.New(), ContainerID: id, Name: "LEGACY", Value: "custom"},
+		{ID: uuid.New(), ContainerID: id, Name: "OLD", Value: ""},
+	}
+	// The port of the template was used, and moved to 15432 at creation.
+	allocated := "15432"
+	suite.ports.ports = types.Ports{
+		{ID: uuid.New(), ContainerID: id, In: "5432", Out: "15432", Protocol: types.PortProtocolTCP, TemplateOut: &allocated},
+		{ID: uuid.New(), ContainerID: id, In: "9187", Out: "9187", Protocol: types.PortProtocolTCP},
+	}
+	suite.volumes.volumes = types.Volumes{
+		{ID: uuid.New(), ContainerID: id, Type: types.VolumeTypeVolume, In: "/var/lib/postgresql/data", Out: "VERTEX_VOLUME_" + id.String() + "_data"},
+		{ID: uuid.New(), ContainerID: id, Type: types.VolumeTypeVolume, In: "/var/log", Out: "VERTEX_VOLUME_" + id.String() + "_logs"},
+	}
+	suite.caps.caps = types.Capabilities{{ID: uuid.New(), ContainerID: id, Name: "NET_ADMIN"}}
+}
+
+func (suite *TemplateUpdateServiceTestSuite) TestApplyAll() {
+	ctx := context.Background()
+	err := suite.service.ApplyTemplateUpdate(ctx, suite.container.ID, types.ApplyTemplateUpdateOptions{
+		Env: map[string]string{"PGDATA": "/var/lib/pg"},
+	})
+	suite.Require().NoError(err)
+
+	suite.Equal(map[string]string{
+		"LOG_LEVEL": "debug", // Edited by the user.
+		"TZ":        "Europe/Paris",
+		"LEGACY":    "custom", // Removed from the template, but edited by the user.
+		"PGDATA":    "/var/lib/pg",
+	}, suite.vars.values())
+
+	suite.Len(suite.ports.ports, 2)
+	port := suite.ports.find("5433", types.PortProtocolTCP)
+	suite.Require().NotNil(port)
+	suite.Equal("5433", port.Out, "the port moved at creation is not an edit")
+	suite.Equal("5433", *port.TemplateOut)
+	suite.NotNil(suite.ports.find("53", types.PortProtocolUDP))
+
+	suite.Equal([]string{"/var/lib/postgresql/data", "/backups"}, suite.volumes.paths())
+	suite.Equal("VERTEX_VOLUME_"+suite.container.ID.String()+"_backups", suite.volumes.volumes[1].Out)
+	suite.Equal([]string{"SYS_TIME"}, suite.caps.names())
+
+	c := suite.containers.containers[suite.container.ID]
+	suite.Equal("postgres:16", c.Image)
+	revision, err := suite.to.Revision()
+	suite.Require().NoError(err)
+	suite.Equal(revision, *c.TemplateRevision)
+
+	update, err := suite.service.GetTemplateUpdate(ctx, suite.container.ID)
+	suite.Require().NoError(err)
+	suite.False(update.Available)
+	suite.Empty(update.Changes)
+}
+
+func (suite *TemplateUpdateServiceTestSuite) TestApplySelected() {
+	ctx := context.Background()
+	err := suite.service.ApplyTemplateUpdate(ctx, suite.container.ID, types.ApplyTemplateUpdateOptions{
+		Changes: []string{"env.TZ", "port.PORT"},
+	})
+	suite.Require().NoError(err)
+
+	suite.Equal("Europe/Paris", suite.vars.values()["TZ"])
+	suite.Contains(suite.vars.values(), "OLD", "the other changes are dismissed")
+	suite.Nil(suite.ports.find("53", types.PortProtocolUDP))
+	suite.NotNil(suite.ports.find("9187", types.PortProtocolTCP))
+	suite.Equal([]string{"NET_ADMIN"}, suite.caps.names())
+
+	c := suite.containers.containers[suite.container.ID]
+	suite.Equal("postgres:15", c.Image)
+
+	update, err := suite.service.GetTemplateUpdate(ctx, suite.container.ID)
+	suite.Require().NoError(err)
+	suite.False(update.Available)
+}
+
+func (suite *TemplateUpdateServiceTestSuite) TestApplyInvalidOptions() {
+	ctx := context.Background()
+	err := suite.service.ApplyTemplateUpdate(ctx, suite.container.ID, types.ApplyTemplateUpdateOptions{
+		Changes: []string{"env.UNKNOWN"},
+	})
+	suite.True(errors.Is(err, errors.NotValid))
+
+	err = suite.service.ApplyTemplateUpdate(ctx, suite.container.ID, types.ApplyTemplateUpdateOptions{
+		Changes: []string{"env.TZ"},
+		Env:     map[string]string{"PGDATA": "/var/lib/pg"},
+	})
+	suite.True(errors.Is(err, errors.NotValid), "PGDATA is added, but not selected")
+
+	err = suite.service.ApplyTemplateUpdate(ctx, suite.container.ID, types.ApplyTemplateUpdateOptions{
+		Env: map[string]string{"PGDATA": "relative"},
+	})
+	suite.True(errors.Is(err, errors.NotValid), "the value must match the type of the variable")
+
+	c := suite.containers.containers[suite.container.ID]
+	suite.Equal(*suite.container.TemplateRevision, *c.TemplateRevision)
+}
+
+func (suite *TemplateUpdateServiceTestSuite) TestApplyEditedPort() {
+	suite.ports.ports[0].Out = "25432"
+
+	err := suite.service.ApplyTemplateUpdate(context.Background(), suite.container.ID, types.ApplyTemplateUpdateOptions{
+		Changes: []string{"port.PORT"},
+	})
+	suite.Require().NoError(err)
+
+	port := suite.ports.find("5433", types.PortProtocolTCP)
+	suite.Require().NotNil(port)
+	suite.Equal("25432", port.Out)
+	suite.Equal("15432", *port.TemplateOut)
+}
+
+func (suite *TemplateUpdateServiceTestSuite) TestApplyAgain() {
+	// A previous update failed after its ports were applied.
+	out, dns := "5433", "53"
+	suite.ports.ports[0].In, suite.ports.ports[0].Out, suite.ports.ports[0].TemplateOut = "5433", "5433", &out
+	suite.ports.ports = append(suite.ports.ports, types.Port{
+		ID: uuid.New(), ContainerID: suite.container.ID, In: "53", Out: "53", Protocol: types.PortProtocolUDP, TemplateOut: &dns,
+	})
+
+	err := suite.service.ApplyTemplateUpdate(context.Background(), suite.container.ID, types.ApplyTemplateUpdateOptions{})
+	suite.Require().NoError(err)
+
+	suite.Len(suite.ports.ports, 2)
+	suite.NotNil(suite.ports.find("5433", types.PortProtocolTCP))
+	suite.NotNil(suite.ports.find("53", types.PortProtocolUDP))
+}
+
+func (suite *TemplateUpdateServiceTestSuite) TestTrackRevisions() {
+	templateID, missing := "postgres", "missing"
+	untracked := types.Container{ID: uuid.New(), TemplateID: &templateID}
+	deleted := types.Container{ID: uuid.New(), TemplateID: &missing}
+	image := types.Container{ID: uuid.New()}
+	for _, c := range []types.Container{untracked, deleted, image} {
+		suite.containers.containers[c.ID] = c
+	}
+
+	err := suite.service.TrackRevisions(context.Background())
+	suite.Require().NoError(err)
+
+	revision, err := suite.to.Revision()
+	suite.Require().NoError(err)
+	suite.Equal(revision, *suite.containers.containers[untracked.ID].TemplateRevision)
+	suite.Nil(suite.containers.containers[deleted.ID].TemplateRevision)
+	suite.Nil(suite.containers.containers[image.ID].TemplateRevision)
+	suite.Equal(*suite.container.TemplateRevision, *suite.containers.containers[suite.container.ID].TemplateRevision)
+
+	_, err = suite.revisions.GetRevision(context.Background(), "postgres", revision)
+	suite.NoError(err)
+}
+
+func (suite *TemplateUpdateServiceTestSuite) TestTrackRevisionsAfterReload() {
+	templateID := "redis"
+	c := types.Container{ID: uuid.New(), TemplateID: &templateID}
+	suite.containers.containers[c.ID] = c
+
+	redis := suite.to
+	redis.ID = templateID
+	suite.templates.reloaded = map[string]types.Template{templateID: redis}
+
+	// The template of the container is only known once the sources are reloaded.
+	sources := &templateSourceService{ctx: suite.ctx, templates: suite.templates}
+	sources.reload()
+
+	revision, err := redis.Revision()
+	suite.Require().NoError(err)
+	suite.Equal(revision, *suite.containers.containers[c.ID].TemplateRevision)
+}
+
+type fakeContainerAdapter struct {
+	port.ContainerAdapter
+	containers map[uuid.UUID]types.Container
+}
+
+func (a *fakeContainerAdapter) GetContainer(ctx context.Context, id uuid.UUID) (*types.Container, error) {
+	c := a.containers[id]
+	return &c, nil
+}
+
+func (a *fakeContainerAdapter) GetContainers(ctx context.Context) (types.Containers, error) {
+	var all types.Containers
+	for _, c := range a.containers {
+		all = append(all, c)
+	}
+	return all, nil
+}
+
+func (a *fakeContainerAdapter) GetContainersWithFilters(ctx context.Context, filters types.ContainerFilters) (types.Containers, error) {
+	var all types.Containers
+	for _, c := range a.containers {
+		if filters.StackID == nil || (c.StackID != nil && *c.StackID == *filters.StackID) {
+			all = append(all, c)
+		}
+	}
+	return all, nil
+}
+
+func (a *fakeContainerAdapter) CreateContainer(ctx context.Context, c types.Container) error {
+	a.containers[c.ID] = c
+	return nil
+}
+
+func (a *fakeContainerAdapter) DeleteContainer(ctx context.Context, id uuid.UUID) error {
+	delete(a.containers, id)
+	return nil
+}
+
+func (a *fakeContainerAdapter) GetContainerTags(ctx context.Context, id uuid.UUID) (types.Tags, error) {
+	return nil, nil
+}
+
+func (a *fakeContainerAdapter) DeleteTags(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
+
+func (a *fakeContainerAdapter) UpdateContainer(ctx context.Context, c types.Container) error {
+	a.containers[c.ID] = c
+	return nil
+}
+
+func (a *fakeContainerAdapter) SetStatus(ctx context.Context, id uuid.UUID, status string) error {
+	c := a.containers[id]
+	c.Status = status
+	a.containers[id] = c
+	return nil
+}
+
+func (a *fakeContainerAdapter) SetStoppedByUser(ctx context.Context, id uuid.UUID, stopped bool) error {
+	c := a.containers[id]
+	c.StoppedByUser = stopped
+	a.containers[id] = c
+	return nil
+}
+
+type fakeTemplateAdapter struct {
+	port.TemplateAdapter
+	templates map[string]types.Template
+	reloaded  map[string]types.Template // Templates read by the next reload.
+}
+
+func (a *fakeTemplateAdapter) Reload() error {
+	for id, t := range a.reloaded {
+		a.templates[id] = t
+	}
+	return nil
+}
+
+func (a *fakeTemplateAdapter) Get(id string) (types.Template, error) {
+	t, ok := a.templates[id]
+	if !ok {
+		return types.Template{}, types.ErrTemplateNotFound
+	}
+	return t, nil
+}
+
+type fakeTemplateRevisionAdapter struct {
+	revisions map[string]types.Template
+}
+
+func (a *fakeTemplateRevisionAdapter) GetRevision(ctx context.Context, templateID string, revision string) (*types.Template, error) {
+	t, ok := a.revisions[templateID+"@"+revision]
+	if !ok {
+		return nil, types.ErrTemplateRevisionNotFound
+	}
+	return &t, nil
+}
+
+func (a *fakeTemplateRevisionAdapter) CreateRevision(ctx context.Context, revision string, template types.Template) error {
+	a.revisions[template.ID+"@"+revision] = template
+	return nil
+}
+
+type fakeEnvAdapter struct {
+	port.EnvAdapter
+	env []types.EnvVariable
+}
+
+func (a *fakeEnvAdapter) GetEnv(ctx context.Context, id uuid.UUID) (*types.EnvVariable, error) {
+	for _, v := range a.env {
+		if v.ID == id {
+			return &v, nil
+		}
+	}
+	return nil, types.ErrEnvVariableNotFound
+}
+
+func (a *fakeEnvAdapter) GetEnvs(ctx context.Context, filters types.EnvVariableFilters) ([]types.EnvVariable, error) {
+	var env []types.EnvVariable
+	for _, v := range a.env {
+		if filters.ContainerID == nil || v.ContainerID == *filters.ContainerID {
+			env = append(env, v)
+		}
+	}
+	return env, nil
+}
+
+func (a *fakeEnvAdapter) UpdateEnvByID(ctx context.Context, v types.EnvVariable) error {
+	for i := range a.env {
+		if a.env[i].ID == v.ID {
+			a.env[i].Name, a.env[i].Value = v.Name, v.Value
+		}
+	}
+	return nil
+}
+
+func (a *fakeEnvAdapter) UpdateEnvByName(ctx context.Context, v types.EnvVariable) error {
+	for i := range a.env {
+		if a.env[i].ContainerID == v.ContainerID && a.env[i].Name == v.Name {
+			a.env[i].Value = v.Value
+		}
+	}
+	return nil
+}
+
+func (a *fakeEnvAdapter) CreateEnv(ctx context.Context, v types.EnvVariable) error {
+	a.env = append(a.env, v)
+	return nil
+}
+
+func (a *fakeEnvAdapter) DeleteEnv(ctx context.Context, id uuid.UUID) error {
+	a.env = slices.DeleteFunc(a.env, func(v types.EnvVariable) bool { return v.ID == id })
+	return nil
+}
+
+func (a *fakeEnvAdapter) DeleteEnvs(ctx context.Context, id uuid.UUID) error {
+	a.env = slices.DeleteFunc(a.env, func(v types.EnvVariable) bool { return v.ContainerID == id })
+	return nil
+}
+
+func (a *fakeEnvAdapter) values() map[string]string {
+	values := map[string]string{}
+	for _, v := range a.env {
+		values[v.Name] = v.Value
+	}
+	return values
+}
+
+type fakePortAdapter struct {
+	port.PortAdapter
+	ports types.Ports
+}
+
+func (a *fakePortAdapter) GetPorts(ctx context.Context, filters types.PortFilters) (types.Ports, error) {
+	var ports types.Ports
+	for _, p := range a.ports {
+		if filters.ContainerID == nil || p.ContainerID == *filters.ContainerID {
+			ports = append(ports, p)
+		}
+	}
+	return ports, nil
+}
+
+func (a *fakePortAdapter) CreatePort(ctx context.Context, p types.Port) error {
+	a.ports = append(a.ports, p)
+	return nil
+}
+
+func (a *fakePortAdapter) DeletePort(ctx context.Context, id uuid.UUID) error {
+	a.ports = slices.DeleteFunc(a.ports, func(p types.Port) bool { return p.ID == id })
+	return nil
+}
+
+func (a *fakePortAdapter) DeletePorts(ctx context.Context, id uuid.UUID) error {
+	a.ports = slices.DeleteFunc(a.ports, func(p types.Port) bool { return p.ContainerID == id })
+	return nil
+}
+
+func (a *fakePortAdapter) UpdatePortByID(ctx context.Context, p types.Port) error {
+	for i := range a.ports {
+		if a.ports[i].ID == p.ID {
+			a.ports[i].In, a.ports[i].Out, a.ports[i].Protocol = p.In, p.Out, p.Protocol
+		}
+	}
+	return nil
+}
+
+func (a *fakePortAdapter) find(in string, protocol types.PortProtocol) *types.Port {
+	for i, p := range a.ports {
+		if p.In == in && p.Protocol == protocol {
+			return &a.ports[i]
+		}
+	}
+	return nil
+}
+
+type fakeVolumeAdapter struct {
+	port.VolumeAdapter
+	volumes types.Volumes
+}
+
+func (a *fakeVolumeAdapter) GetContainerVolumes(ctx context.Context, id uuid.UUID) (types.Volumes, error) {
+	return slices.Clone(a.volumes), nil
+}
+
+func (a *fakeVolumeAdapter) CreateVolume(ctx context.Context, v types.Volume) error {
+	a.volumes = append(a.volumes, v)
+	return nil
+}
+
+func (a *fakeVolumeAdapter) DeleteVolume(ctx context.Context, id uuid.UUID) error {
+	a.volumes = slices.DeleteFunc(a.volumes, func(v types.Volume) bool { return v.ID == id })
+	return nil
+}
+
+func (a *fakeVolumeAdapter) DeleteContainerVolumes(ctx context.Context, id uuid.UUID) error {
+	a.volumes = slices.DeleteFunc(a.volumes, func(v types.Volume) bool { return v.ContainerID == id })
+	return nil
+}
+
+func (a *fakeVolumeAdapter) paths() []string {
+	var paths []string
+	for _, v := range a.volumes {
+		paths = append(paths, v.In)
+	}
+	return paths
+}
+
+type fakeCapAdapter struct {
+	port.CapAdapter
+	caps types.Capabilities
+}
+
+func (a *fakeCapAdapter) GetContainerCaps(ctx context.Context, id uuid.UUID) (types.Capabilities, error) {
+	return slices.Clone(a.caps), nil
+}
+
+func (a *fakeCapAdapter) CreateCap(ctx context.Context, c types.Capability) error {
+	a.caps = append(a.caps, c)
+	return nil
+}
+
+func (a *fakeCapAdapter) DeleteCap(ctx context.Context, id uuid.UUID) error {
+	a.caps = slices.DeleteFunc(a.caps, func(c types.Capability) bool { return c.ID == id })
+	return nil
+}
+
+func (a *fakeCapAdapter) DeleteContainerCaps(ctx context.Context, id uuid.UUID) error {
+	a.caps = slices.DeleteFunc(a.caps, func(c types.Capability) bool { return c.ContainerID == id })
+	return nil
+}
+
+func (a *fakeCapAdapter) names() []string {
+	var names []string
+	for _, c := range a.caps {
+		names = append(names, c.Name)
+	}
+	return names
+}
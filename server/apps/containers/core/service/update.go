@@ -0,0 +1,366 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-co-op/gocron"
+	"github.com/juju/errors"
+	"github.com/vertex-center/uuid"
+	"github.com/vertex-center/vertex/server/apps/containers/core/port"
+	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+	"github.com/vertex-center/vertex/server/common/app"
+	ev "github.com/vertex-center/vertex/server/common/event"
+	"github.com/vertex-center/vertex/server/common/log"
+	"github.com/vertex-center/vertex/server/common/server"
+	"github.com/vertex-center/vertex/server/pkg/event"
+	"github.com/vertex-center/vlog"
+)
+
+const (
+	// updateCheckInterval is the time between two checks for image updates.
+	updateCheckInterval = time.Hour
+
+	// updateWatchDuration is how long a container is watched after an
+	// update. If it fails during this time, it is rolled back.
+	updateWatchDuration = 3 * time.Minute
+)
+
+type updateService struct {
+	uuid             uuid.UUID
+	ctx              *app.Context
+	containerService port.ContainerService
+	containers       port.ContainerAdapter
+	runner           port.RunnerAdapter
+
+	scheduler *gocron.Scheduler
+	notified  map[uuid.UUID]string // The latest image each container was notified about.
+
+	// watches cancels the watch of the containers updated recently.
+	watches   map[uuid.UUID]context.CancelFunc
+	watchesMu sync.Mutex
+}
+
+func NewUpdateService(ctx *app.Context, containerService port.ContainerService, containers port.ContainerAdapter, runner port.RunnerAdapter) port.UpdateService {
+	s := &updateService{
+		uuid:             uuid.New(),
+		ctx:              ctx,
+		containerService: containerService,
+		containers:       containers,
+		runner:           runner,
+		notified:         map[uuid.UUID]string{},
+		watches:          map[uuid.UUID]context.CancelFunc{},
+	}
+	s.ctx.AddListener(s)
+	return s
+}
+
+func (s *updateService) GetUUID() uuid.UUID {
+	return s.uuid
+}
+
+func (s *updateService) OnEvent(e event.Event) error {
+	switch e.(type) {
+	case ev.ServerSetupCompleted:
+		return s.startScheduler()
+	case ev.ServerStop:
+		s.stopScheduler()
+	}
+	return nil
+}
+
+// SetUpdatePolicy changes how the container is updated. Pinning a container
+// keeps the image it currently runs, even when it is recreated.
+func (s *updateService) SetUpdatePolicy(ctx context.Context, id uuid.UUID, policy types.UpdatePolicy, window *string) error {
+	c, err := s.containers.GetContainer(ctx, id)
+	if err != nil {
+		return err
+	}
+	if c.ID != id {
+		return types.ErrContainerNotFound
+	}
+
+	switch {
+	case policy == types.UpdatePolicyPin && c.PinnedImage == nil:
+		image, err := s.runner.GetImageID(ctx, *c)
+		if err != nil && !errors.Is(err, errors.NotFound) {
+			return err
+		} else if err == nil {
+			c.PinnedImage = &image
+		}
+	case policy != types.UpdatePolicyPin && c.UpdatePolicy == types.UpdatePolicyPin:
+		c.PinnedImage = nil
+	}
+
+	c.UpdatePolicy = policy
+	c.UpdateWindow = window
+	return s.containerService.UpdateContainer(ctx, id, *c)
+}
+
+// ApplyUpdate recreates the container on the latest image of its tag. The
+// previous image is kept, and the container is rolled back to it if it
+// fails shortly after the update.
+func (s *updateService) ApplyUpdate(ctx context.Context, id uuid.UUID) error {
+	c, err := s.containers.GetContainer(ctx, id)
+	if err != nil {
+		return err
+	}
+	if c.ID != id {
+		return types.ErrContainerNotFound
+	}
+
+	previous, err := s.runner.GetImageID(ctx, *c)
+	if errors.Is(err, errors.NotFound) {
+		// The Docker container doesn't exist yet, so the latest image
+		// will be pulled when the container starts.
+		c.PinnedImage = nil
+		return s.containers.UpdateContainer(ctx, *c)
+	} else if err != nil {
+		return err
+	}
+
+	c.PreviousImage = &previous
+	c.PinnedImage = nil
+	if c.UpdatePolicy == types.UpdatePolicyPin {
+		c.UpdatePolicy = types.UpdatePolicyNotify
+	}
+	err = s.containers.UpdateContainer(ctx, *c)
+	if err != nil {
+		return err
+	}
+
+	s.ctx.DispatchEvent(types.EventContainerLog{
+		ContainerID: id,
+		Kind:        types.LogKindVertexOut,
+		Message:     types.NewLogLineMessageString("Updating to the latest image of " + c.GetImageNameWithTag() + "..."),
+	})
+
+	running := c.IsRunning()
+	err = s.recreate(ctx, c, running)
+	if err != nil {
+		return err
+	}
+
+	s.ctx.DispatchEvent(types.EventContainerUpdated{
+		ContainerID:   id,
+		Name:          c.Name,
+		PreviousImage: previous,
+		Image:         c.GetImageNameWithTag(),
+	})
+
+	if running {
+		go s.watchUpdate(id)
+	}
+	return nil
+}
+
+// Rollback recreates the container on the image it used before its last
+// update. The container stays pinned to this image until it is updated again.
+func (s *updateService) Rollback(ctx context.Context, id uuid.UUID) error {
+	return s.rollback(ctx, id, "manual rollback")
+}
+
+func (s *updateService) rollback(ctx context.Context, id uuid.UUID, reason string) error {
+	s.stopWatching(id)
+
+	c, err := s.containers.GetContainer(ctx, id)
+	if err != nil {
+		return err
+	}
+	if c.ID != id {
+		return types.ErrContainerNotFound
+	}
+	if c.PreviousImage == nil {
+		return types.ErrNoPreviousImage
+	}
+
+	image := *c.PreviousImage
+	c.PinnedImage = &image
+	c.PreviousImage = nil
+	err = s.containers.UpdateContainer(ctx, *c)
+	if err != nil {
+		return err
+	}
+
+	s.ctx.DispatchEvent(types.EventContainerLog{
+		ContainerID: id,
+		Kind:        types.LogKindVertexErr,
+		Message:     types.NewLogLineMessageString("Rolling back to image " + image + ": " + reason + "."),
+	})
+
+	err = s.recreate(ctx, c, c.IsRunning())
+	if err != nil {
+		return err
+	}
+
+	s.ctx.DispatchEvent(types.EventContainerUpdateRolledBack{
+		ContainerID: id,
+		Name:        c.Name,
+		Image:       image,
+		Reason:      reason,
+	})
+	return nil
+}
+
+// recreate recreates the Docker container if it is running. Otherwise, it
+// is only deleted, to be created again on the next start.
+func (s *updateService) recreate(ctx context.Context, c *types.Container, running bool) error {
+	if running {
+		return s.containerService.RecreateContainer(ctx, c.ID)
+	}
+	err := s.runner.DeleteContainer(ctx, c, []string{})
+	if err != nil && !errors.Is(err, errors.NotFound) {
+		return err
+	}
+	return nil
+}
+
+// watchUpdate rolls back the container if it fails or becomes unhealthy
+// during updateWatchDuration.
+func (s *updateService) watchUpdate(id uuid.UUID) {
+	ctx, cancel := context.WithTimeout(context.Background(), updateWatchDuration)
+	defer cancel()
+
+	s.watchesMu.Lock()
+	if stop, ok := s.watches[id]; ok {
+		stop()
+	}
+	s.watches[id] = cancel
+	s.watchesMu.Unlock()
+
+	failed := make(chan string, 1)
+	prev := ""
+	l := event.NewTempListener(func(e event.Event) error {
+		e2, ok := e.(types.EventContainerStatusChange)
+		if !ok || e2.ContainerID != id {
+			return nil
+		}
+
+		var reason string
+		switch {
+		case e2.Status == types.ContainerStatusError:
+			reason = "the container failed after the update"
+		case e2.Status == types.ContainerStatusUnhealthy:
+			reason = "the container is unhealthy after the update"
+		case e2.Status == types.ContainerStatusOff && prev != types.ContainerStatusStopping:
+			reason = "the container exited after the update"
+		}
+		prev = e2.Status
+
+		if reason != "" {
+			select {
+			case failed <- reason:
+			default:
+			}
+		}
+		return nil
+	})
+
+	s.ctx.AddListener(l)
+	defer s.ctx.RemoveListener(l)
+
+	select {
+	case reason := <-failed:
+		s.stopWatching(id)
+		err := s.rollback(server.NewBackgroundContext(), id, reason)
+		if err != nil {
+			log.Error(err, vlog.String("container_id", id.String()))
+		}
+	case <-ctx.Done():
+		s.stopWatching(id)
+	}
+}
+
+func (s *updateService) stopWatching(id uuid.UUID) {
+	s.watchesMu.Lock()
+	defer s.watchesMu.Unlock()
+	if stop, ok := s.watches[id]; ok {
+		stop()
+		delete(s.watches, id)
+	}
+}
+
+func (s *updateService) startScheduler() error {
+	s.scheduler = gocron.NewScheduler(time.Local)
+	_, err := s.scheduler.Every(updateCheckInterval).WaitForSchedule().Do(s.checkUpdates)
+	if err != nil {
+		return err
+	}
+	s.scheduler.StartAsync()
+	return nil
+}
+
+func (s *updateService) stopScheduler() {
+	if s.scheduler != nil {
+		s.scheduler.Clear()
+		s.scheduler.Stop()
+	}
+
+	s.watchesMu.Lock()
+	defer s.watchesMu.Unlock()
+	for id, stop := range s.watches {
+		stop()
+		delete(s.watches, id)
+	}
+}
+
+// checkUpdates looks for updates of all the containers that are not pinned.
+// Containers with the auto policy are updated if they are in their
+// maintenance window, unless they were rolled back.
+func (s *updateService) checkUpdates() {
+	ctx := server.NewBackgroundContext()
+
+	all, err := s.containers.GetContainers(ctx)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+
+	for _, c := range all {
+		if c.UpdatePolicy == types.UpdatePolicyPin {
+			continue
+		}
+
+		err := s.runner.CheckForUpdates(ctx, &c)
+		if errors.Is(err, errors.NotFound) {
+			continue
+		} else if err != nil {
+			log.Error(err, vlog.String("container_id", c.ID.String()))
+			continue
+		}
+		if c.Update == nil {
+			continue
+		}
+
+		if s.notified[c.ID] != c.Update.LatestVersion {
+			s.notified[c.ID] = c.Update.LatestVersion
+			s.ctx.DispatchEvent(types.EventContainerUpdateAvailable{
+				ContainerID: c.ID,
+				Name:        c.Name,
+				Update:      *c.Update,
+			})
+		}
+
+		if c.UpdatePolicy != types.UpdatePolicyAuto || c.PinnedImage != nil || !inWindow(c.UpdateWindow, time.Now()) {
+			continue
+		}
+
+		err = s.ApplyUpdate(ctx, c.ID)
+		if err != nil {
+			log.Error(err, vlog.String("container_id", c.ID.String()))
+		}
+	}
+}
+
+// inWindow returns true if t is in the maintenance window. No window means always.
+func inWindow(window *string, t time.Time) bool {
+	if window == nil || *window == "" {
+		return true
+	}
+	w, err := types.ParseMaintenanceWindow(*window)
+	if err != nil {
+		return false
+	}
+	return w.Contains(t)
+}
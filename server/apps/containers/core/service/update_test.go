@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/vertex-center/uuid"
+	"github.com/vertex-center/vertex/server/apps/containers/core/port"
+	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+	"github.com/vertex-center/vertex/server/common"
+	"github.com/vertex-center/vertex/server/common/app"
+	"github.com/vertex-center/vertex/server/pkg/event"
+)
+
+type UpdateServiceTestSuite struct {
+	suite.Suite
+
+	ctx              *app.Context
+	service          *updateService
+	containerService port.MockContainerService
+	containers       *fakeContainerAdapter
+	runner           *fakeRunnerAdapter
+
+	// events collects the update events.
+	events []event.Event
+	// rolledBack receives the containers rolled back.
+	rolledBack chan uuid.UUID
+}
+
+func TestUpdateServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(UpdateServiceTestSuite))
+}
+
+func (suite *UpdateServiceTestSuite) SetupTest() {
+	suite.ctx = app.NewContext(common.NewVertexContext(common.About{}, false))
+	suite.containers = &fakeContainerAdapter{containers: map[uuid.UUID]types.Container{}}
+	suite.runner = &fakeRunnerAdapter{
+		images:  map[uuid.UUID]string{},
+		updates: map[uuid.UUID]*types.ContainerUpdate{},
+	}
+	suite.containerService = port.MockContainerService{}
+	suite.containerService.On("RecreateContainer", mock.Anything, mock.Anything).Return(nil)
+	suite.service = NewUpdateService(suite.ctx, &suite.containerService, suite.containers, suite.runner).(*updateService)
+
+	suite.events = nil
+	suite.rolledBack = make(chan uuid.UUID, 1)
+	suite.ctx.AddListener(event.NewTempListener(func(e event.Event) error {
+		switch e := e.(type) {
+		case types.EventContainerUpdated, types.EventContainerUpdateAvailable:
+			suite.events = append(suite.events, e)
+		case types.EventContainerUpdateRolledBack:
+			suite.events = append(suite.events, e)
+			suite.rolledBack <- e.ContainerID
+		}
+		return nil
+	}))
+}
+
+func (suite *UpdateServiceTestSuite) TearDownTest() {
+	suite.service.stopScheduler()
+}
+
+func (suite *UpdateServiceTestSuite) addContainer(c types.Container) types.Container {
+	c.ID = uuid.New()
+	if c.Status == "" {
+		c.Status = types.ContainerStatusOff
+	}
+	suite.containers.containers[c.ID] = c
+	return c
+}
+
+func (suite *UpdateServiceTestSuite) TestApplyUpdate() {
+	c := suite.addContainer(types.Container{UpdatePolicy: types.UpdatePolicyPin})
+	suite.runner.images[c.ID] = "sha256:old"
+
+	err := suite.service.ApplyUpdate(context.Background(), c.ID)
+	suite.Require().NoError(err)
+
+	updated := suite.containers.containers[c.ID]
+	suite.Equal("sha256:old", *updated.PreviousImage)
+	suite.Nil(updated.PinnedImage)
+	suite.Equal(types.UpdatePolicyNotify, updated.UpdatePolicy)
+	suite.Require().Len(suite.events, 1)
+	suite.IsType(types.EventContainerUpdated{}, suite.events[0])
+	suite.containerService.AssertNotCalled(suite.T(), "RecreateContainer", mock.Anything, mock.Anything)
+}
+
+func (suite *UpdateServiceTestSuite) TestMissingContainer() {
+	err := suite.service.ApplyUpdate(context.Background(), uuid.New())
+	suite.ErrorIs(err, types.ErrContainerNotFound)
+
+	err = suite.service.SetUpdatePolicy(context.Background(), uuid.New(), types.UpdatePolicyAuto, nil)
+	suite.ErrorIs(err, types.ErrContainerNotFound)
+
+	err = suite.service.Rollback(context.Background(), uuid.New())
+	suite.ErrorIs(err, types.ErrContainerNotFound)
+	suite.Empty(suite.containers.containers)
+}
+
+func (suite *UpdateServiceTestSuite) TestRollback() {
+	previous := "sha256:old"
+	c := suite.addContainer(types.Container{PreviousImage: &previous})
+
+	err := suite.service.Rollback(context.Background(), c.ID)
+	suite.Require().NoError(err)
+
+	rolledBack := suite.containers.containers[c.ID]
+	suite.Equal(previous, *rolledBack.PinnedImage)
+	suite.Nil(rolledBack.PreviousImage)
+	suite.Equal(c.ID, <-suite.rolledBack)
+	suite.Require().Len(suite.events, 1)
+	suite.Equal("manual rollback", suite.events[0].(types.EventContainerUpdateRolledBack).Reason)
+
+	err = suite.service.Rollback(context.Background(), c.ID)
+	suite.ErrorIs(err, types.ErrNoPreviousImage)
+}
+
+func (suite *UpdateServiceTestSuite) TestWatchUpdateRollsBack() {
+	previous := "sha256:old"
+	c := suite.addContainer(types.Container{Status: types.ContainerStatusRunning, PreviousImage: &previous})
+
+	go suite.service.watchUpdate(c.ID)
+
+	// The status is sent until the watch listens to it.
+	suite.Eventually(func() bool {
+		select {
+		case <-suite.rolledBack:
+			return true
+		default:
+			suite.ctx.DispatchEvent(types.EventContainerStatusChange{
+				ContainerID: c.ID,
+				Status:      types.ContainerStatusUnhealthy,
+			})
+			return false
+		}
+	}, time.Second, 10*time.Millisecond)
+
+	rolledBack := suite.containers.containers[c.ID]
+	suite.Equal(previous, *rolledBack.PinnedImage)
+	suite.Nil(rolledBack.PreviousImage)
+	suite.Require().Len(suite.events, 1)
+	suite.Equal("the container is unhealthy after the update", suite.events[0].(types.EventContainerUpdateRolledBack).Reason)
+}
+
+func (suite *UpdateServiceTestSuite) TestWatchUpdateStopped() {
+	previous := "sha256:old"
+	c := suite.addContainer(types.Container{Status: types.ContainerStatusRunning, PreviousImage: &previous})
+
+	done := make(chan struct{})
+	go func() {
+		suite.service.watchUpdate(c.ID)
+		close(done)
+	}()
+	suite.Eventually(func() bool {
+		suite.service.watchesMu.Lock()
+		defer suite.service.watchesMu.Unlock()
+		_, ok := suite.service.watches[c.ID]
+		return ok
+	}, time.Second, 10*time.Millisecond)
+
+	suite.service.stopWatching(c.ID)
+	<-done
+	suite.Equal(&previous, suite.containers.containers[c.ID].PreviousImage)
+	suite.containerService.AssertNotCalled(suite.T(), "RecreateContainer", mock.Anything, mock.Anything)
+}
+
+func (suite *UpdateServiceTestSuite) TestCheckUpdates() {
+	now := time.Now()
+	outside := fmt.Sprintf("%02d:00-%02d:00", (now.Hour()+2)%24, (now.Hour()+3)%24)
+	pinned := "sha256:pinned"
+
+	var (
+		pin         = suite.addContainer(types.Container{UpdatePolicy: types.UpdatePolicyPin})
+		notify      = suite.addContainer(types.Container{UpdatePolicy: types.UpdatePolicyNotify})
+		auto        = suite.addContainer(types.Container{UpdatePolicy: types.UpdatePolicyAuto})
+		upToDate    = suite.addContainer(types.Container{UpdatePolicy: types.UpdatePolicyAuto})
+		outOfWindow = suite.addContainer(types.Container{UpdatePolicy: types.UpdatePolicyAuto, UpdateWindow: &outside})
+		rolledBack  = suite.addContainer(types.Container{UpdatePolicy: types.UpdatePolicyAuto, PinnedImage: &pinned})
+	)
+	update := &types.ContainerUpdate{CurrentVersion: "sha256:old", LatestVersion: "sha256:new"}
+	for _, c := range []types.Container{pin, notify, auto, outOfWindow, rolledBack} {
+		suite.runner.images[c.ID] = "sha256:old"
+		suite.runner.updates[c.ID] = update
+	}
+	suite.runner.images[upToDate.ID] = "sha256:old"
+
+	suite.service.checkUpdates()
+
+	suite.NotContains(suite.runner.checked, pin.ID)
+	suite.Len(suite.runner.checked, 5)
+	for _, c := range []types.Container{pin, notify, upToDate, outOfWindow, rolledBack} {
+		suite.Nil(suite.containers.containers[c.ID].PreviousImage, c.UpdatePolicy)
+	}
+	suite.Equal("sha256:old", *suite.containers.containers[auto.ID].PreviousImage)
+
+	suite.Equal(4, suite.countAvailable())
+
+	// The containers are notified only once per update.
+	suite.events = nil
+	suite.service.checkUpdates()
+	suite.Zero(suite.countAvailable())
+}
+
+func (suite *UpdateServiceTestSuite) countAvailable() int {
+	count := 0
+	for _, e := range suite.events {
+		if _, ok := e.(types.EventContainerUpdateAvailable); ok {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,80 @@
+package types
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+var (
+	ErrDockerContainerNotFound = errors.NotFoundf("docker container")
+	ErrDockerContainerManaged  = errors.NotValidf("docker container already managed by vertex")
+)
+
+type (
+	AdoptContainerOptions struct {
+		Name *string `json:"name,omitempty" example:"Postgres"` // Name of the container in Vertex. Defaults to the Docker name.
+	}
+
+	// ContainerAdoption is the container created from a Docker container,
+	// with the settings that could not be imported.
+	ContainerAdoption struct {
+		Container Container `json:"container"`
+		Unmapped  []string  `json:"unmapped" example:"mounts[0]"`
+	}
+)
+
+// IsManaged returns whether the Docker container was created by Vertex.
+func (c DockerContainer) IsManaged() bool {
+	for _, name := range c.Names {
+		if strings.HasPrefix(name, "/VERTEX_") {
+			return true
+		}
+	}
+	return false
+}
+
+// Name returns the name of the Docker container, without the leading slash.
+func (c DockerContainer) Name() string {
+	if len(c.Names) == 0 {
+		return c.ID
+	}
+	return strings.TrimPrefix(c.Names[0], "/")
+}
+
+// OwnEnv returns the environment variables set on the container, without
+// those inherited from the image.
+func (r InspectContainerResponse) OwnEnv() map[string]string {
+	env := map[string]string{}
+	for _, e := range r.Env {
+		if slices.Contains(r.ImageEnv, e) {
+			continue
+		}
+		name, value, _ := strings.Cut(e, "=")
+		env[name] = value
+	}
+	return env
+}
+
+// Command returns the command of the container, or nil if it is the command
+// of the image.
+func (r InspectContainerResponse) Command() *string {
+	if len(r.Cmd) == 0 || slices.Equal(r.Cmd, r.ImageCmd) {
+		return nil
+	}
+	cmd := JoinCommand(r.Cmd)
+	return &cmd
+}
+
+// SplitImageRef splits an image reference into its name and its tag. The
+// digest of the reference, if any, is dropped.
+func SplitImageRef(ref string) (string, string) {
+	ref, _, _ = strings.Cut(ref, "@")
+	image, tag := ref, "latest"
+	// The last colon is a tag only if it is not part of a registry host:port.
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image, tag = image[:i], image[i+1:]
+	}
+	return image, tag
+}
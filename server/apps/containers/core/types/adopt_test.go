@@ -0,0 +1,59 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type AdoptTestSuite struct {
+	suite.Suite
+}
+
+func TestAdoptTestSuite(t *testing.T) {
+	suite.Run(t, new(AdoptTestSuite))
+}
+
+func (suite *AdoptTestSuite) TestIsManaged() {
+	suite.True(DockerContainer{Names: []string{"/VERTEX_CONTAINER_d1fb743c-f937-4f3d-95b9-1a8475464591"}}.IsManaged())
+	suite.False(DockerContainer{Names: []string{"/postgres"}}.IsManaged())
+}
+
+func (suite *AdoptTestSuite) TestOwnEnv() {
+	info := InspectContainerResponse{
+		Env:      []string{"PATH=/usr/bin", "POSTGRES_PASSWORD=secret", "PGDATA=/data"},
+		ImageEnv: []string{"PATH=/usr/bin", "PGDATA=/var/lib/postgresql/data"},
+	}
+	suite.Equal(map[string]string{
+		"POSTGRES_PASSWORD": "secret",
+		"PGDATA":            "/data",
+	}, info.OwnEnv())
+}
+
+func (suite *AdoptTestSuite) TestCommand() {
+	info := InspectContainerResponse{
+		Cmd:      []string{"postgres"},
+		ImageCmd: []string{"postgres"},
+	}
+	suite.Nil(info.Command())
+
+	info.Cmd = []string{"postgres", "-c", "max_connections=200"}
+	suite.Equal("postgres -c max_connections=200", *info.Command())
+
+	info.Cmd = []string{"sh", "-c", "exec postgres -c max_connections=200"}
+	suite.Equal("sh -c 'exec postgres -c max_connections=200'", *info.Command())
+}
+
+func (suite *AdoptTestSuite) TestSplitImageRef() {
+	tests := map[string][2]string{
+		"postgres":                        {"postgres", "latest"},
+		"postgres:16":                     {"postgres", "16"},
+		"localhost:5000/app":              {"localhost:5000/app", "latest"},
+		"localhost:5000/app:1.2":          {"localhost:5000/app", "1.2"},
+		"redis:7@sha256:9f86d081884c7d65": {"redis", "7"},
+	}
+	for ref, expected := range tests {
+		image, tag := SplitImageRef(ref)
+		suite.Equal(expected, [2]string{image, tag}, ref)
+	}
+}
@@ -0,0 +1,61 @@
+package types
+
+import (
+	"github.com/juju/errors"
+	"github.com/robfig/cron/v3"
+	"github.com/vertex-center/uuid"
+)
+
+var (
+	ErrBackupNotFound         = errors.NotFoundf("backup")
+	ErrBackupScheduleNotFound = errors.NotFoundf("backup schedule")
+	ErrBackupCorrupted        = errors.NotValidf("backup checksum")
+	ErrInvalidBackupSchedule  = errors.NotValidf("backup schedule")
+	ErrNoVolumesToBackup      = errors.NotFoundf("volumes to backup")
+)
+
+type (
+	Backups []Backup
+	Backup  struct {
+		ID          uuid.UUID `json:"id"           db:"id"           example:"b8f2a1c4-5d6e-4f7a-8b9c-0d1e2f3a4b5c"`
+		ContainerID uuid.UUID `json:"container_id" db:"container_id" example:"d1fb743c-f937-4f3d-95b9-1a8475464591"`
+		Version     int       `json:"version"      db:"version"      example:"3"`
+		CreatedAt   int64     `json:"created_at"   db:"created_at"   example:"1700000000"`
+		Size        int64     `json:"size"         db:"size"         example:"10485760"` // Total size of the archives, in bytes.
+
+		Volumes BackupVolumes `json:"volumes,omitempty" db:"-"`
+	}
+
+	BackupVolumes []BackupVolume
+	BackupVolume  struct {
+		ID       uuid.UUID  `json:"id"        db:"id"        example:"c9a3b2d5-6e7f-4a8b-9c0d-1e2f3a4b5c6d"`
+		BackupID uuid.UUID  `json:"backup_id" db:"backup_id" example:"b8f2a1c4-5d6e-4f7a-8b9c-0d1e2f3a4b5c"`
+		VolumeID uuid.UUID  `json:"volume_id" db:"volume_id" example:"7e63ced7-4f4e-4b79-95ca-62930866f7bc"`
+		Type     VolumeType `json:"type"      db:"type"      example:"volume"`
+		Name     string     `json:"name"      db:"name"      example:"VERTEX_VOLUME_d1fb743c-f937-4f3d-95b9-1a8475464591_data"` // Name of the volume, or path on the host of the bind mount.
+		Path     string     `json:"path"      db:"path"      example:"/var/lib/postgresql/data"`                                // Path in the container.
+		Archive  string     `json:"-"         db:"archive"`
+		Size     int64      `json:"size"      db:"size"      example:"10485760"`
+		Checksum string     `json:"checksum"  db:"checksum"  example:"sha256:9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+	}
+
+	// BackupSchedule makes backups of a container periodically, and only
+	// keeps the most recent ones.
+	BackupSchedule struct {
+		ContainerID   uuid.UUID `json:"container_id"   db:"container_id"   example:"d1fb743c-f937-4f3d-95b9-1a8475464591"`
+		Schedule      string    `json:"schedule"       db:"schedule"       example:"0 3 * * *"` // Cron expression, in local time.
+		Retention     int       `json:"retention"      db:"retention"      example:"7"`         // Number of backups to keep.
+		StopContainer bool      `json:"stop_container" db:"stop_container" example:"true"`      // Stop the container during the backup, for consistent data.
+	}
+)
+
+func (s *BackupSchedule) Validate() error {
+	_, err := cron.ParseStandard(s.Schedule)
+	if err != nil {
+		return errors.NewNotValid(ErrInvalidBackupSchedule, "invalid cron expression: "+err.Error())
+	}
+	if s.Retention < 1 {
+		return errors.NewNotValid(ErrInvalidBackupSchedule, "retention must be at least 1")
+	}
+	return nil
+}
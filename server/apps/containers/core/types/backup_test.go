@@ -0,0 +1,31 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/juju/errors"
+	"github.com/stretchr/testify/suite"
+)
+
+type BackupTestSuite struct {
+	suite.Suite
+}
+
+func TestBackupTestSuite(t *testing.T) {
+	suite.Run(t, new(BackupTestSuite))
+}
+
+func (suite *BackupTestSuite) TestValidateSchedule() {
+	s := BackupSchedule{Schedule: "0 3 * * *", Retention: 7}
+	suite.NoError(s.Validate())
+
+	s.Schedule = "@daily"
+	suite.NoError(s.Validate())
+
+	s.Schedule = "every day"
+	suite.True(errors.Is(s.Validate(), errors.NotValid))
+
+	s.Schedule = "0 3 * * *"
+	s.Retention = 0
+	suite.True(errors.Is(s.Validate(), errors.NotValid))
+}
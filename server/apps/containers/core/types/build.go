@@ -0,0 +1,44 @@
+package types
+
+import (
+	"github.com/juju/errors"
+	"github.com/vertex-center/uuid"
+)
+
+// BuildCommitLabel is the label of built images holding the commit they were
+// built from.
+const BuildCommitLabel = "center.vertex.build.commit"
+
+var ErrContainerBuildNotFound = errors.NotFoundf("container build")
+
+type (
+	// ContainerBuild describes how to build the image of a container from a
+	// git repository, instead of pulling it.
+	ContainerBuild struct {
+		ID          uuid.UUID `json:"id"           db:"id"           example:"5f0b8e1a-3c2d-4e6f-9a7b-1c2d3e4f5a6b"`
+		ContainerID uuid.UUID `json:"container_id" db:"container_id" example:"d1fb743c-f937-4f3d-95b9-1a8475464591"`
+		Repository  string    `json:"repository"   db:"repository"   example:"https://github.com/vertex-center/vertex"`
+		Dockerfile  string    `json:"dockerfile"   db:"dockerfile"   example:"Dockerfile"`
+
+		Args BuildArgs `json:"args,omitempty" db:"-"`
+		Dir  string    `json:"-"              db:"-"` // Directory where the repository is cloned.
+	}
+
+	BuildArgs []BuildArg
+	BuildArg  struct {
+		ID          uuid.UUID `json:"id"           db:"id"           example:"6a1c9f2b-4d3e-4f5a-8b7c-2d3e4f5a6b7c"`
+		ContainerID uuid.UUID `json:"container_id" db:"container_id" example:"d1fb743c-f937-4f3d-95b9-1a8475464591"`
+		Name        string    `json:"name"         db:"name"         example:"VERSION"`
+		Value       string    `json:"value"        db:"value"        example:"1.2.0"`
+	}
+)
+
+// DockerBuildArgs converts the build args into Docker build args.
+func (a BuildArgs) DockerBuildArgs() map[string]*string {
+	args := map[string]*string{}
+	for _, arg := range a {
+		value := arg.Value
+		args[arg.Name] = &value
+	}
+	return args
+}
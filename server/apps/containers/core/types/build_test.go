@@ -0,0 +1,35 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/vertex-center/uuid"
+)
+
+type BuildTestSuite struct {
+	suite.Suite
+}
+
+func TestBuildTestSuite(t *testing.T) {
+	suite.Run(t, new(BuildTestSuite))
+}
+
+func (suite *BuildTestSuite) TestDockerBuildArgs() {
+	args := BuildArgs{
+		{Name: "VERSION", Value: "1.2.0"},
+		{Name: "EMPTY", Value: ""},
+	}.DockerBuildArgs()
+
+	suite.Len(args, 2)
+	suite.Equal("1.2.0", *args["VERSION"])
+	suite.Equal("", *args["EMPTY"])
+}
+
+func (suite *BuildTestSuite) TestIsBuilt() {
+	c := Container{ID: uuid.New(), Image: "postgres", ImageTag: "16"}
+	suite.False(c.IsBuilt())
+
+	c.Image = c.DockerImageVertexName()
+	suite.True(c.IsBuilt())
+}
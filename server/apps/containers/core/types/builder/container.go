@@ -1,8 +1,7 @@
 package builder
 
 import (
-	"strings"
-
+	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/mount"
 	"github.com/docker/go-connections/nat"
 	"github.com/vertex-center/vertex/server/apps/containers/core/types"
@@ -33,7 +32,7 @@ func (b *ContainerBuilder) WithImage(image string) *ContainerBuilder {
 
 func (b *ContainerBuilder) WithCommand(cmd *string) *ContainerBuilder {
 	if cmd != nil {
-		b.opts.Cmd = strings.Split(*cmd, " ")
+		b.opts.Cmd = types.SplitCommand(*cmd)
 	}
 	return b
 }
@@ -66,9 +65,11 @@ func (b *ContainerBuilder) WithSysctls(sysctls types.Sysctls) *ContainerBuilder
 func (b *ContainerBuilder) WithPorts(ports types.Ports) *ContainerBuilder {
 	var all []string
 	for _, p := range ports {
-		out := p.Out
-		in := p.In
-		all = append(all, out+":"+in)
+		spec := p.Out + ":" + p.In
+		if p.Protocol != "" {
+			spec += "/" + string(p.Protocol)
+		}
+		all = append(all, spec)
 	}
 
 	var err error
@@ -94,3 +95,49 @@ func (b *ContainerBuilder) WithVolumes(volumes types.Volumes) *ContainerBuilder
 	}
 	return b
 }
+
+func (b *ContainerBuilder) WithHealthCheck(hc *types.HealthCheck) *ContainerBuilder {
+	if hc != nil {
+		b.opts.Healthcheck = hc.DockerConfig()
+	}
+	return b
+}
+
+func (b *ContainerBuilder) WithResourceLimits(limits *types.ResourceLimits) *ContainerBuilder {
+	if limits == nil {
+		return b
+	}
+	b.opts.Resources = &container.Resources{
+		Ulimits: limits.DockerUlimits(),
+	}
+	if limits.CPUShares != nil {
+		b.opts.Resources.CPUShares = *limits.CPUShares
+	}
+	if limits.CPUPeriod != nil {
+		b.opts.Resources.CPUPeriod = *limits.CPUPeriod
+	}
+	if limits.CPUQuota != nil {
+		b.opts.Resources.CPUQuota = *limits.CPUQuota
+	}
+	if limits.Memory != nil {
+		b.opts.Resources.Memory = *limits.Memory
+	}
+	if limits.MemorySwap != nil {
+		b.opts.Resources.MemorySwap = *limits.MemorySwap
+	}
+	b.opts.Resources.PidsLimit = limits.PidsLimit
+	return b
+}
+
+func (b *ContainerBuilder) WithNetwork(name string, aliases ...string) *ContainerBuilder {
+	b.opts.Networks = append(b.opts.Networks, types.NetworkAttachment{
+		Name:    name,
+		Aliases: aliases,
+	})
+	return b
+}
+
+func (b *ContainerBuilder) WithNetworks(networks []types.NetworkAttachment) *ContainerBuilder {
+	b.opts.Networks = append(b.opts.Networks, networks...)
+	return b
+}
@@ -0,0 +1,40 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type CommandTestSuite struct {
+	suite.Suite
+}
+
+func TestCommandTestSuite(t *testing.T) {
+	suite.Run(t, new(CommandTestSuite))
+}
+
+func (suite *CommandTestSuite) TestJoinCommand() {
+	suite.Equal("postgres -c max_connections=200", JoinCommand([]string{"postgres", "-c", "max_connections=200"}))
+	suite.Equal(`sh -c 'echo "hello world"' ''`, JoinCommand([]string{"sh", "-c", `echo "hello world"`, ""}))
+	suite.Equal(`echo 'it'\''s'`, JoinCommand([]string{"echo", "it's"}))
+}
+
+func (suite *CommandTestSuite) TestSplitCommand() {
+	tests := map[string][]string{
+		"":                                 nil,
+		"tunnel run":                       {"tunnel", "run"},
+		"  serve   --verbose ":             {"serve", "--verbose"},
+		`sh -c 'echo "hello world"'`:       {"sh", "-c", `echo "hello world"`},
+		`echo "a \"quoted\" word" a\ b ''`: {"echo", `a "quoted" word`, "a b", ""},
+		`echo 'it'\''s'`:                   {"echo", "it's"},
+	}
+	for cmd, args := range tests {
+		suite.Equal(args, SplitCommand(cmd), cmd)
+	}
+}
+
+func (suite *CommandTestSuite) TestJoinSplitCommand() {
+	args := []string{"sh", "-c", `printf '%s\n' "$HOME" && echo done`, "", "tab\there"}
+	suite.Equal(args, SplitCommand(JoinCommand(args)))
+}
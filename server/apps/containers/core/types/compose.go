@@ -0,0 +1,330 @@
+package types
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+	"gopkg.in/yaml.v3"
+)
+
+var ErrInvalidCompose = errors.NotValidf("compose file")
+
+type (
+	// ComposeFile is a docker-compose file. Only the parts that Vertex can
+	// map to containers are parsed, the rest is kept in Extra to be reported.
+	ComposeFile struct {
+		Version  string                    `yaml:"version,omitempty"`
+		Name     string                    `yaml:"name,omitempty"`
+		Services map[string]ComposeService `yaml:"services"`
+		Volumes  map[string]any            `yaml:"volumes,omitempty"`
+		Extra    map[string]any            `yaml:",inline"`
+	}
+
+	ComposeService struct {
+		Image         string              `yaml:"image,omitempty"`
+		ContainerName string              `yaml:"container_name,omitempty"`
+		Command       ComposeCommand      `yaml:"command,omitempty"`
+		Environment   ComposeMapOrList    `yaml:"environment,omitempty"`
+		Ports         []ComposePort       `yaml:"ports,omitempty"`
+		Volumes       []ComposeVolume     `yaml:"volumes,omitempty"`
+		CapAdd        []string            `yaml:"cap_add,omitempty"`
+		Sysctls       ComposeMapOrList    `yaml:"sysctls,omitempty"`
+		DependsOn     ComposeDependsOn    `yaml:"depends_on,omitempty"`
+		Restart       string              `yaml:"restart,omitempty"`
+		Healthcheck   *ComposeHealthcheck `yaml:"healthcheck,omitempty"`
+		Extra         map[string]any      `yaml:",inline"`
+	}
+
+	ComposeHealthcheck struct {
+		Test        ComposeCommand `yaml:"test,omitempty"`
+		Interval    string         `yaml:"interval,omitempty"`
+		Timeout     string         `yaml:"timeout,omitempty"`
+		StartPeriod string         `yaml:"start_period,omitempty"`
+		Retries     int            `yaml:"retries,omitempty"`
+		Disable     bool           `yaml:"disable,omitempty"`
+	}
+
+	// ComposeCommand is a command written as a string or as a list.
+	ComposeCommand []string
+
+	// ComposeMapOrList is a map written as a map or as a list of KEY=VALUE.
+	ComposeMapOrList map[string]string
+
+	// ComposeDependsOn maps a service to the condition to wait for.
+	ComposeDependsOn map[string]ComposeDependency
+
+	ComposeDependency struct {
+		Condition string `yaml:"condition"`
+	}
+
+	// ComposePort is a port in the short syntax, like "127.0.0.1:8080:80/tcp".
+	ComposePort struct {
+		HostIP    string // Address the port is published on. Empty for all interfaces.
+		Published string
+		Target    string
+	}
+
+	// ComposeVolume is a volume in the short syntax, like "data:/var/lib/data:ro".
+	ComposeVolume struct {
+		Source string
+		Target string
+		Mode   string
+	}
+
+	ComposeImport struct {
+		Containers Containers `json:"containers"`
+		// Unmapped lists the parts of the compose file that could not be imported.
+		Unmapped []string `json:"unmapped"`
+	}
+
+	ComposeExport struct {
+		Compose string `json:"compose"`
+		// Unmapped lists the parts of the containers that could not be exported.
+		Unmapped []string `json:"unmapped"`
+	}
+)
+
+const (
+	ComposeConditionStarted = "service_started"
+	ComposeConditionHealthy = "service_healthy"
+)
+
+// ParseCompose reads a docker-compose file.
+func ParseCompose(data []byte) (*ComposeFile, error) {
+	var compose ComposeFile
+	err := yaml.Unmarshal(data, &compose)
+	if err != nil {
+		return nil, errors.NewNotValid(err, "invalid compose file")
+	}
+	if len(compose.Services) == 0 {
+		return nil, errors.NewNotValid(ErrInvalidCompose, "the compose file has no services")
+	}
+	return &compose, nil
+}
+
+// ServiceNames returns the names of the services, sorted.
+func (f *ComposeFile) ServiceNames() []string {
+	var names []string
+	for name := range f.Services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Shell returns the health check command to run in a shell. It returns
+// false if the health check is disabled.
+func (h ComposeHealthcheck) Shell() (string, bool) {
+	if h.Disable || len(h.Test) == 0 {
+		return "", false
+	}
+	switch h.Test[0] {
+	case "NONE":
+		return "", false
+	case "CMD", "CMD-SHELL":
+		return ComposeCommand(h.Test[1:]).String(), true
+	}
+	return h.Test.String(), true
+}
+
+// String returns the command as Vertex stores it.
+func (c ComposeCommand) String() string {
+	return JoinCommand(c)
+}
+
+func (c *ComposeCommand) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*c = SplitCommand(node.Value)
+		return nil
+	}
+	var list []string
+	err := node.Decode(&list)
+	*c = list
+	return err
+}
+
+func (m *ComposeMapOrList) UnmarshalYAML(node *yaml.Node) error {
+	*m = map[string]string{}
+	if node.Kind == yaml.SequenceNode {
+		var list []string
+		err := node.Decode(&list)
+		if err != nil {
+			return err
+		}
+		for _, item := range list {
+			key, value, _ := strings.Cut(item, "=")
+			(*m)[key] = value
+		}
+		return nil
+	}
+
+	var values map[string]any
+	err := node.Decode(&values)
+	if err != nil {
+		return err
+	}
+	for key, value := range values {
+		if value == nil {
+			(*m)[key] = ""
+		} else {
+			(*m)[key] = fmt.Sprint(value)
+		}
+	}
+	return nil
+}
+
+func (d *ComposeDependsOn) UnmarshalYAML(node *yaml.Node) error {
+	*d = map[string]ComposeDependency{}
+	if node.Kind == yaml.SequenceNode {
+		var list []string
+		err := node.Decode(&list)
+		if err != nil {
+			return err
+		}
+		for _, name := range list {
+			(*d)[name] = ComposeDependency{Condition: ComposeConditionStarted}
+		}
+		return nil
+	}
+
+	var deps map[string]ComposeDependency
+	err := node.Decode(&deps)
+	if err != nil {
+		return err
+	}
+	for name, dep := range deps {
+		if dep.Condition == "" {
+			dep.Condition = ComposeConditionStarted
+		}
+		(*d)[name] = dep
+	}
+	return nil
+}
+
+func (p *ComposePort) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		spec := node.Value
+		i := strings.LastIndex(spec, ":")
+		if i == -1 {
+			p.Target = spec
+			p.Published, _, _ = strings.Cut(spec, "/")
+			return nil
+		}
+		p.Published, p.Target = spec[:i], spec[i+1:]
+		// The host IP comes first, like in "127.0.0.1:8080:80" or "[::1]:8080:80".
+		if i = strings.LastIndex(p.Published, ":"); i != -1 {
+			p.HostIP, p.Published = strings.Trim(p.Published[:i], "[]"), p.Published[i+1:]
+		}
+		if p.Published == "" {
+			p.Published, _, _ = strings.Cut(p.Target, "/")
+		}
+		return nil
+	}
+
+	var long struct {
+		Target    int    `yaml:"target"`
+		Published string `yaml:"published"`
+		HostIP    string `yaml:"host_ip"`
+		Protocol  string `yaml:"protocol"`
+	}
+	err := node.Decode(&long)
+	if err != nil {
+		return err
+	}
+	p.Target = strconv.Itoa(long.Target)
+	if long.Protocol != "" && long.Protocol != "tcp" {
+		p.Target += "/" + long.Protocol
+	}
+	p.Published = long.Published
+	if p.Published == "" {
+		p.Published = strconv.Itoa(long.Target)
+	}
+	p.HostIP = long.HostIP
+	return nil
+}
+
+// MarshalYAML quotes the port, because "80:80" can be read as a base 60
+// number by YAML 1.1 parsers.
+func (p ComposePort) MarshalYAML() (interface{}, error) {
+	value := p.Published + ":" + p.Target
+	if strings.Contains(p.HostIP, ":") {
+		value = "[" + p.HostIP + "]:" + value
+	} else if p.HostIP != "" {
+		value = p.HostIP + ":" + value
+	}
+	return &yaml.Node{
+		Kind:  yaml.ScalarNode,
+		Style: yaml.DoubleQuotedStyle,
+		Value: value,
+	}, nil
+}
+
+func (v *ComposeVolume) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		parts := strings.Split(node.Value, ":")
+		switch len(parts) {
+		case 1:
+			v.Target = parts[0]
+		case 2:
+			v.Source, v.Target = parts[0], parts[1]
+		default:
+			v.Source, v.Target, v.Mode = parts[0], parts[1], parts[2]
+		}
+		return nil
+	}
+
+	var long struct {
+		Source   string `yaml:"source"`
+		Target   string `yaml:"target"`
+		ReadOnly bool   `yaml:"read_only"`
+	}
+	err := node.Decode(&long)
+	if err != nil {
+		return err
+	}
+	v.Source, v.Target = long.Source, long.Target
+	if long.ReadOnly {
+		v.Mode = "ro"
+	}
+	return nil
+}
+
+func (v ComposeVolume) MarshalYAML() (interface{}, error) {
+	return v.Source + ":" + v.Target, nil
+}
+
+// IsBind returns true if the source of the volume is a path on the host.
+func (v ComposeVolume) IsBind() bool {
+	return strings.HasPrefix(v.Source, "/") || strings.HasPrefix(v.Source, ".") || strings.HasPrefix(v.Source, "~")
+}
+
+// RestartPolicy converts the compose restart policy, like "on-failure:5".
+func (s ComposeService) RestartPolicy() (RestartPolicy, int, error) {
+	if s.Restart == "" {
+		return RestartPolicyNo, 0, nil
+	}
+
+	policy, retries, _ := strings.Cut(s.Restart, ":")
+	p := RestartPolicy(policy)
+	err := p.Validate()
+	if err != nil {
+		return "", 0, err
+	}
+
+	maxRetries := 0
+	if retries != "" {
+		maxRetries, err = strconv.Atoi(retries)
+		if err != nil {
+			return "", 0, errors.NewNotValid(err, "invalid restart policy")
+		}
+	}
+	return p, maxRetries, nil
+}
+
+// ImageAndTag splits the image of the service into its name and its tag.
+func (s ComposeService) ImageAndTag() (string, string) {
+	return SplitImageRef(s.Image)
+}
@@ -0,0 +1,95 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ComposeTestSuite struct {
+	suite.Suite
+}
+
+func TestComposeTestSuite(t *testing.T) {
+	suite.Run(t, new(ComposeTestSuite))
+}
+
+func (suite *ComposeTestSuite) TestParseCompose() {
+	data := `
+services:
+  app:
+    image: ghcr.io/example/app:1.2
+    command: serve --verbose
+    environment:
+      - DB_HOST=db
+      - DEBUG
+    ports:
+      - "8080:80"
+      - "127.0.0.1:53:53/udp"
+      - "[::1]:9090:90"
+      - target: 443
+        published: "8443"
+        host_ip: 127.0.0.1
+    volumes:
+      - data:/data
+      - ./config:/config:ro
+    depends_on:
+      db:
+        condition: service_healthy
+    restart: on-failure:3
+    deploy:
+      replicas: 2
+  db:
+    image: postgres
+    sysctls:
+      net.core.somaxconn: 1024
+    healthcheck:
+      test: ["CMD-SHELL", "pg_isready"]
+networks:
+  default: {}
+`
+
+	compose, err := ParseCompose([]byte(data))
+	suite.Require().NoError(err)
+	suite.Equal([]string{"app", "db"}, compose.ServiceNames())
+	suite.Contains(compose.Extra, "networks")
+
+	app := compose.Services["app"]
+	image, tag := app.ImageAndTag()
+	suite.Equal("ghcr.io/example/app", image)
+	suite.Equal("1.2", tag)
+	suite.Equal("serve --verbose", app.Command.String())
+	suite.Equal(ComposeMapOrList{"DB_HOST": "db", "DEBUG": ""}, app.Environment)
+	suite.Equal([]ComposePort{
+		{Published: "8080", Target: "80"},
+		{HostIP: "127.0.0.1", Published: "53", Target: "53/udp"},
+		{HostIP: "::1", Published: "9090", Target: "90"},
+		{HostIP: "127.0.0.1", Published: "8443", Target: "443"},
+	}, app.Ports)
+	suite.Equal([]ComposeVolume{
+		{Source: "data", Target: "/data"},
+		{Source: "./config", Target: "/config", Mode: "ro"},
+	}, app.Volumes)
+	suite.False(app.Volumes[0].IsBind())
+	suite.True(app.Volumes[1].IsBind())
+	suite.Equal(ComposeConditionHealthy, app.DependsOn["db"].Condition)
+	suite.Contains(app.Extra, "deploy")
+
+	policy, retries, err := app.RestartPolicy()
+	suite.Require().NoError(err)
+	suite.Equal(RestartPolicyOnFailure, policy)
+	suite.Equal(3, retries)
+
+	db := compose.Services["db"]
+	_, tag = db.ImageAndTag()
+	suite.Equal("latest", tag)
+	suite.Equal(ComposeMapOrList{"net.core.somaxconn": "1024"}, db.Sysctls)
+	cmd, ok := db.Healthcheck.Shell()
+	suite.True(ok)
+	suite.Equal("pg_isready", cmd)
+}
+
+func (suite *ComposeTestSuite) TestParseComposeWithoutServices() {
+	_, err := ParseCompose([]byte("version: '3'"))
+	suite.Error(err)
+}
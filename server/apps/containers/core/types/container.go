@@ -12,9 +12,24 @@ const (
 	ContainerStatusRunning  = "running"
 	ContainerStatusStopping = "stopping"
 	ContainerStatusError    = "error"
+
+	// ContainerStatusHealthy and ContainerStatusUnhealthy replace
+	// ContainerStatusRunning for containers that have a health check.
+	ContainerStatusHealthy   = "healthy"
+	ContainerStatusUnhealthy = "unhealthy"
+)
+
+type RestartPolicy string
+
+const (
+	RestartPolicyNo            RestartPolicy = "no"
+	RestartPolicyOnFailure     RestartPolicy = "on-failure"
+	RestartPolicyAlways        RestartPolicy = "always"
+	RestartPolicyUnlessStopped RestartPolicy = "unless-stopped"
 )
 
 var (
+	ErrInvalidRestartPolicy  = errors.NotValidf("restart policy")
 	ErrContainerNotFound     = errors.NotFoundf("container")
 	ErrContainerStillRunning = errors.New("container still running")
 	ErrDatabaseIDNotFound    = errors.NotFoundf("database id")
@@ -23,26 +38,40 @@ var (
 type (
 	Containers []Container
 	Container  struct {
-		ID              uuid.UUID `json:"id"                     db:"id"                 example:"1cb8c970-395f-4810-8c9e-e4df35f456e1"`
-		TemplateID      *string   `json:"template_id,omitempty"  db:"template_id"        example:"postgres"`
-		UserID          uuid.UUID `json:"user_id"                db:"user_id"            example:"596ecff2-ca67-4194-947d-59e90920680f"`
-		Image           string    `json:"image"                  db:"image"              example:"postgres"`
-		ImageTag        string    `json:"image_tag,omitempty"    db:"image_tag"          example:"latest"`
-		Status          string    `json:"status"                 db:"status"             example:"running"`
-		LaunchOnStartup bool      `json:"launch_on_startup"      db:"launch_on_startup"  example:"true"`
-		Name            string    `json:"name"                   db:"name"               example:"Postgres"`
-		Description     *string   `json:"description"            db:"description"        example:"An SQL database."`
-		Color           *string   `json:"color"                  db:"color"              example:"#336699"`
-		Icon            *string   `json:"icon"                   db:"icon"               example:"simpleicons/postgres.svg"`
-		Command         *string   `json:"command,omitempty"      db:"command"            example:"tunnel run"`
+		ID               uuid.UUID `json:"id"                          db:"id"                example:"1cb8c970-395f-4810-8c9e-e4df35f456e1"`
+		TemplateID       *string   `json:"template_id,omitempty"       db:"template_id"       example:"postgres"`
+		TemplateRevision *string   `json:"template_revision,omitempty" db:"template_revision" example:"5f2b7c1d9e0a4b83"` // Revision of the template the container is up to date with.
+		UserID           uuid.UUID `json:"user_id"                     db:"user_id"           example:"596ecff2-ca67-4194-947d-59e90920680f"`
+		Image            string    `json:"image"                       db:"image"             example:"postgres"`
+		ImageTag         string    `json:"image_tag,omitempty"         db:"image_tag"         example:"latest"`
+		Status           string    `json:"status"                      db:"status"            example:"running"`
+		LaunchOnStartup  bool      `json:"launch_on_startup"           db:"launch_on_startup" example:"true"`
+		Name             string    `json:"name"                        db:"name"              example:"Postgres"`
+		Description      *string   `json:"description"                 db:"description"       example:"An SQL database."`
+		Color            *string   `json:"color"                       db:"color"             example:"#336699"`
+		Icon             *string   `json:"icon"                        db:"icon"              example:"simpleicons/postgres.svg"`
+		Command          *string   `json:"command,omitempty"           db:"command"           example:"tunnel run"`
+
+		RestartPolicy     RestartPolicy `json:"restart_policy"      db:"restart_policy"      example:"on-failure"`
+		RestartMaxRetries int           `json:"restart_max_retries" db:"restart_max_retries" example:"5"`    // 0 means no limit
+		StoppedByUser     bool          `json:"stopped_by_user"     db:"stopped_by_user"     example:"true"` // Whether the user stopped the container, for the unless-stopped policy.
+
+		StackID     *uuid.UUID `json:"stack_id,omitempty"     db:"stack_id"     example:"0e9d0a4a-4d4f-4ea4-9a2b-0d1e6c2d9f4e"`
+		StackMember *string    `json:"stack_member,omitempty" db:"stack_member" example:"redis"` // Hostname of the container in the stack network.
+
+		UpdatePolicy  UpdatePolicy `json:"update_policy"            db:"update_policy"  example:"auto"`
+		UpdateWindow  *string      `json:"update_window,omitempty"  db:"update_window"  example:"02:00-05:00"`         // Daily window of automatic updates, in local time.
+		PinnedImage   *string      `json:"pinned_image,omitempty"   db:"pinned_image"   example:"sha256:1b0a2a1e0e5c"` // Image run instead of pulling the tag.
+		PreviousImage *string      `json:"previous_image,omitempty" db:"previous_image" example:"sha256:9e1fd7a6c1a0"` // Image before the last update, to roll back to.
 
 		Databases map[string]uuid.UUID `json:"databases,omitempty"`
 		Update    *ContainerUpdate     `json:"update,omitempty"`
 	}
 
 	ContainerFilters struct {
-		Tags     *[]string `json:"tags,omitempty"`
-		Features *[]string `json:"features,omitempty"`
+		Tags     *[]string  `json:"tags,omitempty"`
+		Features *[]string  `json:"features,omitempty"`
+		StackID  *uuid.UUID `json:"stack_id,omitempty"`
 	}
 
 	ContainerUpdate struct {
@@ -58,9 +87,18 @@ type (
 	}
 
 	CreateContainerOptions struct {
-		TemplateID *string `json:"template_id,omitempty"`
-		Image      *string `json:"image,omitempty"`
-		ImageTag   *string `json:"image_tag,omitempty"`
+		TemplateID    *string `json:"template_id,omitempty"`
+		Image         *string `json:"image,omitempty"`
+		ImageTag      *string `json:"image_tag,omitempty"`
+		AllocatePorts bool    `json:"allocate_ports,omitempty"` // Use the next free host ports when the ports of the template are already used.
+
+		Env map[string]string `json:"env,omitempty"` // Values of the variables of the template, validated against their type and constraints.
+	}
+
+	DuplicateContainerOptions struct {
+		Name        *string `json:"name,omitempty" example:"Postgres (staging)"` // Defaults to the name of the container, followed by (copy).
+		CopyVolumes bool    `json:"copy_volumes"   example:"true"`               // Copy the data of the volumes. Otherwise, the volumes of the copy are empty.
+		Stop        bool    `json:"stop"           example:"true"`               // Stop the container while its volumes are copied, for consistent data.
 	}
 )
 
@@ -68,6 +106,20 @@ func (i *Container) DockerImageVertexName() string { return "vertex_image_" + i.
 func (i *Container) DockerContainerName() string   { return "VERTEX_CONTAINER_" + i.ID.String() }
 func (i *Container) GetImageNameWithTag() string   { return i.Image + ":" + i.ImageTag }
 
+// IsBuilt returns whether the image of the container is built from a
+// repository instead of pulled.
+func (i *Container) IsBuilt() bool {
+	return i.Image == i.DockerImageVertexName()
+}
+
+// ImageToRun returns the pinned image if any, or the image name with its tag.
+func (i *Container) ImageToRun() string {
+	if i.PinnedImage != nil {
+		return *i.PinnedImage
+	}
+	return i.GetImageNameWithTag()
+}
+
 func (i *Container) IsRunning() bool {
 	return i.Status != ContainerStatusOff && i.Status != ContainerStatusError
 }
@@ -76,6 +128,14 @@ func (i *Container) IsBusy() bool {
 	return i.Status == ContainerStatusBuilding || i.Status == ContainerStatusStarting || i.Status == ContainerStatusStopping
 }
 
+func (p RestartPolicy) Validate() error {
+	switch p {
+	case RestartPolicyNo, RestartPolicyOnFailure, RestartPolicyAlways, RestartPolicyUnlessStopped:
+		return nil
+	}
+	return ErrInvalidRestartPolicy
+}
+
 func (o *CreateContainerOptions) FillDefaults() {
 	if o.ImageTag == nil {
 		o.ImageTag = new(string)
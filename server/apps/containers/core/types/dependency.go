@@ -0,0 +1,119 @@
+package types
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/vertex-center/uuid"
+)
+
+type DependencyCondition string
+
+const (
+	DependencyConditionRunning DependencyCondition = "running"
+	DependencyConditionHealthy DependencyCondition = "healthy"
+)
+
+var (
+	ErrDependencyNotFound = errors.NotFoundf("dependency")
+	ErrInvalidDependency  = errors.NotValidf("dependency")
+	ErrDependencyCycle    = errors.NotValidf("dependency cycle")
+)
+
+type (
+	Dependencies []Dependency
+	Dependency   struct {
+		ContainerID uuid.UUID           `json:"container_id"  db:"container_id"  example:"d1fb743c-f937-4f3d-95b9-1a8475464591"`
+		DependsOnID uuid.UUID           `json:"depends_on_id" db:"depends_on_id" example:"7e63ced7-4f4e-4b79-95ca-62930866f7bc"`
+		WaitFor     DependencyCondition `json:"wait_for"      db:"wait_for"      enum:"running,healthy"` // Status the dependency must reach before the container is started.
+	}
+)
+
+func (d *Dependency) Validate() error {
+	if d.WaitFor == "" {
+		d.WaitFor = DependencyConditionRunning
+	}
+	if d.WaitFor != DependencyConditionRunning && d.WaitFor != DependencyConditionHealthy {
+		return errors.NewNotValid(ErrInvalidDependency, "unknown wait condition")
+	}
+	if d.ContainerID == d.DependsOnID {
+		return errors.NewNotValid(ErrInvalidDependency, "a container cannot depend on itself")
+	}
+	return nil
+}
+
+// Of returns the dependencies of the given container.
+func (d Dependencies) Of(id uuid.UUID) Dependencies {
+	var deps Dependencies
+	for _, dep := range d {
+		if dep.ContainerID == id {
+			deps = append(deps, dep)
+		}
+	}
+	return deps
+}
+
+// Sort returns the ids ordered so that each container comes after the
+// containers it depends on. Dependencies on containers that are not in ids
+// are ignored. Containers without order constraints keep their order.
+func (d Dependencies) Sort(ids []uuid.UUID) ([]uuid.UUID, error) {
+	in := map[uuid.UUID]bool{}
+	for _, id := range ids {
+		in[id] = true
+	}
+
+	// remaining counts the dependencies not yet sorted of each container.
+	remaining := map[uuid.UUID]int{}
+	dependents := map[uuid.UUID][]uuid.UUID{}
+	for _, dep := range d {
+		if !in[dep.ContainerID] || !in[dep.DependsOnID] {
+			continue
+		}
+		remaining[dep.ContainerID]++
+		dependents[dep.DependsOnID] = append(dependents[dep.DependsOnID], dep.ContainerID)
+	}
+
+	sorted := make([]uuid.UUID, 0, len(ids))
+	done := map[uuid.UUID]bool{}
+	for len(sorted) < len(ids) {
+		progress := false
+		for _, id := range ids {
+			if done[id] || remaining[id] > 0 {
+				continue
+			}
+			done[id] = true
+			progress = true
+			sorted = append(sorted, id)
+			for _, dependent := range dependents[id] {
+				remaining[dependent]--
+			}
+		}
+
+		if !progress {
+			var cycle []string
+			for _, id := range ids {
+				if !done[id] {
+					cycle = append(cycle, id.String())
+				}
+			}
+			return nil, errors.NewNotValid(ErrDependencyCycle, "dependency cycle between containers "+strings.Join(cycle, ", "))
+		}
+	}
+	return sorted, nil
+}
+
+// CheckCycles returns ErrDependencyCycle if the dependencies contain a cycle.
+func (d Dependencies) CheckCycles() error {
+	var ids []uuid.UUID
+	seen := map[uuid.UUID]bool{}
+	for _, dep := range d {
+		for _, id := range []uuid.UUID{dep.ContainerID, dep.DependsOnID} {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	_, err := d.Sort(ids)
+	return err
+}
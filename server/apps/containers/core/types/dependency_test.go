@@ -0,0 +1,63 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/juju/errors"
+	"github.com/stretchr/testify/suite"
+	"github.com/vertex-center/uuid"
+)
+
+type DependencyTestSuite struct {
+	suite.Suite
+}
+
+func TestDependencyTestSuite(t *testing.T) {
+	suite.Run(t, new(DependencyTestSuite))
+}
+
+func (suite *DependencyTestSuite) TestSort() {
+	app, db, cache, other := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	deps := Dependencies{
+		{ContainerID: app, DependsOnID: db},
+		{ContainerID: app, DependsOnID: cache},
+		{ContainerID: cache, DependsOnID: db},
+	}
+
+	sorted, err := deps.Sort([]uuid.UUID{app, other, cache, db})
+	suite.Require().NoError(err)
+	suite.Equal([]uuid.UUID{other, db, cache, app}, sorted)
+
+	// Dependencies on containers that are not sorted are ignored.
+	sorted, err = deps.Sort([]uuid.UUID{app, cache})
+	suite.Require().NoError(err)
+	suite.Equal([]uuid.UUID{cache, app}, sorted)
+}
+
+func (suite *DependencyTestSuite) TestSortCycle() {
+	a, b, c := uuid.New(), uuid.New(), uuid.New()
+	deps := Dependencies{
+		{ContainerID: a, DependsOnID: b},
+		{ContainerID: b, DependsOnID: c},
+		{ContainerID: c, DependsOnID: a},
+	}
+
+	_, err := deps.Sort([]uuid.UUID{a, b, c})
+	suite.True(errors.Is(err, ErrDependencyCycle))
+	suite.True(errors.Is(deps.CheckCycles(), errors.NotValid))
+	suite.NoError(deps[:2].CheckCycles())
+}
+
+func (suite *DependencyTestSuite) TestValidate() {
+	a, b := uuid.New(), uuid.New()
+
+	dep := Dependency{ContainerID: a, DependsOnID: b}
+	suite.Require().NoError(dep.Validate())
+	suite.Equal(DependencyConditionRunning, dep.WaitFor)
+
+	dep = Dependency{ContainerID: a, DependsOnID: a}
+	suite.True(errors.Is(dep.Validate(), errors.NotValid))
+
+	dep = Dependency{ContainerID: a, DependsOnID: b, WaitFor: "stopped"}
+	suite.True(errors.Is(dep.Validate(), errors.NotValid))
+}
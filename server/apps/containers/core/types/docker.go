@@ -1,6 +1,8 @@
 package types
 
 import (
+	"strings"
+
 	dockertypes "github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/mount"
@@ -16,6 +18,8 @@ type DockerContainer struct {
 }
 
 type Mount struct {
+	Type        string `json:"type,omitempty"`
+	Name        string `json:"name,omitempty"` // Name of the volume, for volume mounts.
 	Source      string `json:"source,omitempty"`
 	Destination string `json:"destination,omitempty"`
 }
@@ -31,22 +35,63 @@ type CreateDockerContainerOptions struct {
 	CapAdd        []string          `json:"cap_add,omitempty"`
 	Sysctls       map[string]string `json:"sysctls,omitempty"`
 	Cmd           []string          `json:"cmd,omitempty"`
+
+	Healthcheck *container.HealthConfig `json:"healthcheck,omitempty"`
+	Resources   *container.Resources    `json:"resources,omitempty"`
+
+	Networks []NetworkAttachment `json:"networks,omitempty"`
 }
 
 type BuildImageOptions struct {
-	Dir        string `json:"dir,omitempty"`
-	Name       string `json:"name,omitempty"`
-	Dockerfile string `json:"dockerfile,omitempty"`
+	Dir        string             `json:"dir,omitempty"`
+	Name       string             `json:"name,omitempty"`
+	Dockerfile string             `json:"dockerfile,omitempty"`
+	BuildArgs  map[string]*string `json:"build_args,omitempty"`
+	Labels     map[string]string  `json:"labels,omitempty"`
+}
+
+type ArchiveVolumeOptions struct {
+	Type VolumeType `json:"type"`
+	Name string     `json:"name"` // Name of the volume, or path on the host of the bind mount.
+	Dest string     `json:"dest"` // Path of the tar.gz archive to create.
+}
+
+type ArchiveVolumeResponse struct {
+	Size     int64  `json:"size"`
+	Checksum string `json:"checksum"`
+}
+
+type RestoreVolumeOptions struct {
+	Type     VolumeType `json:"type"`
+	Name     string     `json:"name"`
+	Src      string     `json:"src"`      // Path of the tar.gz archive to restore.
+	Checksum string     `json:"checksum"` // Expected checksum of the archive.
 }
 
 type PullImageOptions struct {
-	Image string `json:"image,omitempty"`
+	Image string        `json:"image,omitempty"`
+	Auth  *RegistryAuth `json:"auth,omitempty"`
 }
 
 type CreateVolumeOptions struct {
 	Name string `json:"name,omitempty"`
 }
 
+type CreateNetworkOptions struct {
+	Name string `json:"name,omitempty"`
+}
+
+type ConnectNetworkOptions struct {
+	Network   string   `json:"network"`
+	Container string   `json:"container"`
+	Aliases   []string `json:"aliases,omitempty"`
+}
+
+type DisconnectNetworkOptions struct {
+	Network   string `json:"network"`
+	Container string `json:"container"`
+}
+
 type CreateContainerResponse struct {
 	ID       string   `json:"id,omitempty"`
 	Warnings []string `json:"warnings,omitempty"`
@@ -58,14 +103,45 @@ type InfoContainerResponse struct {
 	Platform     string   `json:"platform,omitempty"`
 	Image        string   `json:"image,omitempty"`
 	PortBindings []string `json:"port_bindings,omitempty"`
+	Health       string   `json:"health,omitempty"`
+	ExitCode     int      `json:"exit_code"`
+}
+
+// InspectContainerResponse is the configuration of a Docker container.
+type InspectContainerResponse struct {
+	ID                string              `json:"id,omitempty"`
+	Name              string              `json:"name,omitempty"`
+	Image             string              `json:"image,omitempty"` // Image reference the container was created from.
+	Env               []string            `json:"env,omitempty"`
+	ImageEnv          []string            `json:"image_env,omitempty"` // Env inherited from the image.
+	Cmd               []string            `json:"cmd,omitempty"`
+	ImageCmd          []string            `json:"image_cmd,omitempty"` // Cmd inherited from the image.
+	PortBindings      []DockerPortBinding `json:"port_bindings,omitempty"`
+	Mounts            []Mount             `json:"mounts,omitempty"`
+	CapAdd            []string            `json:"cap_add,omitempty"`
+	Sysctls           map[string]string   `json:"sysctls,omitempty"`
+	RestartPolicy     string              `json:"restart_policy,omitempty"`
+	RestartMaxRetries int                 `json:"restart_max_retries,omitempty"`
+}
+
+type DockerPortBinding struct {
+	Container string `json:"container"` // Port in the container, with its protocol, like 53/udp.
+	Host      string `json:"host"`
+	HostIP    string `json:"host_ip,omitempty"` // Address the port is published on. Empty for all interfaces.
+}
+
+type RenameContainerOptions struct {
+	Name string `json:"name"`
 }
 
 type InfoImageResponse struct {
-	ID           string   `json:"id,omitempty"`
-	Architecture string   `json:"architecture,omitempty"`
-	OS           string   `json:"os,omitempty"`
-	Size         int64    `json:"size,omitempty"`
-	Tags         []string `json:"tags,omitempty"`
+	ID           string            `json:"id,omitempty"`
+	Architecture string            `json:"architecture,omitempty"`
+	OS           string            `json:"os,omitempty"`
+	Size         int64             `json:"size,omitempty"`
+	Tags         []string          `json:"tags,omitempty"`
+	Digests      []string          `json:"digests,omitempty"`
+	Labels       map[string]string `json:"labels,omitempty"`
 }
 
 type WaitContainerCondition container.WaitCondition
@@ -77,6 +153,11 @@ func NewDockerContainer(c dockertypes.Container) DockerContainer {
 		state = ContainerStatusOff
 	case "running":
 		state = ContainerStatusRunning
+		if strings.Contains(c.Status, "(unhealthy)") {
+			state = ContainerStatusUnhealthy
+		} else if strings.Contains(c.Status, "(healthy)") {
+			state = ContainerStatusHealthy
+		}
 	case "paused":
 		state = "Paused"
 	case "restarting":
@@ -106,6 +187,8 @@ func NewMounts(m []dockertypes.MountPoint) []Mount {
 
 func NewMount(m dockertypes.MountPoint) Mount {
 	return Mount{
+		Type:        string(m.Type),
+		Name:        m.Name,
 		Source:      m.Source,
 		Destination: m.Destination,
 	}
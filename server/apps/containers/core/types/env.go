@@ -12,7 +12,13 @@ const (
 	EnvVariableTypePort   EnvVariableType = "port"
 )
 
-var ErrInvalidEnvVariableName = errors.NotValidf("environment variable name")
+var (
+	ErrEnvVariableNotFound    = errors.NotFoundf("environment variable")
+	ErrInvalidEnvVariableName = errors.NotValidf("environment variable name")
+)
+
+// SecretRedacted replaces the values of the secret variables in the responses.
+const SecretRedacted = "********"
 
 type (
 	EnvVariable struct {
@@ -32,6 +38,13 @@ type (
 	}
 )
 
+// Redact hides the value of a secret variable.
+func (v *EnvVariable) Redact() {
+	if v.Secret {
+		v.Value = SecretRedacted
+	}
+}
+
 func (v *EnvVariable) Validate() error {
 	if v.Name == "" {
 		return ErrInvalidEnvVariableName
@@ -0,0 +1,25 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type EnvTestSuite struct {
+	suite.Suite
+}
+
+func TestEnvTestSuite(t *testing.T) {
+	suite.Run(t, new(EnvTestSuite))
+}
+
+func (suite *EnvTestSuite) TestRedact() {
+	v := EnvVariable{Name: "POSTGRES_PASSWORD", Value: "enc:v1:c2VjcmV0", Secret: true}
+	v.Redact()
+	suite.Equal(SecretRedacted, v.Value)
+
+	v = EnvVariable{Name: "POSTGRES_USER", Value: "postgres"}
+	v.Redact()
+	suite.Equal("postgres", v.Value)
+}
@@ -1,6 +1,10 @@
 package types
 
-import "github.com/vertex-center/uuid"
+import (
+	"time"
+
+	"github.com/vertex-center/uuid"
+)
 
 const (
 	EventNameContainersChange      = "change"
@@ -8,6 +12,7 @@ const (
 	EventNameContainerStdout       = "stdout"
 	EventNameContainerStderr       = "stderr"
 	EventNameContainerDownload     = "download"
+	EventNameContainerStats        = "stats"
 )
 
 type (
@@ -15,6 +20,11 @@ type (
 		ContainerID uuid.UUID
 		Kind        string
 		Message     LogLineMessage
+
+		// Time, Level and Fields are set for the lines printed by the container.
+		Time   time.Time
+		Level  string
+		Fields map[string]any
 	}
 
 	EventContainerStatusChange struct {
@@ -24,8 +34,37 @@ type (
 		Status      string
 	}
 
+	EventContainerUpdateAvailable struct {
+		ContainerID uuid.UUID
+		Name        string
+		Update      ContainerUpdate
+	}
+
+	EventContainerUpdated struct {
+		ContainerID   uuid.UUID
+		Name          string
+		PreviousImage string
+		Image         string
+	}
+
+	EventContainerUpdateRolledBack struct {
+		ContainerID uuid.UUID
+		Name        string
+		Image       string // The image the container rolled back to.
+		Reason      string
+	}
+
+	EventContainerStats struct {
+		ContainerID uuid.UUID
+		Stats       ContainerStats
+	}
+
 	EventContainerDeleted struct{ ContainerID uuid.UUID }
 	EventContainersLoaded struct{ Count int }
 	EventContainerCreated struct{}
 	EventContainersChange struct{}
+
+	// EventTemplatesReloaded is dispatched once the templates of the sources
+	// are reloaded.
+	EventTemplatesReloaded struct{}
 )
@@ -0,0 +1,24 @@
+package types
+
+const (
+	ExecMessageInput  = "input"
+	ExecMessageResize = "resize"
+)
+
+// DefaultExecCmd is the command run by a terminal when none is given.
+var DefaultExecCmd = []string{"/bin/sh"}
+
+type ExecOptions struct {
+	Cmd  []string `json:"cmd,omitempty"`
+	Cols uint     `json:"cols,omitempty"`
+	Rows uint     `json:"rows,omitempty"`
+}
+
+// ExecMessage is a message sent by a terminal to an exec session. The output
+// of the session is sent back as binary messages.
+type ExecMessage struct {
+	Type string `json:"type"`
+	Data string `json:"data,omitempty"`
+	Cols uint   `json:"cols,omitempty"`
+	Rows uint   `json:"rows,omitempty"`
+}
@@ -0,0 +1,169 @@
+package types
+
+import (
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/juju/errors"
+	"github.com/vertex-center/uuid"
+)
+
+type HealthCheckType string
+
+const (
+	HealthCheckTypeHTTP HealthCheckType = "http"
+	HealthCheckTypeTCP  HealthCheckType = "tcp"
+	HealthCheckTypeExec HealthCheckType = "exec"
+)
+
+const (
+	DefaultHealthCheckInterval    = "30s"
+	DefaultHealthCheckTimeout     = "10s"
+	DefaultHealthCheckStartPeriod = "0s"
+	DefaultHealthCheckRetries     = 3
+)
+
+var (
+	ErrHealthCheckNotFound = errors.NotFoundf("health check")
+	ErrInvalidHealthCheck  = errors.NotValidf("health check")
+)
+
+type HealthCheck struct {
+	ID          uuid.UUID       `json:"id"                db:"id"             example:"7e63ced7-4f4e-4b79-95ca-62930866f7bc"`
+	ContainerID uuid.UUID       `json:"container_id"      db:"container_id"   example:"d1fb743c-f937-4f3d-95b9-1a8475464591"`
+	Type        HealthCheckType `json:"type"              db:"type"           enum:"http,tcp,exec"`
+	Port        *string         `json:"port,omitempty"    db:"port"           example:"8080"`       // Port probed inside the container, for http and tcp.
+	Path        *string         `json:"path,omitempty"    db:"path"           example:"/health"`    // Path requested, for http.
+	Command     *string         `json:"command,omitempty" db:"command"        example:"pg_isready"` // Command run inside the container, for exec.
+	Interval    string          `json:"interval"          db:"check_interval" example:"30s"`
+	Timeout     string          `json:"timeout"           db:"check_timeout"  example:"10s"`
+	StartPeriod string          `json:"start_period"      db:"start_period"   example:"0s"`
+	Retries     int             `json:"retries"           db:"retries"        example:"3"`
+}
+
+// FillDefaults sets the default values of the timing fields that are not set.
+func (h *HealthCheck) FillDefaults() {
+	if h.Interval == "" {
+		h.Interval = DefaultHealthCheckInterval
+	}
+	if h.Timeout == "" {
+		h.Timeout = DefaultHealthCheckTimeout
+	}
+	if h.StartPeriod == "" {
+		h.StartPeriod = DefaultHealthCheckStartPeriod
+	}
+	if h.Retries == 0 {
+		h.Retries = DefaultHealthCheckRetries
+	}
+}
+
+func (h *HealthCheck) Validate() error {
+	h.FillDefaults()
+
+	switch h.Type {
+	case HealthCheckTypeHTTP, HealthCheckTypeTCP:
+		if h.Port == nil || *h.Port == "" {
+			return errors.NewNotValid(ErrInvalidHealthCheck, "a port is required for http and tcp health checks")
+		}
+	case HealthCheckTypeExec:
+		if h.Command == nil || *h.Command == "" {
+			return errors.NewNotValid(ErrInvalidHealthCheck, "a command is required for exec health checks")
+		}
+	default:
+		return errors.NewNotValid(ErrInvalidHealthCheck, "unknown health check type")
+	}
+
+	for _, d := range []string{h.Interval, h.Timeout, h.StartPeriod} {
+		if _, err := time.ParseDuration(d); err != nil {
+			return errors.NewNotValid(err, "invalid health check duration")
+		}
+	}
+
+	if h.Retries < 0 {
+		return errors.NewNotValid(ErrInvalidHealthCheck, "retries must be positive")
+	}
+	return nil
+}
+
+// HealthCheck converts the template health check into the health check of a
+// container.
+func (t TemplateHealthCheck) HealthCheck(containerID uuid.UUID) HealthCheck {
+	hc := HealthCheck{
+		ID:          uuid.New(),
+		ContainerID: containerID,
+		Type:        HealthCheckType(t.Type),
+		Port:        t.Port,
+		Path:        t.Path,
+		Command:     t.Command,
+	}
+	if t.Interval != nil {
+		hc.Interval = *t.Interval
+	}
+	if t.Timeout != nil {
+		hc.Timeout = *t.Timeout
+	}
+	if t.StartPeriod != nil {
+		hc.StartPeriod = *t.StartPeriod
+	}
+	if t.Retries != nil {
+		hc.Retries = *t.Retries
+	}
+	return hc
+}
+
+// Test returns the Docker HEALTHCHECK test command of this health check.
+// HTTP and TCP probes are run from inside the container, so the image
+// must provide wget/curl or nc respectively.
+func (h *HealthCheck) Test() []string {
+	switch h.Type {
+	case HealthCheckTypeHTTP:
+		p := "/"
+		if h.Path != nil && *h.Path != "" {
+			p = "/" + strings.TrimPrefix(*h.Path, "/")
+		}
+		url := "http://localhost:" + *h.Port + p
+		return []string{"CMD-SHELL", "wget -q --spider " + url + " || curl -fs -o /dev/null " + url + " || exit 1"}
+	case HealthCheckTypeTCP:
+		return []string{"CMD-SHELL", "nc -z localhost " + *h.Port + " || exit 1"}
+	case HealthCheckTypeExec:
+		return []string{"CMD-SHELL", *h.Command + " || exit 1"}
+	}
+	return nil
+}
+
+// DockerConfig converts the health check into the Docker health configuration.
+func (h *HealthCheck) DockerConfig() *container.HealthConfig {
+	interval, _ := time.ParseDuration(h.Interval)
+	timeout, _ := time.ParseDuration(h.Timeout)
+	startPeriod, _ := time.ParseDuration(h.StartPeriod)
+
+	return &container.HealthConfig{
+		Test:        h.Test(),
+		Interval:    interval,
+		Timeout:     timeout,
+		StartPeriod: startPeriod,
+		Retries:     h.Retries,
+	}
+}
+
+// PollInterval returns how often Vertex should read the health status of the container.
+func (h *HealthCheck) PollInterval() time.Duration {
+	interval, err := time.ParseDuration(h.Interval)
+	if err != nil || interval < time.Second {
+		return time.Second
+	}
+	return interval
+}
+
+// StatusFromDockerHealth converts a Docker health status into a container status.
+// The container stays "running" while Docker has not decided yet.
+func StatusFromDockerHealth(health string) string {
+	switch health {
+	case "healthy":
+		return ContainerStatusHealthy
+	case "unhealthy":
+		return ContainerStatusUnhealthy
+	}
+	return ContainerStatusRunning
+}
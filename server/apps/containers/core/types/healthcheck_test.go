@@ -0,0 +1,67 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/stretchr/testify/suite"
+)
+
+type HealthCheckTestSuite struct {
+	suite.Suite
+}
+
+func TestHealthCheckTestSuite(t *testing.T) {
+	suite.Run(t, new(HealthCheckTestSuite))
+}
+
+func (suite *HealthCheckTestSuite) TestValidate() {
+	port := "8080"
+	command := "pg_isready"
+
+	hc := HealthCheck{Type: HealthCheckTypeHTTP, Port: &port}
+	suite.Require().NoError(hc.Validate())
+	suite.Equal(DefaultHealthCheckInterval, hc.Interval)
+	suite.Equal(DefaultHealthCheckRetries, hc.Retries)
+
+	hc = HealthCheck{Type: HealthCheckTypeExec, Command: &command}
+	suite.NoError(hc.Validate())
+
+	hc = HealthCheck{Type: HealthCheckTypeTCP}
+	suite.True(errors.Is(hc.Validate(), errors.NotValid))
+
+	hc = HealthCheck{Type: "unknown", Port: &port}
+	suite.True(errors.Is(hc.Validate(), errors.NotValid))
+
+	hc = HealthCheck{Type: HealthCheckTypeTCP, Port: &port, Interval: "often"}
+	suite.True(errors.Is(hc.Validate(), errors.NotValid))
+}
+
+func (suite *HealthCheckTestSuite) TestDockerConfig() {
+	port := "8080"
+	path := "health"
+	hc := HealthCheck{
+		Type:        HealthCheckTypeHTTP,
+		Port:        &port,
+		Path:        &path,
+		Interval:    "5s",
+		Timeout:     "2s",
+		StartPeriod: "1m",
+		Retries:     2,
+	}
+
+	config := hc.DockerConfig()
+
+	suite.Equal([]string{"CMD-SHELL", "wget -q --spider http://localhost:8080/health || curl -fs -o /dev/null http://localhost:8080/health || exit 1"}, config.Test)
+	suite.Equal(5*time.Second, config.Interval)
+	suite.Equal(2*time.Second, config.Timeout)
+	suite.Equal(time.Minute, config.StartPeriod)
+	suite.Equal(2, config.Retries)
+}
+
+func (suite *HealthCheckTestSuite) TestStatusFromDockerHealth() {
+	suite.Equal(ContainerStatusHealthy, StatusFromDockerHealth("healthy"))
+	suite.Equal(ContainerStatusUnhealthy, StatusFromDockerHealth("unhealthy"))
+	suite.Equal(ContainerStatusRunning, StatusFromDockerHealth("starting"))
+}
@@ -4,6 +4,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/vertex-center/vertex/server/common/log"
 )
@@ -17,14 +21,142 @@ const (
 	LogKindVertexErr = "vertex_err"
 )
 
+const (
+	LogLevelTrace = "trace"
+	LogLevelDebug = "debug"
+	LogLevelInfo  = "info"
+	LogLevelWarn  = "warn"
+	LogLevelError = "error"
+	LogLevelFatal = "fatal"
+)
+
 var ErrBufferEmpty = errors.New("the buffer is empty")
 
+var (
+	// logLevelKeys are the fields holding the level in JSON log lines.
+	logLevelKeys = []string{"level", "lvl", "severity", "log.level", "loglevel"}
+
+	logLevels = map[string]string{
+		"trace":       LogLevelTrace,
+		"debug":       LogLevelDebug,
+		"dbg":         LogLevelDebug,
+		"info":        LogLevelInfo,
+		"information": LogLevelInfo,
+		"notice":      LogLevelInfo,
+		"warn":        LogLevelWarn,
+		"warning":     LogLevelWarn,
+		"error":       LogLevelError,
+		"err":         LogLevelError,
+		"fatal":       LogLevelFatal,
+		"critical":    LogLevelFatal,
+		"crit":        LogLevelFatal,
+		"panic":       LogLevelFatal,
+		"emerg":       LogLevelFatal,
+		"alert":       LogLevelFatal,
+	}
+
+	ansiRegexp = regexp.MustCompile(`\x1b\[[0-9;]*[a-zA-Z]`)
+
+	// Levels in text lines are either a key=value pair, or an uppercase word
+	// to avoid matching regular words of the message.
+	logLevelPairRegexp = regexp.MustCompile(`(?i)\b(?:level|lvl|severity)=["']?(\w+)`)
+	logLevelWordRegexp = regexp.MustCompile(`\b(TRACE|DEBUG|INFO|NOTICE|WARN|WARNING|ERROR|ERR|FATAL|CRITICAL|CRIT|PANIC)\b`)
+)
+
+const (
+	DefaultLogsLimit = 100
+	MaxLogsLimit     = 1000
+)
+
 type LogLine struct {
 	Id      int            `json:"id"`
+	Time    time.Time      `json:"time"`
 	Kind    string         `json:"kind"`
+	Level   string         `json:"level,omitempty"`
+	Fields  map[string]any `json:"fields,omitempty"` // Fields of the JSON log lines.
 	Message LogLineMessage `json:"message"`
 }
 
+// ParseLogLine parses a line printed by a container. The timestamp added by
+// Docker is removed from the message, JSON lines are parsed into fields, and
+// the level is detected.
+func ParseLogLine(kind string, text string) LogLine {
+	line := LogLine{Kind: kind}
+
+	if ts, msg, ok := strings.Cut(text, " "); ok {
+		if t, err := time.Parse(time.RFC3339Nano, ts); err == nil {
+			line.Time = t
+			text = msg
+		}
+	}
+
+	if strings.HasPrefix(strings.TrimSpace(text), "{") {
+		var fields map[string]any
+		if json.Unmarshal([]byte(text), &fields) == nil {
+			line.Fields = fields
+		}
+	}
+
+	line.Level = parseLogLevel(text, line.Fields)
+	line.Message = NewLogLineMessageString(text)
+	return line
+}
+
+func parseLogLevel(text string, fields map[string]any) string {
+	if fields != nil {
+		for _, key := range logLevelKeys {
+			if v, ok := fields[key].(string); ok {
+				return normalizeLogLevel(v)
+			}
+		}
+		return ""
+	}
+
+	text = ansiRegexp.ReplaceAllString(text, "")
+	if m := logLevelPairRegexp.FindStringSubmatch(text); m != nil {
+		return normalizeLogLevel(m[1])
+	}
+	if m := logLevelWordRegexp.FindStringSubmatch(text); m != nil {
+		return normalizeLogLevel(m[1])
+	}
+	return ""
+}
+
+func normalizeLogLevel(level string) string {
+	return logLevels[strings.ToLower(level)]
+}
+
+// LogFilters filters the log history of a container. The most recent lines
+// are kept when more than Limit lines match.
+type LogFilters struct {
+	Since  time.Time // Since is inclusive.
+	Until  time.Time // Until is exclusive, to get the previous page from the oldest line.
+	Query  string    // Query is searched in the messages, ignoring the case.
+	Kinds  []string
+	Levels []string
+	Limit  int
+}
+
+func (f LogFilters) IsZero() bool {
+	return f.Since.IsZero() && f.Until.IsZero() && f.Query == "" && len(f.Kinds) == 0 && len(f.Levels) == 0 && f.Limit == 0
+}
+
+func (f LogFilters) Match(line LogLine) bool {
+	if !f.Since.IsZero() && line.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !line.Time.Before(f.Until) {
+		return false
+	}
+	if len(f.Kinds) > 0 && !slices.Contains(f.Kinds, line.Kind) {
+		return false
+	}
+	if len(f.Levels) > 0 && !slices.Contains(f.Levels, line.Level) {
+		return false
+	}
+	return f.Query == "" || strings.Contains(strings.ToLower(line.Message.String()), strings.ToLower(f.Query))
+}
+
 type LogLineMessage interface {
 	String() string
 }
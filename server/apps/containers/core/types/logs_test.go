@@ -0,0 +1,73 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type LogsTestSuite struct {
+	suite.Suite
+}
+
+func TestLogsTestSuite(t *testing.T) {
+	suite.Run(t, new(LogsTestSuite))
+}
+
+func (suite *LogsTestSuite) TestParseLogLineTimestamp() {
+	line := ParseLogLine(LogKindOut, "2024-01-02T03:04:05.123456789Z Server started")
+	suite.Equal(time.Date(2024, 1, 2, 3, 4, 5, 123456789, time.UTC), line.Time.UTC())
+	suite.Equal("Server started", line.Message.String())
+	suite.Equal(LogKindOut, line.Kind)
+
+	line = ParseLogLine(LogKindOut, "Step 1/2 : FROM alpine")
+	suite.True(line.Time.IsZero())
+	suite.Equal("Step 1/2 : FROM alpine", line.Message.String())
+}
+
+func (suite *LogsTestSuite) TestParseLogLineJSON() {
+	line := ParseLogLine(LogKindErr, `2024-01-02T03:04:05Z {"severity":"WARNING","msg":"disk almost full","used":0.95}`)
+	suite.Equal(LogLevelWarn, line.Level)
+	suite.Equal("disk almost full", line.Fields["msg"])
+	suite.Equal(0.95, line.Fields["used"])
+
+	// Only the JSON fields are used to find the level.
+	line = ParseLogLine(LogKindOut, `{"msg":"ERROR is not the level"}`)
+	suite.Empty(line.Level)
+	suite.NotNil(line.Fields)
+
+	line = ParseLogLine(LogKindOut, `{not json`)
+	suite.Nil(line.Fields)
+}
+
+func (suite *LogsTestSuite) TestParseLogLineLevel() {
+	tests := map[string]string{
+		`time="2024-01-02" level=debug msg="cache miss"`: LogLevelDebug,
+		"[ERROR] connection refused":                     LogLevelError,
+		"2024/01/02 03:04:05 WARN retrying":              LogLevelWarn,
+		"\x1b[32mINFO\x1b[0m listening on :80":           LogLevelInfo,
+		"FATAL: out of memory":                           LogLevelFatal,
+		"no error here":                                  "",
+	}
+	for text, level := range tests {
+		suite.Equal(level, ParseLogLine(LogKindOut, text).Level, text)
+	}
+}
+
+func (suite *LogsTestSuite) TestLogFiltersMatch() {
+	now := time.Now()
+	line := LogLine{
+		Time:    now,
+		Kind:    LogKindOut,
+		Level:   LogLevelError,
+		Message: NewLogLineMessageString("Connection Refused"),
+	}
+
+	suite.True(LogFilters{}.Match(line))
+	suite.True(LogFilters{Since: now, Query: "refused", Levels: []string{LogLevelError}}.Match(line))
+	suite.False(LogFilters{Until: now}.Match(line))
+	suite.False(LogFilters{Kinds: []string{LogKindErr}}.Match(line))
+	suite.False(LogFilters{Levels: []string{LogLevelInfo}}.Match(line))
+	suite.False(LogFilters{Query: "accepted"}.Match(line))
+}
@@ -0,0 +1,74 @@
+package types
+
+import (
+	"regexp"
+
+	"github.com/juju/errors"
+	"github.com/vertex-center/uuid"
+)
+
+var (
+	ErrNetworkNotFound      = errors.NotFoundf("network")
+	ErrNetworkAlreadyExists = errors.AlreadyExistsf("network")
+	ErrNetworkInUse         = errors.NotValidf("network in use")
+	ErrInvalidNetwork       = errors.NotValidf("network")
+)
+
+// networkNameRegexp matches the names accepted by Docker for networks and
+// network aliases.
+var networkNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+type (
+	Networks []Network
+	Network  struct {
+		ID        uuid.UUID `json:"id"         db:"id"         example:"3b2f8c4e-1d7a-4e6b-9f0c-5a8d2e1b7c9f"`
+		Name      string    `json:"name"       db:"name"       example:"backend"`
+		CreatedAt int64     `json:"created_at" db:"created_at" example:"1700000000"`
+	}
+
+	// ContainerNetwork attaches a container to a network. The container is
+	// reachable by the other containers of the network with its aliases.
+	ContainerNetworks []ContainerNetwork
+	ContainerNetwork  struct {
+		ContainerID uuid.UUID `json:"container_id" db:"container_id" example:"d1fb743c-f937-4f3d-95b9-1a8475464591"`
+		NetworkID   uuid.UUID `json:"network_id"   db:"network_id"   example:"3b2f8c4e-1d7a-4e6b-9f0c-5a8d2e1b7c9f"`
+		Aliases     []string  `json:"aliases"      db:"-"            example:"postgres,db"`
+	}
+
+	// NetworkAttachment is a Docker network a container is connected to.
+	NetworkAttachment struct {
+		Name    string   `json:"name"              example:"VERTEX_NETWORK_3b2f8c4e-1d7a-4e6b-9f0c-5a8d2e1b7c9f"`
+		Aliases []string `json:"aliases,omitempty" example:"postgres"`
+	}
+)
+
+func (n *Network) Validate() error {
+	if !networkNameRegexp.MatchString(n.Name) {
+		return errors.NewNotValid(ErrInvalidNetwork, "invalid network name: "+n.Name)
+	}
+	return nil
+}
+
+// DockerName returns the name of the Docker network.
+func (n *Network) DockerName() string {
+	return "VERTEX_NETWORK_" + n.ID.String()
+}
+
+func (n *ContainerNetwork) Validate() error {
+	for _, alias := range n.Aliases {
+		if !networkNameRegexp.MatchString(alias) {
+			return errors.NewNotValid(ErrInvalidNetwork, "invalid network alias: "+alias)
+		}
+	}
+	return nil
+}
+
+// Find returns the attachment to the given network, or nil.
+func (n ContainerNetworks) Find(networkID uuid.UUID) *ContainerNetwork {
+	for i := range n {
+		if n[i].NetworkID == networkID {
+			return &n[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/juju/errors"
+	"github.com/stretchr/testify/suite"
+	"github.com/vertex-center/uuid"
+)
+
+type NetworkTestSuite struct {
+	suite.Suite
+}
+
+func TestNetworkTestSuite(t *testing.T) {
+	suite.Run(t, new(NetworkTestSuite))
+}
+
+func (suite *NetworkTestSuite) TestValidate() {
+	for _, name := range []string{"backend", "my-network", "net_1.local"} {
+		n := Network{Name: name}
+		suite.NoError(n.Validate(), name)
+	}
+
+	for _, name := range []string{"", "-backend", "my network", "net/1"} {
+		n := Network{Name: name}
+		suite.True(errors.Is(n.Validate(), errors.NotValid), name)
+	}
+}
+
+func (suite *NetworkTestSuite) TestValidateAliases() {
+	cn := ContainerNetwork{Aliases: []string{"postgres", "db"}}
+	suite.NoError(cn.Validate())
+
+	cn.Aliases = append(cn.Aliases, "my db")
+	suite.True(errors.Is(cn.Validate(), errors.NotValid))
+}
+
+func (suite *NetworkTestSuite) TestFind() {
+	a, b := uuid.New(), uuid.New()
+	networks := ContainerNetworks{
+		{NetworkID: a, Aliases: []string{"postgres"}},
+	}
+
+	suite.Equal([]string{"postgres"}, networks.Find(a).Aliases)
+	suite.Nil(networks.Find(b))
+}
+
+func (suite *NetworkTestSuite) TestDockerName() {
+	n := Network{ID: uuid.MustParse("3b2f8c4e-1d7a-4e6b-9f0c-5a8d2e1b7c9f")}
+	suite.Equal("VERTEX_NETWORK_3b2f8c4e-1d7a-4e6b-9f0c-5a8d2e1b7c9f", n.DockerName())
+}
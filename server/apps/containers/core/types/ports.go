@@ -1,19 +1,41 @@
 package types
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+
 	"github.com/juju/errors"
 	"github.com/vertex-center/uuid"
 )
 
-var ErrInvalidPort = errors.NotValidf("port")
+var (
+	ErrNoFreePort   = errors.NotFoundf("free port")
+	ErrPortNotFound = errors.NotFoundf("port")
+)
+
+const (
+	PortProtocolTCP PortProtocol = "tcp"
+	PortProtocolUDP PortProtocol = "udp"
+)
 
 type (
 	Ports []Port
 	Port  struct {
-		ID          uuid.UUID `json:"id"           db:"id"            example:"7e63ced7-4f4e-4b79-95ca-62930866f7bc"`
-		ContainerID uuid.UUID `json:"container_id" db:"container_id"  example:"d1fb743c-f937-4f3d-95b9-1a8475464591"`
-		In          string    `json:"in"           db:"internal_port" example:"5432"` // Port in the container
-		Out         string    `json:"out"          db:"external_port" example:"5432"` // Port exposed
+		ID          uuid.UUID    `json:"id"           db:"id"            example:"7e63ced7-4f4e-4b79-95ca-62930866f7bc"`
+		ContainerID uuid.UUID    `json:"container_id" db:"container_id"  example:"d1fb743c-f937-4f3d-95b9-1a8475464591"`
+		In          string       `json:"in"           db:"internal_port" example:"5432"` // Port in the container, or range like 8000-8010
+		Out         string       `json:"out"          db:"external_port" example:"5432"` // Port exposed, or range like 8000-8010
+		Protocol    PortProtocol `json:"protocol"     db:"protocol"      example:"tcp"`
+		TemplateOut *string      `json:"-"            db:"template_out"` // Port exposed when the port was created from the template, to know whether the user changed it.
+	}
+
+	PortProtocol string
+
+	// PortRange is a range of ports, from Start to End included.
+	PortRange struct {
+		Start int
+		End   int
 	}
 
 	PortFilters struct {
@@ -22,8 +44,100 @@ type (
 )
 
 func (p *Port) Validate() error {
-	if len(p.In) == 0 || len(p.Out) == 0 {
-		return ErrInvalidPort
+	if p.Protocol != PortProtocolTCP && p.Protocol != PortProtocolUDP {
+		return errors.NewNotValid(nil, fmt.Sprintf("invalid port protocol '%s'", p.Protocol))
+	}
+	in, err := ParsePortRange(p.In)
+	if err != nil {
+		return err
+	}
+	out, err := ParsePortRange(p.Out)
+	if err != nil {
+		return err
+	}
+	if in.Size() != out.Size() {
+		return errors.NewNotValid(nil, fmt.Sprintf("port ranges %s and %s have different sizes", p.Out, p.In))
+	}
+	return nil
+}
+
+// Conflicts returns whether the two ports expose a same host port.
+func (p Port) Conflicts(other Port) bool {
+	if p.ID == other.ID || p.Protocol != other.Protocol {
+		return false
+	}
+	r, err := ParsePortRange(p.Out)
+	if err != nil {
+		return false
+	}
+	o, err := ParsePortRange(other.Out)
+	if err != nil {
+		return false
+	}
+	return r.Overlaps(o)
+}
+
+// Conflict returns the first port exposing a same host port as port, or nil.
+func (p Ports) Conflict(port Port) *Port {
+	for i := range p {
+		if p[i].Conflicts(port) {
+			return &p[i]
+		}
 	}
 	return nil
 }
+
+// FreePort returns the external port of the given port if no port uses it,
+// or the next free one otherwise. Ranges are moved as a whole.
+func (p Ports) FreePort(port Port) (string, error) {
+	r, err := ParsePortRange(port.Out)
+	if err != nil {
+		return "", err
+	}
+	for ; r.End <= 65535; r.Start, r.End = r.Start+1, r.End+1 {
+		port.Out = r.String()
+		if p.Conflict(port) == nil {
+			return port.Out, nil
+		}
+	}
+	return "", ErrNoFreePort
+}
+
+// ParsePortRange parses a port like "8080", or a range like "8000-8010".
+func ParsePortRange(s string) (PortRange, error) {
+	start, end, isRange := strings.Cut(s, "-")
+
+	var (
+		r   PortRange
+		err error
+	)
+	r.Start, err = strconv.Atoi(start)
+	if err != nil {
+		return r, errors.NewNotValid(nil, fmt.Sprintf("invalid port '%s'", s))
+	}
+	r.End = r.Start
+	if isRange {
+		r.End, err = strconv.Atoi(end)
+		if err != nil {
+			return r, errors.NewNotValid(nil, fmt.Sprintf("invalid port '%s'", s))
+		}
+	}
+
+	if r.Start < 1 || r.End > 65535 || r.Start > r.End {
+		return r, errors.NewNotValid(nil, fmt.Sprintf("invalid port '%s'", s))
+	}
+	return r, nil
+}
+
+func (r PortRange) Size() int { return r.End - r.Start + 1 }
+
+func (r PortRange) Overlaps(other PortRange) bool {
+	return r.Start <= other.End && other.Start <= r.End
+}
+
+func (r PortRange) String() string {
+	if r.Start == r.End {
+		return strconv.Itoa(r.Start)
+	}
+	return strconv.Itoa(r.Start) + "-" + strconv.Itoa(r.End)
+}
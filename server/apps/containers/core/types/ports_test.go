@@ -0,0 +1,89 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/juju/errors"
+	"github.com/stretchr/testify/suite"
+	"github.com/vertex-center/uuid"
+)
+
+type PortsTestSuite struct {
+	suite.Suite
+}
+
+func TestPortsTestSuite(t *testing.T) {
+	suite.Run(t, new(PortsTestSuite))
+}
+
+func (suite *PortsTestSuite) TestParsePortRange() {
+	r, err := ParsePortRange("8080")
+	suite.NoError(err)
+	suite.Equal(PortRange{Start: 8080, End: 8080}, r)
+	suite.Equal("8080", r.String())
+
+	r, err = ParsePortRange("8000-8010")
+	suite.NoError(err)
+	suite.Equal(PortRange{Start: 8000, End: 8010}, r)
+	suite.Equal(11, r.Size())
+	suite.Equal("8000-8010", r.String())
+
+	for _, s := range []string{"", "http", "0", "65536", "8010-8000", "8000-"} {
+		_, err = ParsePortRange(s)
+		suite.True(errors.Is(err, errors.NotValid), s)
+	}
+}
+
+func (suite *PortsTestSuite) TestValidate() {
+	p := Port{In: "53", Out: "5353", Protocol: PortProtocolUDP}
+	suite.NoError(p.Validate())
+
+	p = Port{In: "8000-8010", Out: "9000-9010", Protocol: PortProtocolTCP}
+	suite.NoError(p.Validate())
+
+	p = Port{In: "8000-8010", Out: "9000", Protocol: PortProtocolTCP}
+	suite.True(errors.Is(p.Validate(), errors.NotValid))
+
+	p = Port{In: "80", Out: "80", Protocol: "sctp"}
+	suite.True(errors.Is(p.Validate(), errors.NotValid))
+}
+
+func (suite *PortsTestSuite) TestConflict() {
+	ports := Ports{
+		{ID: uuid.New(), Out: "8000-8010", Protocol: PortProtocolTCP},
+		{ID: uuid.New(), Out: "53", Protocol: PortProtocolUDP},
+	}
+
+	suite.Equal(&ports[0], ports.Conflict(Port{ID: uuid.New(), Out: "8010", Protocol: PortProtocolTCP}))
+	suite.Nil(ports.Conflict(Port{ID: uuid.New(), Out: "8011", Protocol: PortProtocolTCP}))
+	suite.Nil(ports.Conflict(Port{ID: uuid.New(), Out: "53", Protocol: PortProtocolTCP}))
+	suite.Nil(ports.Conflict(Port{ID: ports[1].ID, Out: "53", Protocol: PortProtocolUDP}))
+}
+
+func (suite *PortsTestSuite) TestFreePort() {
+	ports := Ports{
+		{ID: uuid.New(), Out: "5432", Protocol: PortProtocolTCP},
+		{ID: uuid.New(), Out: "8080", Protocol: PortProtocolTCP},
+		{ID: uuid.New(), Out: "8081", Protocol: PortProtocolTCP},
+	}
+
+	tests := []struct {
+		port     Port
+		expected string
+	}{
+		{Port{Out: "6379", Protocol: PortProtocolTCP}, "6379"},
+		{Port{Out: "5432", Protocol: PortProtocolTCP}, "5433"},
+		{Port{Out: "8080", Protocol: PortProtocolTCP}, "8082"},
+		{Port{Out: "8079-8080", Protocol: PortProtocolTCP}, "8082-8083"},
+		{Port{Out: "5432", Protocol: PortProtocolUDP}, "5432"},
+	}
+	for _, test := range tests {
+		out, err := ports.FreePort(test.port)
+		suite.NoError(err)
+		suite.Equal(test.expected, out)
+	}
+
+	ports = Ports{{ID: uuid.New(), Out: "65535", Protocol: PortProtocolTCP}}
+	_, err := ports.FreePort(Port{Out: "65535", Protocol: PortProtocolTCP})
+	suite.ErrorIs(err, ErrNoFreePort)
+}
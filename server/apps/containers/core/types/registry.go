@@ -0,0 +1,55 @@
+package types
+
+import (
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/juju/errors"
+	"github.com/vertex-center/uuid"
+)
+
+var (
+	ErrRegistryCredentialNotFound = errors.NotFoundf("registry credential")
+	ErrRegistryCredentialExists   = errors.AlreadyExistsf("registry credential")
+	ErrInvalidRegistry            = errors.NotValidf("registry")
+)
+
+type (
+	RegistryCredentials []RegistryCredential
+	RegistryCredential  struct {
+		ID       uuid.UUID `json:"id"                 db:"id"       example:"3a6f0b8e-6c1d-4d3e-a4c5-2b7f1b0f9c8d"`
+		Registry string    `json:"registry"           db:"registry" example:"ghcr.io"`
+		Username string    `json:"username"           db:"username" example:"vertex"`
+		Password string    `json:"password,omitempty" db:"password" example:"ghp_xxxxxxxxxxxx"` // Never returned by the API.
+	}
+
+	RegistryCredentialPatch struct {
+		Username *string `json:"username,omitempty"`
+		Password *string `json:"password,omitempty"`
+	}
+
+	RegistryAuth struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+	}
+)
+
+// NormalizeRegistry returns the host of a registry the way image references
+// name it, so that "docker.io" matches images pulled from Docker Hub.
+func NormalizeRegistry(registry string) (string, error) {
+	if registry == "" {
+		return "", errors.NewNotValid(ErrInvalidRegistry, "registry is required")
+	}
+	r, err := name.NewRegistry(registry)
+	if err != nil {
+		return "", errors.NewNotValid(err, "invalid registry "+registry)
+	}
+	return r.RegistryStr(), nil
+}
+
+// ImageRegistry returns the host of the registry an image is pulled from.
+func ImageRegistry(image string) (string, error) {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return "", err
+	}
+	return ref.Context().RegistryStr(), nil
+}
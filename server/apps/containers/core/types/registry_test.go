@@ -0,0 +1,39 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/juju/errors"
+	"github.com/stretchr/testify/suite"
+)
+
+type RegistryTestSuite struct {
+	suite.Suite
+}
+
+func TestRegistryTestSuite(t *testing.T) {
+	suite.Run(t, new(RegistryTestSuite))
+}
+
+func (suite *RegistryTestSuite) TestNormalizeRegistry() {
+	registry, err := NormalizeRegistry("docker.io")
+	suite.Require().NoError(err)
+	suite.Equal("index.docker.io", registry)
+
+	registry, err = NormalizeRegistry("harbor.local:8443")
+	suite.Require().NoError(err)
+	suite.Equal("harbor.local:8443", registry)
+
+	_, err = NormalizeRegistry("")
+	suite.True(errors.Is(err, errors.NotValid))
+}
+
+func (suite *RegistryTestSuite) TestImageRegistry() {
+	registry, err := ImageRegistry("postgres:16")
+	suite.Require().NoError(err)
+	suite.Equal("index.docker.io", registry)
+
+	registry, err = ImageRegistry("ghcr.io/vertex-center/vertex:latest")
+	suite.Require().NoError(err)
+	suite.Equal("ghcr.io", registry)
+}
@@ -0,0 +1,160 @@
+package types
+
+import (
+	"sort"
+
+	"github.com/docker/go-units"
+	"github.com/juju/errors"
+	"github.com/vertex-center/uuid"
+)
+
+const (
+	MinCPUShares = 2
+	MaxCPUShares = 262144
+	MinCPUPeriod = 1000    // 1ms, in microseconds
+	MaxCPUPeriod = 1000000 // 1s, in microseconds
+	MinCPUQuota  = 1000    // 1ms, in microseconds
+	MinMemory    = 6 * units.MiB
+)
+
+var (
+	ErrResourceLimitsNotFound = errors.NotFoundf("resource limits")
+	ErrInvalidResourceLimits  = errors.NotValidf("resource limits")
+)
+
+// ulimitNames are the ulimits supported by Docker.
+var ulimitNames = map[string]bool{
+	"core": true, "cpu": true, "data": true, "fsize": true, "locks": true,
+	"memlock": true, "msgqueue": true, "nice": true, "nofile": true, "nproc": true,
+	"rss": true, "rtprio": true, "rttime": true, "sigpending": true, "stack": true,
+}
+
+type (
+	// ResourceLimits are the resources a container can use. A nil value means no limit.
+	ResourceLimits struct {
+		ID          uuid.UUID `json:"id"                    db:"id"           example:"7e63ced7-4f4e-4b79-95ca-62930866f7bc"`
+		ContainerID uuid.UUID `json:"container_id"          db:"container_id" example:"d1fb743c-f937-4f3d-95b9-1a8475464591"`
+		CPUShares   *int64    `json:"cpu_shares,omitempty"  db:"cpu_shares"   example:"512"`       // Relative weight against other containers.
+		CPUPeriod   *int64    `json:"cpu_period,omitempty"  db:"cpu_period"   example:"100000"`    // CFS period, in microseconds.
+		CPUQuota    *int64    `json:"cpu_quota,omitempty"   db:"cpu_quota"    example:"50000"`     // CPU time per period, in microseconds.
+		Memory      *int64    `json:"memory,omitempty"      db:"memory"       example:"536870912"` // In bytes.
+		MemorySwap  *int64    `json:"memory_swap,omitempty" db:"memory_swap"  example:"-1"`        // Memory + swap, in bytes. -1 for unlimited swap.
+		PidsLimit   *int64    `json:"pids_limit,omitempty"  db:"pids_limit"   example:"200"`       // -1 for unlimited.
+
+		Ulimits Ulimits `json:"ulimits,omitempty" db:"-"`
+	}
+
+	Ulimits []Ulimit
+	Ulimit  struct {
+		ID          uuid.UUID `json:"id"           db:"id"           example:"0c3bd1e4-4a4f-4f4e-9b8f-3e8b8b6c2f7a"`
+		ContainerID uuid.UUID `json:"container_id" db:"container_id" example:"d1fb743c-f937-4f3d-95b9-1a8475464591"`
+		Name        string    `json:"name"         db:"name"         example:"nofile"`
+		Soft        int64     `json:"soft"         db:"soft"         example:"1024"`
+		Hard        int64     `json:"hard"         db:"hard"         example:"4096"`
+	}
+)
+
+func (l *ResourceLimits) Validate() error {
+	if l.CPUShares != nil && (*l.CPUShares < MinCPUShares || *l.CPUShares > MaxCPUShares) {
+		return errors.NewNotValid(ErrInvalidResourceLimits, "cpu shares must be between 2 and 262144")
+	}
+	if l.CPUPeriod != nil && (*l.CPUPeriod < MinCPUPeriod || *l.CPUPeriod > MaxCPUPeriod) {
+		return errors.NewNotValid(ErrInvalidResourceLimits, "cpu period must be between 1000 and 1000000 microseconds")
+	}
+	if l.CPUQuota != nil && *l.CPUQuota < MinCPUQuota {
+		return errors.NewNotValid(ErrInvalidResourceLimits, "cpu quota must be at least 1000 microseconds")
+	}
+	if l.Memory != nil && *l.Memory < MinMemory {
+		return errors.NewNotValid(ErrInvalidResourceLimits, "memory must be at least 6MiB")
+	}
+	if l.MemorySwap != nil {
+		if l.Memory == nil {
+			return errors.NewNotValid(ErrInvalidResourceLimits, "memory swap requires a memory limit")
+		}
+		if *l.MemorySwap != -1 && *l.MemorySwap < *l.Memory {
+			return errors.NewNotValid(ErrInvalidResourceLimits, "memory swap must be -1 or greater than memory")
+		}
+	}
+	if l.PidsLimit != nil && *l.PidsLimit != -1 && *l.PidsLimit <= 0 {
+		return errors.NewNotValid(ErrInvalidResourceLimits, "pids limit must be -1 or positive")
+	}
+
+	seen := map[string]bool{}
+	for _, u := range l.Ulimits {
+		if !ulimitNames[u.Name] {
+			return errors.NewNotValid(ErrInvalidResourceLimits, "unknown ulimit "+u.Name)
+		}
+		if seen[u.Name] {
+			return errors.NewNotValid(ErrInvalidResourceLimits, "duplicate ulimit "+u.Name)
+		}
+		seen[u.Name] = true
+		if u.Soft < -1 || u.Hard < -1 {
+			return errors.NewNotValid(ErrInvalidResourceLimits, "ulimit "+u.Name+" must be -1 or positive")
+		}
+		if u.Hard != -1 && (u.Soft == -1 || u.Soft > u.Hard) {
+			return errors.NewNotValid(ErrInvalidResourceLimits, "soft ulimit "+u.Name+" must not exceed the hard limit")
+		}
+	}
+	return nil
+}
+
+// DockerUlimits converts the ulimits into Docker ulimits.
+func (l *ResourceLimits) DockerUlimits() []*units.Ulimit {
+	var ulimits []*units.Ulimit
+	for _, u := range l.Ulimits {
+		ulimits = append(ulimits, &units.Ulimit{
+			Name: u.Name,
+			Soft: u.Soft,
+			Hard: u.Hard,
+		})
+	}
+	return ulimits
+}
+
+// ResourceLimits converts the template resources into the limits of a container.
+func (t TemplateResources) ResourceLimits(containerID uuid.UUID) (ResourceLimits, error) {
+	l := ResourceLimits{
+		ID:          uuid.New(),
+		ContainerID: containerID,
+		CPUShares:   t.CPUShares,
+		CPUPeriod:   t.CPUPeriod,
+		CPUQuota:    t.CPUQuota,
+		PidsLimit:   t.PidsLimit,
+	}
+
+	if t.Memory != nil {
+		memory, err := units.RAMInBytes(*t.Memory)
+		if err != nil {
+			return l, errors.NewNotValid(err, "invalid memory limit")
+		}
+		l.Memory = &memory
+	}
+	if t.MemorySwap != nil {
+		swap := int64(-1)
+		if *t.MemorySwap != "-1" {
+			var err error
+			swap, err = units.RAMInBytes(*t.MemorySwap)
+			if err != nil {
+				return l, errors.NewNotValid(err, "invalid memory swap limit")
+			}
+		}
+		l.MemorySwap = &swap
+	}
+
+	var names []string
+	for name := range t.Ulimits {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		l.Ulimits = append(l.Ulimits, Ulimit{
+			ID:          uuid.New(),
+			ContainerID: containerID,
+			Name:        name,
+			Soft:        t.Ulimits[name].Soft,
+			Hard:        t.Ulimits[name].Hard,
+		})
+	}
+
+	return l, l.Validate()
+}
@@ -0,0 +1,75 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/docker/go-units"
+	"github.com/juju/errors"
+	"github.com/stretchr/testify/suite"
+	"github.com/vertex-center/uuid"
+)
+
+type ResourcesTestSuite struct {
+	suite.Suite
+}
+
+func TestResourcesTestSuite(t *testing.T) {
+	suite.Run(t, new(ResourcesTestSuite))
+}
+
+func (suite *ResourcesTestSuite) TestValidate() {
+	int64p := func(v int64) *int64 { return &v }
+
+	valid := []ResourceLimits{
+		{},
+		{CPUShares: int64p(512), CPUPeriod: int64p(100000), CPUQuota: int64p(50000)},
+		{Memory: int64p(512 * units.MiB), MemorySwap: int64p(-1), PidsLimit: int64p(-1)},
+		{Ulimits: Ulimits{{Name: "nofile", Soft: 1024, Hard: 4096}, {Name: "core", Soft: -1, Hard: -1}}},
+	}
+	for _, l := range valid {
+		suite.NoError(l.Validate())
+	}
+
+	invalid := []ResourceLimits{
+		{CPUShares: int64p(1)},
+		{CPUPeriod: int64p(10)},
+		{CPUQuota: int64p(999)},
+		{Memory: int64p(units.MiB)},
+		{MemorySwap: int64p(units.GiB)},
+		{Memory: int64p(units.GiB), MemorySwap: int64p(512 * units.MiB)},
+		{PidsLimit: int64p(0)},
+		{Ulimits: Ulimits{{Name: "unknown", Soft: 1, Hard: 1}}},
+		{Ulimits: Ulimits{{Name: "nofile", Soft: 4096, Hard: 1024}}},
+		{Ulimits: Ulimits{{Name: "nofile", Soft: 1, Hard: 1}, {Name: "nofile", Soft: 2, Hard: 2}}},
+	}
+	for _, l := range invalid {
+		err := l.Validate()
+		suite.Error(err)
+		suite.True(errors.Is(err, errors.NotValid))
+	}
+}
+
+func (suite *ResourcesTestSuite) TestTemplateResources() {
+	memory, swap := "512m", "-1"
+	id := uuid.New()
+
+	l, err := TemplateResources{
+		Memory:     &memory,
+		MemorySwap: &swap,
+		Ulimits: map[string]TemplateUlimit{
+			"nproc":  {Soft: 100, Hard: 200},
+			"nofile": {Soft: 1024, Hard: 4096},
+		},
+	}.ResourceLimits(id)
+	suite.Require().NoError(err)
+	suite.Equal(id, l.ContainerID)
+	suite.Equal(int64(512*units.MiB), *l.Memory)
+	suite.Equal(int64(-1), *l.MemorySwap)
+	suite.Require().Len(l.Ulimits, 2)
+	suite.Equal("nofile", l.Ulimits[0].Name)
+	suite.Equal("nproc", l.Ulimits[1].Name)
+
+	memory = "a lot"
+	_, err = TemplateResources{Memory: &memory}.ResourceLimits(id)
+	suite.True(errors.Is(err, errors.NotValid))
+}
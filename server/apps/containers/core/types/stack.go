@@ -0,0 +1,38 @@
+package types
+
+import (
+	"github.com/juju/errors"
+	"github.com/vertex-center/uuid"
+)
+
+var (
+	ErrStackNotFound      = errors.NotFoundf("stack")
+	ErrTemplateNotStack   = errors.NotValidf("stack template")
+	ErrStackMemberUnknown = errors.NotValidf("stack member")
+)
+
+type (
+	Stacks []Stack
+	Stack  struct {
+		ID          uuid.UUID `json:"id"                    db:"id"          example:"0e9d0a4a-4d4f-4ea4-9a2b-0d1e6c2d9f4e"`
+		TemplateID  *string   `json:"template_id,omitempty" db:"template_id" example:"immich"`
+		UserID      uuid.UUID `json:"user_id"               db:"user_id"     example:"596ecff2-ca67-4194-947d-59e90920680f"`
+		Name        string    `json:"name"                  db:"name"        example:"Immich"`
+		Description *string   `json:"description"           db:"description" example:"A photo backup solution."`
+		Color       *string   `json:"color"                 db:"color"       example:"#4250af"`
+		Icon        *string   `json:"icon"                  db:"icon"        example:"simpleicons/immich.svg"`
+
+		Containers Containers `json:"containers,omitempty"`
+	}
+
+	CreateStackOptions struct {
+		TemplateID string  `json:"template_id"`
+		Name       *string `json:"name,omitempty"`
+	}
+)
+
+// StackNetworkName returns the name of the Docker network shared by the
+// containers of a stack.
+func StackNetworkName(id uuid.UUID) string {
+	return "VERTEX_STACK_" + id.String()
+}
@@ -0,0 +1,70 @@
+package types
+
+import (
+	"strings"
+	"time"
+
+	dockertypes "github.com/docker/docker/api/types"
+)
+
+// ContainerStats are the resources used by a running container.
+type ContainerStats struct {
+	Read          time.Time `json:"read"`
+	CPUPercent    float64   `json:"cpu_percent"    example:"12.5"`      // Percentage of one CPU. Can exceed 100 with multiple CPUs.
+	MemoryUsage   uint64    `json:"memory_usage"   example:"104857600"` // In bytes, without the page cache.
+	MemoryLimit   uint64    `json:"memory_limit"   example:"536870912"` // In bytes.
+	MemoryPercent float64   `json:"memory_percent" example:"19.5"`
+	NetworkRx     uint64    `json:"network_rx"     example:"1024"` // Bytes received.
+	NetworkTx     uint64    `json:"network_tx"     example:"2048"` // Bytes sent.
+	BlockRead     uint64    `json:"block_read"     example:"4096"`
+	BlockWrite    uint64    `json:"block_write"    example:"8192"`
+	PIDs          uint64    `json:"pids"           example:"12"`
+}
+
+// NewContainerStats computes the stats the same way as the docker stats command.
+func NewContainerStats(s dockertypes.StatsJSON) ContainerStats {
+	stats := ContainerStats{
+		Read:        s.Read,
+		MemoryLimit: s.MemoryStats.Limit,
+		PIDs:        s.PidsStats.Current,
+	}
+
+	cpuDelta := float64(s.CPUStats.CPUUsage.TotalUsage) - float64(s.PreCPUStats.CPUUsage.TotalUsage)
+	systemDelta := float64(s.CPUStats.SystemUsage) - float64(s.PreCPUStats.SystemUsage)
+	cpus := float64(s.CPUStats.OnlineCPUs)
+	if cpus == 0 {
+		cpus = float64(len(s.CPUStats.CPUUsage.PercpuUsage))
+	}
+	if cpuDelta > 0 && systemDelta > 0 {
+		stats.CPUPercent = cpuDelta / systemDelta * cpus * 100
+	}
+
+	// The page cache can be reclaimed, so it is not counted as used memory.
+	stats.MemoryUsage = s.MemoryStats.Usage
+	cache := s.MemoryStats.Stats["total_inactive_file"] // cgroup v1
+	if v, ok := s.MemoryStats.Stats["inactive_file"]; ok {
+		cache = v // cgroup v2
+	}
+	if cache < stats.MemoryUsage {
+		stats.MemoryUsage -= cache
+	}
+	if stats.MemoryLimit != 0 {
+		stats.MemoryPercent = float64(stats.MemoryUsage) / float64(stats.MemoryLimit) * 100
+	}
+
+	for _, n := range s.Networks {
+		stats.NetworkRx += n.RxBytes
+		stats.NetworkTx += n.TxBytes
+	}
+
+	for _, e := range s.BlkioStats.IoServiceBytesRecursive {
+		switch strings.ToLower(e.Op) {
+		case "read":
+			stats.BlockRead += e.Value
+		case "write":
+			stats.BlockWrite += e.Value
+		}
+	}
+
+	return stats
+}
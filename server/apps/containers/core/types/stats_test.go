@@ -0,0 +1,59 @@
+package types
+
+import (
+	"testing"
+
+	dockertypes "github.com/docker/docker/api/types"
+	"github.com/stretchr/testify/suite"
+)
+
+type StatsTestSuite struct {
+	suite.Suite
+}
+
+func TestStatsTestSuite(t *testing.T) {
+	suite.Run(t, new(StatsTestSuite))
+}
+
+func (suite *StatsTestSuite) TestNewContainerStats() {
+	var s dockertypes.StatsJSON
+	s.CPUStats.CPUUsage.TotalUsage = 300
+	s.CPUStats.SystemUsage = 2000
+	s.CPUStats.OnlineCPUs = 4
+	s.PreCPUStats.CPUUsage.TotalUsage = 100
+	s.PreCPUStats.SystemUsage = 1000
+	s.MemoryStats.Usage = 300
+	s.MemoryStats.Limit = 1000
+	s.MemoryStats.Stats = map[string]uint64{"inactive_file": 100}
+	s.PidsStats.Current = 7
+	s.Networks = map[string]dockertypes.NetworkStats{
+		"eth0": {RxBytes: 10, TxBytes: 20},
+		"eth1": {RxBytes: 1, TxBytes: 2},
+	}
+	s.BlkioStats.IoServiceBytesRecursive = []dockertypes.BlkioStatEntry{
+		{Op: "Read", Value: 5},
+		{Op: "Write", Value: 6},
+		{Op: "read", Value: 1},
+		{Op: "Total", Value: 12},
+	}
+
+	stats := NewContainerStats(s)
+	suite.InDelta(80.0, stats.CPUPercent, 0.001)
+	suite.Equal(uint64(200), stats.MemoryUsage)
+	suite.InDelta(20.0, stats.MemoryPercent, 0.001)
+	suite.Equal(uint64(11), stats.NetworkRx)
+	suite.Equal(uint64(22), stats.NetworkTx)
+	suite.Equal(uint64(6), stats.BlockRead)
+	suite.Equal(uint64(6), stats.BlockWrite)
+	suite.Equal(uint64(7), stats.PIDs)
+}
+
+func (suite *StatsTestSuite) TestNewContainerStatsFirstSample() {
+	var s dockertypes.StatsJSON
+	s.CPUStats.CPUUsage.TotalUsage = 300
+	s.CPUStats.SystemUsage = 2000
+
+	stats := NewContainerStats(s)
+	suite.Equal(0.0, stats.CPUPercent)
+	suite.Equal(0.0, stats.MemoryPercent)
+}
@@ -2,21 +2,40 @@ package types
 
 import (
 	"fmt"
+	"regexp"
 
 	"github.com/juju/errors"
+	"github.com/vertex-center/uuid"
 	"github.com/vertex-center/vertex/server/common/log"
 	"github.com/vertex-center/vlog"
+	"gopkg.in/yaml.v3"
 )
 
-const MaxSupportedVersion Version = 3
+const MaxSupportedVersion Version = 4
 
-var ErrTemplateNotFound = errors.NotFoundf("template")
+// TemplateSourceUser is the source of the templates created by users.
+const TemplateSourceUser = "user"
+
+var (
+	ErrTemplateNotFound      = errors.NotFoundf("template")
+	ErrTemplateAlreadyExists = errors.AlreadyExistsf("template")
+	ErrInvalidTemplate       = errors.NotValidf("template")
+)
+
+var templateIDRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
 
 type Version int
 
+type TemplateKind string
+
+const (
+	TemplateKindContainer TemplateKind = "container"
+	TemplateKindStack     TemplateKind = "stack"
+)
+
 type TemplateVersioning struct {
 	// Version of the template format used.
-	Version Version `yaml:"version" json:"version" example:"3"`
+	Version Version `yaml:"version" json:"version" example:"4"`
 }
 
 type Template struct {
@@ -58,6 +77,49 @@ type Template struct {
 
 	// Methods define different methods to install the template.
 	Methods TemplateMethods `yaml:"methods" json:"methods"`
+
+	// Kind is the kind of template. It can be: container, stack.
+	// Templates without kind are container templates.
+	Kind TemplateKind `yaml:"kind,omitempty" json:"kind,omitempty" example:"container" enum:"container,stack"`
+
+	// Stack describes the containers of a stack template.
+	Stack *TemplateStack `yaml:"stack,omitempty" json:"stack,omitempty"`
+
+	// Networks defines the networks the container is attached to. Networks
+	// that don't exist are created.
+	Networks []TemplateNetwork `yaml:"networks,omitempty" json:"networks,omitempty"`
+
+	// Source is the name of the template source the template was read from.
+	Source string `yaml:"-" json:"source,omitempty" example:"vertex"`
+
+	// UserID is the owner of the template, for templates created by users.
+	UserID *uuid.UUID `yaml:"-" json:"user_id,omitempty" example:"596ecff2-ca67-4194-947d-59e90920680f"`
+}
+
+// ParseTemplate reads a template in any supported version, and upgrades it
+// to the latest one.
+func ParseTemplate(data []byte) (*Template, error) {
+	var t Template
+	err := yaml.Unmarshal(data, &t)
+	if err != nil {
+		return nil, errors.NewNotValid(err, "invalid template")
+	}
+	return &t, nil
+}
+
+// IsStack returns true if the template describes a stack of containers.
+func (s *Template) IsStack() bool {
+	return s.Kind == TemplateKindStack
+}
+
+// Validate returns the first problem of the template. See Lint for all of
+// them.
+func (s *Template) Validate() error {
+	issues := s.Lint()
+	if len(issues) > 0 {
+		return errors.NewNotValid(ErrInvalidTemplate, issues[0].String())
+	}
+	return nil
 }
 
 type TemplateV1 Template
@@ -113,7 +175,21 @@ func (s *TemplateV2) Upgrade() *TemplateV3 {
 
 type TemplateV3 Template
 
-func (s *TemplateV3) Upgrade() *Template {
+// Upgrade TemplateV3 to TemplateV4.
+// Variables are now typed, and variables without type are strings.
+func (s *TemplateV3) Upgrade() *TemplateV4 {
+	s.Version = 4
+	for i, env := range s.Env {
+		if env.Type == "" {
+			s.Env[i].Type = string(TemplateEnvTypeString)
+		}
+	}
+	return (*TemplateV4)(s)
+}
+
+type TemplateV4 Template
+
+func (s *TemplateV4) Upgrade() *Template {
 	return (*Template)(s)
 }
 
@@ -142,6 +218,8 @@ func (s *Template) UnmarshalYAML(unmarshal func(interface{}) error) error {
 		tmpl = &TemplateV2{}
 	case 3:
 		tmpl = &TemplateV3{}
+	case 4:
+		tmpl = &TemplateV4{}
 	}
 	err = unmarshal(tmpl)
 	if err != nil {
@@ -159,6 +237,9 @@ func (s *Template) UnmarshalYAML(unmarshal func(interface{}) error) error {
 		fallthrough
 	case 3:
 		tmpl = tmpl.(*TemplateV3).Upgrade()
+		fallthrough
+	case 4:
+		tmpl = tmpl.(*TemplateV4).Upgrade()
 	}
 
 	if serv, ok := tmpl.(*Template); ok {
@@ -169,8 +250,10 @@ func (s *Template) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	return nil
 }
 
-type TemplateUpdate struct {
-	Available bool `json:"available"`
+// ContainerTemplateOptions are the options to save a container as a template.
+type ContainerTemplateOptions struct {
+	ID   string  `json:"id"             example:"postgres-custom"`
+	Name *string `json:"name,omitempty" example:"Postgres (custom)"` // Defaults to the name of the container.
 }
 
 type DatabaseEnvironment struct {
@@ -224,8 +307,8 @@ type Features struct {
 
 type TemplateEnv struct {
 	// Type is the environment variable type.
-	// It can be: port, string, url.
-	Type string `yaml:"type" json:"type" example:"port"`
+	// It can be: string, int, bool, enum, url, email, password, path, port.
+	Type string `yaml:"type" json:"type" example:"port" enum:"string,int,bool,enum,url,email,password,path,port"`
 
 	// Name is the environment variable name that will be used by the template.
 	Name string `yaml:"name" json:"name" example:"PORT"`
@@ -241,6 +324,45 @@ type TemplateEnv struct {
 
 	// Description describes this variable to the user.
 	Description string `yaml:"description" json:"description" example:"The port where the server will listen."`
+
+	// Required is true if the value can't be empty when the variable is visible.
+	Required bool `yaml:"required,omitempty" json:"required,omitempty" example:"true"`
+
+	// Choices are the values accepted by enum variables.
+	Choices []string `yaml:"choices,omitempty" json:"choices,omitempty" example:"debug,info,warn"`
+
+	// Min and Max bound the value of int variables, and the length of the
+	// value of the other variables.
+	Min *int `yaml:"min,omitempty" json:"min,omitempty" example:"1"`
+	Max *int `yaml:"max,omitempty" json:"max,omitempty" example:"65535"`
+
+	// Regex is a regular expression that the whole value must match.
+	Regex *string `yaml:"regex,omitempty" json:"regex,omitempty" example:"^[a-z]+$"`
+
+	// Generator generates the default value of password variables.
+	Generator *TemplateEnvGenerator `yaml:"generator,omitempty" json:"generator,omitempty"`
+
+	// ShowIf makes the variable visible only if another variable has a
+	// given value. Hidden variables are not validated.
+	ShowIf *TemplateEnvCondition `yaml:"show_if,omitempty" json:"show_if,omitempty"`
+}
+
+type TemplateEnvGenerator struct {
+	// Length is the number of characters generated. Defaults to 32.
+	Length int `yaml:"length,omitempty" json:"length,omitempty" example:"32"`
+
+	// Charset is the set of characters used: alphanumeric or hex.
+	// Defaults to alphanumeric.
+	Charset string `yaml:"charset,omitempty" json:"charset,omitempty" example:"alphanumeric" enum:"alphanumeric,hex"`
+}
+
+type TemplateEnvCondition struct {
+	// Name is the name of the variable the visibility depends on.
+	Name string `yaml:"name" json:"name" example:"SMTP_ENABLED"`
+
+	// Equals is the value the variable must have. If not set, the variable
+	// must be set to a value other than false.
+	Equals *string `yaml:"equals,omitempty" json:"equals,omitempty" example:"true"`
 }
 
 type TemplatePort struct {
@@ -249,10 +371,49 @@ type TemplatePort struct {
 
 	// Port is the port where this port is supposed to be.
 	Port string `yaml:"port" json:"port" example:"3000"`
+
+	// Protocol is the protocol of the port, tcp or udp. Defaults to tcp.
+	Protocol string `yaml:"protocol,omitempty" json:"protocol,omitempty" example:"tcp"`
+}
+
+type TemplateNetwork struct {
+	// Name is the name of the network.
+	Name string `yaml:"name" json:"name" example:"backend"`
+
+	// Aliases are the names of the container in the network.
+	Aliases []string `yaml:"aliases,omitempty" json:"aliases,omitempty" example:"postgres"`
 }
 
 type TemplateDependency struct{}
 
+type TemplateStack struct {
+	// Containers are the containers of the stack, created in this order.
+	// The environment of the stack template is shared by all of them.
+	Containers []TemplateStackContainer `yaml:"containers" json:"containers"`
+}
+
+type TemplateStackContainer struct {
+	// Name identifies the container in the stack. The other containers of the
+	// stack can reach it with this hostname.
+	Name string `yaml:"name" json:"name" example:"redis"`
+
+	// Template is the ID of the template used to create the container.
+	Template *string `yaml:"template,omitempty" json:"template,omitempty" example:"redis"`
+
+	// Image is the Docker image to run, for containers without template.
+	Image *string `yaml:"image,omitempty" json:"image,omitempty" example:"redis"`
+
+	// ImageTag is the tag of the Docker image.
+	ImageTag *string `yaml:"image_tag,omitempty" json:"image_tag,omitempty" example:"7"`
+
+	// Env overrides environment variables of this container only.
+	Env map[string]string `yaml:"environment,omitempty" json:"environment,omitempty"`
+
+	// DependsOn are the names of the containers of the stack that must be
+	// running before this container is started.
+	DependsOn []string `yaml:"depends_on,omitempty" json:"depends_on,omitempty"`
+}
+
 type TemplateClone struct {
 	Repository string `yaml:"repository" json:"repository" example:"https://github.com/vertex-center/vertex"`
 }
@@ -267,6 +428,9 @@ type TemplateMethodDocker struct {
 	// Dockerfile is the name of the Dockerfile if the repository is cloned.
 	Dockerfile *string `yaml:"dockerfile,omitempty" json:"dockerfile,omitempty" example:"Dockerfile"`
 
+	// BuildArgs are the build-time variables passed to the Dockerfile.
+	BuildArgs map[string]string `yaml:"build_args,omitempty" json:"build_args,omitempty"`
+
 	// Ports is a map containing docker port as a key, and output port as a value.
 	// The output port is automatically adjusted with PORT environment variables.
 	// Deprecated: Use the root Ports variable instead.
@@ -284,6 +448,67 @@ type TemplateMethodDocker struct {
 
 	// Cmd is the command to run in the container.
 	Cmd *string `yaml:"command,omitempty" json:"command,omitempty"`
+
+	// Healthcheck describes how Docker can probe the container to know if it is healthy.
+	Healthcheck *TemplateHealthCheck `yaml:"healthcheck,omitempty" json:"healthcheck,omitempty"`
+
+	// Resources are the default resource limits of the container.
+	Resources *TemplateResources `yaml:"resources,omitempty" json:"resources,omitempty"`
+}
+
+type TemplateHealthCheck struct {
+	// Type is the kind of probe.
+	// It can be: http, tcp, exec.
+	Type string `yaml:"type" json:"type" example:"http"`
+
+	// Port is the port probed inside the container, for http and tcp probes.
+	Port *string `yaml:"port,omitempty" json:"port,omitempty" example:"8080"`
+
+	// Path is the path requested by http probes.
+	Path *string `yaml:"path,omitempty" json:"path,omitempty" example:"/health"`
+
+	// Command is the command run inside the container by exec probes.
+	Command *string `yaml:"command,omitempty" json:"command,omitempty" example:"pg_isready"`
+
+	// Interval is the time between two probes.
+	Interval *string `yaml:"interval,omitempty" json:"interval,omitempty" example:"30s"`
+
+	// Timeout is the time after which a probe is considered failed.
+	Timeout *string `yaml:"timeout,omitempty" json:"timeout,omitempty" example:"10s"`
+
+	// StartPeriod is the time given to the container to start before failures are counted.
+	StartPeriod *string `yaml:"start_period,omitempty" json:"start_period,omitempty" example:"10s"`
+
+	// Retries is the number of consecutive failures needed to consider the container unhealthy.
+	Retries *int `yaml:"retries,omitempty" json:"retries,omitempty" example:"3"`
+}
+
+type TemplateResources struct {
+	// CPUShares is the relative weight of the container against other containers.
+	CPUShares *int64 `yaml:"cpu_shares,omitempty" json:"cpu_shares,omitempty" example:"512"`
+
+	// CPUPeriod is the CFS period, in microseconds.
+	CPUPeriod *int64 `yaml:"cpu_period,omitempty" json:"cpu_period,omitempty" example:"100000"`
+
+	// CPUQuota is the CPU time the container can use each period, in microseconds.
+	CPUQuota *int64 `yaml:"cpu_quota,omitempty" json:"cpu_quota,omitempty" example:"50000"`
+
+	// Memory is the memory limit, like "512m" or "1g".
+	Memory *string `yaml:"memory,omitempty" json:"memory,omitempty" example:"512m"`
+
+	// MemorySwap is the memory + swap limit, like "1g". Use "-1" for unlimited swap.
+	MemorySwap *string `yaml:"memory_swap,omitempty" json:"memory_swap,omitempty" example:"1g"`
+
+	// PidsLimit is the maximum number of processes. Use -1 for unlimited.
+	PidsLimit *int64 `yaml:"pids_limit,omitempty" json:"pids_limit,omitempty" example:"200"`
+
+	// Ulimits are the ulimits of the container, by name.
+	Ulimits map[string]TemplateUlimit `yaml:"ulimits,omitempty" json:"ulimits,omitempty"`
+}
+
+type TemplateUlimit struct {
+	Soft int64 `yaml:"soft" json:"soft" example:"1024"`
+	Hard int64 `yaml:"hard" json:"hard" example:"4096"`
 }
 
 type TemplateMethods struct {
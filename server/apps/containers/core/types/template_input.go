@@ -0,0 +1,224 @@
+package types
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"net/mail"
+	"net/url"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/juju/errors"
+)
+
+type TemplateEnvType string
+
+const (
+	TemplateEnvTypeString   TemplateEnvType = "string"
+	TemplateEnvTypeInt      TemplateEnvType = "int"
+	TemplateEnvTypeBool     TemplateEnvType = "bool"
+	TemplateEnvTypeEnum     TemplateEnvType = "enum"
+	TemplateEnvTypeURL      TemplateEnvType = "url"
+	TemplateEnvTypeEmail    TemplateEnvType = "email"
+	TemplateEnvTypePassword TemplateEnvType = "password"
+	TemplateEnvTypePath     TemplateEnvType = "path"
+	TemplateEnvTypePort     TemplateEnvType = "port"
+)
+
+const (
+	GeneratorCharsetAlphanumeric = "alphanumeric"
+	GeneratorCharsetHex          = "hex"
+
+	defaultGeneratedLength = 32
+)
+
+var templateEnvTypes = []TemplateEnvType{
+	TemplateEnvTypeString,
+	TemplateEnvTypeInt,
+	TemplateEnvTypeBool,
+	TemplateEnvTypeEnum,
+	TemplateEnvTypeURL,
+	TemplateEnvTypeEmail,
+	TemplateEnvTypePassword,
+	TemplateEnvTypePath,
+	TemplateEnvTypePort,
+}
+
+var generatorCharsets = map[string]string{
+	GeneratorCharsetAlphanumeric: "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789",
+	GeneratorCharsetHex:          "0123456789abcdef",
+}
+
+var ErrInvalidEnvValue = errors.NotValidf("environment variable value")
+
+// IsSecret returns whether the value of the variable must be hidden.
+func (e *TemplateEnv) IsSecret() bool {
+	return (e.Secret != nil && *e.Secret) || TemplateEnvType(e.Type) == TemplateEnvTypePassword
+}
+
+// IsVisible returns whether the variable is shown, given the values of the
+// other variables.
+func (e *TemplateEnv) IsVisible(values map[string]string) bool {
+	if e.ShowIf == nil {
+		return true
+	}
+	value := values[e.ShowIf.Name]
+	if e.ShowIf.Equals != nil {
+		return value == *e.ShowIf.Equals
+	}
+	return value != "" && value != "false"
+}
+
+// ValidateValue checks that the value matches the type and the constraints
+// of the variable. Empty values are only rejected for required variables.
+func (e *TemplateEnv) ValidateValue(value string) error {
+	problem := e.valueProblem(value)
+	if problem != "" {
+		return errors.NewNotValid(ErrInvalidEnvValue, e.Name+": "+problem)
+	}
+	return nil
+}
+
+// valueProblem returns why the value is not valid, or an empty string.
+func (e *TemplateEnv) valueProblem(value string) string {
+	if value == "" {
+		if e.Required {
+			return "a value is required"
+		}
+		return ""
+	}
+
+	switch TemplateEnvType(e.Type) {
+	case TemplateEnvTypeInt:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Sprintf("'%s' is not an integer", value)
+		}
+		if e.Min != nil && n < *e.Min {
+			return fmt.Sprintf("must be at least %d", *e.Min)
+		}
+		if e.Max != nil && n > *e.Max {
+			return fmt.Sprintf("must be at most %d", *e.Max)
+		}
+	case TemplateEnvTypeBool:
+		if value != "true" && value != "false" {
+			return "must be true or false"
+		}
+	case TemplateEnvTypeEnum:
+		if !slices.Contains(e.Choices, value) {
+			return fmt.Sprintf("'%s' is not one of %v", value, e.Choices)
+		}
+	case TemplateEnvTypeURL:
+		u, err := url.Parse(value)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Sprintf("'%s' is not a valid url", value)
+		}
+	case TemplateEnvTypeEmail:
+		addr, err := mail.ParseAddress(value)
+		if err != nil || addr.Address != value {
+			return fmt.Sprintf("'%s' is not a valid email address", value)
+		}
+	case TemplateEnvTypePath:
+		if !path.IsAbs(value) {
+			return fmt.Sprintf("'%s' is not an absolute path", value)
+		}
+	case TemplateEnvTypePort:
+		if _, err := ParsePortRange(value); err != nil {
+			return fmt.Sprintf("'%s' is not a valid port", value)
+		}
+	}
+
+	if TemplateEnvType(e.Type) != TemplateEnvTypeInt {
+		length := utf8.RuneCountInString(value)
+		if e.Min != nil && length < *e.Min {
+			return fmt.Sprintf("must be at least %d characters long", *e.Min)
+		}
+		if e.Max != nil && length > *e.Max {
+			return fmt.Sprintf("must be at most %d characters long", *e.Max)
+		}
+	}
+
+	if e.Regex != nil {
+		re, err := regexp.Compile("^(?:" + *e.Regex + ")$")
+		if err != nil {
+			return fmt.Sprintf("invalid regex: %s", err)
+		}
+		if !re.MatchString(value) {
+			return fmt.Sprintf("must match %s", *e.Regex)
+		}
+	}
+	return ""
+}
+
+// DefaultValue returns the value of the variable for a new container: its
+// default, or a generated value.
+func (e *TemplateEnv) DefaultValue() (string, error) {
+	if e.Default != "" || e.Generator == nil {
+		return e.Default, nil
+	}
+
+	charset, ok := generatorCharsets[e.Generator.Charset]
+	if e.Generator.Charset == "" {
+		charset, ok = generatorCharsets[GeneratorCharsetAlphanumeric], true
+	}
+	if !ok {
+		return "", errors.NewNotValid(nil, fmt.Sprintf("unknown charset '%s'", e.Generator.Charset))
+	}
+	length := e.Generator.Length
+	if length <= 0 {
+		length = defaultGeneratedLength
+	}
+
+	b := make([]byte, length)
+	size := big.NewInt(int64(len(charset)))
+	for i := range b {
+		n, err := rand.Int(rand.Reader, size)
+		if err != nil {
+			return "", err
+		}
+		b[i] = charset[n.Int64()]
+	}
+	return string(b), nil
+}
+
+// TemplateEnvValues returns the values of the variables of a new container:
+// their default values, replaced by the values given. The values of the
+// visible variables are validated.
+func TemplateEnvValues(env []TemplateEnv, values map[string]string) (map[string]string, error) {
+	all := map[string]string{}
+	for _, e := range env {
+		value, err := e.DefaultValue()
+		if err != nil {
+			return nil, err
+		}
+		all[e.Name] = value
+	}
+	for name, value := range values {
+		if _, ok := all[name]; !ok {
+			return nil, errors.NewNotValid(ErrInvalidEnvValue, fmt.Sprintf("unknown variable %s", name))
+		}
+		all[name] = value
+	}
+
+	for _, e := range env {
+		err := ValidateTemplateEnvValue(env, e.Name, all)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return all, nil
+}
+
+// ValidateTemplateEnvValue validates the value of the variable name, if it is
+// visible. Variables unknown to the template are accepted.
+func ValidateTemplateEnvValue(env []TemplateEnv, name string, values map[string]string) error {
+	i := slices.IndexFunc(env, func(e TemplateEnv) bool { return e.Name == name })
+	if i == -1 || !env[i].IsVisible(values) {
+		return nil
+	}
+	return env[i].ValidateValue(values[name])
+}
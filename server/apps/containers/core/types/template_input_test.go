@@ -0,0 +1,151 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/juju/errors"
+	"github.com/stretchr/testify/suite"
+)
+
+type TemplateInputTestSuite struct {
+	suite.Suite
+}
+
+func TestTemplateInputTestSuite(t *testing.T) {
+	suite.Run(t, new(TemplateInputTestSuite))
+}
+
+func (suite *TemplateInputTestSuite) TestValidateValue() {
+	minimum, maximum := 1, 10
+	regex := "[a-z]+"
+
+	tests := []struct {
+		env   TemplateEnv
+		value string
+		valid bool
+	}{
+		{TemplateEnv{Type: "string"}, "", true},
+		{TemplateEnv{Type: "string", Required: true}, "", false},
+		{TemplateEnv{Type: "int", Min: &minimum, Max: &maximum}, "5", true},
+		{TemplateEnv{Type: "int", Min: &minimum, Max: &maximum}, "11", false},
+		{TemplateEnv{Type: "int"}, "five", false},
+		{TemplateEnv{Type: "bool"}, "true", true},
+		{TemplateEnv{Type: "bool"}, "yes", false},
+		{TemplateEnv{Type: "enum", Choices: []string{"debug", "info"}}, "info", true},
+		{TemplateEnv{Type: "enum", Choices: []string{"debug", "info"}}, "trace", false},
+		{TemplateEnv{Type: "url"}, "https://vertex.arra.red", true},
+		{TemplateEnv{Type: "url"}, "vertex.arra.red", false},
+		{TemplateEnv{Type: "email"}, "admin@example.com", true},
+		{TemplateEnv{Type: "email"}, "Admin <admin@example.com>", false},
+		{TemplateEnv{Type: "path"}, "/var/lib/data", true},
+		{TemplateEnv{Type: "path"}, "data", false},
+		{TemplateEnv{Type: "port"}, "8080", true},
+		{TemplateEnv{Type: "port"}, "80800", false},
+		{TemplateEnv{Type: "password", Min: &maximum}, "short", false},
+		{TemplateEnv{Type: "string", Regex: &regex}, "vertex", true},
+		{TemplateEnv{Type: "string", Regex: &regex}, "vertex1", false},
+	}
+	for _, test := range tests {
+		err := test.env.ValidateValue(test.value)
+		if test.valid {
+			suite.NoError(err, "%s %q", test.env.Type, test.value)
+		} else {
+			suite.True(errors.Is(err, errors.NotValid), "%s %q", test.env.Type, test.value)
+		}
+	}
+}
+
+func (suite *TemplateInputTestSuite) TestIsVisible() {
+	yes := "yes"
+	env := TemplateEnv{ShowIf: &TemplateEnvCondition{Name: "SMTP_ENABLED"}}
+	suite.True(env.IsVisible(map[string]string{"SMTP_ENABLED": "true"}))
+	suite.False(env.IsVisible(map[string]string{"SMTP_ENABLED": "false"}))
+	suite.False(env.IsVisible(map[string]string{}))
+
+	env.ShowIf.Equals = &yes
+	suite.True(env.IsVisible(map[string]string{"SMTP_ENABLED": "yes"}))
+	suite.False(env.IsVisible(map[string]string{"SMTP_ENABLED": "true"}))
+}
+
+func (suite *TemplateInputTestSuite) TestDefaultValue() {
+	env := TemplateEnv{Type: "password", Generator: &TemplateEnvGenerator{Length: 16, Charset: GeneratorCharsetHex}}
+	value, err := env.DefaultValue()
+	suite.Require().NoError(err)
+	suite.Len(value, 16)
+	suite.Regexp("^[0-9a-f]+$", value)
+
+	env = TemplateEnv{Type: "password", Generator: &TemplateEnvGenerator{}}
+	value, err = env.DefaultValue()
+	suite.Require().NoError(err)
+	suite.Len(value, 32)
+
+	env = TemplateEnv{Type: "string", Default: "postgres", Generator: &TemplateEnvGenerator{}}
+	value, err = env.DefaultValue()
+	suite.Require().NoError(err)
+	suite.Equal("postgres", value)
+}
+
+func (suite *TemplateInputTestSuite) TestTemplateEnvValues() {
+	env := []TemplateEnv{
+		{Type: "bool", Name: "SMTP_ENABLED", Default: "false"},
+		{Type: "string", Name: "SMTP_HOST", Required: true, ShowIf: &TemplateEnvCondition{Name: "SMTP_ENABLED"}},
+		{Type: "password", Name: "SECRET", Generator: &TemplateEnvGenerator{}},
+	}
+
+	values, err := TemplateEnvValues(env, nil)
+	suite.Require().NoError(err)
+	suite.Equal("false", values["SMTP_ENABLED"])
+	suite.Len(values["SECRET"], 32)
+
+	_, err = TemplateEnvValues(env, map[string]string{"SMTP_ENABLED": "true"})
+	suite.ErrorIs(err, ErrInvalidEnvValue)
+
+	values, err = TemplateEnvValues(env, map[string]string{"SMTP_ENABLED": "true", "SMTP_HOST": "smtp.example.com"})
+	suite.Require().NoError(err)
+	suite.Equal("smtp.example.com", values["SMTP_HOST"])
+
+	_, err = TemplateEnvValues(env, map[string]string{"UNKNOWN": "value"})
+	suite.ErrorIs(err, ErrInvalidEnvValue)
+}
+
+func (suite *TemplateInputTestSuite) TestUpgradeV4() {
+	t, err := ParseTemplate([]byte(`
+version: 3
+id: app
+name: App
+environment:
+  - name: HOST
+  - name: URL
+    type: url
+methods:
+  docker:
+    image: app
+`))
+	suite.Require().NoError(err)
+	suite.Equal(Version(4), t.Version)
+	suite.Equal("string", t.Env[0].Type)
+	suite.Equal("url", t.Env[1].Type)
+
+	t, err = ParseTemplate([]byte(`
+version: 4
+id: app
+name: App
+environment:
+  - name: LOG_LEVEL
+    type: enum
+    choices: [debug, info]
+    default: info
+  - name: ADMIN_PASSWORD
+    type: password
+    generator:
+      length: 24
+methods:
+  docker:
+    image: app
+`))
+	suite.Require().NoError(err)
+	suite.Equal([]string{"debug", "info"}, t.Env[0].Choices)
+	suite.Equal(24, t.Env[1].Generator.Length)
+	suite.True(t.Env[1].IsSecret())
+	suite.Empty(t.Lint())
+}
@@ -0,0 +1,342 @@
+package types
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/vertex-center/uuid"
+)
+
+var (
+	envNameRegexp    = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	volumeNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+	sysctlRegexp     = regexp.MustCompile(`^[a-z0-9_]+([./][a-z0-9_-]+)+$`)
+	capabilityRegexp = regexp.MustCompile(`^(CAP_)?[A-Z_]+$`)
+)
+
+type (
+	// TemplateIssue is a problem found in a template. Field is the path of
+	// the faulty field, like features.databases[0].port.
+	TemplateIssue struct {
+		Field   string `json:"field"   example:"features.databases[0].port"`
+		Message string `json:"message" example:"references the undeclared variable DB_PORT"`
+	}
+
+	TemplateIssues []TemplateIssue
+
+	// TemplateLint is the result of the validation of a template file.
+	TemplateLint struct {
+		File   string         `json:"file,omitempty" example:"services/redis/service.yml"`
+		ID     string         `json:"id,omitempty"   example:"redis"`
+		Valid  bool           `json:"valid"`
+		Issues TemplateIssues `json:"issues,omitempty"`
+	}
+
+	TemplateLintReport struct {
+		Valid     bool           `json:"valid"`
+		Templates []TemplateLint `json:"templates"`
+	}
+)
+
+func (i TemplateIssue) String() string {
+	if i.Field == "" {
+		return i.Message
+	}
+	return i.Field + ": " + i.Message
+}
+
+// LintTemplate parses and validates a template file.
+func LintTemplate(file string, data []byte) TemplateLint {
+	lint := TemplateLint{File: file}
+	t, err := ParseTemplate(data)
+	if err != nil {
+		lint.Issues = TemplateIssues{{Message: err.Error()}}
+		return lint
+	}
+	lint.ID = t.ID
+	lint.Issues = t.Lint()
+	lint.Valid = len(lint.Issues) == 0
+	return lint
+}
+
+// LintTemplates validates template files, by name.
+func LintTemplates(files map[string][]byte) TemplateLintReport {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	report := TemplateLintReport{
+		Valid:     true,
+		Templates: []TemplateLint{},
+	}
+	for _, name := range names {
+		lint := LintTemplate(name, files[name])
+		report.Valid = report.Valid && lint.Valid
+		report.Templates = append(report.Templates, lint)
+	}
+	return report
+}
+
+// Lint returns all the problems of the template.
+func (s *Template) Lint() TemplateIssues {
+	var issues TemplateIssues
+	add := func(field string, format string, args ...any) {
+		issues = append(issues, TemplateIssue{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if s.Version > MaxSupportedVersion {
+		add("version", "version %d is not supported, the latest version is %d", s.Version, MaxSupportedVersion)
+	}
+	if !templateIDRegexp.MatchString(s.ID) {
+		add("id", "invalid id '%s'", s.ID)
+	}
+	if s.Name == "" {
+		add("name", "the name is required")
+	}
+
+	// Names that the features and databases can reference.
+	declared := map[string]bool{}
+	for i, e := range s.Env {
+		field := fmt.Sprintf("environment[%d]", i)
+		if !envNameRegexp.MatchString(e.Name) {
+			add(field+".name", "invalid variable name '%s'", e.Name)
+		} else if declared[e.Name] {
+			add(field+".name", "the variable %s is declared twice", e.Name)
+		}
+		declared[e.Name] = true
+		issues = append(issues, e.lint(field)...)
+	}
+	for i, e := range s.Env {
+		if e.ShowIf != nil && !declared[e.ShowIf.Name] {
+			add(fmt.Sprintf("environment[%d].show_if.name", i), "references the undeclared variable %s", e.ShowIf.Name)
+		}
+	}
+
+	for i, p := range s.Ports {
+		field := fmt.Sprintf("ports[%d]", i)
+		if p.Name == "" {
+			add(field+".name", "the name is required")
+		}
+		declared[p.Name] = true
+		if _, err := ParsePortRange(p.Port); err != nil {
+			add(field+".port", "%s", err)
+		}
+		if p.Protocol != "" && p.Protocol != string(PortProtocolTCP) && p.Protocol != string(PortProtocolUDP) {
+			add(field+".protocol", "invalid protocol '%s'", p.Protocol)
+		}
+	}
+
+	ref := func(field string, name *string) {
+		if name != nil && *name != "" && !declared[*name] {
+			add(field, "references the undeclared variable %s", *name)
+		}
+	}
+	if s.Features != nil && s.Features.Databases != nil {
+		for i, db := range *s.Features.Databases {
+			field := fmt.Sprintf("features.databases[%d]", i)
+			if db.Type == "" {
+				add(field+".type", "the type is required")
+			}
+			ref(field+".port", &db.Port)
+			ref(field+".username", db.Username)
+			ref(field+".password", db.Password)
+			ref(field+".database_default", db.DefaultDatabase)
+		}
+	}
+	for _, id := range sortedMapKeys(s.Databases) {
+		names := s.Databases[id].Names
+		field := "databases." + id + ".names"
+		ref(field+".host", &names.Host)
+		ref(field+".port", &names.Port)
+		ref(field+".username", &names.Username)
+		ref(field+".password", &names.Password)
+		ref(field+".database", &names.Database)
+	}
+
+	for i, n := range s.Networks {
+		field := fmt.Sprintf("networks[%d]", i)
+		if !networkNameRegexp.MatchString(n.Name) {
+			add(field+".name", "invalid network name '%s'", n.Name)
+		}
+		for _, alias := range n.Aliases {
+			if !networkNameRegexp.MatchString(alias) {
+				add(field+".aliases", "invalid alias '%s'", alias)
+			}
+		}
+	}
+
+	switch s.Kind {
+	case TemplateKindContainer, "":
+		issues = append(issues, s.lintDocker()...)
+	case TemplateKindStack:
+		issues = append(issues, s.lintStack()...)
+	default:
+		add("kind", "invalid kind '%s'", s.Kind)
+	}
+	return issues
+}
+
+func (e *TemplateEnv) lint(field string) TemplateIssues {
+	var issues TemplateIssues
+	add := func(f string, format string, args ...any) {
+		issues = append(issues, TemplateIssue{Field: field + f, Message: fmt.Sprintf(format, args...)})
+	}
+
+	tp := TemplateEnvType(e.Type)
+	if !slices.Contains(templateEnvTypes, tp) {
+		add(".type", "invalid type '%s'", e.Type)
+	}
+	if tp == TemplateEnvTypeEnum && len(e.Choices) == 0 {
+		add(".choices", "enum variables need choices")
+	}
+	if tp != TemplateEnvTypeEnum && len(e.Choices) > 0 {
+		add(".choices", "only enum variables have choices")
+	}
+	if e.Min != nil && e.Max != nil && *e.Min > *e.Max {
+		add(".min", "min is greater than max")
+	}
+	regexValid := true
+	if e.Regex != nil {
+		if _, err := regexp.Compile(*e.Regex); err != nil {
+			add(".regex", "invalid regex: %s", err)
+			regexValid = false
+		}
+	}
+	if e.Generator != nil {
+		if tp != TemplateEnvTypePassword && tp != TemplateEnvTypeString {
+			add(".generator", "only password and string variables can be generated")
+		}
+		if _, ok := generatorCharsets[e.Generator.Charset]; !ok && e.Generator.Charset != "" {
+			add(".generator.charset", "unknown charset '%s'", e.Generator.Charset)
+		}
+	}
+	if e.Default != "" && regexValid {
+		if problem := e.valueProblem(e.Default); problem != "" {
+			add(".default", "%s", problem)
+		}
+	}
+	return issues
+}
+
+func (s *Template) lintDocker() TemplateIssues {
+	var issues TemplateIssues
+	add := func(field string, format string, args ...any) {
+		issues = append(issues, TemplateIssue{Field: "methods.docker" + field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	docker := s.Methods.Docker
+	if docker == nil {
+		add("", "the docker method is required")
+		return issues
+	}
+	if docker.Image == nil && docker.Clone == nil {
+		add("", "an image or a repository to clone is required")
+	}
+	if docker.Image != nil && *docker.Image == "" {
+		add(".image", "the image is empty")
+	}
+	if docker.Clone != nil && docker.Clone.Repository == "" {
+		add(".clone.repository", "the repository is required")
+	}
+
+	if docker.Volumes != nil {
+		for _, out := range sortedMapKeys(*docker.Volumes) {
+			in := (*docker.Volumes)[out]
+			field := ".volumes." + out
+			if !strings.Contains(out, "/") && !volumeNameRegexp.MatchString(out) {
+				add(field, "invalid volume name '%s'", out)
+			}
+			if !path.IsAbs(in) {
+				add(field, "the path in the container must be absolute, got '%s'", in)
+			}
+		}
+	}
+
+	if docker.Sysctls != nil {
+		for _, name := range sortedMapKeys(*docker.Sysctls) {
+			if !sysctlRegexp.MatchString(name) {
+				add(".sysctls."+name, "invalid sysctl name")
+			}
+			if (*docker.Sysctls)[name] == "" {
+				add(".sysctls."+name, "the value is required")
+			}
+		}
+	}
+
+	if docker.Capabilities != nil {
+		for i, c := range *docker.Capabilities {
+			if !capabilityRegexp.MatchString(c) {
+				add(fmt.Sprintf(".capabilities[%d]", i), "invalid capability '%s'", c)
+			}
+		}
+	}
+
+	if docker.Healthcheck != nil {
+		hc := docker.Healthcheck.HealthCheck(uuid.Nil)
+		if err := hc.Validate(); err != nil {
+			add(".healthcheck", "%s", err)
+		}
+	}
+
+	if docker.Resources != nil {
+		limits, err := docker.Resources.ResourceLimits(uuid.Nil)
+		if err == nil {
+			err = limits.Validate()
+		}
+		if err != nil {
+			add(".resources", "%s", err)
+		}
+	}
+	return issues
+}
+
+func (s *Template) lintStack() TemplateIssues {
+	var issues TemplateIssues
+	add := func(field string, format string, args ...any) {
+		issues = append(issues, TemplateIssue{Field: "stack" + field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if s.Stack == nil || len(s.Stack.Containers) == 0 {
+		add(".containers", "the stack has no containers")
+		return issues
+	}
+
+	names := map[string]bool{}
+	for _, c := range s.Stack.Containers {
+		names[c.Name] = true
+	}
+	seen := map[string]bool{}
+	for i, c := range s.Stack.Containers {
+		field := fmt.Sprintf(".containers[%d]", i)
+		if !networkNameRegexp.MatchString(c.Name) {
+			add(field+".name", "invalid name '%s'", c.Name)
+		} else if seen[c.Name] {
+			add(field+".name", "the name %s is used twice", c.Name)
+		}
+		seen[c.Name] = true
+		if c.Template == nil && c.Image == nil {
+			add(field, "a template or an image is required")
+		}
+		for _, dep := range c.DependsOn {
+			if !names[dep] {
+				add(field+".depends_on", "unknown container %s", dep)
+			}
+		}
+	}
+	return issues
+}
+
+func sortedMapKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
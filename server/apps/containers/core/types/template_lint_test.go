@@ -0,0 +1,149 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type TemplateLintTestSuite struct {
+	suite.Suite
+}
+
+func TestTemplateLintTestSuite(t *testing.T) {
+	suite.Run(t, new(TemplateLintTestSuite))
+}
+
+func (suite *TemplateLintTestSuite) TestLintValid() {
+	lint := LintTemplate("postgres/service.yml", []byte(`
+version: 3
+id: postgres
+name: Postgres
+environment:
+  - name: DB_USER
+    default: postgres
+ports:
+  - name: DB_PORT
+    port: "5432"
+features:
+  databases:
+    - type: postgres
+      port: DB_PORT
+      username: DB_USER
+methods:
+  docker:
+    image: postgres
+    volumes:
+      data: /var/lib/postgresql/data
+    capabilities: [NET_ADMIN]
+    sysctls:
+      net.ipv4.ip_forward: "1"
+`))
+	suite.True(lint.Valid)
+	suite.Equal("postgres", lint.ID)
+	suite.Empty(lint.Issues)
+}
+
+func (suite *TemplateLintTestSuite) TestLintIssues() {
+	lint := LintTemplate("bad/service.yml", []byte(`
+version: 3
+id: bad
+environment:
+  - name: DB_USER
+  - name: DB_USER
+ports:
+  - name: Web
+    port: "80-70"
+    protocol: sctp
+features:
+  databases:
+    - type: postgres
+      port: DB_PORT
+methods:
+  docker:
+    image: postgres
+    volumes:
+      data: var/lib
+    capabilities: [net_admin]
+    sysctls:
+      ip_forward: "1"
+    healthcheck:
+      type: http
+`))
+	suite.False(lint.Valid)
+
+	var fields []string
+	for _, issue := range lint.Issues {
+		fields = append(fields, issue.Field)
+	}
+	suite.Equal([]string{
+		"name",
+		"environment[1].name",
+		"ports[0].port",
+		"ports[0].protocol",
+		"features.databases[0].port",
+		"methods.docker.volumes.data",
+		"methods.docker.sysctls.ip_forward",
+		"methods.docker.capabilities[0]",
+		"methods.docker.healthcheck",
+	}, fields)
+}
+
+func (suite *TemplateLintTestSuite) TestLintStack() {
+	image := "redis"
+	t := Template{
+		ID:   "wiki",
+		Name: "Wiki",
+		Kind: TemplateKindStack,
+		Stack: &TemplateStack{Containers: []TemplateStackContainer{
+			{Name: "redis", Image: &image},
+			{Name: "app", DependsOn: []string{"db"}},
+		}},
+	}
+	suite.Equal(TemplateIssues{
+		{Field: "stack.containers[1]", Message: "a template or an image is required"},
+		{Field: "stack.containers[1].depends_on", Message: "unknown container db"},
+	}, t.Lint())
+}
+
+func (suite *TemplateLintTestSuite) TestLintTemplates() {
+	report := LintTemplates(map[string][]byte{
+		"redis/service.yml": []byte("id: redis\nname: Redis\nmethods:\n  docker:\n    image: redis\n"),
+		"bad/service.yml":   []byte("id: [bad"),
+	})
+	suite.False(report.Valid)
+	suite.Len(report.Templates, 2)
+	suite.Equal("bad/service.yml", report.Templates[0].File)
+	suite.Len(report.Templates[0].Issues, 1)
+	suite.True(report.Templates[1].Valid)
+}
+
+func (suite *TemplateLintTestSuite) TestLintInputs() {
+	regex := "[a-z"
+	t := Template{
+		TemplateVersioning: TemplateVersioning{Version: 4},
+		ID:                 "app",
+		Name:               "App",
+		Env: []TemplateEnv{
+			{Type: "enum", Name: "LEVEL"},
+			{Type: "number", Name: "COUNT"},
+			{Type: "int", Name: "WORKERS", Default: "many"},
+			{Type: "string", Name: "HOST", Regex: &regex, ShowIf: &TemplateEnvCondition{Name: "ENABLED"}},
+			{Type: "bool", Name: "DEBUG", Generator: &TemplateEnvGenerator{}},
+		},
+		Methods: TemplateMethods{Docker: &TemplateMethodDocker{Image: &regex}},
+	}
+
+	var fields []string
+	for _, issue := range t.Lint() {
+		fields = append(fields, issue.Field)
+	}
+	suite.Equal([]string{
+		"environment[0].choices",
+		"environment[1].type",
+		"environment[2].default",
+		"environment[3].regex",
+		"environment[4].generator",
+		"environment[3].show_if.name",
+	}, fields)
+}
@@ -0,0 +1,132 @@
+package types
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/vertex-center/uuid"
+)
+
+var (
+	ErrTemplateSourceNotFound      = errors.NotFoundf("template source")
+	ErrTemplateSourceAlreadyExists = errors.AlreadyExistsf("template source")
+	ErrTemplateSourceBuiltIn       = errors.NotValidf("built-in template source")
+	ErrInvalidTemplateSource       = errors.NotValidf("template source")
+	ErrInvalidCatalogSignature     = errors.NotValidf("catalog signature")
+)
+
+const (
+	TemplateSourceTypeDir  TemplateSourceType = "dir"
+	TemplateSourceTypeGit  TemplateSourceType = "git"
+	TemplateSourceTypeHTTP TemplateSourceType = "http"
+)
+
+type (
+	TemplateSourceType string
+
+	TemplateSources []TemplateSource
+	TemplateSource  struct {
+		ID        uuid.UUID            `json:"id"                   db:"id"         example:"f6a4bd8c-7c1e-4d5f-9e3a-2b8c1d0e7f4a"`
+		Name      string               `json:"name"                 db:"name"       example:"community"`
+		Type      TemplateSourceType   `json:"type"                 db:"type"       example:"git" enum:"dir,git,http"`
+		URL       string               `json:"url"                  db:"url"        example:"https://github.com/vertex-center/services"` // Path of the directory, or URL of the repository or of the index file.
+		Priority  int                  `json:"priority"             db:"priority"   example:"10"`                                        // Templates of sources with a higher priority replace those with the same ID.
+		PublicKey *string              `json:"public_key,omitempty" db:"public_key" example:"MCowBQYDK2VwAyEA"`                          // Ed25519 public key, base64 encoded. If set, the catalog must be signed with it.
+		BuiltIn   bool                 `json:"built_in"             db:"-"`
+		Status    TemplateSourceStatus `json:"status"               db:"-"`
+	}
+
+	TemplateSourceStatus struct {
+		RefreshedAt *time.Time `json:"refreshed_at,omitempty"`
+		Refreshing  bool       `json:"refreshing"`
+		Templates   int        `json:"templates"`
+		Errors      []string   `json:"errors,omitempty" example:"redis: yaml: line 3: did not find expected key"`
+	}
+)
+
+func (s *TemplateSource) Validate() error {
+	if s.Name == "" {
+		return errors.NewNotValid(ErrInvalidTemplateSource, "the name of the source is empty")
+	}
+	if s.URL == "" {
+		return errors.NewNotValid(ErrInvalidTemplateSource, "the url of the source is empty")
+	}
+
+	switch s.Type {
+	case TemplateSourceTypeDir, TemplateSourceTypeGit:
+	case TemplateSourceTypeHTTP:
+		u, err := url.Parse(s.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return errors.NewNotValid(ErrInvalidTemplateSource, fmt.Sprintf("invalid url '%s'", s.URL))
+		}
+	default:
+		return errors.NewNotValid(ErrInvalidTemplateSource, fmt.Sprintf("invalid type '%s'", s.Type))
+	}
+
+	if s.PublicKey != nil {
+		key, err := base64.StdEncoding.DecodeString(*s.PublicKey)
+		if err != nil || len(key) != ed25519.PublicKeySize {
+			return errors.NewNotValid(ErrInvalidTemplateSource, "the public key must be a base64 encoded ed25519 key")
+		}
+	}
+	return nil
+}
+
+// Verify checks that the catalog content was signed with the public key of
+// the source. The signature is base64 encoded. Sources without public key
+// accept any catalog.
+func (s *TemplateSource) Verify(content []byte, signature []byte) error {
+	if s.PublicKey == nil {
+		return nil
+	}
+	key, err := base64.StdEncoding.DecodeString(*s.PublicKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return ErrInvalidTemplateSource
+	}
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+	if err != nil {
+		return ErrInvalidCatalogSignature
+	}
+	if !ed25519.Verify(key, content, sig) {
+		return ErrInvalidCatalogSignature
+	}
+	return nil
+}
+
+// Sort sorts the sources from the highest priority to the lowest. Sources
+// with the same priority are sorted by name.
+func (s TemplateSources) Sort() {
+	sort.SliceStable(s, func(i, j int) bool {
+		if s[i].Priority != s[j].Priority {
+			return s[i].Priority > s[j].Priority
+		}
+		return s[i].Name < s[j].Name
+	})
+}
+
+// CatalogManifest returns the content signed for a catalog made of files:
+// one line per file with its SHA-256 and its path, sorted by path. It is the
+// output of `sha256sum` on the sorted files.
+func CatalogManifest(files map[string][]byte) []byte {
+	paths := make([]string, 0, len(files))
+	for p := range files {
+		paths = append(paths, p)
+	}
+	slices.Sort(paths)
+
+	var b strings.Builder
+	for _, p := range paths {
+		sum := sha256.Sum256(files[p])
+		b.WriteString(hex.EncodeToString(sum[:]) + "  " + p + "\n")
+	}
+	return []byte(b.String())
+}
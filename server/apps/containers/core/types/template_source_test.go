@@ -0,0 +1,90 @@
+package types
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"testing"
+
+	"github.com/juju/errors"
+	"github.com/stretchr/testify/suite"
+)
+
+type TemplateSourceTestSuite struct {
+	suite.Suite
+}
+
+func TestTemplateSourceTestSuite(t *testing.T) {
+	suite.Run(t, new(TemplateSourceTestSuite))
+}
+
+func (suite *TemplateSourceTestSuite) TestValidate() {
+	key := base64.StdEncoding.EncodeToString(make([]byte, ed25519.PublicKeySize))
+	short := base64.StdEncoding.EncodeToString([]byte("key"))
+
+	tests := []struct {
+		source TemplateSource
+		valid  bool
+	}{
+		{TemplateSource{Name: "local", Type: TemplateSourceTypeDir, URL: "/srv/templates"}, true},
+		{TemplateSource{Name: "community", Type: TemplateSourceTypeGit, URL: "https://github.com/vertex-center/services"}, true},
+		{TemplateSource{Name: "index", Type: TemplateSourceTypeHTTP, URL: "https://example.com/index.yml", PublicKey: &key}, true},
+		{TemplateSource{Name: "index", Type: TemplateSourceTypeHTTP, URL: "ftp://example.com/index.yml"}, false},
+		{TemplateSource{Name: "", Type: TemplateSourceTypeDir, URL: "/srv/templates"}, false},
+		{TemplateSource{Name: "local", Type: "svn", URL: "/srv/templates"}, false},
+		{TemplateSource{Name: "local", Type: TemplateSourceTypeDir, URL: "/srv/templates", PublicKey: &short}, false},
+	}
+	for _, test := range tests {
+		err := test.source.Validate()
+		if test.valid {
+			suite.NoError(err, test.source.Name)
+		} else {
+			suite.True(errors.Is(err, errors.NotValid), test.source.Name)
+		}
+	}
+}
+
+func (suite *TemplateSourceTestSuite) TestVerify() {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	suite.Require().NoError(err)
+
+	key := base64.StdEncoding.EncodeToString(pub)
+	source := TemplateSource{PublicKey: &key}
+
+	content := []byte("templates: []")
+	signature := base64.StdEncoding.EncodeToString(ed25519.Sign(priv, content))
+
+	suite.NoError(source.Verify(content, []byte(signature+"\n")))
+	suite.ErrorIs(source.Verify([]byte("templates: [redis]"), []byte(signature)), ErrInvalidCatalogSignature)
+	suite.ErrorIs(source.Verify(content, []byte("not base64")), ErrInvalidCatalogSignature)
+
+	unsigned := TemplateSource{}
+	suite.NoError(unsigned.Verify(content, nil))
+}
+
+func (suite *TemplateSourceTestSuite) TestSort() {
+	sources := TemplateSources{
+		{Name: "vertex"},
+		{Name: "mirror", Priority: 10},
+		{Name: "community", Priority: 10},
+		{Name: "old", Priority: -1},
+	}
+	sources.Sort()
+
+	var names []string
+	for _, s := range sources {
+		names = append(names, s.Name)
+	}
+	suite.Equal([]string{"community", "mirror", "vertex", "old"}, names)
+}
+
+func (suite *TemplateSourceTestSuite) TestCatalogManifest() {
+	manifest := CatalogManifest(map[string][]byte{
+		"services/redis/service.yml":    []byte(""),
+		"services/postgres/service.yml": []byte(""),
+	})
+	suite.Equal(""+
+		"e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855  services/postgres/service.yml\n"+
+		"e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855  services/redis/service.yml\n",
+		string(manifest))
+}
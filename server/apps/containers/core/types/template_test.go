@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/suite"
+	"gopkg.in/yaml.v3"
 )
 
 type TemplateTestSuite struct {
@@ -117,3 +118,85 @@ func (suite *TemplateTestSuite) TestTemplateUpgradeV3() {
 	suite.Empty(template.Env)
 	suite.Empty(template.URLs)
 }
+
+func (suite *TemplateTestSuite) TestUnmarshalStack() {
+	data := `
+version: 3
+id: app-stack
+name: App
+kind: stack
+environment:
+  - name: TZ
+    default: UTC
+stack:
+  containers:
+    - name: redis
+      image: redis
+    - name: app
+      template: app
+      environment:
+        REDIS_HOST: redis
+      depends_on: [redis]
+`
+
+	var template Template
+	err := yaml.Unmarshal([]byte(data), &template)
+	suite.Require().NoError(err)
+
+	suite.True(template.IsStack())
+	suite.Require().NotNil(template.Stack)
+	suite.Len(template.Stack.Containers, 2)
+	suite.Equal("app", template.Stack.Containers[1].Name)
+	suite.Equal([]string{"redis"}, template.Stack.Containers[1].DependsOn)
+	suite.Equal(map[string]string{"REDIS_HOST": "redis"}, template.Stack.Containers[1].Env)
+}
+
+func (suite *TemplateTestSuite) TestParseTemplate() {
+	t, err := ParseTemplate([]byte(`
+version: 2
+id: custom
+name: Custom
+environment:
+  - type: port
+    name: PORT
+    default: "8080"
+methods:
+  docker:
+    image: nginx
+`))
+	suite.Require().NoError(err)
+	suite.Equal(MaxSupportedVersion, t.Version)
+	suite.Equal([]TemplatePort{{Name: "PORT", Port: "8080"}}, t.Ports)
+	suite.NoError(t.Validate())
+
+	_, err = ParseTemplate([]byte("id: [custom"))
+	suite.Error(err)
+}
+
+func (suite *TemplateTestSuite) TestValidate() {
+	image := "nginx"
+	docker := TemplateMethods{Docker: &TemplateMethodDocker{Image: &image}}
+
+	tests := []struct {
+		name     string
+		template Template
+		valid    bool
+	}{
+		{"container", Template{ID: "nginx", Name: "Nginx", Methods: docker}, true},
+		{"stack", Template{ID: "wiki", Name: "Wiki", Kind: TemplateKindStack, Stack: &TemplateStack{Containers: []TemplateStackContainer{{Name: "db", Image: &image}}}}, true},
+		{"unsupported version", Template{TemplateVersioning: TemplateVersioning{Version: MaxSupportedVersion + 1}, ID: "nginx", Name: "Nginx", Methods: docker}, false},
+		{"invalid id", Template{ID: "My Nginx", Name: "Nginx", Methods: docker}, false},
+		{"no name", Template{ID: "nginx", Methods: docker}, false},
+		{"no image", Template{ID: "nginx", Name: "Nginx"}, false},
+		{"empty stack", Template{ID: "wiki", Name: "Wiki", Kind: TemplateKindStack}, false},
+		{"invalid kind", Template{ID: "nginx", Name: "Nginx", Kind: "vm", Methods: docker}, false},
+	}
+	for _, test := range tests {
+		err := test.template.Validate()
+		if test.valid {
+			suite.NoError(err, test.name)
+		} else {
+			suite.ErrorIs(err, ErrInvalidTemplate, test.name)
+		}
+	}
+}
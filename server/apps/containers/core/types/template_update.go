@@ -0,0 +1,184 @@
+package types
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+
+	"github.com/juju/errors"
+	"gopkg.in/yaml.v3"
+)
+
+var ErrTemplateRevisionNotFound = errors.NotFoundf("template revision")
+
+const (
+	TemplateChangeTypeEnv        TemplateChangeType = "env"
+	TemplateChangeTypePort       TemplateChangeType = "port"
+	TemplateChangeTypeVolume     TemplateChangeType = "volume"
+	TemplateChangeTypeCapability TemplateChangeType = "capability"
+	TemplateChangeTypeImage      TemplateChangeType = "image"
+)
+
+const (
+	TemplateChangeActionAdd    TemplateChangeAction = "add"
+	TemplateChangeActionRemove TemplateChangeAction = "remove"
+	TemplateChangeActionUpdate TemplateChangeAction = "update"
+)
+
+type (
+	TemplateChangeType   string
+	TemplateChangeAction string
+
+	// TemplateUpdate is the difference between the template revision a
+	// container was created from, and the current template.
+	TemplateUpdate struct {
+		Available       bool             `json:"available"`
+		TemplateID      string           `json:"template_id"      example:"postgres"`
+		CurrentRevision string           `json:"current_revision" example:"5f2b7c1d9e0a4b83"`
+		LatestRevision  string           `json:"latest_revision"  example:"a41c3e8b7d62f015"`
+		Changes         []TemplateChange `json:"changes"`
+	}
+
+	TemplateChange struct {
+		ID     string               `json:"id"             example:"env.LOG_LEVEL"` // Type and name of the change, to select it when the update is applied.
+		Type   TemplateChangeType   `json:"type"           example:"env"    enum:"env,port,volume,capability,image"`
+		Action TemplateChangeAction `json:"action"         example:"update" enum:"add,remove,update"`
+		Name   string               `json:"name"           example:"LOG_LEVEL"`
+		From   *string              `json:"from,omitempty" example:"info"`
+		To     *string              `json:"to,omitempty"   example:"warn"`
+		Edited bool                 `json:"edited"` // The user changed the value in the container. Applying the change keeps it.
+	}
+
+	ApplyTemplateUpdateOptions struct {
+		Changes []string          `json:"changes,omitempty" example:"env.LOG_LEVEL"` // IDs of the changes to apply. All the changes are applied when none is given.
+		Env     map[string]string `json:"env,omitempty"`                             // Values of the added variables. Defaults to the default values of the template.
+	}
+)
+
+// Revision identifies the content of the template. It changes whenever the
+// template changes.
+func (s *Template) Revision() (string, error) {
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8]), nil
+}
+
+// DiffTemplates returns the changes from one revision of a template to
+// another. Builds from a repository are not compared.
+func DiffTemplates(from *Template, to *Template) []TemplateChange {
+	changes := []TemplateChange{}
+	add := func(tp TemplateChangeType, action TemplateChangeAction, name string, before *string, after *string) {
+		changes = append(changes, TemplateChange{
+			ID:     string(tp) + "." + name,
+			Type:   tp,
+			Action: action,
+			Name:   name,
+			From:   before,
+			To:     after,
+		})
+	}
+
+	if from.Methods.Docker != nil && to.Methods.Docker != nil {
+		before, after := from.Methods.Docker.Image, to.Methods.Docker.Image
+		if before != nil && after != nil && *before != *after {
+			add(TemplateChangeTypeImage, TemplateChangeActionUpdate, "image", before, after)
+		}
+	}
+
+	for _, e := range to.Env {
+		i := slices.IndexFunc(from.Env, func(prev TemplateEnv) bool { return prev.Name == e.Name })
+		if i == -1 {
+			add(TemplateChangeTypeEnv, TemplateChangeActionAdd, e.Name, nil, &e.Default)
+		} else if prev := from.Env[i]; !prev.equal(e) {
+			add(TemplateChangeTypeEnv, TemplateChangeActionUpdate, e.Name, &prev.Default, &e.Default)
+		}
+	}
+	for _, e := range from.Env {
+		if !slices.ContainsFunc(to.Env, func(next TemplateEnv) bool { return next.Name == e.Name }) {
+			add(TemplateChangeTypeEnv, TemplateChangeActionRemove, e.Name, &e.Default, nil)
+		}
+	}
+
+	for _, p := range to.Ports {
+		i := slices.IndexFunc(from.Ports, func(prev TemplatePort) bool { return prev.Name == p.Name })
+		after := p.String()
+		if i == -1 {
+			add(TemplateChangeTypePort, TemplateChangeActionAdd, p.Name, nil, &after)
+		} else if before := from.Ports[i].String(); before != after {
+			add(TemplateChangeTypePort, TemplateChangeActionUpdate, p.Name, &before, &after)
+		}
+	}
+	for _, p := range from.Ports {
+		if !slices.ContainsFunc(to.Ports, func(next TemplatePort) bool { return next.Name == p.Name }) {
+			before := p.String()
+			add(TemplateChangeTypePort, TemplateChangeActionRemove, p.Name, &before, nil)
+		}
+	}
+
+	// Volumes are identified by their path in the container.
+	before, after := from.volumes(), to.volumes()
+	for _, in := range sortedMapKeys(after) {
+		if _, ok := before[in]; !ok {
+			out := after[in]
+			add(TemplateChangeTypeVolume, TemplateChangeActionAdd, in, nil, &out)
+		}
+	}
+	for _, in := range sortedMapKeys(before) {
+		if _, ok := after[in]; !ok {
+			out := before[in]
+			add(TemplateChangeTypeVolume, TemplateChangeActionRemove, in, &out, nil)
+		}
+	}
+
+	prevCaps, nextCaps := from.capabilities(), to.capabilities()
+	for _, c := range nextCaps {
+		if !slices.Contains(prevCaps, c) {
+			add(TemplateChangeTypeCapability, TemplateChangeActionAdd, c, nil, nil)
+		}
+	}
+	for _, c := range prevCaps {
+		if !slices.Contains(nextCaps, c) {
+			add(TemplateChangeTypeCapability, TemplateChangeActionRemove, c, nil, nil)
+		}
+	}
+	return changes
+}
+
+// String returns the port with its protocol, like 53/udp.
+func (p TemplatePort) String() string {
+	if p.Protocol == "" {
+		return fmt.Sprintf("%s/%s", p.Port, PortProtocolTCP)
+	}
+	return p.Port + "/" + p.Protocol
+}
+
+// equal returns whether the variables are declared the same way. The
+// constraints of the values are compared through their YAML form.
+func (e TemplateEnv) equal(other TemplateEnv) bool {
+	a, errA := yaml.Marshal(e)
+	b, errB := yaml.Marshal(other)
+	return errA == nil && errB == nil && string(a) == string(b)
+}
+
+// volumes returns the host paths of the volumes, by path in the container.
+func (s *Template) volumes() map[string]string {
+	volumes := map[string]string{}
+	if s.Methods.Docker == nil || s.Methods.Docker.Volumes == nil {
+		return volumes
+	}
+	for out, in := range *s.Methods.Docker.Volumes {
+		volumes[in] = out
+	}
+	return volumes
+}
+
+func (s *Template) capabilities() []string {
+	if s.Methods.Docker == nil || s.Methods.Docker.Capabilities == nil {
+		return nil
+	}
+	return *s.Methods.Docker.Capabilities
+}
@@ -0,0 +1,96 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type TemplateUpdateTestSuite struct {
+	suite.Suite
+}
+
+func TestTemplateUpdateTestSuite(t *testing.T) {
+	suite.Run(t, new(TemplateUpdateTestSuite))
+}
+
+func (suite *TemplateUpdateTestSuite) TestRevision() {
+	image := "postgres"
+	t := Template{ID: "postgres", Name: "Postgres", Methods: TemplateMethods{Docker: &TemplateMethodDocker{Image: &image}}}
+
+	rev, err := t.Revision()
+	suite.Require().NoError(err)
+	suite.Len(rev, 16)
+
+	same, err := t.Revision()
+	suite.Require().NoError(err)
+	suite.Equal(rev, same)
+
+	t.Source = "community"
+	same, err = t.Revision()
+	suite.Require().NoError(err)
+	suite.Equal(rev, same, "the source is not part of the template")
+
+	t.Name = "PostgreSQL"
+	other, err := t.Revision()
+	suite.Require().NoError(err)
+	suite.NotEqual(rev, other)
+}
+
+func (suite *TemplateUpdateTestSuite) TestDiffTemplates() {
+	before, after := "postgres:15", "postgres:16"
+	caps := []string{"NET_ADMIN"}
+	nextCaps := []string{"SYS_TIME"}
+	volumes := map[string]string{"data": "/var/lib/postgresql/data", "logs": "/var/log"}
+	nextVolumes := map[string]string{"data": "/var/lib/postgresql/data", "/srv/backups": "/backups"}
+
+	from := &Template{
+		Env: []TemplateEnv{
+			{Type: "string", Name: "LOG_LEVEL", Default: "info"},
+			{Type: "string", Name: "LEGACY"},
+			{Type: "port", Name: "PORT", Default: "5432"},
+		},
+		Ports: []TemplatePort{
+			{Name: "PORT", Port: "5432"},
+			{Name: "METRICS", Port: "9187"},
+		},
+		Methods: TemplateMethods{Docker: &TemplateMethodDocker{Image: &before, Capabilities: &caps, Volumes: &volumes}},
+	}
+	to := &Template{
+		Env: []TemplateEnv{
+			{Type: "enum", Name: "LOG_LEVEL", Default: "warn", Choices: []string{"info", "warn"}},
+			{Type: "port", Name: "PORT", Default: "5432"},
+			{Type: "password", Name: "ADMIN_PASSWORD"},
+		},
+		Ports: []TemplatePort{
+			{Name: "PORT", Port: "5432", Protocol: "tcp"},
+			{Name: "DNS", Port: "53", Protocol: "udp"},
+		},
+		Methods: TemplateMethods{Docker: &TemplateMethodDocker{Image: &after, Capabilities: &nextCaps, Volumes: &nextVolumes}},
+	}
+
+	var ids []string
+	for _, c := range DiffTemplates(from, to) {
+		ids = append(ids, string(c.Action)+" "+c.ID)
+	}
+	suite.Equal([]string{
+		"update image.image",
+		"update env.LOG_LEVEL",
+		"add env.ADMIN_PASSWORD",
+		"remove env.LEGACY",
+		"add port.DNS",
+		"remove port.METRICS",
+		"add volume./backups",
+		"remove volume./var/log",
+		"add capability.SYS_TIME",
+		"remove capability.NET_ADMIN",
+	}, ids)
+
+	changes := DiffTemplates(from, to)
+	suite.Equal("info", *changes[1].From)
+	suite.Equal("warn", *changes[1].To)
+	suite.Equal("53/udp", *changes[4].To)
+	suite.Equal("/srv/backups", *changes[6].To)
+
+	suite.Empty(DiffTemplates(to, to))
+}
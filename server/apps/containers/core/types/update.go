@@ -0,0 +1,81 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+type UpdatePolicy string
+
+const (
+	UpdatePolicyNotify UpdatePolicy = "notify" // Only notify that an update is available.
+	UpdatePolicyAuto   UpdatePolicy = "auto"   // Update automatically, in the maintenance window if any.
+	UpdatePolicyPin    UpdatePolicy = "pin"    // Keep the current image.
+)
+
+var (
+	ErrInvalidUpdatePolicy      = errors.NotValidf("update policy")
+	ErrInvalidMaintenanceWindow = errors.NotValidf("maintenance window")
+	ErrNoUpdateAvailable        = errors.NotFoundf("update")
+	ErrNoPreviousImage          = errors.NotFoundf("previous image")
+)
+
+func (p UpdatePolicy) Validate() error {
+	switch p {
+	case UpdatePolicyNotify, UpdatePolicyAuto, UpdatePolicyPin:
+		return nil
+	}
+	return ErrInvalidUpdatePolicy
+}
+
+// MaintenanceWindow is a daily time range, like "02:00-05:00". The range
+// can go past midnight, like "23:00-01:00".
+type MaintenanceWindow struct {
+	Start time.Duration // Since midnight.
+	End   time.Duration // Since midnight.
+}
+
+func ParseMaintenanceWindow(s string) (MaintenanceWindow, error) {
+	start, end, ok := strings.Cut(s, "-")
+	if !ok {
+		return MaintenanceWindow{}, errors.NewNotValid(ErrInvalidMaintenanceWindow, "the maintenance window must look like 02:00-05:00")
+	}
+
+	var (
+		w   MaintenanceWindow
+		err error
+	)
+	w.Start, err = parseTimeOfDay(start)
+	if err != nil {
+		return MaintenanceWindow{}, err
+	}
+	w.End, err = parseTimeOfDay(end)
+	if err != nil {
+		return MaintenanceWindow{}, err
+	}
+	if w.Start == w.End {
+		return MaintenanceWindow{}, errors.NewNotValid(ErrInvalidMaintenanceWindow, "the maintenance window is empty")
+	}
+	return w, nil
+}
+
+func parseTimeOfDay(s string) (time.Duration, error) {
+	var h, m int
+	_, err := fmt.Sscanf(strings.TrimSpace(s), "%d:%d", &h, &m)
+	if err != nil || h < 0 || h > 23 || m < 0 || m > 59 {
+		return 0, errors.NewNotValid(ErrInvalidMaintenanceWindow, "invalid time "+s)
+	}
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute, nil
+}
+
+// Contains returns true if t is inside the window.
+func (w MaintenanceWindow) Contains(t time.Time) bool {
+	d := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	if w.Start < w.End {
+		return d >= w.Start && d < w.End
+	}
+	return d >= w.Start || d < w.End
+}
@@ -0,0 +1,52 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/stretchr/testify/suite"
+)
+
+type UpdateTestSuite struct {
+	suite.Suite
+}
+
+func TestUpdateTestSuite(t *testing.T) {
+	suite.Run(t, new(UpdateTestSuite))
+}
+
+func (suite *UpdateTestSuite) TestValidatePolicy() {
+	suite.NoError(UpdatePolicyNotify.Validate())
+	suite.NoError(UpdatePolicyAuto.Validate())
+	suite.NoError(UpdatePolicyPin.Validate())
+	suite.True(errors.Is(UpdatePolicy("sometimes").Validate(), errors.NotValid))
+}
+
+func (suite *UpdateTestSuite) TestMaintenanceWindow() {
+	at := func(h, m int) time.Time {
+		return time.Date(2024, 1, 1, h, m, 0, 0, time.Local)
+	}
+
+	w, err := ParseMaintenanceWindow("02:00-05:30")
+	suite.Require().NoError(err)
+	suite.True(w.Contains(at(2, 0)))
+	suite.True(w.Contains(at(5, 29)))
+	suite.False(w.Contains(at(5, 30)))
+	suite.False(w.Contains(at(1, 59)))
+
+	// The window goes past midnight.
+	w, err = ParseMaintenanceWindow("23:00-01:00")
+	suite.Require().NoError(err)
+	suite.True(w.Contains(at(23, 30)))
+	suite.True(w.Contains(at(0, 30)))
+	suite.False(w.Contains(at(1, 0)))
+	suite.False(w.Contains(at(12, 0)))
+}
+
+func (suite *UpdateTestSuite) TestParseMaintenanceWindowInvalid() {
+	for _, s := range []string{"", "02:00", "25:00-03:00", "02:00-02:00", "a-b"} {
+		_, err := ParseMaintenanceWindow(s)
+		suite.True(errors.Is(err, errors.NotValid), s)
+	}
+}
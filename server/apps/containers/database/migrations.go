@@ -10,6 +10,24 @@ var Migrations = []vsql.Migration{
 	&v1{}, // Rename service_id to template_id
 	&v2{}, // Make template_id nullable
 	&v3{}, // Move external port from env variable to ports table
+
+	// 0.17
+	&v4{},  // Add health_checks table
+	&v5{},  // Add restart policy to containers
+	&v6{},  // Add container_dependencies table
+	&v7{},  // Add stacks table
+	&v8{},  // Add resource_limits and ulimits tables
+	&v9{},  // Add update policy to containers
+	&v10{}, // Add registry_credentials table
+	&v11{}, // Add container_builds and build_args tables
+	&v12{}, // Add backups, backup_volumes and backup_schedules tables
+	&v13{}, // Add networks, container_networks and container_network_aliases tables
+	&v14{}, // Add protocol to ports
+	&v15{}, // Add template_sources table
+	&v16{}, // Add user_templates table
+	&v17{}, // Add template_revisions table and template_revision to containers
+	&v18{}, // Add template_out to ports
+	&v19{}, // Add stopped_by_user to containers
 }
 
 type v1 struct{}
@@ -65,3 +83,351 @@ func (m *v3) Up(tx *sqlx.Tx) error {
     `)
 	return err
 }
+
+type v4 struct{}
+
+func (m *v4) Up(tx *sqlx.Tx) error {
+	_, err := tx.Exec(`
+		CREATE TABLE health_checks (
+			id VARCHAR(36) NOT NULL PRIMARY KEY,
+			container_id VARCHAR(36) NOT NULL UNIQUE,
+			type VARCHAR(255) NOT NULL,
+			port VARCHAR(255),
+			path VARCHAR(255),
+			command VARCHAR(255),
+			check_interval VARCHAR(255) NOT NULL,
+			check_timeout VARCHAR(255) NOT NULL,
+			start_period VARCHAR(255) NOT NULL,
+			retries INTEGER NOT NULL,
+			FOREIGN KEY (container_id) REFERENCES containers(id)
+		);
+	`)
+	return err
+}
+
+type v5 struct{}
+
+func (m *v5) Up(tx *sqlx.Tx) error {
+	_, err := tx.Exec(`
+		ALTER TABLE containers
+		ADD COLUMN restart_policy VARCHAR(255) NOT NULL DEFAULT 'no';
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		ALTER TABLE containers
+		ADD COLUMN restart_max_retries INTEGER NOT NULL DEFAULT 0;
+	`)
+	return err
+}
+
+type v6 struct{}
+
+func (m *v6) Up(tx *sqlx.Tx) error {
+	_, err := tx.Exec(`
+		CREATE TABLE container_dependencies (
+			container_id VARCHAR(36) NOT NULL,
+			depends_on_id VARCHAR(36) NOT NULL,
+			wait_for VARCHAR(255) NOT NULL,
+			PRIMARY KEY (container_id, depends_on_id),
+			FOREIGN KEY (container_id) REFERENCES containers(id),
+			FOREIGN KEY (depends_on_id) REFERENCES containers(id)
+		);
+	`)
+	return err
+}
+
+type v7 struct{}
+
+func (m *v7) Up(tx *sqlx.Tx) error {
+	_, err := tx.Exec(`
+		CREATE TABLE stacks (
+			id VARCHAR(36) NOT NULL PRIMARY KEY,
+			template_id VARCHAR(255),
+			user_id VARCHAR(36) NOT NULL,
+			name VARCHAR(255) NOT NULL,
+			description VARCHAR(255),
+			color VARCHAR(7),
+			icon VARCHAR(255)
+		);
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		ALTER TABLE containers
+		ADD COLUMN stack_id VARCHAR(36);
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		ALTER TABLE containers
+		ADD COLUMN stack_member VARCHAR(255);
+	`)
+	return err
+}
+
+type v8 struct{}
+
+func (m *v8) Up(tx *sqlx.Tx) error {
+	_, err := tx.Exec(`
+		CREATE TABLE resource_limits (
+			id VARCHAR(36) NOT NULL PRIMARY KEY,
+			container_id VARCHAR(36) NOT NULL UNIQUE,
+			cpu_shares BIGINT,
+			cpu_period BIGINT,
+			cpu_quota BIGINT,
+			memory BIGINT,
+			memory_swap BIGINT,
+			pids_limit BIGINT,
+			FOREIGN KEY (container_id) REFERENCES containers(id)
+		);
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		CREATE TABLE ulimits (
+			id VARCHAR(36) NOT NULL PRIMARY KEY,
+			container_id VARCHAR(36) NOT NULL,
+			name VARCHAR(255) NOT NULL,
+			soft BIGINT NOT NULL,
+			hard BIGINT NOT NULL,
+			FOREIGN KEY (container_id) REFERENCES containers(id)
+		);
+	`)
+	return err
+}
+
+type v9 struct{}
+
+func (m *v9) Up(tx *sqlx.Tx) error {
+	for _, column := range []string{
+		"update_policy VARCHAR(255) NOT NULL DEFAULT 'notify'",
+		"update_window VARCHAR(255)",
+		"pinned_image VARCHAR(255)",
+		"previous_image VARCHAR(255)",
+	} {
+		_, err := tx.Exec(`ALTER TABLE containers ADD COLUMN ` + column + `;`)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type v10 struct{}
+
+func (m *v10) Up(tx *sqlx.Tx) error {
+	_, err := tx.Exec(`
+		CREATE TABLE registry_credentials (
+			id VARCHAR(36) NOT NULL PRIMARY KEY,
+			registry VARCHAR(255) NOT NULL UNIQUE,
+			username VARCHAR(255) NOT NULL,
+			password TEXT NOT NULL
+		);
+	`)
+	return err
+}
+
+type v11 struct{}
+
+func (m *v11) Up(tx *sqlx.Tx) error {
+	_, err := tx.Exec(`
+		CREATE TABLE container_builds (
+			id VARCHAR(36) NOT NULL PRIMARY KEY,
+			container_id VARCHAR(36) NOT NULL UNIQUE,
+			repository VARCHAR(255) NOT NULL,
+			dockerfile VARCHAR(255) NOT NULL,
+			FOREIGN KEY (container_id) REFERENCES containers(id)
+		);
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		CREATE TABLE build_args (
+			id VARCHAR(36) NOT NULL PRIMARY KEY,
+			container_id VARCHAR(36) NOT NULL,
+			name VARCHAR(255) NOT NULL,
+			value VARCHAR(255) NOT NULL,
+			FOREIGN KEY (container_id) REFERENCES containers(id)
+		);
+	`)
+	return err
+}
+
+type v12 struct{}
+
+func (m *v12) Up(tx *sqlx.Tx) error {
+	_, err := tx.Exec(`
+		CREATE TABLE backups (
+			id VARCHAR(36) NOT NULL PRIMARY KEY,
+			container_id VARCHAR(36) NOT NULL,
+			version INTEGER NOT NULL,
+			created_at INTEGER NOT NULL,
+			size BIGINT NOT NULL
+		);
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		CREATE TABLE backup_volumes (
+			id VARCHAR(36) NOT NULL PRIMARY KEY,
+			backup_id VARCHAR(36) NOT NULL,
+			volume_id VARCHAR(36) NOT NULL,
+			type VARCHAR(255) NOT NULL,
+			name VARCHAR(255) NOT NULL,
+			path VARCHAR(255) NOT NULL,
+			archive VARCHAR(255) NOT NULL,
+			size BIGINT NOT NULL,
+			checksum VARCHAR(255) NOT NULL,
+			FOREIGN KEY (backup_id) REFERENCES backups(id)
+		);
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		CREATE TABLE backup_schedules (
+			container_id VARCHAR(36) NOT NULL PRIMARY KEY,
+			schedule VARCHAR(255) NOT NULL,
+			retention INTEGER NOT NULL,
+			stop_container BOOLEAN NOT NULL
+		);
+	`)
+	return err
+}
+
+type v13 struct{}
+
+func (m *v13) Up(tx *sqlx.Tx) error {
+	_, err := tx.Exec(`
+		CREATE TABLE networks (
+			id VARCHAR(36) NOT NULL PRIMARY KEY,
+			name VARCHAR(255) NOT NULL UNIQUE,
+			created_at INTEGER NOT NULL
+		);
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		CREATE TABLE container_networks (
+			container_id VARCHAR(36) NOT NULL,
+			network_id VARCHAR(36) NOT NULL,
+			PRIMARY KEY (container_id, network_id),
+			FOREIGN KEY (container_id) REFERENCES containers(id),
+			FOREIGN KEY (network_id) REFERENCES networks(id)
+		);
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		CREATE TABLE container_network_aliases (
+			container_id VARCHAR(36) NOT NULL,
+			network_id VARCHAR(36) NOT NULL,
+			alias VARCHAR(255) NOT NULL,
+			PRIMARY KEY (container_id, network_id, alias),
+			FOREIGN KEY (container_id) REFERENCES containers(id),
+			FOREIGN KEY (network_id) REFERENCES networks(id)
+		);
+	`)
+	return err
+}
+
+type v14 struct{}
+
+func (m *v14) Up(tx *sqlx.Tx) error {
+	_, err := tx.Exec(`
+		ALTER TABLE ports
+		ADD COLUMN protocol VARCHAR(255) NOT NULL DEFAULT 'tcp';
+	`)
+	return err
+}
+
+type v15 struct{}
+
+func (m *v15) Up(tx *sqlx.Tx) error {
+	_, err := tx.Exec(`
+		CREATE TABLE template_sources (
+			id VARCHAR(36) NOT NULL PRIMARY KEY,
+			name VARCHAR(255) NOT NULL UNIQUE,
+			type VARCHAR(255) NOT NULL,
+			url TEXT NOT NULL,
+			priority INTEGER NOT NULL DEFAULT 0,
+			public_key TEXT
+		);
+	`)
+	return err
+}
+
+type v16 struct{}
+
+func (m *v16) Up(tx *sqlx.Tx) error {
+	_, err := tx.Exec(`
+		CREATE TABLE user_templates (
+			id VARCHAR(255) NOT NULL PRIMARY KEY,
+			user_id VARCHAR(36) NOT NULL,
+			content TEXT NOT NULL,
+			created_at INTEGER NOT NULL
+		);
+	`)
+	return err
+}
+
+type v17 struct{}
+
+func (m *v17) Up(tx *sqlx.Tx) error {
+	_, err := tx.Exec(`
+		CREATE TABLE template_revisions (
+			template_id VARCHAR(255) NOT NULL,
+			revision VARCHAR(64) NOT NULL,
+			content TEXT NOT NULL,
+			created_at INTEGER NOT NULL,
+			PRIMARY KEY (template_id, revision)
+		);
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		ALTER TABLE containers
+		ADD COLUMN template_revision VARCHAR(64);
+	`)
+	return err
+}
+
+type v18 struct{}
+
+func (m *v18) Up(tx *sqlx.Tx) error {
+	_, err := tx.Exec(`
+		ALTER TABLE ports
+		ADD COLUMN template_out VARCHAR(255);
+	`)
+	return err
+}
+
+type v19 struct{}
+
+func (m *v19) Up(tx *sqlx.Tx) error {
+	_, err := tx.Exec(`
+		ALTER TABLE containers
+		ADD COLUMN stopped_by_user BOOLEAN NOT NULL DEFAULT FALSE;
+	`)
+	return err
+}
@@ -18,7 +18,26 @@ func GetSchema(driver vsql.Driver) string {
 			WithField("description", "VARCHAR(255)").
 			WithField("color", "VARCHAR(7)").
 			WithField("icon", "VARCHAR(255)").
-			WithField("command", "VARCHAR(255)"),
+			WithField("command", "VARCHAR(255)").
+			WithField("restart_policy", "VARCHAR(255)", "NOT NULL", "DEFAULT 'no'").
+			WithField("restart_max_retries", "INTEGER", "NOT NULL", "DEFAULT 0").
+			WithField("stopped_by_user", "BOOLEAN", "NOT NULL", "DEFAULT FALSE").
+			WithField("stack_id", "VARCHAR(36)").
+			WithField("stack_member", "VARCHAR(255)").
+			WithField("update_policy", "VARCHAR(255)", "NOT NULL", "DEFAULT 'notify'").
+			WithField("update_window", "VARCHAR(255)").
+			WithField("pinned_image", "VARCHAR(255)").
+			WithField("previous_image", "VARCHAR(255)").
+			WithField("template_revision", "VARCHAR(64)"),
+
+		vsql.CreateTable("stacks").
+			WithField("id", "VARCHAR(36)", "NOT NULL", "PRIMARY KEY").
+			WithField("template_id", "VARCHAR(255)").
+			WithField("user_id", "VARCHAR(36)", "NOT NULL").
+			WithField("name", "VARCHAR(255)", "NOT NULL").
+			WithField("description", "VARCHAR(255)").
+			WithField("color", "VARCHAR(7)").
+			WithField("icon", "VARCHAR(255)"),
 
 		vsql.CreateTable("env_variables").
 			WithField("id", "VARCHAR(36)", "NOT NULL", "PRIMARY KEY").
@@ -43,6 +62,8 @@ func GetSchema(driver vsql.Driver) string {
 			WithField("container_id", "VARCHAR(36)", "NOT NULL").
 			WithField("internal_port", "VARCHAR(255)", "NOT NULL").
 			WithField("external_port", "VARCHAR(255)", "NOT NULL").
+			WithField("protocol", "VARCHAR(255)", "NOT NULL", "DEFAULT 'tcp'").
+			WithField("template_out", "VARCHAR(255)").
 			WithForeignKey("container_id", "containers", "id"),
 
 		vsql.CreateTable("volumes").
@@ -60,6 +81,60 @@ func GetSchema(driver vsql.Driver) string {
 			WithField("value", "VARCHAR(255)", "NOT NULL").
 			WithForeignKey("container_id", "containers", "id"),
 
+		vsql.CreateTable("health_checks").
+			WithField("id", "VARCHAR(36)", "NOT NULL", "PRIMARY KEY").
+			WithField("container_id", "VARCHAR(36)", "NOT NULL", "UNIQUE").
+			WithField("type", "VARCHAR(255)", "NOT NULL").
+			WithField("port", "VARCHAR(255)").
+			WithField("path", "VARCHAR(255)").
+			WithField("command", "VARCHAR(255)").
+			WithField("check_interval", "VARCHAR(255)", "NOT NULL").
+			WithField("check_timeout", "VARCHAR(255)", "NOT NULL").
+			WithField("start_period", "VARCHAR(255)", "NOT NULL").
+			WithField("retries", "INTEGER", "NOT NULL").
+			WithForeignKey("container_id", "containers", "id"),
+
+		vsql.CreateTable("resource_limits").
+			WithField("id", "VARCHAR(36)", "NOT NULL", "PRIMARY KEY").
+			WithField("container_id", "VARCHAR(36)", "NOT NULL", "UNIQUE").
+			WithField("cpu_shares", "BIGINT").
+			WithField("cpu_period", "BIGINT").
+			WithField("cpu_quota", "BIGINT").
+			WithField("memory", "BIGINT").
+			WithField("memory_swap", "BIGINT").
+			WithField("pids_limit", "BIGINT").
+			WithForeignKey("container_id", "containers", "id"),
+
+		vsql.CreateTable("ulimits").
+			WithField("id", "VARCHAR(36)", "NOT NULL", "PRIMARY KEY").
+			WithField("container_id", "VARCHAR(36)", "NOT NULL").
+			WithField("name", "VARCHAR(255)", "NOT NULL").
+			WithField("soft", "BIGINT", "NOT NULL").
+			WithField("hard", "BIGINT", "NOT NULL").
+			WithForeignKey("container_id", "containers", "id"),
+
+		vsql.CreateTable("container_builds").
+			WithField("id", "VARCHAR(36)", "NOT NULL", "PRIMARY KEY").
+			WithField("container_id", "VARCHAR(36)", "NOT NULL", "UNIQUE").
+			WithField("repository", "VARCHAR(255)", "NOT NULL").
+			WithField("dockerfile", "VARCHAR(255)", "NOT NULL").
+			WithForeignKey("container_id", "containers", "id"),
+
+		vsql.CreateTable("build_args").
+			WithField("id", "VARCHAR(36)", "NOT NULL", "PRIMARY KEY").
+			WithField("container_id", "VARCHAR(36)", "NOT NULL").
+			WithField("name", "VARCHAR(255)", "NOT NULL").
+			WithField("value", "VARCHAR(255)", "NOT NULL").
+			WithForeignKey("container_id", "containers", "id"),
+
+		vsql.CreateTable("container_dependencies").
+			WithField("container_id", "VARCHAR(36)", "NOT NULL").
+			WithField("depends_on_id", "VARCHAR(36)", "NOT NULL").
+			WithField("wait_for", "VARCHAR(255)", "NOT NULL").
+			WithPrimaryKey("container_id", "depends_on_id").
+			WithForeignKey("container_id", "containers", "id").
+			WithForeignKey("depends_on_id", "containers", "id"),
+
 		vsql.CreateTable("tags").
 			WithField("id", "VARCHAR(36)", "NOT NULL", "PRIMARY KEY").
 			WithField("user_id", "VARCHAR(36)", "NOT NULL").
@@ -71,5 +146,79 @@ func GetSchema(driver vsql.Driver) string {
 			WithPrimaryKey("container_id", "tag_id").
 			WithForeignKey("container_id", "containers", "id").
 			WithForeignKey("tag_id", "tags", "id"),
+
+		// Backups are not linked to containers with a foreign key, because
+		// they are deleted after the container.
+		vsql.CreateTable("backups").
+			WithField("id", "VARCHAR(36)", "NOT NULL", "PRIMARY KEY").
+			WithField("container_id", "VARCHAR(36)", "NOT NULL").
+			WithField("version", "INTEGER", "NOT NULL").
+			WithCreatedAt().
+			WithField("size", "BIGINT", "NOT NULL"),
+
+		vsql.CreateTable("backup_volumes").
+			WithField("id", "VARCHAR(36)", "NOT NULL", "PRIMARY KEY").
+			WithField("backup_id", "VARCHAR(36)", "NOT NULL").
+			WithField("volume_id", "VARCHAR(36)", "NOT NULL").
+			WithField("type", "VARCHAR(255)", "NOT NULL").
+			WithField("name", "VARCHAR(255)", "NOT NULL").
+			WithField("path", "VARCHAR(255)", "NOT NULL").
+			WithField("archive", "VARCHAR(255)", "NOT NULL").
+			WithField("size", "BIGINT", "NOT NULL").
+			WithField("checksum", "VARCHAR(255)", "NOT NULL").
+			WithForeignKey("backup_id", "backups", "id"),
+
+		vsql.CreateTable("backup_schedules").
+			WithField("container_id", "VARCHAR(36)", "NOT NULL", "PRIMARY KEY").
+			WithField("schedule", "VARCHAR(255)", "NOT NULL").
+			WithField("retention", "INTEGER", "NOT NULL").
+			WithField("stop_container", "BOOLEAN", "NOT NULL"),
+
+		vsql.CreateTable("registry_credentials").
+			WithField("id", "VARCHAR(36)", "NOT NULL", "PRIMARY KEY").
+			WithField("registry", "VARCHAR(255)", "NOT NULL", "UNIQUE").
+			WithField("username", "VARCHAR(255)", "NOT NULL").
+			WithField("password", "TEXT", "NOT NULL"),
+
+		vsql.CreateTable("networks").
+			WithField("id", "VARCHAR(36)", "NOT NULL", "PRIMARY KEY").
+			WithField("name", "VARCHAR(255)", "NOT NULL", "UNIQUE").
+			WithCreatedAt(),
+
+		vsql.CreateTable("container_networks").
+			WithField("container_id", "VARCHAR(36)", "NOT NULL").
+			WithField("network_id", "VARCHAR(36)", "NOT NULL").
+			WithPrimaryKey("container_id", "network_id").
+			WithForeignKey("container_id", "containers", "id").
+			WithForeignKey("network_id", "networks", "id"),
+
+		vsql.CreateTable("container_network_aliases").
+			WithField("container_id", "VARCHAR(36)", "NOT NULL").
+			WithField("network_id", "VARCHAR(36)", "NOT NULL").
+			WithField("alias", "VARCHAR(255)", "NOT NULL").
+			WithPrimaryKey("container_id", "network_id", "alias").
+			WithForeignKey("container_id", "containers", "id").
+			WithForeignKey("network_id", "networks", "id"),
+
+		vsql.CreateTable("template_sources").
+			WithField("id", "VARCHAR(36)", "NOT NULL", "PRIMARY KEY").
+			WithField("name", "VARCHAR(255)", "NOT NULL", "UNIQUE").
+			WithField("type", "VARCHAR(255)", "NOT NULL").
+			WithField("url", "TEXT", "NOT NULL").
+			WithField("priority", "INTEGER", "NOT NULL", "DEFAULT 0").
+			WithField("public_key", "TEXT"),
+
+		vsql.CreateTable("user_templates").
+			WithField("id", "VARCHAR(255)", "NOT NULL", "PRIMARY KEY").
+			WithField("user_id", "VARCHAR(36)", "NOT NULL").
+			WithField("content", "TEXT", "NOT NULL").
+			WithCreatedAt(),
+
+		vsql.CreateTable("template_revisions").
+			WithField("template_id", "VARCHAR(255)", "NOT NULL").
+			WithField("revision", "VARCHAR(64)", "NOT NULL").
+			WithField("content", "TEXT", "NOT NULL").
+			WithCreatedAt().
+			WithPrimaryKey("template_id", "revision"),
 	)
 }
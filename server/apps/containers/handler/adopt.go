@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/loopfz/gadgeto/tonic"
+	"github.com/vertex-center/vertex/server/apps/containers/core/port"
+	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+)
+
+type adoptHandler struct {
+	adoptService port.AdoptService
+}
+
+func NewAdoptHandler(adoptService port.AdoptService) port.AdoptHandler {
+	return &adoptHandler{adoptService}
+}
+
+func (h *adoptHandler) GetUnmanagedContainers() gin.HandlerFunc {
+	return tonic.Handler(func(ctx *gin.Context) ([]types.DockerContainer, error) {
+		return h.adoptService.GetUnmanagedContainers(ctx)
+	}, http.StatusOK)
+}
+
+type AdoptParams struct {
+	DockerID string  `path:"docker_id"`
+	Name     *string `json:"name,omitempty"`
+}
+
+func (h *adoptHandler) Adopt() gin.HandlerFunc {
+	return tonic.Handler(func(ctx *gin.Context, params *AdoptParams) (*types.ContainerAdoption, error) {
+		return h.adoptService.Adopt(ctx, params.DockerID, types.AdoptContainerOptions{
+			Name: params.Name,
+		})
+	}, http.StatusCreated)
+}
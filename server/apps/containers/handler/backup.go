@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/loopfz/gadgeto/tonic"
+	"github.com/vertex-center/uuid"
+	"github.com/vertex-center/vertex/server/apps/containers/core/port"
+	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+)
+
+type backupHandler struct {
+	backupService port.BackupService
+}
+
+func NewBackupHandler(backupService port.BackupService) port.BackupHandler {
+	return &backupHandler{backupService}
+}
+
+type GetBackupsParams struct {
+	ContainerID uuid.NullUUID `path:"container_id"`
+}
+
+func (h *backupHandler) GetBackups() gin.HandlerFunc {
+	return tonic.Handler(func(ctx *gin.Context, params *GetBackupsParams) (types.Backups, error) {
+		return h.backupService.GetBackups(ctx, params.ContainerID.UUID)
+	}, http.StatusOK)
+}
+
+type CreateBackupParams struct {
+	ContainerID uuid.NullUUID `path:"container_id"`
+	Stop        bool          `json:"stop"` // Stop the container during the backup.
+}
+
+func (h *backupHandler) CreateBackup() gin.HandlerFunc {
+	return tonic.Handler(func(ctx *gin.Context, params *CreateBackupParams) (*types.Backup, error) {
+		return h.backupService.CreateBackup(ctx, params.ContainerID.UUID, params.Stop)
+	}, http.StatusCreated)
+}
+
+type DeleteBackupParams struct {
+	ContainerID uuid.NullUUID `path:"container_id"`
+	BackupID    uuid.NullUUID `path:"backup_id"`
+}
+
+func (h *backupHandler) DeleteBackup() gin.HandlerFunc {
+	return tonic.Handler(func(ctx *gin.Context, params *DeleteBackupParams) error {
+		return h.backupService.DeleteBackup(ctx, params.ContainerID.UUID, params.BackupID.UUID)
+	}, http.StatusOK)
+}
+
+type RestoreBackupParams struct {
+	ContainerID uuid.NullUUID `path:"container_id"`
+	BackupID    uuid.NullUUID `path:"backup_id"`
+}
+
+func (h *backupHandler) RestoreBackup() gin.HandlerFunc {
+	return tonic.Handler(func(ctx *gin.Context, params *RestoreBackupParams) error {
+		return h.backupService.RestoreBackup(ctx, params.ContainerID.UUID, params.BackupID.UUID)
+	}, http.StatusOK)
+}
+
+type GetBackupScheduleParams struct {
+	ContainerID uuid.NullUUID `path:"container_id"`
+}
+
+func (h *backupHandler) GetBackupSchedule() gin.HandlerFunc {
+	return tonic.Handler(func(ctx *gin.Context, params *GetBackupScheduleParams) (*types.BackupSchedule, error) {
+		return h.backupService.GetBackupSchedule(ctx, params.ContainerID.UUID)
+	}, http.StatusOK)
+}
+
+type SetBackupScheduleParams struct {
+	ContainerID   uuid.NullUUID `path:"container_id"`
+	Schedule      string        `json:"schedule"`
+	Retention     int           `json:"retention"`
+	StopContainer bool          `json:"stop_container"`
+}
+
+func (h *backupHandler) SetBackupSchedule() gin.HandlerFunc {
+	return tonic.Handler(func(ctx *gin.Context, params *SetBackupScheduleParams) error {
+		return h.backupService.SetBackupSchedule(ctx, types.BackupSchedule{
+			ContainerID:   params.ContainerID.UUID,
+			Schedule:      params.Schedule,
+			Retention:     params.Retention,
+			StopContainer: params.StopContainer,
+		})
+	}, http.StatusOK)
+}
+
+type DeleteBackupScheduleParams struct {
+	ContainerID uuid.NullUUID `path:"container_id"`
+}
+
+func (h *backupHandler) DeleteBackupSchedule() gin.HandlerFunc {
+	return tonic.Handler(func(ctx *gin.Context, params *DeleteBackupScheduleParams) error {
+		return h.backupService.DeleteBackupSchedule(ctx, params.ContainerID.UUID)
+	}, http.StatusOK)
+}
@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/loopfz/gadgeto/tonic"
+	"github.com/vertex-center/uuid"
+	"github.com/vertex-center/vertex/server/apps/containers/core/port"
+	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+)
+
+type composeHandler struct {
+	composeService port.ComposeService
+}
+
+func NewComposeHandler(composeService port.ComposeService) port.ComposeHandler {
+	return &composeHandler{composeService}
+}
+
+type ImportComposeParams struct {
+	Compose string `json:"compose"`
+}
+
+func (h *composeHandler) Import() gin.HandlerFunc {
+	return tonic.Handler(func(ctx *gin.Context, params *ImportComposeParams) (*types.ComposeImport, error) {
+		return h.composeService.Import(ctx, []byte(params.Compose))
+	}, http.StatusCreated)
+}
+
+type ExportComposeParams struct {
+	ContainerIDs []uuid.UUID `json:"container_ids"`
+}
+
+func (h *composeHandler) Export() gin.HandlerFunc {
+	return tonic.Handler(func(ctx *gin.Context, params *ExportComposeParams) (*types.ComposeExport, error) {
+		return h.composeService.Export(ctx, params.ContainerIDs)
+	}, http.StatusOK)
+}
@@ -1,8 +1,10 @@
 package handler
 
 import (
+	"fmt"
 	"io"
 	"net/http"
+	"time"
 
 	"github.com/gin-contrib/sse"
 	"github.com/gin-gonic/gin"
@@ -57,17 +59,22 @@ func (h *containerHandler) GetContainers() gin.HandlerFunc {
 }
 
 type CreateContainerParams struct {
-	TemplateID *string `json:"template_id,omitempty"`
-	Image      *string `json:"image,omitempty"`
-	ImageTag   *string `json:"image_tag,omitempty"`
+	TemplateID    *string `json:"template_id,omitempty"`
+	Image         *string `json:"image,omitempty"`
+	ImageTag      *string `json:"image_tag,omitempty"`
+	AllocatePorts bool    `json:"allocate_ports,omitempty"`
+
+	Env map[string]string `json:"env,omitempty"`
 }
 
 func (h *containerHandler) CreateContainer() gin.HandlerFunc {
 	return tonic.Handler(func(ctx *gin.Context, params *CreateContainerParams) (*types.Container, error) {
 		return h.containerService.CreateContainer(ctx, types.CreateContainerOptions{
-			TemplateID: params.TemplateID,
-			Image:      params.Image,
-			ImageTag:   params.ImageTag,
+			TemplateID:    params.TemplateID,
+			Image:         params.Image,
+			ImageTag:      params.ImageTag,
+			AllocatePorts: params.AllocatePorts,
+			Env:           params.Env,
 		})
 	}, http.StatusCreated)
 }
@@ -88,10 +95,14 @@ type PatchBodyDatabase struct {
 }
 
 type PatchContainerParams struct {
-	ContainerID     uuid.NullUUID `path:"container_id"`
-	LaunchOnStartup *bool         `json:"launch_on_startup,omitempty"`
-	Name            *string       `json:"name,omitempty"`
-	ImageTag        *string       `json:"image_tag,omitempty"`
+	ContainerID       uuid.NullUUID        `path:"container_id"`
+	LaunchOnStartup   *bool                `json:"launch_on_startup,omitempty"`
+	Name              *string              `json:"name,omitempty"`
+	ImageTag          *string              `json:"image_tag,omitempty"`
+	RestartPolicy     *types.RestartPolicy `json:"restart_policy,omitempty" enum:"no,on-failure,always,unless-stopped"`
+	RestartMaxRetries *int                 `json:"restart_max_retries,omitempty"`
+
+	Databases map[string]PatchBodyDatabase `json:"databases,omitempty"` // Databases to link the container to, by database ID of the template.
 }
 
 func (h *containerHandler) Patch() gin.HandlerFunc {
@@ -110,6 +121,25 @@ func (h *containerHandler) Patch() gin.HandlerFunc {
 		if params.ImageTag != nil {
 			c.ImageTag = *params.ImageTag
 		}
+		if params.RestartPolicy != nil {
+			c.RestartPolicy = *params.RestartPolicy
+		}
+		if params.RestartMaxRetries != nil {
+			c.RestartMaxRetries = *params.RestartMaxRetries
+		}
+
+		if params.Databases != nil {
+			databases := map[string]uuid.UUID{}
+			options := map[string]*types.SetDatabasesOptions{}
+			for id, db := range params.Databases {
+				databases[id] = db.ContainerID.UUID
+				options[id] = &types.SetDatabasesOptions{DatabaseName: db.DatabaseName}
+			}
+			err = h.containerService.SetDatabases(ctx, c, databases, options)
+			if err != nil {
+				return err
+			}
+		}
 
 		return h.containerService.UpdateContainer(ctx, params.ContainerID.UUID, *c)
 	}, http.StatusOK)
@@ -162,6 +192,33 @@ func (h *containerHandler) RecreateDocker() gin.HandlerFunc {
 	}, http.StatusNoContent)
 }
 
+type DuplicateContainerParams struct {
+	ContainerID uuid.NullUUID `path:"container_id"`
+	Name        *string       `json:"name,omitempty"`
+	CopyVolumes bool          `json:"copy_volumes"`
+	Stop        bool          `json:"stop"`
+}
+
+func (h *containerHandler) DuplicateContainer() gin.HandlerFunc {
+	return tonic.Handler(func(ctx *gin.Context, params *DuplicateContainerParams) (*types.Container, error) {
+		return h.containerService.DuplicateContainer(ctx, params.ContainerID.UUID, types.DuplicateContainerOptions{
+			Name:        params.Name,
+			CopyVolumes: params.CopyVolumes,
+			Stop:        params.Stop,
+		})
+	}, http.StatusCreated)
+}
+
+type RebuildContainerParams struct {
+	ContainerID uuid.NullUUID `path:"container_id"`
+}
+
+func (h *containerHandler) RebuildContainer() gin.HandlerFunc {
+	return tonic.Handler(func(ctx *gin.Context, params *RebuildContainerParams) error {
+		return h.containerService.RebuildContainer(ctx, params.ContainerID.UUID)
+	}, http.StatusOK)
+}
+
 type ReloadContainerParams struct {
 	ContainerID uuid.NullUUID `path:"container_id"`
 }
@@ -174,14 +231,72 @@ func (h *containerHandler) ReloadContainer() gin.HandlerFunc {
 
 type LogsContainerParams struct {
 	ContainerID uuid.NullUUID `path:"container_id"`
+	Since       time.Time     `query:"since"`
+	Until       time.Time     `query:"until"`
+	Query       string        `query:"q"`
+	Kinds       []string      `query:"kind"`
+	Levels      []string      `query:"level"`
+	Limit       int           `query:"limit"`
 }
 
 func (h *containerHandler) GetLogs() gin.HandlerFunc {
 	return tonic.Handler(func(ctx *gin.Context, params *LogsContainerParams) ([]types.LogLine, error) {
-		return h.containerService.GetLatestLogs(params.ContainerID.UUID)
+		filters := types.LogFilters{
+			Since:  params.Since,
+			Until:  params.Until,
+			Query:  params.Query,
+			Kinds:  params.Kinds,
+			Levels: params.Levels,
+			Limit:  params.Limit,
+		}
+		if filters.IsZero() {
+			return h.containerService.GetLatestLogs(params.ContainerID.UUID)
+		}
+		return h.containerService.SearchLogs(ctx, params.ContainerID.UUID, filters)
+	}, http.StatusOK)
+}
+
+type DownloadLogsParams struct {
+	ContainerID uuid.NullUUID `path:"container_id"`
+}
+
+func (h *containerHandler) DownloadLogs() gin.HandlerFunc {
+	return tonic.Handler(func(ctx *gin.Context, params *DownloadLogsParams) error {
+		w := &attachmentWriter{
+			ctx:         ctx,
+			contentType: "application/gzip",
+			filename:    fmt.Sprintf("logs_%s.tar.gz", params.ContainerID.UUID),
+		}
+		err := h.containerService.DownloadLogs(ctx, params.ContainerID.UUID, w)
+		if err != nil && w.started {
+			// The archive is partially sent, the error can't be returned to the client anymore.
+			log.Error(err, vlog.String("container_id", params.ContainerID.UUID.String()))
+			ctx.Abort()
+			return nil
+		}
+		return err
 	}, http.StatusOK)
 }
 
+// attachmentWriter sets the attachment headers only when the first bytes are
+// written, so that errors happening before can still be returned as JSON.
+type attachmentWriter struct {
+	ctx         *gin.Context
+	contentType string
+	filename    string
+	started     bool
+}
+
+func (w *attachmentWriter) Write(p []byte) (int, error) {
+	if !w.started {
+		w.started = true
+		w.ctx.Header("Content-Type", w.contentType)
+		w.ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, w.filename))
+		w.ctx.Status(http.StatusOK)
+	}
+	return w.ctx.Writer.Write(p)
+}
+
 type GetVersionsParams struct {
 	ContainerID uuid.NullUUID `path:"container_id"`
 	UseCache    bool          `query:"cache"`
@@ -299,6 +414,66 @@ func (h *containerHandler) ContainerEvents() gin.HandlerFunc {
 	}, http.StatusOK)
 }
 
+// statsStreamInterval is the time between two stats sent to the client.
+const statsStreamInterval = 2 * time.Second
+
+type StatsContainerParams struct {
+	ContainerID uuid.NullUUID `path:"container_id"`
+}
+
+func (h *containerHandler) ContainerStats() gin.HandlerFunc {
+	return tonic.Handler(func(ctx *gin.Context, params *StatsContainerParams) error {
+		inst, err := h.containerService.Get(ctx, params.ContainerID.UUID)
+		if err != nil {
+			return err
+		}
+
+		ticker := time.NewTicker(statsStreamInterval)
+		defer ticker.Stop()
+
+		done := ctx.Request.Context().Done()
+
+		first := true
+
+		ctx.Stream(func(w io.Writer) bool {
+			if first {
+				err := sse.Encode(w, sse.Event{
+					Event: "open",
+				})
+
+				if err != nil {
+					log.Error(err)
+					return false
+				}
+				first = false
+				return true
+			}
+
+			select {
+			case <-ticker.C:
+				stats, err := h.containerService.GetContainerStats(ctx, inst.ID)
+				if err != nil {
+					log.Error(err)
+					return true
+				}
+
+				err = sse.Encode(w, sse.Event{
+					Event: types.EventNameContainerStats,
+					Data:  stats,
+				})
+				if err != nil {
+					log.Error(err)
+				}
+				return true
+			case <-done:
+				return false
+			}
+		})
+
+		return nil
+	}, http.StatusOK)
+}
+
 func (h *containerHandler) ContainersEvents() gin.HandlerFunc {
 	return tonic.Handler(func(ctx *gin.Context) error {
 		eventsChan := make(chan sse.Event)
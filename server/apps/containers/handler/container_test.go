@@ -2,6 +2,7 @@ package handler
 
 import (
 	"errors"
+	"io"
 	"testing"
 
 	"github.com/stretchr/testify/mock"
@@ -94,3 +95,46 @@ func (suite *ContainerHandlerTestSuite) TestDelete() {
 		suite.service.AssertExpectations(suite.T())
 	})
 }
+
+func (suite *ContainerHandlerTestSuite) TestDownloadLogs() {
+	suite.Run("OK", func() {
+		suite.service.On("DownloadLogs", mock.Anything, suite.testContainer.ID, mock.Anything).
+			Run(func(args mock.Arguments) {
+				_, _ = args.Get(2).(io.Writer).Write([]byte("archive"))
+			}).
+			Return(nil)
+
+		res := routertest.Request("GET", suite.handler.DownloadLogs(), suite.opts)
+
+		suite.Equal(200, res.Code)
+		suite.Equal("application/gzip", res.Header().Get("Content-Type"))
+		suite.Contains(res.Header().Get("Content-Disposition"), "attachment")
+		suite.Equal("archive", res.Body.String())
+		suite.service.AssertExpectations(suite.T())
+	})
+
+	suite.Run("Not Found", func() {
+		suite.service.On("DownloadLogs", mock.Anything, suite.testContainer.ID, mock.Anything).Return(types.ErrContainerNotFound)
+
+		res := routertest.Request("GET", suite.handler.DownloadLogs(), suite.opts)
+
+		suite.Equal(404, res.Code)
+		suite.Empty(res.Header().Get("Content-Disposition"))
+		suite.JSONEq(`{"error":"container not found"}`, res.Body.String())
+		suite.service.AssertExpectations(suite.T())
+	})
+
+	suite.Run("Error while streaming", func() {
+		suite.service.On("DownloadLogs", mock.Anything, suite.testContainer.ID, mock.Anything).
+			Run(func(args mock.Arguments) {
+				_, _ = args.Get(2).(io.Writer).Write([]byte("arch"))
+			}).
+			Return(errors.New("error"))
+
+		res := routertest.Request("GET", suite.handler.DownloadLogs(), suite.opts)
+
+		suite.Equal(200, res.Code)
+		suite.Equal("arch", res.Body.String())
+		suite.service.AssertExpectations(suite.T())
+	})
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/loopfz/gadgeto/tonic"
+	"github.com/vertex-center/uuid"
+	"github.com/vertex-center/vertex/server/apps/containers/core/port"
+	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+)
+
+type dependencyHandler struct {
+	dependencyService port.DependencyService
+}
+
+func NewDependencyHandler(service port.DependencyService) port.DependencyHandler {
+	return &dependencyHandler{service}
+}
+
+type GetDependenciesParams struct {
+	ContainerID uuid.NullUUID `path:"container_id"`
+}
+
+func (h *dependencyHandler) GetDependencies() gin.HandlerFunc {
+	return tonic.Handler(func(ctx *gin.Context, params *GetDependenciesParams) (types.Dependencies, error) {
+		return h.dependencyService.GetDependencies(ctx, params.ContainerID.UUID)
+	}, http.StatusOK)
+}
+
+type SetDependencyParams struct {
+	ContainerID  uuid.NullUUID             `path:"container_id"`
+	DependencyID uuid.NullUUID             `path:"dependency_id"`
+	WaitFor      types.DependencyCondition `json:"wait_for,omitempty"`
+}
+
+func (h *dependencyHandler) SetDependency() gin.HandlerFunc {
+	return tonic.Handler(func(ctx *gin.Context, params *SetDependencyParams) error {
+		return h.dependencyService.SetDependency(ctx, types.Dependency{
+			ContainerID: params.ContainerID.UUID,
+			DependsOnID: params.DependencyID.UUID,
+			WaitFor:     params.WaitFor,
+		})
+	}, http.StatusOK)
+}
+
+type DeleteDependencyParams struct {
+	ContainerID  uuid.NullUUID `path:"container_id"`
+	DependencyID uuid.NullUUID `path:"dependency_id"`
+}
+
+func (h *dependencyHandler) DeleteDependency() gin.HandlerFunc {
+	return tonic.Handler(func(ctx *gin.Context, params *DeleteDependencyParams) error {
+		return h.dependencyService.DeleteDependency(ctx, params.ContainerID.UUID, params.DependencyID.UUID)
+	}, http.StatusOK)
+}
@@ -90,6 +90,49 @@ func (h *dockerKernelHandler) InfoContainer() gin.HandlerFunc {
 	})
 }
 
+type InspectContainerParams struct {
+	ID string `path:"id"`
+}
+
+func (h *dockerKernelHandler) InspectContainer() gin.HandlerFunc {
+	return router.Handler(func(ctx *gin.Context, params *InspectContainerParams) (*types.InspectContainerResponse, error) {
+		info, err := h.dockerService.InspectContainer(params.ID)
+		if err != nil && client.IsErrNotFound(err) {
+			return nil, apierrors.NewNotFound(err, "container not found")
+		}
+		return &info, err
+	})
+}
+
+type RenameContainerParams struct {
+	ID   string `path:"id"`
+	Name string `json:"name"`
+}
+
+func (h *dockerKernelHandler) RenameContainer() gin.HandlerFunc {
+	return router.Handler(func(ctx *gin.Context, params *RenameContainerParams) error {
+		err := h.dockerService.RenameContainer(params.ID, params.Name)
+		if err != nil && client.IsErrNotFound(err) {
+			return apierrors.NewNotFound(err, "container not found")
+		}
+		return err
+	})
+}
+
+type StatsDockerContainerParams struct {
+	ID string `path:"id"`
+}
+
+func (h *dockerKernelHandler) StatsContainer() gin.HandlerFunc {
+	return router.Handler(func(ctx *gin.Context, params *StatsDockerContainerParams) (*types.ContainerStats, error) {
+		stats, err := h.dockerService.StatsContainer(params.ID)
+		if err != nil && client.IsErrNotFound(err) {
+			return nil, apierrors.NewNotFound(err, "container not found")
+		}
+		return &stats, err
+	})
+}
+
 type LogsStdoutContainerParams struct {
 	ID string `path:"id"`
 }
@@ -167,6 +210,38 @@ func (h *dockerKernelHandler) WaitContainer() gin.HandlerFunc {
 	})
 }
 
+type ExecDockerContainerParams struct {
+	ID   string   `path:"id"`
+	Cmd  []string `query:"cmd"`
+	Cols uint     `query:"cols"`
+	Rows uint     `query:"rows"`
+}
+
+func (h *dockerKernelHandler) ExecContainer() gin.HandlerFunc {
+	return router.Handler(func(ctx *gin.Context, params *ExecDockerContainerParams) error {
+		s, err := h.dockerService.ExecContainer(params.ID, types.ExecOptions{
+			Cmd:  params.Cmd,
+			Cols: params.Cols,
+			Rows: params.Rows,
+		})
+		if err != nil && client.IsErrNotFound(err) {
+			return apierrors.NewNotFound(err, "container not found")
+		} else if err != nil {
+			return err
+		}
+		defer s.Close()
+
+		conn, err := execUpgrader.Upgrade(ctx.Writer, ctx.Request, nil)
+		if err != nil {
+			return apierrors.Annotate(err, "upgrade connection")
+		}
+		defer conn.Close()
+
+		pipeExec(conn, s)
+		return nil
+	})
+}
+
 type DeleteMountsParams struct {
 	ID string `path:"id"`
 }
@@ -270,3 +345,60 @@ func (h *dockerKernelHandler) DeleteVolume() gin.HandlerFunc {
 		return h.dockerService.DeleteVolume(params.Name)
 	})
 }
+
+func (h *dockerKernelHandler) ArchiveVolume() gin.HandlerFunc {
+	return router.Handler(func(ctx *gin.Context, params *types.ArchiveVolumeOptions) (*types.ArchiveVolumeResponse, error) {
+		res, err := h.dockerService.ArchiveVolume(*params)
+		return &res, err
+	})
+}
+
+func (h *dockerKernelHandler) RestoreVolume() gin.HandlerFunc {
+	return router.Handler(func(ctx *gin.Context, params *types.RestoreVolumeOptions) error {
+		return h.dockerService.RestoreVolume(*params)
+	})
+}
+
+type CreateNetworkParams struct {
+	Name string `json:"name"`
+}
+
+func (h *dockerKernelHandler) CreateNetwork() gin.HandlerFunc {
+	return router.Handler(func(ctx *gin.Context, params *CreateNetworkParams) error {
+		return h.dockerService.CreateNetwork(params.Name)
+	})
+}
+
+type DeleteNetworkParams struct {
+	Name string `json:"name"`
+}
+
+func (h *dockerKernelHandler) DeleteNetwork() gin.HandlerFunc {
+	return router.Handler(func(ctx *gin.Context, params *DeleteNetworkParams) error {
+		err := h.dockerService.DeleteNetwork(params.Name)
+		if err != nil && client.IsErrNotFound(err) {
+			return apierrors.NewNotFound(err, "network not found")
+		}
+		return err
+	})
+}
+
+func (h *dockerKernelHandler) ConnectNetwork() gin.HandlerFunc {
+	return router.Handler(func(ctx *gin.Context, params *types.ConnectNetworkOptions) error {
+		err := h.dockerService.ConnectNetwork(*params)
+		if err != nil && client.IsErrNotFound(err) {
+			return apierrors.NewNotFound(err, "network or container not found")
+		}
+		return err
+	})
+}
+
+func (h *dockerKernelHandler) DisconnectNetwork() gin.HandlerFunc {
+	return router.Handler(func(ctx *gin.Context, params *types.DisconnectNetworkOptions) error {
+		err := h.dockerService.DisconnectNetwork(*params)
+		if err != nil && client.IsErrNotFound(err) {
+			return apierrors.NewNotFound(err, "network or container not found")
+		}
+		return err
+	})
+}
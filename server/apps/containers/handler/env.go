@@ -32,6 +32,22 @@ func (h *envHandler) GetEnv() gin.HandlerFunc {
 	}, http.StatusOK)
 }
 
+type RevealEnvironmentParams struct {
+	EnvID uuid.NullUUID `path:"env_id"`
+}
+
+func (h *envHandler) RevealEnv() gin.HandlerFunc {
+	return tonic.Handler(func(ctx *gin.Context, params *RevealEnvironmentParams) (*types.EnvVariable, error) {
+		return h.envService.RevealEnv(ctx, params.EnvID.UUID)
+	}, http.StatusOK)
+}
+
+func (h *envHandler) RotateKey() gin.HandlerFunc {
+	return tonic.Handler(func(ctx *gin.Context) error {
+		return h.envService.RotateKey(ctx)
+	}, http.StatusNoContent)
+}
+
 type PatchEnvironmentParams struct {
 	EnvID uuid.NullUUID `path:"env_id"`
 	types.EnvVariable
@@ -59,6 +75,7 @@ type CreateEnvironmentParams struct {
 	Type        string        `json:"type"`
 	Name        string        `json:"name"`
 	Value       string        `json:"value"`
+	Secret      bool          `json:"secret"`
 }
 
 func (h *envHandler) CreateEnv() gin.HandlerFunc {
@@ -68,6 +85,7 @@ func (h *envHandler) CreateEnv() gin.HandlerFunc {
 			Type:        types.EnvVariableType(params.Type),
 			Name:        params.Name,
 			Value:       params.Value,
+			Secret:      params.Secret,
 		})
 	}, http.StatusCreated)
 }
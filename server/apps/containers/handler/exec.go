@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/juju/errors"
+	"github.com/loopfz/gadgeto/tonic"
+	"github.com/vertex-center/uuid"
+	"github.com/vertex-center/vertex/server/apps/auth/core/types/session"
+	"github.com/vertex-center/vertex/server/apps/containers/core/port"
+	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+	"github.com/vertex-center/vertex/server/common/log"
+)
+
+// execUpgrader accepts all origins, like the CORS configuration of the server.
+// The requests are authenticated with a token, not with cookies.
+var execUpgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+type execHandler struct {
+	execService port.ExecService
+}
+
+func NewExecHandler(execService port.ExecService) port.ExecHandler {
+	return &execHandler{execService}
+}
+
+type ExecParams struct {
+	ContainerID uuid.NullUUID `path:"container_id"`
+	Cmd         []string      `query:"cmd"`
+	Cols        uint          `query:"cols"`
+	Rows        uint          `query:"rows"`
+}
+
+func (h *execHandler) Exec() gin.HandlerFunc {
+	return tonic.Handler(func(ctx *gin.Context, params *ExecParams) error {
+		s, err := h.execService.Exec(ctx, params.ContainerID.UUID, session.Get(ctx).UserID, types.ExecOptions{
+			Cmd:  params.Cmd,
+			Cols: params.Cols,
+			Rows: params.Rows,
+		})
+		if err != nil {
+			return err
+		}
+		defer s.Close()
+
+		conn, err := execUpgrader.Upgrade(ctx.Writer, ctx.Request, nil)
+		if err != nil {
+			return errors.Annotate(err, "upgrade connection")
+		}
+		defer conn.Close()
+
+		pipeExec(conn, s)
+		return nil
+	}, http.StatusOK)
+}
+
+// pipeExec forwards the messages of a terminal to an exec session, and the
+// output of the session to the terminal, until one of them is closed.
+func pipeExec(conn *websocket.Conn, s port.ExecSession) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		buf := make([]byte, 32*1024)
+		for {
+			n, err := s.Read(buf)
+			if n > 0 {
+				if err := conn.WriteMessage(websocket.BinaryMessage, buf[:n]); err != nil {
+					return
+				}
+			}
+			if err != nil {
+				_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+				// Don't wait forever for the terminal to acknowledge the close.
+				_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+				return
+			}
+		}
+	}()
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			break
+		}
+
+		var msg types.ExecMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			log.Error(errors.Annotate(err, "decode exec message"))
+			continue
+		}
+
+		switch msg.Type {
+		case types.ExecMessageInput:
+			_, err = s.Write([]byte(msg.Data))
+		case types.ExecMessageResize:
+			err = s.Resize(msg.Cols, msg.Rows)
+		}
+		if err != nil {
+			break
+		}
+	}
+
+	_ = s.Close()
+	<-done
+}
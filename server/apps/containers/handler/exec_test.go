@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/suite"
+	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+)
+
+type fakeExecSession struct {
+	out *io.PipeReader
+
+	mu     sync.Mutex
+	in     strings.Builder
+	resize [2]uint
+}
+
+func (s *fakeExecSession) Read(p []byte) (int, error) { return s.out.Read(p) }
+func (s *fakeExecSession) Close() error               { return s.out.Close() }
+
+func (s *fakeExecSession) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.in.Write(p)
+}
+
+func (s *fakeExecSession) Resize(cols, rows uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.resize = [2]uint{cols, rows}
+	return nil
+}
+
+type PipeExecTestSuite struct {
+	suite.Suite
+
+	session *fakeExecSession
+	out     *io.PipeWriter
+	done    chan struct{}
+	conn    *websocket.Conn
+}
+
+func TestPipeExecTestSuite(t *testing.T) {
+	suite.Run(t, new(PipeExecTestSuite))
+}
+
+func (suite *PipeExecTestSuite) SetupTest() {
+	r, w := io.Pipe()
+	suite.session = &fakeExecSession{out: r}
+	suite.out = w
+	suite.done = make(chan struct{})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		conn, err := execUpgrader.Upgrade(rw, req, nil)
+		suite.Require().NoError(err)
+		defer conn.Close()
+		pipeExec(conn, suite.session)
+		close(suite.done)
+	}))
+	suite.T().Cleanup(srv.Close)
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	suite.Require().NoError(err)
+	suite.T().Cleanup(func() { _ = conn.Close() })
+	suite.conn = conn
+}
+
+func (suite *PipeExecTestSuite) TestPipe() {
+	err := suite.conn.WriteJSON(types.ExecMessage{Type: types.ExecMessageInput, Data: "ls\n"})
+	suite.Require().NoError(err)
+	err = suite.conn.WriteJSON(types.ExecMessage{Type: types.ExecMessageResize, Cols: 80, Rows: 24})
+	suite.Require().NoError(err)
+
+	go func() {
+		_, _ = suite.out.Write([]byte("hello"))
+		_ = suite.out.Close()
+	}()
+
+	kind, msg, err := suite.conn.ReadMessage()
+	suite.Require().NoError(err)
+	suite.Equal(websocket.BinaryMessage, kind)
+	suite.Equal("hello", string(msg))
+
+	// The session ended, so the terminal is closed.
+	_, _, err = suite.conn.ReadMessage()
+	suite.True(websocket.IsCloseError(err, websocket.CloseNormalClosure))
+	<-suite.done
+
+	suite.session.mu.Lock()
+	defer suite.session.mu.Unlock()
+	suite.Equal("ls\n", suite.session.in.String())
+	suite.Equal([2]uint{80, 24}, suite.session.resize)
+}
+
+func (suite *PipeExecTestSuite) TestTerminalClosed() {
+	err := suite.conn.Close()
+	suite.Require().NoError(err)
+
+	// Closing the terminal closes the session.
+	<-suite.done
+	_, err = suite.session.out.Read(make([]byte, 1))
+	suite.ErrorIs(err, io.ErrClosedPipe)
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/loopfz/gadgeto/tonic"
+	"github.com/vertex-center/uuid"
+	"github.com/vertex-center/vertex/server/apps/containers/core/port"
+	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+)
+
+type healthCheckHandler struct {
+	healthCheckService port.HealthCheckService
+}
+
+func NewHealthCheckHandler(service port.HealthCheckService) port.HealthCheckHandler {
+	return &healthCheckHandler{service}
+}
+
+type GetHealthCheckParams struct {
+	ContainerID uuid.NullUUID `path:"container_id"`
+}
+
+func (h *healthCheckHandler) GetHealthCheck() gin.HandlerFunc {
+	return tonic.Handler(func(ctx *gin.Context, params *GetHealthCheckParams) (*types.HealthCheck, error) {
+		return h.healthCheckService.GetHealthCheck(ctx, params.ContainerID.UUID)
+	}, http.StatusOK)
+}
+
+type SetHealthCheckParams struct {
+	ContainerID uuid.NullUUID         `path:"container_id"`
+	Type        types.HealthCheckType `json:"type"`
+	Port        *string               `json:"port,omitempty"`
+	Path        *string               `json:"path,omitempty"`
+	Command     *string               `json:"command,omitempty"`
+	Interval    string                `json:"interval,omitempty"`
+	Timeout     string                `json:"timeout,omitempty"`
+	StartPeriod string                `json:"start_period,omitempty"`
+	Retries     int                   `json:"retries,omitempty"`
+}
+
+func (h *healthCheckHandler) SetHealthCheck() gin.HandlerFunc {
+	return tonic.Handler(func(ctx *gin.Context, params *SetHealthCheckParams) error {
+		return h.healthCheckService.SetHealthCheck(ctx, types.HealthCheck{
+			ContainerID: params.ContainerID.UUID,
+			Type:        params.Type,
+			Port:        params.Port,
+			Path:        params.Path,
+			Command:     params.Command,
+			Interval:    params.Interval,
+			Timeout:     params.Timeout,
+			StartPeriod: params.StartPeriod,
+			Retries:     params.Retries,
+		})
+	}, http.StatusOK)
+}
+
+type DeleteHealthCheckParams struct {
+	ContainerID uuid.NullUUID `path:"container_id"`
+}
+
+func (h *healthCheckHandler) DeleteHealthCheck() gin.HandlerFunc {
+	return tonic.Handler(func(ctx *gin.Context, params *DeleteHealthCheckParams) error {
+		return h.healthCheckService.DeleteHealthCheck(ctx, params.ContainerID.UUID)
+	}, http.StatusOK)
+}
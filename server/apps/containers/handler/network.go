@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/loopfz/gadgeto/tonic"
+	"github.com/vertex-center/uuid"
+	"github.com/vertex-center/vertex/server/apps/containers/core/port"
+	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+)
+
+type networkHandler struct {
+	networkService port.NetworkService
+}
+
+func NewNetworkHandler(networkService port.NetworkService) port.NetworkHandler {
+	return &networkHandler{networkService}
+}
+
+func (h *networkHandler) GetNetworks() gin.HandlerFunc {
+	return tonic.Handler(func(ctx *gin.Context) (types.Networks, error) {
+		return h.networkService.GetNetworks(ctx)
+	}, http.StatusOK)
+}
+
+type NetworkCreateParams struct {
+	Name string `json:"name"`
+}
+
+func (h *networkHandler) CreateNetwork() gin.HandlerFunc {
+	return tonic.Handler(func(ctx *gin.Context, params *NetworkCreateParams) (*types.Network, error) {
+		return h.networkService.CreateNetwork(ctx, params.Name)
+	}, http.StatusCreated)
+}
+
+type NetworkDeleteParams struct {
+	NetworkID uuid.NullUUID `path:"network_id"`
+}
+
+func (h *networkHandler) DeleteNetwork() gin.HandlerFunc {
+	return tonic.Handler(func(ctx *gin.Context, params *NetworkDeleteParams) error {
+		return h.networkService.DeleteNetwork(ctx, params.NetworkID.UUID)
+	}, http.StatusOK)
+}
+
+type GetContainerNetworksParams struct {
+	ContainerID uuid.NullUUID `path:"container_id"`
+}
+
+func (h *networkHandler) GetContainerNetworks() gin.HandlerFunc {
+	return tonic.Handler(func(ctx *gin.Context, params *GetContainerNetworksParams) (types.ContainerNetworks, error) {
+		return h.networkService.GetContainerNetworks(ctx, params.ContainerID.UUID)
+	}, http.StatusOK)
+}
+
+type AttachContainerParams struct {
+	ContainerID uuid.NullUUID `path:"container_id"`
+	NetworkID   uuid.NullUUID `path:"network_id"`
+	Aliases     []string      `json:"aliases,omitempty"`
+}
+
+func (h *networkHandler) AttachContainer() gin.HandlerFunc {
+	return tonic.Handler(func(ctx *gin.Context, params *AttachContainerParams) error {
+		return h.networkService.AttachContainer(ctx, types.ContainerNetwork{
+			ContainerID: params.ContainerID.UUID,
+			NetworkID:   params.NetworkID.UUID,
+			Aliases:     params.Aliases,
+		})
+	}, http.StatusOK)
+}
+
+type DetachContainerParams struct {
+	ContainerID uuid.NullUUID `path:"container_id"`
+	NetworkID   uuid.NullUUID `path:"network_id"`
+}
+
+func (h *networkHandler) DetachContainer() gin.HandlerFunc {
+	return tonic.Handler(func(ctx *gin.Context, params *DetachContainerParams) error {
+		return h.networkService.DetachContainer(ctx, params.ContainerID.UUID, params.NetworkID.UUID)
+	}, http.StatusOK)
+}
@@ -33,17 +33,19 @@ func (h *portsHandler) GetPorts() gin.HandlerFunc {
 }
 
 type PatchPortParams struct {
-	PortID uuid.NullUUID `path:"port_id"`
-	In     string        `json:"in"`
-	Out    string        `json:"out"`
+	PortID   uuid.NullUUID `path:"port_id"`
+	In       string        `json:"in"`
+	Out      string        `json:"out"`
+	Protocol string        `json:"protocol,omitempty"`
 }
 
 func (h *portsHandler) PatchPort() gin.HandlerFunc {
 	return tonic.Handler(func(ctx *gin.Context, params *PatchPortParams) error {
 		return h.portsService.PatchPort(ctx, types.Port{
-			ID:  params.PortID.UUID,
-			In:  params.In,
-			Out: params.Out,
+			ID:       params.PortID.UUID,
+			In:       params.In,
+			Out:      params.Out,
+			Protocol: types.PortProtocol(params.Protocol),
 		})
 	}, http.StatusOK)
 }
@@ -62,6 +64,7 @@ type CreatePortParams struct {
 	ContainerID uuid.NullUUID `json:"container_id"`
 	In          string        `json:"in"`
 	Out         string        `json:"out"`
+	Protocol    string        `json:"protocol,omitempty"`
 }
 
 func (h *portsHandler) CreatePort() gin.HandlerFunc {
@@ -70,6 +73,7 @@ func (h *portsHandler) CreatePort() gin.HandlerFunc {
 			ContainerID: params.ContainerID.UUID,
 			In:          params.In,
 			Out:         params.Out,
+			Protocol:    types.PortProtocol(params.Protocol),
 		})
 	}, http.StatusCreated)
 }
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/loopfz/gadgeto/tonic"
+	"github.com/vertex-center/uuid"
+	"github.com/vertex-center/vertex/server/apps/containers/core/port"
+	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+)
+
+type registryHandler struct {
+	registryService port.RegistryService
+}
+
+func NewRegistryHandler(registryService port.RegistryService) port.RegistryHandler {
+	return &registryHandler{registryService}
+}
+
+func (h *registryHandler) GetRegistryCredentials() gin.HandlerFunc {
+	return tonic.Handler(func(ctx *gin.Context) (types.RegistryCredentials, error) {
+		return h.registryService.GetRegistryCredentials(ctx)
+	}, http.StatusOK)
+}
+
+type CreateRegistryCredentialParams struct {
+	Registry string `json:"registry"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+func (h *registryHandler) CreateRegistryCredential() gin.HandlerFunc {
+	return tonic.Handler(func(ctx *gin.Context, params *CreateRegistryCredentialParams) (*types.RegistryCredential, error) {
+		return h.registryService.CreateRegistryCredential(ctx, types.RegistryCredential{
+			Registry: params.Registry,
+			Username: params.Username,
+			Password: params.Password,
+		})
+	}, http.StatusCreated)
+}
+
+type PatchRegistryCredentialParams struct {
+	CredentialID uuid.NullUUID `path:"credential_id"`
+	Username     *string       `json:"username,omitempty"`
+	Password     *string       `json:"password,omitempty"`
+}
+
+func (h *registryHandler) PatchRegistryCredential() gin.HandlerFunc {
+	return tonic.Handler(func(ctx *gin.Context, params *PatchRegistryCredentialParams) (*types.RegistryCredential, error) {
+		return h.registryService.PatchRegistryCredential(ctx, params.CredentialID.UUID, types.RegistryCredentialPatch{
+			Username: params.Username,
+			Password: params.Password,
+		})
+	}, http.StatusOK)
+}
+
+type DeleteRegistryCredentialParams struct {
+	CredentialID uuid.NullUUID `path:"credential_id"`
+}
+
+func (h *registryHandler) DeleteRegistryCredential() gin.HandlerFunc {
+	return tonic.Handler(func(ctx *gin.Context, params *DeleteRegistryCredentialParams) error {
+		return h.registryService.DeleteRegistryCredential(ctx, params.CredentialID.UUID)
+	}, http.StatusOK)
+}
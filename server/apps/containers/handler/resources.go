@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/loopfz/gadgeto/tonic"
+	"github.com/vertex-center/uuid"
+	"github.com/vertex-center/vertex/server/apps/containers/core/port"
+	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+)
+
+type resourceLimitsHandler struct {
+	resourceLimitsService port.ResourceLimitsService
+}
+
+func NewResourceLimitsHandler(service port.ResourceLimitsService) port.ResourceLimitsHandler {
+	return &resourceLimitsHandler{service}
+}
+
+type GetResourceLimitsParams struct {
+	ContainerID uuid.NullUUID `path:"container_id"`
+}
+
+func (h *resourceLimitsHandler) GetResourceLimits() gin.HandlerFunc {
+	return tonic.Handler(func(ctx *gin.Context, params *GetResourceLimitsParams) (*types.ResourceLimits, error) {
+		return h.resourceLimitsService.GetResourceLimits(ctx, params.ContainerID.UUID)
+	}, http.StatusOK)
+}
+
+type SetResourceLimitsParams struct {
+	ContainerID uuid.NullUUID `path:"container_id"`
+	CPUShares   *int64        `json:"cpu_shares,omitempty"`
+	CPUPeriod   *int64        `json:"cpu_period,omitempty"`
+	CPUQuota    *int64        `json:"cpu_quota,omitempty"`
+	Memory      *int64        `json:"memory,omitempty"`
+	MemorySwap  *int64        `json:"memory_swap,omitempty"`
+	PidsLimit   *int64        `json:"pids_limit,omitempty"`
+	Ulimits     types.Ulimits `json:"ulimits,omitempty"`
+}
+
+func (h *resourceLimitsHandler) SetResourceLimits() gin.HandlerFunc {
+	return tonic.Handler(func(ctx *gin.Context, params *SetResourceLimitsParams) error {
+		return h.resourceLimitsService.SetResourceLimits(ctx, types.ResourceLimits{
+			ContainerID: params.ContainerID.UUID,
+			CPUShares:   params.CPUShares,
+			CPUPeriod:   params.CPUPeriod,
+			CPUQuota:    params.CPUQuota,
+			Memory:      params.Memory,
+			MemorySwap:  params.MemorySwap,
+			PidsLimit:   params.PidsLimit,
+			Ulimits:     params.Ulimits,
+		})
+	}, http.StatusOK)
+}
+
+type DeleteResourceLimitsParams struct {
+	ContainerID uuid.NullUUID `path:"container_id"`
+}
+
+func (h *resourceLimitsHandler) DeleteResourceLimits() gin.HandlerFunc {
+	return tonic.Handler(func(ctx *gin.Context, params *DeleteResourceLimitsParams) error {
+		return h.resourceLimitsService.DeleteResourceLimits(ctx, params.ContainerID.UUID)
+	}, http.StatusOK)
+}
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/loopfz/gadgeto/tonic"
+	"github.com/vertex-center/uuid"
+	"github.com/vertex-center/vertex/server/apps/containers/core/port"
+	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+)
+
+type stackHandler struct {
+	stackService port.StackService
+}
+
+func NewStackHandler(stackService port.StackService) port.StackHandler {
+	return &stackHandler{stackService}
+}
+
+type GetStackParams struct {
+	StackID uuid.NullUUID `path:"stack_id"`
+}
+
+func (h *stackHandler) GetStack() gin.HandlerFunc {
+	return tonic.Handler(func(ctx *gin.Context, params *GetStackParams) (*types.Stack, error) {
+		return h.stackService.GetStack(ctx, params.StackID.UUID)
+	}, http.StatusOK)
+}
+
+func (h *stackHandler) GetStacks() gin.HandlerFunc {
+	return tonic.Handler(func(ctx *gin.Context) (types.Stacks, error) {
+		return h.stackService.GetStacks(ctx)
+	}, http.StatusOK)
+}
+
+type CreateStackParams struct {
+	TemplateID string  `json:"template_id"`
+	Name       *string `json:"name,omitempty"`
+}
+
+func (h *stackHandler) CreateStack() gin.HandlerFunc {
+	return tonic.Handler(func(ctx *gin.Context, params *CreateStackParams) (*types.Stack, error) {
+		return h.stackService.CreateStack(ctx, types.CreateStackOptions{
+			TemplateID: params.TemplateID,
+			Name:       params.Name,
+		})
+	}, http.StatusCreated)
+}
+
+type DeleteStackParams struct {
+	StackID uuid.NullUUID `path:"stack_id"`
+}
+
+func (h *stackHandler) DeleteStack() gin.HandlerFunc {
+	return tonic.Handler(func(ctx *gin.Context, params *DeleteStackParams) error {
+		return h.stackService.DeleteStack(ctx, params.StackID.UUID)
+	}, http.StatusOK)
+}
+
+type StartStackParams struct {
+	StackID uuid.NullUUID `path:"stack_id"`
+}
+
+func (h *stackHandler) StartStack() gin.HandlerFunc {
+	return tonic.Handler(func(ctx *gin.Context, params *StartStackParams) error {
+		return h.stackService.StartStack(ctx, params.StackID.UUID)
+	}, http.StatusOK)
+}
+
+type StopStackParams struct {
+	StackID uuid.NullUUID `path:"stack_id"`
+}
+
+func (h *stackHandler) StopStack() gin.HandlerFunc {
+	return tonic.Handler(func(ctx *gin.Context, params *StopStackParams) error {
+		return h.stackService.StopStack(ctx, params.StackID.UUID)
+	}, http.StatusOK)
+}
@@ -1,7 +1,13 @@
 package handler
 
 import (
+	"net/http"
+
 	"github.com/gin-gonic/gin"
+	"github.com/juju/errors"
+	"github.com/loopfz/gadgeto/tonic"
+	"github.com/vertex-center/uuid"
+	"github.com/vertex-center/vertex/server/apps/auth/core/types/session"
 	"github.com/vertex-center/vertex/server/apps/containers/core/port"
 	"github.com/vertex-center/vertex/server/apps/containers/core/types"
 	"github.com/vertex-center/vertex/server/pkg/router"
@@ -9,10 +15,12 @@ import (
 
 type templateHandler struct {
 	containers port.ContainerService
+	templates  port.TemplateService
+	sources    port.TemplateSourceService
 }
 
-func NewTemplateHandler(containerService port.ContainerService) port.TemplateHandler {
-	return &templateHandler{containerService}
+func NewTemplateHandler(containerService port.ContainerService, templateService port.TemplateService, sourceService port.TemplateSourceService) port.TemplateHandler {
+	return &templateHandler{containerService, templateService, sourceService}
 }
 
 type GetServiceParams struct {
@@ -30,3 +38,91 @@ func (h *templateHandler) GetTemplates() gin.HandlerFunc {
 		return h.containers.GetTemplates(ctx), nil
 	})
 }
+
+type CreateTemplateParams struct {
+	Template    *types.Template `json:"template,omitempty"`
+	ContainerID uuid.NullUUID   `json:"container_id,omitempty"`
+	ID          string          `json:"id,omitempty"`
+	Name        *string         `json:"name,omitempty"`
+}
+
+func (h *templateHandler) CreateTemplate() gin.HandlerFunc {
+	return tonic.Handler(func(ctx *gin.Context, params *CreateTemplateParams) (*types.Template, error) {
+		s := session.Get(ctx)
+		switch {
+		case params.Template != nil && params.ContainerID.Valid:
+			return nil, errors.NewBadRequest(nil, "template and container_id can't be both set")
+		case params.Template != nil:
+			return h.templates.CreateTemplate(ctx, s.UserID, *params.Template)
+		case params.ContainerID.Valid:
+			return h.templates.CreateTemplateFromContainer(ctx, s.UserID, params.ContainerID.UUID, types.ContainerTemplateOptions{
+				ID:   params.ID,
+				Name: params.Name,
+			})
+		}
+		return nil, errors.NewBadRequest(nil, "template or container_id is required")
+	}, http.StatusCreated)
+}
+
+type ValidateTemplateParams struct {
+	Content string `json:"content"`
+}
+
+func (h *templateHandler) ValidateTemplate() gin.HandlerFunc {
+	return tonic.Handler(func(ctx *gin.Context, params *ValidateTemplateParams) (types.TemplateLint, error) {
+		return h.templates.ValidateTemplate(ctx, []byte(params.Content)), nil
+	}, http.StatusOK)
+}
+
+type DeleteTemplateParams struct {
+	TemplateID string `path:"template_id"`
+}
+
+func (h *templateHandler) DeleteTemplate() gin.HandlerFunc {
+	return tonic.Handler(func(ctx *gin.Context, params *DeleteTemplateParams) error {
+		s := session.Get(ctx)
+		return h.templates.DeleteTemplate(ctx, s.UserID, params.TemplateID)
+	}, http.StatusOK)
+}
+
+func (h *templateHandler) GetSources() gin.HandlerFunc {
+	return tonic.Handler(func(ctx *gin.Context) (types.TemplateSources, error) {
+		return h.sources.GetSources(ctx), nil
+	}, http.StatusOK)
+}
+
+type CreateTemplateSourceParams struct {
+	Name      string                   `json:"name"`
+	Type      types.TemplateSourceType `json:"type"`
+	URL       string                   `json:"url"`
+	Priority  int                      `json:"priority,omitempty"`
+	PublicKey *string                  `json:"public_key,omitempty"`
+}
+
+func (h *templateHandler) CreateSource() gin.HandlerFunc {
+	return tonic.Handler(func(ctx *gin.Context, params *CreateTemplateSourceParams) (*types.TemplateSource, error) {
+		return h.sources.CreateSource(ctx, types.TemplateSource{
+			Name:      params.Name,
+			Type:      params.Type,
+			URL:       params.URL,
+			Priority:  params.Priority,
+			PublicKey: params.PublicKey,
+		})
+	}, http.StatusCreated)
+}
+
+type DeleteTemplateSourceParams struct {
+	SourceID uuid.NullUUID `path:"source_id"`
+}
+
+func (h *templateHandler) DeleteSource() gin.HandlerFunc {
+	return tonic.Handler(func(ctx *gin.Context, params *DeleteTemplateSourceParams) error {
+		return h.sources.DeleteSource(ctx, params.SourceID.UUID)
+	}, http.StatusOK)
+}
+
+func (h *templateHandler) RefreshSources() gin.HandlerFunc {
+	return tonic.Handler(func(ctx *gin.Context) (types.TemplateSources, error) {
+		return h.sources.RefreshSources(ctx), nil
+	}, http.StatusAccepted)
+}
@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/loopfz/gadgeto/tonic"
+	"github.com/vertex-center/uuid"
+	"github.com/vertex-center/vertex/server/apps/containers/core/port"
+	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+)
+
+type templateUpdateHandler struct {
+	templateUpdateService port.TemplateUpdateService
+}
+
+func NewTemplateUpdateHandler(templateUpdateService port.TemplateUpdateService) port.TemplateUpdateHandler {
+	return &templateUpdateHandler{templateUpdateService}
+}
+
+type GetTemplateUpdateParams struct {
+	ContainerID uuid.NullUUID `path:"container_id"`
+}
+
+func (h *templateUpdateHandler) GetTemplateUpdate() gin.HandlerFunc {
+	return tonic.Handler(func(ctx *gin.Context, params *GetTemplateUpdateParams) (*types.TemplateUpdate, error) {
+		return h.templateUpdateService.GetTemplateUpdate(ctx, params.ContainerID.UUID)
+	}, http.StatusOK)
+}
+
+type ApplyTemplateUpdateParams struct {
+	ContainerID uuid.NullUUID     `path:"container_id"`
+	Changes     []string          `json:"changes,omitempty"`
+	Env         map[string]string `json:"env,omitempty"`
+}
+
+func (h *templateUpdateHandler) ApplyTemplateUpdate() gin.HandlerFunc {
+	return tonic.Handler(func(ctx *gin.Context, params *ApplyTemplateUpdateParams) error {
+		return h.templateUpdateService.ApplyTemplateUpdate(ctx, params.ContainerID.UUID, types.ApplyTemplateUpdateOptions{
+			Changes: params.Changes,
+			Env:     params.Env,
+		})
+	}, http.StatusOK)
+}
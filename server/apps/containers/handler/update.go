@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/loopfz/gadgeto/tonic"
+	"github.com/vertex-center/uuid"
+	"github.com/vertex-center/vertex/server/apps/containers/core/port"
+	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+)
+
+type updateHandler struct {
+	updateService port.UpdateService
+}
+
+func NewUpdateHandler(updateService port.UpdateService) port.UpdateHandler {
+	return &updateHandler{updateService}
+}
+
+type SetUpdatePolicyParams struct {
+	ContainerID uuid.NullUUID      `path:"container_id"`
+	Policy      types.UpdatePolicy `json:"policy" enum:"notify,auto,pin"`
+	Window      *string            `json:"window,omitempty"`
+}
+
+func (h *updateHandler) SetUpdatePolicy() gin.HandlerFunc {
+	return tonic.Handler(func(ctx *gin.Context, params *SetUpdatePolicyParams) error {
+		return h.updateService.SetUpdatePolicy(ctx, params.ContainerID.UUID, params.Policy, params.Window)
+	}, http.StatusOK)
+}
+
+type ApplyUpdateParams struct {
+	ContainerID uuid.NullUUID `path:"container_id"`
+}
+
+func (h *updateHandler) ApplyUpdate() gin.HandlerFunc {
+	return tonic.Handler(func(ctx *gin.Context, params *ApplyUpdateParams) error {
+		return h.updateService.ApplyUpdate(ctx, params.ContainerID.UUID)
+	}, http.StatusOK)
+}
+
+type RollbackParams struct {
+	ContainerID uuid.NullUUID `path:"container_id"`
+}
+
+func (h *updateHandler) Rollback() gin.HandlerFunc {
+	return tonic.Handler(func(ctx *gin.Context, params *RollbackParams) error {
+		return h.updateService.Rollback(ctx, params.ContainerID.UUID)
+	}, http.StatusOK)
+}
@@ -0,0 +1,31 @@
+package containers
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/vertex-center/vertex/server/apps/containers/adapter"
+	"github.com/vertex-center/vertex/server/apps/containers/core/types"
+)
+
+// LintTemplates validates the templates of a directory, and writes the
+// report to w as JSON. It returns whether all the templates are valid.
+func LintTemplates(dir string, w io.Writer) (bool, error) {
+	files, err := adapter.ReadTemplateDir(dir)
+	if files == nil {
+		return false, err
+	}
+
+	report := types.LintTemplates(files)
+	if err != nil {
+		// Files that can't be read are reported like invalid templates.
+		report.Valid = false
+		report.Templates = append(report.Templates, types.TemplateLint{
+			Issues: types.TemplateIssues{{Message: err.Error()}},
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return report.Valid, enc.Encode(report)
+}
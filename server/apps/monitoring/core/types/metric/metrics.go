@@ -1,8 +1,9 @@
 package metric
 
 const (
-	StatusOff float64 = 0.0
-	StatusOn  float64 = 1.0
+	StatusOff       float64 = 0.0
+	StatusOn        float64 = 1.0
+	StatusUnhealthy float64 = 0.5 // StatusUnhealthy is for things that run but fail their health check.
 )
 
 type Type string
@@ -3,7 +3,9 @@ package main
 import (
 	"os"
 
+	"github.com/alecthomas/kingpin/v2"
 	"github.com/vertex-center/vertex/server/apps"
+	"github.com/vertex-center/vertex/server/apps/containers"
 	logsmeta "github.com/vertex-center/vertex/server/apps/logs/meta"
 	"github.com/vertex-center/vertex/server/common"
 	"github.com/vertex-center/vertex/server/common/app"
@@ -18,11 +20,18 @@ var (
 	date    = "unknown"
 )
 
+var (
+	_ = kingpin.Command("serve", "Run Vertex.").Default()
+
+	templatesLintCmd = kingpin.Command("templates", "Manage the container templates.").
+				Command("lint", "Validate the templates of a directory, and print the report as JSON.")
+	templatesLintDir = templatesLintCmd.Arg("dir", "Directory of the templates, with one <id>/service.yml per template.").Required().ExistingDir()
+	templatesLintOut = templatesLintCmd.Flag("output", "File to write the report to, instead of the standard output.").Short('o').String()
+)
+
 func main() {
 	defer log.Default.Close()
 
-	ensureNotRoot()
-
 	about := common.NewAbout(version, commit, date)
 	for _, a := range apps.Apps {
 		meta := a.Meta()
@@ -31,13 +40,45 @@ func main() {
 	}
 	config.Current.RegisterDBArgs()
 	config.RegisterHost("vertex", "6130")
-	config.ParseArgs(about)
+	cmd := config.ParseArgs(about)
+	if cmd == templatesLintCmd.FullCommand() {
+		code := lintTemplates()
+		log.Default.Close()
+		os.Exit(code)
+	}
+
+	ensureNotRoot()
 
 	log.SetupAgent(*config.Current.Addr(logsmeta.Meta.ID))
 
 	app.RunApps(about, apps.Apps)
 }
 
+// lintTemplates returns the exit code of the lint command: 1 if a template
+// is invalid, and 2 if the templates couldn't be linted.
+func lintTemplates() int {
+	out := os.Stdout
+	if *templatesLintOut != "" {
+		f, err := os.Create(*templatesLintOut)
+		if err != nil {
+			log.Error(err)
+			return 2
+		}
+		defer f.Close()
+		out = f
+	}
+
+	valid, err := containers.LintTemplates(*templatesLintDir, out)
+	if err != nil {
+		log.Error(err)
+		return 2
+	}
+	if !valid {
+		return 1
+	}
+	return 0
+}
+
 func ensureNotRoot() {
 	if os.Getuid() == 0 {
 		log.Warn("while vertex-kernel must be run as root, the vertex user should not be root")
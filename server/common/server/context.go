@@ -0,0 +1,14 @@
+package server
+
+import (
+	"context"
+
+	"github.com/vertex-center/uuid"
+)
+
+// NewBackgroundContext returns a context for work that is not triggered by a
+// request, like scheduled tasks. It carries a new correlation ID, so it can be
+// used to create API clients.
+func NewBackgroundContext() context.Context {
+	return context.WithValue(context.Background(), KeyCorrelationID, uuid.New().String())
+}
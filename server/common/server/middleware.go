@@ -18,6 +18,21 @@ const (
 	KeyCorrelationID = "correlationID"
 )
 
+// redactToken hides the token passed in the query of websocket requests.
+func redactToken(path string) string {
+	p, err := url.Parse(path)
+	if err != nil {
+		return path
+	}
+	q := p.Query()
+	if !q.Has("token") {
+		return path
+	}
+	q.Set("token", "REDACTED")
+	p.RawQuery = q.Encode()
+	return p.String()
+}
+
 func logger(u *url.URL, app string) gin.HandlerFunc {
 	urlString := u.String()
 	return gin.LoggerWithFormatter(func(params gin.LogFormatterParams) string {
@@ -28,7 +43,7 @@ func logger(u *url.URL, app string) gin.HandlerFunc {
 			vlog.String("method", params.Method),
 			vlog.String("app", app),
 			vlog.Int("status", params.StatusCode),
-			vlog.String("path", params.Path),
+			vlog.String("path", redactToken(params.Path)),
 			vlog.String("latency", params.Latency.String()),
 			vlog.String("ip", params.ClientIP),
 			vlog.Int("size", params.BodySize),
@@ -46,9 +46,11 @@ func (c *Config) RegisterDBArgs() {
 	fields["VERTEX_DB_PASS"] = kingpin.Flag("db-pass", "Database pass.").Envar("VERTEX_DB_PASS").Default("postgres").String()
 }
 
-func ParseArgs(about common.About) {
+// ParseArgs parses the command line, and returns the selected command, if
+// commands were registered.
+func ParseArgs(about common.About) string {
 	kingpin.Version(about.Version)
-	kingpin.Parse()
+	cmd := kingpin.Parse()
 
 	if commit != nil && *commit {
 		fmt.Println(about.Commit)
@@ -80,4 +82,5 @@ func ParseArgs(about common.About) {
 		}
 		Current.fields[id] = *val
 	}
+	return cmd
 }
@@ -53,6 +53,20 @@ func CloneOrPullRepository(url string, dest string) error {
 	return nil
 }
 
+// RepositoryHead returns the hash of the commit checked out in the repository.
+func RepositoryHead(dir string) (string, error) {
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		return "", err
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return "", err
+	}
+	return head.Hash().String(), nil
+}
+
 func DownloadGithubRelease(release *github.RepositoryRelease, dest string) error {
 	log.Info("downloading release",
 		vlog.String("release", *release.Name),
@@ -51,3 +51,19 @@ func (suite *RepositoryTestSuite) TestCloneOrPullRepository() {
 	suite.Require().NoError(err)
 	suite.DirExists(dir)
 }
+
+func (suite *RepositoryTestSuite) TestRepositoryHead() {
+	fs := fixtures.Basic().One().DotGit()
+
+	dir, err := os.MkdirTemp("", "*_live_test")
+	suite.Require().NoError(err)
+
+	defer os.RemoveAll(dir)
+
+	err = CloneRepository(fs.Root(), dir)
+	suite.Require().NoError(err)
+
+	head, err := RepositoryHead(dir)
+	suite.Require().NoError(err)
+	suite.Equal("6ecf0ef2c2dffb796033e5a02219af86ec6584e5", head)
+}
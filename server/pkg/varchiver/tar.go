@@ -9,6 +9,7 @@ import (
 	"io"
 	"os"
 	"path"
+	"path/filepath"
 	"strings"
 )
 
@@ -75,9 +76,90 @@ func Unzip(src string, dest string) error {
 	return nil
 }
 
+// Tar archives the content of the src directory into a gzipped tarball
+// written to w. Modes, owners and symbolic links are kept. Symbolic links
+// pointing outside src are skipped, as UntarWithOptions rejects them.
+func Tar(src string, w io.Writer) error {
+	stream := gzip.NewWriter(w)
+	writer := tar.NewWriter(stream)
+
+	err := filepath.Walk(src, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		var link string
+		if info.Mode()&os.ModeSymlink != 0 {
+			link, err = os.Readlink(p)
+			if err != nil {
+				return err
+			}
+			if !linkInside(src, p, link) {
+				return nil
+			}
+		} else if !info.Mode().IsRegular() && !info.IsDir() {
+			// Sockets, pipes and devices can't be restored.
+			return nil
+		}
+
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+
+		name, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(name)
+		if info.IsDir() {
+			header.Name += "/"
+		}
+
+		err = writer.WriteHeader(header)
+		if err != nil {
+			return err
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		file, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		// Files can grow while being archived, so only the size in the header is copied.
+		_, err = io.CopyN(writer, file, header.Size)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return err
+	}
+	return stream.Close()
+}
+
+type UntarOptions struct {
+	// Preserve keeps the modes, owners and symbolic links of the archive.
+	// Otherwise, all files are made executable.
+	Preserve bool
+}
+
 // Untar a tarball to a destination. src is the path to
 // the tarball, and dest is the path to the destination directory.
 func Untar(src string, dest string) error {
+	return UntarWithOptions(src, dest, UntarOptions{})
+}
+
+// UntarWithOptions is like Untar, with options.
+func UntarWithOptions(src string, dest string, opts UntarOptions) error {
 	if zipSlipAttack(src) || zipSlipAttack(dest) {
 		return ErrZipSlipAttack
 	}
@@ -96,6 +178,10 @@ func Untar(src string, dest string) error {
 
 	reader := tar.NewReader(stream)
 
+	// The modes of directories are set once their content is extracted,
+	// in case they are read-only.
+	dirModes := map[string]os.FileMode{}
+
 	for {
 		header, err := reader.Next()
 		if errors.Is(err, io.EOF) {
@@ -116,12 +202,25 @@ func Untar(src string, dest string) error {
 			return ErrZipSlipAttack
 		}
 
+		// Files must not be written through symbolic links extracted before.
+		if throughSymlink(dest, p) {
+			return ErrZipSlipAttack
+		}
+
+		mode := os.FileMode(0755)
+		if opts.Preserve {
+			mode = header.FileInfo().Mode().Perm()
+		}
+
 		switch header.Typeflag {
 		case tar.TypeDir:
 			err = os.MkdirAll(p, os.ModePerm)
 			if err != nil {
 				return err
 			}
+			if opts.Preserve {
+				dirModes[p] = mode
+			}
 		case tar.TypeReg:
 			err := os.MkdirAll(path.Dir(p), os.ModePerm)
 			if err != nil {
@@ -138,15 +237,47 @@ func Untar(src string, dest string) error {
 				return err
 			}
 
-			err = os.Chmod(p, 0755)
+			err = os.Chmod(p, mode)
 			if err != nil {
 				return err
 			}
 
 			file.Close()
+		case tar.TypeSymlink:
+			if !opts.Preserve {
+				return fmt.Errorf("unknown flag type (%b) for file '%s'", header.Typeflag, header.Name)
+			}
+
+			if !linkInside(dest, p, header.Linkname) {
+				return ErrZipSlipAttack
+			}
+
+			err := os.MkdirAll(path.Dir(p), os.ModePerm)
+			if err != nil {
+				return err
+			}
+
+			err = os.Symlink(header.Linkname, p)
+			if err != nil {
+				return err
+			}
 		default:
 			return fmt.Errorf("unknown flag type (%b) for file '%s'", header.Typeflag, header.Name)
 		}
+
+		if opts.Preserve {
+			err = os.Lchown(p, header.Uid, header.Gid)
+			if err != nil && !errors.Is(err, os.ErrPermission) {
+				return err
+			}
+		}
+	}
+
+	for p, mode := range dirModes {
+		err = os.Chmod(p, mode)
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -156,3 +287,37 @@ func Untar(src string, dest string) error {
 func zipSlipAttack(path string) bool {
 	return strings.Contains(path, "..")
 }
+
+// linkInside returns whether the target of the symbolic link at p stays in
+// the dest directory. Tar and UntarWithOptions must agree on it, so that
+// archives can always be extracted.
+func linkInside(dest string, p string, target string) bool {
+	if path.IsAbs(target) || filepath.IsAbs(target) {
+		return false
+	}
+	rel, err := filepath.Rel(dest, filepath.Join(filepath.Dir(p), target))
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// throughSymlink returns whether p, or one of its parents in dest, is a
+// symbolic link.
+func throughSymlink(dest string, p string) bool {
+	rel, err := filepath.Rel(dest, p)
+	if err != nil {
+		return true
+	}
+	current := dest
+	for _, part := range strings.Split(rel, string(filepath.Separator)) {
+		current = filepath.Join(current, part)
+		info, err := os.Lstat(current)
+		if errors.Is(err, os.ErrNotExist) {
+			return false
+		} else if err != nil {
+			return true
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,126 @@
+package varchiver
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type TarTestSuite struct {
+	suite.Suite
+
+	dir string
+}
+
+func TestTarTestSuite(t *testing.T) {
+	suite.Run(t, new(TarTestSuite))
+}
+
+func (suite *TarTestSuite) SetupTest() {
+	var err error
+	suite.dir, err = os.MkdirTemp("", "*_live_test")
+	suite.Require().NoError(err)
+}
+
+func (suite *TarTestSuite) TearDownTest() {
+	_ = os.RemoveAll(suite.dir)
+}
+
+func (suite *TarTestSuite) TestTarUntarPreserve() {
+	src := path.Join(suite.dir, "src")
+	suite.Require().NoError(os.MkdirAll(path.Join(src, "data"), 0700))
+	suite.Require().NoError(os.WriteFile(path.Join(src, "data", "config"), []byte("key=value"), 0600))
+	suite.Require().NoError(os.Symlink("data/config", path.Join(src, "config")))
+
+	archive, err := os.Create(path.Join(suite.dir, "archive.tar.gz"))
+	suite.Require().NoError(err)
+	suite.Require().NoError(Tar(src, archive))
+	suite.Require().NoError(archive.Close())
+
+	dest := path.Join(suite.dir, "dest")
+	err = UntarWithOptions(archive.Name(), dest, UntarOptions{Preserve: true})
+	suite.Require().NoError(err)
+
+	content, err := os.ReadFile(path.Join(dest, "config"))
+	suite.Require().NoError(err)
+	suite.Equal("key=value", string(content))
+
+	info, err := os.Stat(path.Join(dest, "data"))
+	suite.Require().NoError(err)
+	suite.Equal(os.FileMode(0700), info.Mode().Perm())
+
+	info, err = os.Stat(path.Join(dest, "data", "config"))
+	suite.Require().NoError(err)
+	suite.Equal(os.FileMode(0600), info.Mode().Perm())
+
+	link, err := os.Readlink(path.Join(dest, "config"))
+	suite.Require().NoError(err)
+	suite.Equal("data/config", link)
+}
+
+func (suite *TarTestSuite) TestTarSkipsOutsideSymlinks() {
+	src := path.Join(suite.dir, "src")
+	suite.Require().NoError(os.MkdirAll(src, 0755))
+	suite.Require().NoError(os.WriteFile(path.Join(src, "file"), []byte("content"), 0644))
+	suite.Require().NoError(os.Symlink("/etc/passwd", path.Join(src, "absolute")))
+	suite.Require().NoError(os.Symlink("../outside", path.Join(src, "escaping")))
+	suite.Require().NoError(os.Symlink("file", path.Join(src, "inside")))
+
+	archive, err := os.Create(path.Join(suite.dir, "archive.tar.gz"))
+	suite.Require().NoError(err)
+	suite.Require().NoError(Tar(src, archive))
+	suite.Require().NoError(archive.Close())
+
+	dest := path.Join(suite.dir, "dest")
+	err = UntarWithOptions(archive.Name(), dest, UntarOptions{Preserve: true})
+	suite.Require().NoError(err)
+
+	entries, err := os.ReadDir(dest)
+	suite.Require().NoError(err)
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	suite.Equal([]string{"file", "inside"}, names)
+}
+
+func (suite *TarTestSuite) TestUntarUnsafeSymlinks() {
+	type entry struct {
+		name string
+		link string
+	}
+	tests := map[string][]entry{
+		"absolute link":        {{name: "etc", link: "/etc"}},
+		"escaping link":        {{name: "up", link: "data/../../outside"}},
+		"file through link":    {{name: "data", link: "."}, {name: "data/file"}},
+		"file replacing link":  {{name: "file", link: "other"}, {name: "file"}},
+		"nested through links": {{name: "a/b", link: "../c"}, {name: "a/b/file"}},
+	}
+	for name, entries := range tests {
+		src := path.Join(suite.dir, "archive.tar.gz")
+		file, err := os.Create(src)
+		suite.Require().NoError(err)
+		stream := gzip.NewWriter(file)
+		writer := tar.NewWriter(stream)
+		for _, e := range entries {
+			header := &tar.Header{Name: e.name, Mode: 0644, Typeflag: tar.TypeReg}
+			if e.link != "" {
+				header.Typeflag = tar.TypeSymlink
+				header.Linkname = e.link
+			}
+			suite.Require().NoError(writer.WriteHeader(header))
+		}
+		suite.Require().NoError(writer.Close())
+		suite.Require().NoError(stream.Close())
+		suite.Require().NoError(file.Close())
+
+		dest := path.Join(suite.dir, "dest")
+		err = UntarWithOptions(src, dest, UntarOptions{Preserve: true})
+		suite.ErrorIs(err, ErrZipSlipAttack, name)
+		suite.Require().NoError(os.RemoveAll(dest))
+	}
+}
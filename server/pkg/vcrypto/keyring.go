@@ -0,0 +1,156 @@
+package vcrypto
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// keyringPrefix starts the values encrypted by a Keyring. It is followed by
+// the version of the key, a colon and the ciphertext.
+const keyringPrefix = "enc:v"
+
+var ErrUnknownKey = errors.New("unknown key version")
+
+// Keyring holds the versions of a key, one file per version. Values are
+// encrypted with the latest version, and remember the version used, so that
+// they can still be decrypted after a rotation.
+type Keyring struct {
+	dir string
+
+	mu      sync.RWMutex
+	keys    map[int][]byte
+	current int
+}
+
+// LoadOrCreateKeyring reads the keys stored in dir. If there is none, a first
+// key is created.
+func LoadOrCreateKeyring(dir string) (*Keyring, error) {
+	k := &Keyring{
+		dir:  dir,
+		keys: map[int][]byte{},
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, entry := range entries {
+		version, err := strconv.Atoi(strings.TrimSuffix(entry.Name(), ".key"))
+		if err != nil || entry.IsDir() {
+			continue
+		}
+		key, err := LoadOrCreateKey(path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		k.keys[version] = key
+		k.current = max(k.current, version)
+	}
+
+	if len(k.keys) == 0 {
+		return k, k.Rotate()
+	}
+	return k, nil
+}
+
+// Encrypt encrypts the plaintext with the current key.
+func (k *Keyring) Encrypt(plaintext string) (string, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	ciphertext, err := Encrypt(k.keys[k.current], plaintext)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s%d:%s", keyringPrefix, k.current, ciphertext), nil
+}
+
+// Decrypt decrypts a value encrypted by Encrypt, with any version of the key.
+func (k *Keyring) Decrypt(value string) (string, error) {
+	version, ciphertext, err := parseKeyringValue(value)
+	if err != nil {
+		return "", err
+	}
+
+	k.mu.RLock()
+	key, ok := k.keys[version]
+	k.mu.RUnlock()
+	if !ok {
+		return "", ErrUnknownKey
+	}
+	return Decrypt(key, ciphertext)
+}
+
+// IsCurrent returns whether the value is encrypted with the current key.
+func (k *Keyring) IsCurrent(value string) bool {
+	version, _, err := parseKeyringValue(value)
+	if err != nil {
+		return false
+	}
+
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return version == k.current
+}
+
+// Rotate creates a new version of the key, used to encrypt the next values.
+// The previous versions are kept until Prune is called.
+func (k *Keyring) Rotate() error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	version := k.current + 1
+	key, err := LoadOrCreateKey(path.Join(k.dir, strconv.Itoa(version)+".key"))
+	if err != nil {
+		return err
+	}
+	k.keys[version] = key
+	k.current = version
+	return nil
+}
+
+// Prune deletes the previous versions of the key. Values encrypted with
+// them can't be decrypted anymore.
+func (k *Keyring) Prune() error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	for version := range k.keys {
+		if version == k.current {
+			continue
+		}
+		err := os.Remove(path.Join(k.dir, strconv.Itoa(version)+".key"))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		delete(k.keys, version)
+	}
+	return nil
+}
+
+// IsEncrypted returns whether the value was encrypted by a Keyring.
+func IsEncrypted(value string) bool {
+	_, _, err := parseKeyringValue(value)
+	return err == nil
+}
+
+func parseKeyringValue(value string) (int, string, error) {
+	rest, ok := strings.CutPrefix(value, keyringPrefix)
+	if !ok {
+		return 0, "", ErrInvalidCiphertext
+	}
+	v, ciphertext, ok := strings.Cut(rest, ":")
+	if !ok {
+		return 0, "", ErrInvalidCiphertext
+	}
+	version, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, "", ErrInvalidCiphertext
+	}
+	return version, ciphertext, nil
+}
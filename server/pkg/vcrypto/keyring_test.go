@@ -0,0 +1,72 @@
+package vcrypto
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type KeyringTestSuite struct {
+	suite.Suite
+
+	dir     string
+	keyring *Keyring
+}
+
+func TestKeyringTestSuite(t *testing.T) {
+	suite.Run(t, new(KeyringTestSuite))
+}
+
+func (suite *KeyringTestSuite) SetupTest() {
+	var err error
+	suite.dir, err = os.MkdirTemp("", "keyring")
+	suite.Require().NoError(err)
+
+	suite.keyring, err = LoadOrCreateKeyring(suite.dir)
+	suite.Require().NoError(err)
+}
+
+func (suite *KeyringTestSuite) TearDownTest() {
+	_ = os.RemoveAll(suite.dir)
+}
+
+func (suite *KeyringTestSuite) TestEncryptDecrypt() {
+	value, err := suite.keyring.Encrypt("hunter2")
+	suite.Require().NoError(err)
+	suite.NotContains(value, "hunter2")
+	suite.True(IsEncrypted(value))
+	suite.True(suite.keyring.IsCurrent(value))
+	suite.False(IsEncrypted("hunter2"))
+
+	plaintext, err := suite.keyring.Decrypt(value)
+	suite.Require().NoError(err)
+	suite.Equal("hunter2", plaintext)
+}
+
+func (suite *KeyringTestSuite) TestRotate() {
+	old, err := suite.keyring.Encrypt("hunter2")
+	suite.Require().NoError(err)
+
+	suite.Require().NoError(suite.keyring.Rotate())
+	suite.False(suite.keyring.IsCurrent(old))
+
+	plaintext, err := suite.keyring.Decrypt(old)
+	suite.Require().NoError(err)
+	suite.Equal("hunter2", plaintext)
+
+	value, err := suite.keyring.Encrypt(plaintext)
+	suite.Require().NoError(err)
+	suite.True(suite.keyring.IsCurrent(value))
+
+	suite.Require().NoError(suite.keyring.Prune())
+	_, err = suite.keyring.Decrypt(old)
+	suite.ErrorIs(err, ErrUnknownKey)
+
+	// The keys are loaded back from the disk.
+	loaded, err := LoadOrCreateKeyring(suite.dir)
+	suite.Require().NoError(err)
+	plaintext, err = loaded.Decrypt(value)
+	suite.Require().NoError(err)
+	suite.Equal("hunter2", plaintext)
+}
@@ -0,0 +1,75 @@
+package vcrypto
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type CryptoTestSuite struct {
+	suite.Suite
+
+	key []byte
+}
+
+func TestCryptoTestSuite(t *testing.T) {
+	suite.Run(t, new(CryptoTestSuite))
+}
+
+func (suite *CryptoTestSuite) SetupTest() {
+	var err error
+	suite.key, err = NewKey()
+	suite.Require().NoError(err)
+}
+
+func (suite *CryptoTestSuite) TestEncryptDecrypt() {
+	ciphertext, err := Encrypt(suite.key, "hunter2")
+	suite.Require().NoError(err)
+	suite.NotContains(ciphertext, "hunter2")
+
+	other, err := Encrypt(suite.key, "hunter2")
+	suite.Require().NoError(err)
+	suite.NotEqual(ciphertext, other)
+
+	plaintext, err := Decrypt(suite.key, ciphertext)
+	suite.Require().NoError(err)
+	suite.Equal("hunter2", plaintext)
+}
+
+func (suite *CryptoTestSuite) TestDecryptWrongKey() {
+	ciphertext, err := Encrypt(suite.key, "hunter2")
+	suite.Require().NoError(err)
+
+	key, err := NewKey()
+	suite.Require().NoError(err)
+
+	_, err = Decrypt(key, ciphertext)
+	suite.ErrorIs(err, ErrInvalidCiphertext)
+
+	_, err = Decrypt(suite.key, "not base64!")
+	suite.ErrorIs(err, ErrInvalidCiphertext)
+
+	_, err = Decrypt(suite.key, "AAAA")
+	suite.ErrorIs(err, ErrInvalidCiphertext)
+}
+
+func (suite *CryptoTestSuite) TestLoadOrCreateKey() {
+	dir, err := os.MkdirTemp("", "*_live_test")
+	suite.Require().NoError(err)
+	defer os.RemoveAll(dir)
+
+	p := path.Join(dir, "keys", "secret.key")
+	key, err := LoadOrCreateKey(p)
+	suite.Require().NoError(err)
+	suite.Len(key, KeySize)
+
+	info, err := os.Stat(p)
+	suite.Require().NoError(err)
+	suite.Equal(os.FileMode(0600), info.Mode().Perm())
+
+	loaded, err := LoadOrCreateKey(p)
+	suite.Require().NoError(err)
+	suite.Equal(key, loaded)
+}